@@ -7,16 +7,21 @@ import (
 	"github.com/almighty/almighty-core/codebase"
 
 	"github.com/almighty/almighty-core/comment"
+	"github.com/almighty/almighty-core/filter"
 	"github.com/almighty/almighty-core/iteration"
+	"github.com/almighty/almighty-core/label"
 	"github.com/almighty/almighty-core/space"
 	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
 	"github.com/almighty/almighty-core/workitem/link"
+	"github.com/almighty/almighty-core/workitem/template"
 )
 
 //An Application stands for a particular implementation of the business logic of our application
 type Application interface {
 	WorkItems() workitem.WorkItemRepository
 	WorkItemTypes() workitem.WorkItemTypeRepository
+	WorkItemRevisions() workitem.RevisionRepository
 	Trackers() TrackerRepository
 	TrackerQueries() TrackerQueryRepository
 	SearchItems() SearchRepository
@@ -32,6 +37,10 @@ type Application interface {
 	Areas() area.Repository
 	OauthStates() auth.OauthStateReferenceRepository
 	Codebases() codebase.Repository
+	SavedFilters() filter.SavedFilterRepository
+	WorkItemTemplates() template.TemplateRepository
+	Attachments() attachment.Repository
+	Labels() label.Repository
 }
 
 // A Transaction abstracts a database transaction. The repositories created for the transaction object make changes inside the the transaction
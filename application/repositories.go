@@ -3,7 +3,7 @@ package application
 import (
 	"github.com/almighty/almighty-core/app"
 	"github.com/almighty/almighty-core/criteria"
-	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/search"
 
 	uuid "github.com/satori/go.uuid"
 	"golang.org/x/net/context"
@@ -29,5 +29,6 @@ type TrackerQueryRepository interface {
 
 // SearchRepository encapsulates searching of woritems,users,etc
 type SearchRepository interface {
-	SearchFullText(ctx context.Context, searchStr string, start *int, length *int) ([]workitem.WorkItem, uint64, error)
+	SearchFullText(ctx context.Context, searchStr string, scope search.Scope, start *int, length *int) ([]search.Result, uint64, error)
+	SearchFacets(ctx context.Context, searchStr string, scope search.Scope) (search.Facets, error)
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
 	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/rendering"
 	"github.com/goadesign/goa"
@@ -20,10 +21,22 @@ type Repository interface {
 	Save(ctx context.Context, comment *Comment, modifier uuid.UUID) error
 	Delete(ctx context.Context, commentID uuid.UUID, suppressor uuid.UUID) error
 	List(ctx context.Context, parent string, start *int, limit *int) ([]*Comment, uint64, error)
+	ListAfter(ctx context.Context, parent string, cursor *string, limit int) ([]*Comment, uint64, *string, error)
 	Load(ctx context.Context, id uuid.UUID) (*Comment, error)
 	Count(ctx context.Context, parent string) (int, error)
+	Purge(ctx context.Context, parent string) error
 }
 
+// order is the order of comment lists, newest first. The ID makes the order
+// unique, which is required for keyset pagination.
+var order = []gormsupport.OrderTerm{
+	{Expression: "created_at", Descending: true},
+	{Expression: "id", Descending: true},
+}
+
+// cursorOrder identifies the order of comment lists in the cursors of the lists
+const cursorOrder = "comments"
+
 // NewRepository creates a new storage type.
 func NewRepository(db *gorm.DB) Repository {
 	return &GormCommentRepository{db: db, revisionRepository: &GormCommentRevisionRepository{db}}
@@ -132,6 +145,22 @@ func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID,
 	return nil
 }
 
+// Purge permanently deletes all comments related to a single item, including the deleted ones
+// and the revisions of the comments.
+func (m *GormCommentRepository) Purge(ctx context.Context, parent string) error {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "purge"}, time.Now())
+	// revisions are deleted by the database
+	tx := m.db.Unscoped().Where("parent_id = ?", parent).Delete(&Comment{})
+	if err := tx.Error; err != nil {
+		return errors.NewInternalError(err.Error())
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"parent_id": parent,
+		"comments":  tx.RowsAffected,
+	}, "Comments purged")
+	return nil
+}
+
 // List all comments related to a single item
 func (m *GormCommentRepository) List(ctx context.Context, parent string, start *int, limit *int) ([]*Comment, uint64, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
@@ -150,7 +179,7 @@ func (m *GormCommentRepository) List(ctx context.Context, parent string, start *
 		}
 		db = db.Limit(*limit)
 	}
-	db = db.Select("count(*) over () as cnt2 , *").Order("created_at desc")
+	db = db.Select("count(*) over () as cnt2 , *").Order(gormsupport.OrderClause(order))
 
 	rows, err := db.Rows()
 	if err != nil {
@@ -202,6 +231,77 @@ func (m *GormCommentRepository) List(ctx context.Context, parent string, start *
 	return result, count, nil
 }
 
+// ListAfter returns at most limit comments related to a single item that follow the comment the
+// given cursor points to. The list starts with the newest comment if there is no cursor. It also
+// returns the total number of comments and a cursor pointing to the last returned comment, if more
+// comments follow.
+func (m *GormCommentRepository) ListAfter(ctx context.Context, parent string, cursor *string, limit int) ([]*Comment, uint64, *string, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
+	if limit <= 0 {
+		return nil, 0, nil, errors.NewBadParameterError("limit", limit)
+	}
+	db := m.db.Model(&Comment{}).Where("parent_id = ?", parent)
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, nil, errors.NewInternalError(err.Error())
+	}
+	if cursor != nil && *cursor != "" {
+		c, err := decodeCursor(*cursor)
+		if err != nil {
+			return nil, 0, nil, errs.WithStack(err)
+		}
+		where, parameters, err := gormsupport.KeysetCondition(order, *c)
+		if err != nil {
+			return nil, 0, nil, errs.WithStack(err)
+		}
+		db = db.Where(where, parameters...)
+	}
+	// fetch one more comment to find out whether another page follows
+	result := []*Comment{}
+	if err := db.Order(gormsupport.OrderClause(order)).Limit(limit + 1).Find(&result).Error; err != nil {
+		return nil, 0, nil, errors.NewInternalError(err.Error())
+	}
+	var next *string
+	if len(result) > limit {
+		result = result[:limit]
+		last := result[limit-1]
+		token := gormsupport.Cursor{Order: cursorOrder, Keys: []interface{}{last.CreatedAt, last.ID.String()}}.Encode()
+		next = &token
+	}
+	return result, count, next, nil
+}
+
+// decodeCursor parses a cursor created by ListAfter and converts its keys to the types of the
+// order terms, so that a tampered cursor cannot cause a database error.
+// returns BadParameterError
+func decodeCursor(token string) (*gormsupport.Cursor, error) {
+	c, err := gormsupport.DecodeCursor(token)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	invalid := errors.NewBadParameterError("page[cursor]", token).Expected("cursor of a comment list")
+	if c.Order != cursorOrder || len(c.Keys) != len(order) {
+		return nil, invalid
+	}
+	createdAtKey, isString := c.Keys[0].(string)
+	if !isString {
+		return nil, invalid
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtKey)
+	if err != nil {
+		return nil, invalid
+	}
+	idKey, isString := c.Keys[1].(string)
+	if !isString {
+		return nil, invalid
+	}
+	id, err := uuid.FromString(idKey)
+	if err != nil {
+		return nil, invalid
+	}
+	return &gormsupport.Cursor{Order: c.Order, Keys: []interface{}{createdAt, id.String()}}, nil
+}
+
 // Count all comments related to a single item
 func (m *GormCommentRepository) Count(ctx context.Context, parent string) (int, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
@@ -8,6 +8,8 @@ import (
 
 	"github.com/almighty/almighty-core/account"
 	"github.com/almighty/almighty-core/comment"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
 	"github.com/almighty/almighty-core/gormsupport/cleaner"
 	"github.com/almighty/almighty-core/gormtestsupport"
 	"github.com/almighty/almighty-core/migration"
@@ -15,6 +17,7 @@ import (
 	"github.com/almighty/almighty-core/resource"
 	testsupport "github.com/almighty/almighty-core/test"
 
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -153,6 +156,27 @@ func (s *TestCommentRepository) TestDeleteComment() {
 	assert.Nil(s.T(), err)
 }
 
+func (s *TestCommentRepository) TestPurgeComments() {
+	// given
+	comment1 := newComment("P", "Test P1", rendering.SystemMarkupMarkdown)
+	comment2 := newComment("P", "Test P2", rendering.SystemMarkupMarkdown)
+	comment3 := newComment("Q", "Test Q", rendering.SystemMarkupMarkdown)
+	s.createComments([]*comment.Comment{comment1, comment2, comment3}, s.testIdentity.ID)
+	err := s.repo.Delete(s.ctx, comment2.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when
+	err = s.repo.Purge(s.ctx, "P")
+	// then
+	require.Nil(s.T(), err)
+	var count int
+	err = s.DB.Unscoped().Model(&comment.Comment{}).Where("parent_id = ?", "P").Count(&count).Error
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, count)
+	count, err = s.repo.Count(s.ctx, "Q")
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, count)
+}
+
 func (s *TestCommentRepository) TestCountComments() {
 	// given
 	parentID := "A"
@@ -211,6 +235,61 @@ func (s *TestCommentRepository) TestListCommentsWrongLimit() {
 	assert.NotNil(s.T(), err)
 }
 
+func (s *TestCommentRepository) TestListCommentsAfterCursor() {
+	// given
+	parentID := uuid.NewV4().String()
+	comments := []*comment.Comment{
+		newComment(parentID, "Test 1", rendering.SystemMarkupMarkdown),
+		newComment(parentID, "Test 2", rendering.SystemMarkupMarkdown),
+		newComment(parentID, "Test 3", rendering.SystemMarkupMarkdown),
+	}
+	s.createComments(comments, s.testIdentity.ID)
+	// when
+	page1, count, cursor, err := s.repo.ListAfter(s.ctx, parentID, nil, 2)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(3), count)
+	require.Len(s.T(), page1, 2)
+	assert.Equal(s.T(), "Test 3", page1[0].Body)
+	assert.Equal(s.T(), "Test 2", page1[1].Body)
+	require.NotNil(s.T(), cursor)
+	// when a new comment is added while paging
+	s.createComment(newComment(parentID, "Test 4", rendering.SystemMarkupMarkdown), s.testIdentity.ID)
+	page2, count, cursor, err := s.repo.ListAfter(s.ctx, parentID, cursor, 2)
+	// then the next page still starts after the last comment of the first page
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(4), count)
+	require.Len(s.T(), page2, 1)
+	assert.Equal(s.T(), "Test 1", page2[0].Body)
+	assert.Nil(s.T(), cursor)
+}
+
+func (s *TestCommentRepository) TestListCommentsWrongCursor() {
+	// given
+	invalid := "not a cursor"
+	// when
+	_, _, _, err := s.repo.ListAfter(s.ctx, "A", &invalid, 1)
+	// then
+	assert.NotNil(s.T(), err)
+	// when the keys of the cursor are tampered with
+	for _, keys := range [][]interface{}{
+		{42, uuid.NewV4().String()},
+		{"yesterday", uuid.NewV4().String()},
+		{time.Now(), "not an id"},
+		{time.Now()},
+	} {
+		tampered := gormsupport.Cursor{Order: "comments", Keys: keys}.Encode()
+		_, _, _, err = s.repo.ListAfter(s.ctx, "A", &tampered, 1)
+		// then
+		assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err), "keys %v", keys)
+	}
+	// when the cursor belongs to another list
+	other := gormsupport.Cursor{Order: "system.title", Keys: []interface{}{time.Now(), uuid.NewV4().String()}}.Encode()
+	_, _, _, err = s.repo.ListAfter(s.ctx, "A", &other, 1)
+	// then
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+}
+
 func (s *TestCommentRepository) TestLoadComment() {
 	// given
 	comment := newComment("A", "Test A", rendering.SystemMarkupMarkdown)
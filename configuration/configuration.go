@@ -3,6 +3,7 @@ package configuration
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -72,6 +73,10 @@ const (
 	varCacheControlAreas                = "cachecontrol.areas"
 	varCacheControlSpace                = "cachecontrol.space"
 	varCacheControlIteration            = "cachecontrol.iteration"
+	varTrashRetention                   = "trash.retention"
+	varTrashPurgeSchedule               = "trash.purge.schedule"
+	varAttachmentsMaxSize               = "attachments.maxsize"
+	varAttachmentsStoragePath           = "attachments.storage.path"
 	defaultConfigFile                   = "config.yaml"
 	varOpenshiftTenantMasterURL         = "openshift.tenant.masterurl"
 	varCheStarterURL                    = "chestarterurl"
@@ -176,6 +181,14 @@ func (c *ConfigurationData) setConfigDefaults() {
 	c.v.SetDefault(varCacheControlSpace, "max-age=300")
 	c.v.SetDefault(varCacheControlIteration, "max-age=300")
 
+	// Trash
+	c.v.SetDefault(varTrashRetention, time.Duration(30*24*time.Hour)) // 30 days
+	c.v.SetDefault(varTrashPurgeSchedule, "@hourly")
+
+	// Attachments
+	c.v.SetDefault(varAttachmentsMaxSize, int64(10*1024*1024)) // 10 MiB
+	c.v.SetDefault(varAttachmentsStoragePath, filepath.Join(os.TempDir(), "almighty-attachments"))
+
 	c.v.SetDefault(varKeycloakTesUser2Name, defaultKeycloakTesUser2Name)
 	c.v.SetDefault(varKeycloakTesUser2Secret, defaultKeycloakTesUser2Secret)
 	c.v.SetDefault(varOpenshiftTenantMasterURL, defaultOpenshiftTenantMasterURL)
@@ -302,6 +315,30 @@ func (c *ConfigurationData) GetCacheControlIteration() string {
 	return c.v.GetString(varCacheControlIteration)
 }
 
+// GetTrashRetention returns the duration (as set via default, config file, or environment variable)
+// after which deleted work items are purged from the trash
+func (c *ConfigurationData) GetTrashRetention() time.Duration {
+	return c.v.GetDuration(varTrashRetention)
+}
+
+// GetTrashPurgeSchedule returns the cron schedule (as set via default, config file, or environment variable)
+// of the job purging the trash. The job is disabled if the schedule is empty.
+func (c *ConfigurationData) GetTrashPurgeSchedule() string {
+	return c.v.GetString(varTrashPurgeSchedule)
+}
+
+// GetAttachmentsMaxSize returns the maximum size in bytes (as set via default, config file, or environment variable)
+// of the files attached to work items
+func (c *ConfigurationData) GetAttachmentsMaxSize() int64 {
+	return c.v.GetInt64(varAttachmentsMaxSize)
+}
+
+// GetAttachmentsStoragePath returns the directory (as set via default, config file, or environment variable)
+// where the content of the files attached to work items is stored
+func (c *ConfigurationData) GetAttachmentsStoragePath() string {
+	return c.v.GetString(varAttachmentsStoragePath)
+}
+
 // GetTokenPrivateKey returns the private key (as set via config file or environment variable)
 // that is used to sign the authentication token.
 func (c *ConfigurationData) GetTokenPrivateKey() []byte {
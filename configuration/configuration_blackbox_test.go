@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"net/http"
 
@@ -231,6 +232,38 @@ func TestGetTokenPublicKeyFromConfigFile(t *testing.T) {
 	assert.NotNil(t, parsedKey)
 }
 
+func TestGetTrashConfigurationOK(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, 30*24*time.Hour, config.GetTrashRetention())
+	assert.Equal(t, "@hourly", config.GetTrashPurgeSchedule())
+
+	envKey := generateEnvKey("trash.retention")
+	realEnvValue := os.Getenv(envKey)
+	defer func() {
+		os.Setenv(envKey, realEnvValue)
+		resetConfiguration(defaultValuesConfigFilePath)
+	}()
+	os.Setenv(envKey, "1h30m")
+	resetConfiguration(defaultValuesConfigFilePath)
+	assert.Equal(t, 90*time.Minute, config.GetTrashRetention())
+}
+
+func TestGetAttachmentsConfigurationOK(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, int64(10*1024*1024), config.GetAttachmentsMaxSize())
+	assert.NotEmpty(t, config.GetAttachmentsStoragePath())
+
+	envKey := generateEnvKey("attachments.maxsize")
+	realEnvValue := os.Getenv(envKey)
+	defer func() {
+		os.Setenv(envKey, realEnvValue)
+		resetConfiguration(defaultValuesConfigFilePath)
+	}()
+	os.Setenv(envKey, "1024")
+	resetConfiguration(defaultValuesConfigFilePath)
+	assert.Equal(t, int64(1024), config.GetAttachmentsMaxSize())
+}
+
 func generateEnvKey(yamlKey string) string {
 	return "ALMIGHTY_" + strings.ToUpper(strings.Replace(yamlKey, ".", "_", -1))
 }
@@ -2,18 +2,24 @@ package controller
 
 import (
 	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/filter"
+	"github.com/almighty/almighty-core/jsonapi"
 	"github.com/almighty/almighty-core/rest"
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
 )
 
 // FilterController implements the filter resource.
 type FilterController struct {
 	*goa.Controller
+	db application.DB
 }
 
 // NewFilterController creates a filter controller.
-func NewFilterController(service *goa.Service) *FilterController {
-	return &FilterController{Controller: service.NewController("FilterController")}
+func NewFilterController(service *goa.Service, db application.DB) *FilterController {
+	return &FilterController{Controller: service.NewController("FilterController"), db: db}
 }
 
 // List runs the list action.
@@ -65,11 +71,62 @@ func (c *FilterController) List(ctx *app.ListFilterContext) error {
 			Type: "filters",
 		},
 	)
-	result := &app.FilterList{
-		Data: arr,
+	// the saved filters of the current user and the ones shared with the space follow the built-in filters
+	identityID, err := optionalIdentity(ctx, ctx.RequestData)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	if identityID == nil {
+		// anonymous users have no saved filters and see no shared ones
+		return ctx.OK(&app.FilterList{
+			Data: arr,
+		})
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		// the filters shared with the space are listed for its members only
+		spaceID := ctx.FilterSpace
+		if spaceID != nil {
+			s, err := appl.Spaces().Load(ctx, *spaceID)
+			if err != nil {
+				if _, ok := errs.Cause(err).(errors.NotFoundError); !ok {
+					return jsonapi.JSONErrorResponse(ctx, err)
+				}
+				spaceID = nil
+			} else if s.OwnerId != *identityID {
+				spaceID = nil
+			}
+		}
+		saved, err := appl.SavedFilters().List(ctx, identityID, spaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		for _, f := range saved {
+			arr = append(arr, convertSavedFilterToFilter(ctx.RequestData, f))
+		}
+		result := &app.FilterList{
+			Data: arr,
+		}
+		return ctx.OK(result)
+	})
+}
 
-	return ctx.OK(result)
+// convertSavedFilterToFilter converts a saved filter to an entry of the filter list
+func convertSavedFilterToFilter(request *goa.RequestData, f filter.SavedFilter) *app.Filters {
+	selfURL := rest.AbsoluteURL(request, app.SavedFilterHref(f.ID))
+	id := f.ID
+	return &app.Filters{
+		ID: &id,
+		Attributes: &app.FilterAttributes{
+			Title:       f.Title,
+			Query:       "filter[saved]=" + f.ID.String(),
+			Description: f.Description,
+			Type:        filter.APIStringTypeSavedFilter,
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+		Type: "filters",
+	}
 }
 
 func addFilterLinks(links *app.PagingLinks, request *goa.RequestData) {
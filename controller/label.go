@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/label"
+	"github.com/almighty/almighty-core/login"
+	"github.com/almighty/almighty-core/rest"
+	"github.com/almighty/almighty-core/space"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// LabelController implements the label resource.
+type LabelController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewLabelController creates a label controller.
+func NewLabelController(service *goa.Service, db application.DB) *LabelController {
+	return &LabelController{Controller: service.NewController("LabelController"), db: db}
+}
+
+// List runs the list action.
+func (c *LabelController) List(ctx *app.ListLabelContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, spaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		labels, err := appl.Labels().List(ctx, spaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.LabelList{
+			Data: []*app.Label{},
+		}
+		for _, l := range labels {
+			res.Data = append(res.Data, ConvertLabel(ctx.RequestData, l))
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Show runs the show action.
+func (c *LabelController) Show(ctx *app.ShowLabelContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		l, err := appl.Labels().Load(ctx, spaceID, ctx.LabelID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.LabelSingle{
+			Data: ConvertLabel(ctx.RequestData, *l),
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *LabelController) Create(ctx *app.CreateLabelContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	_, err = login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	l := label.Label{
+		SpaceID: spaceID,
+	}
+	ConvertJSONAPIToLabel(*ctx.Payload.Data, &l)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, spaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if err := appl.Labels().Create(ctx, &l); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.LabelSingle{
+			Data: ConvertLabel(ctx.RequestData, l),
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.LabelHref(spaceID, l.ID)))
+		return ctx.Created(res)
+	})
+}
+
+// Update runs the update action.
+func (c *LabelController) Update(ctx *app.UpdateLabelContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	_, err = login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		l, err := appl.Labels().Load(ctx, spaceID, ctx.LabelID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		l.Version = *ctx.Payload.Data.Attributes.Version
+		ConvertJSONAPIToLabel(*ctx.Payload.Data, l)
+		l, err = appl.Labels().Save(ctx, *l)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.LabelSingle{
+			Data: ConvertLabel(ctx.RequestData, *l),
+		})
+	})
+}
+
+// Delete runs the delete action.
+func (c *LabelController) Delete(ctx *app.DeleteLabelContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	_, err = login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Labels().Delete(ctx, spaceID, ctx.LabelID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK([]byte{})
+	})
+}
+
+// loadLabelID returns the ID of the label of the given space with the given ID or name
+// returns BadParameterError or InternalError
+func loadLabelID(ctx context.Context, appl application.Application, spaceID uuid.UUID, param string, idOrName string) (uuid.UUID, error) {
+	var l *label.Label
+	var err error
+	if id, convErr := uuid.FromString(idOrName); convErr == nil {
+		l, err = appl.Labels().Load(ctx, spaceID, id)
+	} else {
+		l, err = appl.Labels().LoadByName(ctx, spaceID, idOrName)
+	}
+	if err != nil {
+		if _, ok := errs.Cause(err).(errors.NotFoundError); ok {
+			return uuid.Nil, errors.NewBadParameterError(param, idOrName).Expected("a label of the space")
+		}
+		return uuid.Nil, err
+	}
+	return l.ID, nil
+}
+
+// ConvertLabelsSimple converts the label IDs of a work item of the given space into generic relationships
+func ConvertLabelsSimple(request *goa.RequestData, spaceID uuid.UUID, ids []interface{}) []*app.GenericData {
+	ops := []*app.GenericData{}
+	for _, id := range ids {
+		ops = append(ops, ConvertLabelSimple(request, spaceID, id))
+	}
+	return ops
+}
+
+// ConvertLabelSimple converts a label ID of a work item of the given space into a generic relationship
+func ConvertLabelSimple(request *goa.RequestData, spaceID uuid.UUID, id interface{}) *app.GenericData {
+	t := label.APIStringTypeLabels
+	i := fmt.Sprint(id)
+	selfURL := rest.AbsoluteURL(request, app.LabelHref(spaceID, i))
+	return &app.GenericData{
+		Type: &t,
+		ID:   &i,
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
+// ConvertJSONAPIToLabel copies the attributes of the given JSONAPI label to the target.
+// Attributes that are not set are left as is.
+func ConvertJSONAPIToLabel(source app.Label, target *label.Label) {
+	if source.Attributes.Name != nil {
+		target.Name = *source.Attributes.Name
+	}
+	if source.Attributes.Color != nil {
+		target.Color = *source.Attributes.Color
+	}
+	if source.Attributes.Description != nil {
+		target.Description = *source.Attributes.Description
+	}
+}
+
+// ConvertLabel converts between internal and external REST representation
+func ConvertLabel(request *goa.RequestData, l label.Label) *app.Label {
+	selfURL := rest.AbsoluteURL(request, app.LabelHref(l.SpaceID, l.ID))
+	spaceType := space.SpaceType
+	spaceID := l.SpaceID.String()
+	spaceURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	return &app.Label{
+		Type: label.APIStringTypeLabels,
+		ID:   &l.ID,
+		Attributes: &app.LabelAttributes{
+			Name:        &l.Name,
+			Color:       &l.Color,
+			Description: &l.Description,
+			CreatedAt:   &l.CreatedAt,
+			UpdatedAt:   &l.UpdatedAt,
+			Version:     &l.Version,
+		},
+		Relationships: &app.LabelRelationships{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &spaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Related: &spaceURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
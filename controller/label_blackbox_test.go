@@ -0,0 +1,176 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/app/test"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/label"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestLabelREST struct {
+	gormtestsupport.DBTestSuite
+	db       *gormapplication.GormDB
+	clean    func()
+	identity account.Identity
+	ctx      context.Context
+	svc      *goa.Service
+	ctrl     *LabelController
+	spaceID  uuid.UUID
+}
+
+func TestRunLabelREST(t *testing.T) {
+	suite.Run(t, &TestLabelREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestLabelREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	var err error
+	rest.identity, err = testsupport.CreateTestIdentity(rest.DB, "TestLabelREST user", "test provider")
+	require.Nil(rest.T(), err)
+	req := &http.Request{Host: "localhost"}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, url.Values{})
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	rest.svc = testsupport.ServiceAsUser("Label-Service", almtoken.NewManagerWithPrivateKey(priv), rest.identity)
+	rest.ctrl = NewLabelController(rest.svc, rest.db)
+	testSpace, err := space.NewRepository(rest.DB).Create(rest.ctx, &space.Space{
+		Name: "TestLabelREST " + uuid.NewV4().String(),
+	})
+	require.Nil(rest.T(), err)
+	rest.spaceID = testSpace.ID
+}
+
+func (rest *TestLabelREST) TearDownTest() {
+	rest.clean()
+}
+
+func newCreateLabelPayload(name string, color *string) *app.CreateLabelPayload {
+	return &app.CreateLabelPayload{
+		Data: &app.Label{
+			Type: label.APIStringTypeLabels,
+			Attributes: &app.LabelAttributes{
+				Name:  &name,
+				Color: color,
+			},
+		},
+	}
+}
+
+func (rest *TestLabelREST) createLabel(name string) *app.Label {
+	_, created := test.CreateLabelCreated(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), newCreateLabelPayload(name, nil))
+	return created.Data
+}
+
+func (rest *TestLabelREST) TestCreateShowAndList() {
+	// when
+	created := rest.createLabel("needs-triage")
+	rest.createLabel("backend")
+	// then
+	require.NotNil(rest.T(), created.ID)
+	assert.Equal(rest.T(), "needs-triage", *created.Attributes.Name)
+	assert.Equal(rest.T(), label.DefaultColor, *created.Attributes.Color)
+	_, shown := test.ShowLabelOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+	assert.Equal(rest.T(), *created.ID, *shown.Data.ID)
+	_, list := test.ListLabelOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String())
+	require.Len(rest.T(), list.Data, 2)
+	assert.Equal(rest.T(), "backend", *list.Data[0].Attributes.Name)
+	test.ShowLabelNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, space.SystemSpace.String(), *created.ID)
+}
+
+func (rest *TestLabelREST) TestCreateInvalid() {
+	rest.createLabel("needs-triage")
+	test.CreateLabelBadRequest(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), newCreateLabelPayload("needs-triage", nil))
+	test.CreateLabelNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, uuid.NewV4().String(), newCreateLabelPayload("unknown space", nil))
+	svc := goa.New("Label-Service")
+	test.CreateLabelUnauthorized(rest.T(), svc.Context, svc, NewLabelController(svc, rest.db), rest.spaceID.String(), newCreateLabelPayload("unauthorized", nil))
+}
+
+func (rest *TestLabelREST) TestUpdateAndDelete() {
+	// given
+	created := rest.createLabel("needs-triage")
+	name := "triaged"
+	color := "#00ff00"
+	payload := &app.UpdateLabelPayload{
+		Data: &app.Label{
+			Type: label.APIStringTypeLabels,
+			ID:   created.ID,
+			Attributes: &app.LabelAttributes{
+				Name:    &name,
+				Color:   &color,
+				Version: created.Attributes.Version,
+			},
+		},
+	}
+	// when
+	_, updated := test.UpdateLabelOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID, payload)
+	// then
+	assert.Equal(rest.T(), name, *updated.Data.Attributes.Name)
+	assert.Equal(rest.T(), color, *updated.Data.Attributes.Color)
+	assert.Equal(rest.T(), *created.Attributes.Version+1, *updated.Data.Attributes.Version)
+	test.UpdateLabelBadRequest(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID, payload)
+	// when
+	test.DeleteLabelOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+	// then
+	test.ShowLabelNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+	test.DeleteLabelNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+}
+
+func (rest *TestLabelREST) TestLabelWorkItems() {
+	// given
+	triage := rest.createLabel("needs-triage")
+	triageID := triage.ID.String()
+	payload := minimumRequiredCreateWithType(workitem.SystemBug)
+	payload.Data.Attributes[workitem.SystemTitle] = "Crash on startup"
+	payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+	payload.Data.Relationships.Labels = &app.RelationGenericList{
+		Data: []*app.GenericData{{ID: &triageID}},
+	}
+	wiCtrl := NewWorkitemController(rest.svc, rest.db, rest.Configuration)
+	// when
+	_, wi := test.CreateWorkitemCreated(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), &payload)
+	// then
+	require.Len(rest.T(), wi.Data.Relationships.Labels.Data, 1)
+	assert.Equal(rest.T(), triageID, *wi.Data.Relationships.Labels.Data[0].ID)
+	assert.Equal(rest.T(), label.APIStringTypeLabels, *wi.Data.Relationships.Labels.Data[0].Type)
+	// the work items can be filtered by the ID or the name of the label
+	for _, filter := range []string{triageID, "needs-triage"} {
+		_, list := test.ListWorkitemOK(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), nil, nil, nil, nil, &filter, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		require.Len(rest.T(), list.Data, 1)
+		assert.Equal(rest.T(), *wi.Data.ID, *list.Data[0].ID)
+	}
+	unknown := "unknown"
+	test.ListWorkitemBadRequest(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), nil, nil, nil, nil, &unknown, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	// labels of other spaces are rejected
+	otherSpaceLabel := label.Label{SpaceID: space.SystemSpace, Name: "needs-triage " + uuid.NewV4().String()}
+	require.Nil(rest.T(), label.NewLabelRepository(rest.DB).Create(rest.ctx, &otherSpaceLabel))
+	otherSpaceLabelID := otherSpaceLabel.ID.String()
+	payload.Data.Relationships.Labels.Data = []*app.GenericData{{ID: &otherSpaceLabelID}}
+	test.CreateWorkitemBadRequest(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), &payload)
+	// when the label is deleted
+	test.DeleteLabelOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *triage.ID)
+	// then it is removed from the work item
+	_, shown := test.ShowWorkitemOK(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), *wi.Data.ID, nil, nil, nil, nil)
+	assert.Empty(rest.T(), shown.Data.Relationships.Labels.Data)
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"net/http"
@@ -16,9 +17,12 @@ import (
 	"github.com/almighty/almighty-core/jsonapi"
 	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/login"
+	tokencontext "github.com/almighty/almighty-core/login/token_context"
 	"github.com/almighty/almighty-core/test"
 	"github.com/almighty/almighty-core/token"
 	"github.com/goadesign/goa"
+	goajwt "github.com/goadesign/goa/middleware/security/jwt"
+	uuid "github.com/satori/go.uuid"
 )
 
 type loginConfiguration interface {
@@ -240,3 +244,25 @@ func GenerateUserToken(ctx context.Context, tokenEndpoint string, configuration
 
 	return &app.AuthToken{Token: token}, nil
 }
+
+// optionalIdentity returns the ID of the user who sent the request or nil for anonymous requests.
+// It is meant for actions that are not secured, so a token that was not validated by the JWT
+// middleware is validated here.
+func optionalIdentity(ctx context.Context, request *goa.RequestData) (*uuid.UUID, error) {
+	if goajwt.ContextJWT(ctx) != nil {
+		return login.ContextIdentity(ctx)
+	}
+	authorization := request.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return nil, nil
+	}
+	tm := tokencontext.ReadTokenManagerFromContext(ctx)
+	if tm == nil {
+		return nil, errors.NewInternalError("missing token manager")
+	}
+	identity, err := tm.(token.Manager).Extract(strings.TrimPrefix(authorization, "Bearer "))
+	if err != nil {
+		return nil, errors.NewUnauthorizedError(err.Error())
+	}
+	return &identity.ID, nil
+}
@@ -4,6 +4,7 @@ package controller
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -114,6 +115,22 @@ func setPagingLinks(links *app.PagingLinks, path string, resultLen, offset, limi
 	links.Last = &last
 }
 
+// setCursorPagingLinks sets the links of a page that was fetched with a cursor. Cursors
+// only allow paging forward, so there are no prev and last links. The next link is only
+// set if another page follows.
+func setCursorPagingLinks(links *app.PagingLinks, path string, limit int, next *string, additionalQuery ...string) {
+	additional := ""
+	if len(additionalQuery) > 0 {
+		additional = "&" + strings.Join(additionalQuery, "&")
+	}
+	first := fmt.Sprintf("%s?page[cursor]=&page[limit]=%d%s", path, limit, additional)
+	links.First = &first
+	if next != nil {
+		nextLink := fmt.Sprintf("%s?page[cursor]=%s&page[limit]=%d%s", path, url.QueryEscape(*next), limit, additional)
+		links.Next = &nextLink
+	}
+}
+
 func buildAbsoluteURL(req *goa.RequestData) string {
 	return rest.AbsoluteURL(req, req.URL.Path)
 }
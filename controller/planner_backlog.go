@@ -8,7 +8,7 @@ import (
 	"github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/errors"
 	"github.com/almighty/almighty-core/jsonapi"
-	query "github.com/almighty/almighty-core/query/simple"
+	"github.com/almighty/almighty-core/query"
 	"github.com/almighty/almighty-core/workitem"
 
 	"github.com/goadesign/goa"
@@ -89,7 +89,7 @@ func generateBacklogExpression(ctx context.Context, db application.DB, spaceID u
 		if err != nil {
 			return errs.Wrap(err, "unable to fetch root iteration")
 		}
-		exp = criteria.Equals(criteria.Field(workitem.SystemIteration), criteria.Literal(iteration.ID.String()))
+		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemIteration), criteria.Literal(iteration.ID.String())))
 
 		// Get the list of work item types that derive of PlannerItem in the space
 		var expWits criteria.Expression
@@ -130,7 +130,7 @@ func getBacklogItems(ctx context.Context, db application.DB, spaceID uuid.UUID,
 	err = application.Transactional(db, func(appl application.Application) error {
 		// Get the list of work items for the following criteria
 		var tc uint64
-		result, tc, err = appl.WorkItems().List(ctx, spaceID, backlogExp, offset, limit)
+		result, tc, err = appl.WorkItems().List(ctx, spaceID, backlogExp, nil, offset, limit)
 		count = int(tc)
 		if err != nil {
 			return errs.Wrap(err, "error listing backlog items")
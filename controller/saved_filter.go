@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/filter"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/login"
+	"github.com/almighty/almighty-core/rest"
+	"github.com/almighty/almighty-core/space"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SavedFilterController implements the saved_filter resource.
+type SavedFilterController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSavedFilterController creates a saved_filter controller.
+func NewSavedFilterController(service *goa.Service, db application.DB) *SavedFilterController {
+	return &SavedFilterController{Controller: service.NewController("SavedFilterController"), db: db}
+}
+
+// Show runs the show action.
+func (c *SavedFilterController) Show(ctx *app.ShowSavedFilterContext) error {
+	identityID, err := optionalIdentity(ctx, ctx.RequestData)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		f, err := loadVisibleSavedFilter(ctx, appl, ctx.FilterID, identityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.SavedFilterSingle{
+			Data: ConvertSavedFilter(ctx.RequestData, *f),
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *SavedFilterController) Create(ctx *app.CreateSavedFilterContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	f := filter.SavedFilter{OwnerID: *identityID}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := ConvertJSONAPIToSavedFilter(ctx, appl, *ctx.Payload.Data, &f); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if err := appl.SavedFilters().Create(ctx, &f); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.SavedFilterSingle{
+			Data: ConvertSavedFilter(ctx.RequestData, f),
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.SavedFilterHref(f.ID)))
+		return ctx.Created(res)
+	})
+}
+
+// Update runs the update action.
+func (c *SavedFilterController) Update(ctx *app.UpdateSavedFilterContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		f, err := loadVisibleSavedFilter(ctx, appl, ctx.FilterID, identityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if *identityID != f.OwnerID {
+			// need to use the goa.NewErrorClass() func as there is no native support for 403 in goa
+			return jsonapi.JSONErrorResponse(ctx, goa.NewErrorClass("forbidden", 403)("User is not the filter owner"))
+		}
+		f.Version = *ctx.Payload.Data.Attributes.Version
+		if err := ConvertJSONAPIToSavedFilter(ctx, appl, *ctx.Payload.Data, f); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		f, err = appl.SavedFilters().Save(ctx, *f)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.SavedFilterSingle{
+			Data: ConvertSavedFilter(ctx.RequestData, *f),
+		})
+	})
+}
+
+// Delete runs the delete action.
+func (c *SavedFilterController) Delete(ctx *app.DeleteSavedFilterContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		f, err := loadVisibleSavedFilter(ctx, appl, ctx.FilterID, identityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if *identityID != f.OwnerID {
+			// need to use the goa.NewErrorClass() func as there is no native support for 403 in goa
+			return jsonapi.JSONErrorResponse(ctx, goa.NewErrorClass("forbidden", 403)("User is not the filter owner"))
+		}
+		if err := appl.SavedFilters().Delete(ctx, f.ID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK([]byte{})
+	})
+}
+
+// loadVisibleSavedFilter loads the saved filter with the given ID. Filters that are not
+// visible to the given identity are reported as not found.
+func loadVisibleSavedFilter(ctx context.Context, appl application.Application, id uuid.UUID, identityID *uuid.UUID) (*filter.SavedFilter, error) {
+	f, err := appl.SavedFilters().Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var sharedWith *space.Space
+	if identityID != nil && *identityID != f.OwnerID && f.SpaceID != nil {
+		sharedWith, err = appl.Spaces().Load(ctx, *f.SpaceID)
+		if err != nil {
+			if _, ok := errs.Cause(err).(errors.NotFoundError); !ok {
+				return nil, err
+			}
+		}
+	}
+	if !f.VisibleTo(identityID, sharedWith) {
+		return nil, errors.NewNotFoundError("saved filter", id.String())
+	}
+	return f, nil
+}
+
+// ConvertJSONAPIToSavedFilter copies the attributes and the space relationship of the
+// given JSONAPI saved filter to the target. Attributes that are not set are left as is.
+// Filters can only be shared with spaces that are owned by the owner of the filter.
+func ConvertJSONAPIToSavedFilter(ctx context.Context, appl application.Application, source app.SavedFilter, target *filter.SavedFilter) error {
+	if source.Attributes.Title != nil {
+		target.Title = *source.Attributes.Title
+	}
+	if source.Attributes.Description != nil {
+		target.Description = *source.Attributes.Description
+	}
+	if source.Attributes.Query != nil {
+		target.Query = *source.Attributes.Query
+	}
+	if source.Attributes.Sort != nil {
+		target.Sort = *source.Attributes.Sort
+	}
+	if source.Relationships != nil && source.Relationships.Space != nil {
+		if source.Relationships.Space.Data == nil || source.Relationships.Space.Data.ID == nil {
+			// an empty space relationship makes the filter private
+			target.SpaceID = nil
+			return nil
+		}
+		spaceID, err := uuid.FromString(*source.Relationships.Space.Data.ID)
+		if err != nil {
+			return errors.NewBadParameterError("data.relationships.space.data.id", *source.Relationships.Space.Data.ID)
+		}
+		s, err := appl.Spaces().Load(ctx, spaceID)
+		if err != nil {
+			return err
+		}
+		if s.OwnerId != target.OwnerID {
+			// need to use the goa.NewErrorClass() func as there is no native support for 403 in goa
+			return goa.NewErrorClass("forbidden", 403)("User is not the owner of the space the filter is shared with")
+		}
+		target.SpaceID = &spaceID
+	}
+	return nil
+}
+
+// ConvertSavedFilter converts between internal and external REST representation
+func ConvertSavedFilter(request *goa.RequestData, f filter.SavedFilter) *app.SavedFilter {
+	selfURL := rest.AbsoluteURL(request, app.SavedFilterHref(f.ID))
+	ownerType := APIStringTypeUser
+	ownerID := f.OwnerID.String()
+	ownerURL := rest.AbsoluteURL(request, app.UsersHref(ownerID))
+	result := &app.SavedFilter{
+		Type: filter.APIStringTypeSavedFilter,
+		ID:   &f.ID,
+		Attributes: &app.SavedFilterAttributes{
+			Title:       &f.Title,
+			Description: &f.Description,
+			Query:       &f.Query,
+			Sort:        &f.Sort,
+			CreatedAt:   &f.CreatedAt,
+			Version:     &f.Version,
+		},
+		Relationships: &app.SavedFilterRelationships{
+			Owner: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &ownerType,
+					ID:   &ownerID,
+				},
+				Links: &app.GenericLinks{
+					Related: &ownerURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if f.SpaceID != nil {
+		spaceType := space.SpaceType
+		spaceID := f.SpaceID.String()
+		spaceURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+		result.Relationships.Space = &app.RelationGeneric{
+			Data: &app.GenericData{
+				Type: &spaceType,
+				ID:   &spaceID,
+			},
+			Links: &app.GenericLinks{
+				Related: &spaceURL,
+			},
+		}
+	}
+	return result
+}
@@ -0,0 +1,317 @@
+package controller_test
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/app/test"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/filter"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRunSavedFilterTests(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &savedFilterBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+type savedFilterBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	db            *gormapplication.GormDB
+	clean         func()
+	testIdentity  account.Identity
+	testIdentity2 account.Identity
+}
+
+func (s *savedFilterBlackBoxTest) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	s.DBTestSuite.PopulateDBTestSuite(migration.NewMigrationContext(context.Background()))
+	s.db = gormapplication.NewGormDB(s.DB)
+}
+
+func (s *savedFilterBlackBoxTest) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	testIdentity, err := testsupport.CreateTestIdentity(s.DB, "SavedFilterBlackBoxTest user", "test provider")
+	require.Nil(s.T(), err)
+	s.testIdentity = testIdentity
+	testIdentity2, err := testsupport.CreateTestIdentity(s.DB, "SavedFilterBlackBoxTest user2", "test provider")
+	require.Nil(s.T(), err)
+	s.testIdentity2 = testIdentity2
+}
+
+func (s *savedFilterBlackBoxTest) TearDownTest() {
+	s.clean()
+}
+
+func (s *savedFilterBlackBoxTest) securedControllers(identity account.Identity) (*goa.Service, *SavedFilterController, *FilterController, *WorkitemController) {
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	svc := testsupport.ServiceAsUser("SavedFilter-Service", almtoken.NewManagerWithPrivateKey(priv), identity)
+	return svc, NewSavedFilterController(svc, s.db), NewFilterController(svc, s.db), NewWorkitemController(svc, s.db, s.Configuration)
+}
+
+func (s *savedFilterBlackBoxTest) unsecuredControllers() (*goa.Service, *SavedFilterController, *WorkitemController) {
+	svc := goa.New("SavedFilter-Service-test")
+	return svc, NewSavedFilterController(svc, s.db), NewWorkitemController(svc, s.db, s.Configuration)
+}
+
+// createSpace creates a space owned by the given identity
+func (s *savedFilterBlackBoxTest) createSpace(owner account.Identity) uuid.UUID {
+	sp, err := space.NewRepository(s.DB).Create(context.Background(), &space.Space{
+		Name:    "SavedFilterBlackBoxTest space " + uuid.NewV4().String(),
+		OwnerId: owner.ID,
+	})
+	require.Nil(s.T(), err)
+	return sp.ID
+}
+
+// createSharedFilter stores a filter of the given owner that is shared with the given space.
+// Such filters cannot be created through the API unless the filter owner also owns the space,
+// because the space owner is the only member of a space for now.
+func (s *savedFilterBlackBoxTest) createSharedFilter(owner account.Identity, spaceID uuid.UUID, title string) filter.SavedFilter {
+	f := filter.SavedFilter{
+		Title:   title,
+		Query:   `system.state = "open"`,
+		OwnerID: owner.ID,
+		SpaceID: &spaceID,
+	}
+	err := filter.NewSavedFilterRepository(s.DB).Create(context.Background(), &f)
+	require.Nil(s.T(), err)
+	return f
+}
+
+func newCreateSavedFilterPayload(title, query string, spaceID *uuid.UUID) *app.CreateSavedFilterPayload {
+	payload := app.CreateSavedFilterPayload{
+		Data: &app.SavedFilter{
+			Type: filter.APIStringTypeSavedFilter,
+			Attributes: &app.SavedFilterAttributes{
+				Title: &title,
+				Query: &query,
+			},
+		},
+	}
+	if spaceID != nil {
+		id := spaceID.String()
+		spaceType := space.SpaceType
+		payload.Data.Relationships = &app.SavedFilterRelationships{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &spaceType,
+					ID:   &id,
+				},
+			},
+		}
+	}
+	return &payload
+}
+
+func (s *savedFilterBlackBoxTest) TestCreateShowUpdateDelete() {
+	// given
+	svc, ctrl, _, _ := s.securedControllers(s.testIdentity)
+	// when
+	_, created := test.CreateSavedFilterCreated(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("open bugs", `system.state = "open"`, nil))
+	// then
+	require.NotNil(s.T(), created.Data.ID)
+	assert.Equal(s.T(), "open bugs", *created.Data.Attributes.Title)
+	assert.Equal(s.T(), s.testIdentity.ID.String(), *created.Data.Relationships.Owner.Data.ID)
+	assert.Nil(s.T(), created.Data.Relationships.Space)
+	filterID := *created.Data.ID
+	// when
+	_, shown := test.ShowSavedFilterOK(s.T(), svc.Context, svc, ctrl, filterID)
+	// then
+	assert.Equal(s.T(), *created.Data.Attributes.Query, *shown.Data.Attributes.Query)
+	// when
+	title := "open bugs of mine"
+	sort := "-system.updated_at"
+	payload := app.UpdateSavedFilterPayload{
+		Data: &app.SavedFilter{
+			Type: filter.APIStringTypeSavedFilter,
+			ID:   &filterID,
+			Attributes: &app.SavedFilterAttributes{
+				Title:   &title,
+				Sort:    &sort,
+				Version: shown.Data.Attributes.Version,
+			},
+		},
+	}
+	_, updated := test.UpdateSavedFilterOK(s.T(), svc.Context, svc, ctrl, filterID, &payload)
+	// then
+	assert.Equal(s.T(), title, *updated.Data.Attributes.Title)
+	assert.Equal(s.T(), sort, *updated.Data.Attributes.Sort)
+	assert.Equal(s.T(), *shown.Data.Attributes.Version+1, *updated.Data.Attributes.Version)
+	// when updating with the outdated version
+	test.UpdateSavedFilterBadRequest(s.T(), svc.Context, svc, ctrl, filterID, &payload)
+	// when
+	test.DeleteSavedFilterOK(s.T(), svc.Context, svc, ctrl, filterID)
+	// then
+	test.ShowSavedFilterNotFound(s.T(), svc.Context, svc, ctrl, filterID)
+}
+
+func (s *savedFilterBlackBoxTest) TestCreateInvalidQuery() {
+	svc, ctrl, _, _ := s.securedControllers(s.testIdentity)
+	test.CreateSavedFilterBadRequest(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("broken", `system.state = `, nil))
+}
+
+func (s *savedFilterBlackBoxTest) TestShareWithSpace() {
+	// given
+	svc, ctrl, _, _ := s.securedControllers(s.testIdentity)
+	ownSpaceID := s.createSpace(s.testIdentity)
+	otherSpaceID := s.createSpace(s.testIdentity2)
+	// when sharing with a space of the filter owner
+	_, shared := test.CreateSavedFilterCreated(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("shared", `system.state = "open"`, &ownSpaceID))
+	// then
+	require.NotNil(s.T(), shared.Data.Relationships.Space)
+	assert.Equal(s.T(), ownSpaceID.String(), *shared.Data.Relationships.Space.Data.ID)
+	// when sharing with a space of another user
+	test.CreateSavedFilterForbidden(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("shared", `system.state = "open"`, &otherSpaceID))
+	_, private := test.CreateSavedFilterCreated(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("private", `system.state = "open"`, nil))
+	update := newCreateSavedFilterPayload("private", `system.state = "open"`, &otherSpaceID)
+	update.Data.ID = private.Data.ID
+	update.Data.Attributes.Version = private.Data.Attributes.Version
+	payload := app.UpdateSavedFilterPayload{Data: update.Data}
+	test.UpdateSavedFilterForbidden(s.T(), svc.Context, svc, ctrl, *private.Data.ID, &payload)
+	// then the filter is still private
+	_, shown := test.ShowSavedFilterOK(s.T(), svc.Context, svc, ctrl, *private.Data.ID)
+	assert.Nil(s.T(), shown.Data.Relationships.Space)
+}
+
+func (s *savedFilterBlackBoxTest) TestVisibility() {
+	// given a private filter of the first user and one shared with the space of the second user
+	svc, ctrl, _, _ := s.securedControllers(s.testIdentity)
+	spaceID := s.createSpace(s.testIdentity2)
+	_, private := test.CreateSavedFilterCreated(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("private", `system.state = "open"`, nil))
+	shared := s.createSharedFilter(s.testIdentity, spaceID, "shared")
+	otherSvc, otherCtrl, _, _ := s.securedControllers(s.testIdentity2)
+	anonymousSvc, anonymousCtrl, _ := s.unsecuredControllers()
+	outsider, err := testsupport.CreateTestIdentity(s.DB, "SavedFilterBlackBoxTest outsider", "test provider")
+	require.Nil(s.T(), err)
+	outsiderSvc, outsiderCtrl, _, _ := s.securedControllers(outsider)
+	// then private filters are hidden from other users
+	test.ShowSavedFilterNotFound(s.T(), otherSvc.Context, otherSvc, otherCtrl, *private.Data.ID)
+	test.ShowSavedFilterNotFound(s.T(), anonymousSvc.Context, anonymousSvc, anonymousCtrl, *private.Data.ID)
+	test.DeleteSavedFilterNotFound(s.T(), otherSvc.Context, otherSvc, otherCtrl, *private.Data.ID)
+	// then shared filters are hidden from anonymous users and users outside of the space
+	test.ShowSavedFilterNotFound(s.T(), anonymousSvc.Context, anonymousSvc, anonymousCtrl, shared.ID)
+	test.ShowSavedFilterNotFound(s.T(), outsiderSvc.Context, outsiderSvc, outsiderCtrl, shared.ID)
+	// then shared filters can be read but not changed by the members of the space
+	test.ShowSavedFilterOK(s.T(), otherSvc.Context, otherSvc, otherCtrl, shared.ID)
+	title := "taken over"
+	payload := app.UpdateSavedFilterPayload{
+		Data: &app.SavedFilter{
+			Type: filter.APIStringTypeSavedFilter,
+			ID:   &shared.ID,
+			Attributes: &app.SavedFilterAttributes{
+				Title:   &title,
+				Version: &shared.Version,
+			},
+		},
+	}
+	test.UpdateSavedFilterForbidden(s.T(), otherSvc.Context, otherSvc, otherCtrl, shared.ID, &payload)
+	test.DeleteSavedFilterForbidden(s.T(), otherSvc.Context, otherSvc, otherCtrl, shared.ID)
+}
+
+func (s *savedFilterBlackBoxTest) TestFilterListIncludesSavedFilters() {
+	// given
+	svc, ctrl, filterCtrl, _ := s.securedControllers(s.testIdentity)
+	spaceID := s.createSpace(s.testIdentity2)
+	_, private := test.CreateSavedFilterCreated(s.T(), svc.Context, svc, ctrl, newCreateSavedFilterPayload("private", `system.state = "open"`, nil))
+	shared := s.createSharedFilter(s.testIdentity, spaceID, "shared")
+	savedIDs := func(list *app.FilterList) []uuid.UUID {
+		result := []uuid.UUID{}
+		for _, f := range list.Data {
+			if f.ID != nil {
+				assert.Equal(s.T(), "filter[saved]="+f.ID.String(), f.Attributes.Query)
+				result = append(result, *f.ID)
+			}
+		}
+		return result
+	}
+	// when listing as the owner with the space
+	_, list := test.ListFilterOK(s.T(), svc.Context, svc, filterCtrl, nil, &spaceID)
+	// then the built-in filters come first
+	require.True(s.T(), len(list.Data) > 2)
+	assert.Nil(s.T(), list.Data[0].ID)
+	assert.Equal(s.T(), []uuid.UUID{*private.Data.ID, shared.ID}, savedIDs(list))
+	// when listing as a member of the space
+	otherSvc, _, otherFilterCtrl, _ := s.securedControllers(s.testIdentity2)
+	_, list = test.ListFilterOK(s.T(), otherSvc.Context, otherSvc, otherFilterCtrl, nil, &spaceID)
+	// then
+	assert.Equal(s.T(), []uuid.UUID{shared.ID}, savedIDs(list))
+	// when listing as a member without space
+	_, list = test.ListFilterOK(s.T(), otherSvc.Context, otherSvc, otherFilterCtrl, nil, nil)
+	// then
+	assert.Empty(s.T(), savedIDs(list))
+	// when listing as a user outside of the space
+	outsider, err := testsupport.CreateTestIdentity(s.DB, "SavedFilterBlackBoxTest outsider", "test provider")
+	require.Nil(s.T(), err)
+	outsiderSvc, _, outsiderFilterCtrl, _ := s.securedControllers(outsider)
+	_, list = test.ListFilterOK(s.T(), outsiderSvc.Context, outsiderSvc, outsiderFilterCtrl, nil, &spaceID)
+	// then
+	assert.Empty(s.T(), savedIDs(list))
+	// when listing anonymously
+	anonymousSvc := goa.New("SavedFilter-Service-test")
+	_, list = test.ListFilterOK(s.T(), anonymousSvc.Context, anonymousSvc, NewFilterController(anonymousSvc, s.db), nil, &spaceID)
+	// then
+	assert.Empty(s.T(), savedIDs(list))
+}
+
+func (s *savedFilterBlackBoxTest) TestListWorkItemsWithSavedFilter() {
+	// given
+	svc, ctrl, _, workitemCtrl := s.securedControllers(s.testIdentity)
+	title := "TestListWorkItemsWithSavedFilter " + uuid.NewV4().String()
+	var matching []string
+	for i := 0; i < 3; i++ {
+		state := workitem.SystemStateOpen
+		if i == 2 {
+			state = workitem.SystemStateClosed
+		}
+		wi, err := workitem.NewWorkItemRepository(s.DB).Create(context.Background(), space.SystemSpace, workitem.SystemBug, map[string]interface{}{
+			workitem.SystemTitle: fmt.Sprintf("%s %d", title, i),
+			workitem.SystemState: state,
+		}, s.testIdentity.ID)
+		require.Nil(s.T(), err)
+		if state == workitem.SystemStateOpen {
+			matching = append(matching, wi.ID.String())
+		}
+	}
+	query := fmt.Sprintf(`system.state = "%s" AND system.creator = "%s"`, workitem.SystemStateOpen, s.testIdentity.ID.String())
+	payload := newCreateSavedFilterPayload("open items", query, nil)
+	sort := "system.title"
+	payload.Data.Attributes.Sort = &sort
+	_, saved := test.CreateSavedFilterCreated(s.T(), svc.Context, svc, ctrl, payload)
+	// when
+	_, result := test.ListWorkitemOK(s.T(), svc.Context, svc, workitemCtrl, space.SystemSpace.String(), nil, nil, nil, nil, nil, saved.Data.ID, nil, nil, nil, nil, nil, nil, nil, nil)
+	// then the saved query and order apply
+	var ids []string
+	for _, wi := range result.Data {
+		ids = append(ids, *wi.ID)
+	}
+	assert.Equal(s.T(), matching, ids)
+	assert.Contains(s.T(), *result.Links.First, "filter[saved]="+saved.Data.ID.String())
+	// when an explicit order is given
+	descending := "-system.title"
+	_, result = test.ListWorkitemOK(s.T(), svc.Context, svc, workitemCtrl, space.SystemSpace.String(), nil, nil, nil, nil, nil, saved.Data.ID, nil, nil, nil, nil, nil, &descending, nil, nil)
+	// then it takes precedence over the saved order
+	require.Len(s.T(), result.Data, 2)
+	assert.Equal(s.T(), matching[1], *result.Data[0].ID)
+	// when the private filter is used by somebody else
+	anonymousSvc, _, anonymousWorkitemCtrl := s.unsecuredControllers()
+	test.ListWorkitemNotFound(s.T(), anonymousSvc.Context, anonymousSvc, anonymousWorkitemCtrl, space.SystemSpace.String(), nil, nil, nil, nil, nil, saved.Data.ID, nil, nil, nil, nil, nil, nil, nil, nil)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/search"
 	"github.com/almighty/almighty-core/space"
+	"github.com/almighty/almighty-core/workitem"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
 )
@@ -50,9 +51,19 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	urlRegexString = fmt.Sprintf("(?P<domain>%s)(?P<path>/work-item/board/detail/)(?P<id>\\d*)", hostString)
 	search.RegisterAsKnownURL(search.HostRegistrationKeyForBoardWI, urlRegexString)
 
+	identity, err := optionalIdentity(ctx, ctx.RequestData)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	scope := search.Scope{SpaceID: ctx.FilterSpace, Identity: identity}
+	var additionalQuery []string
+	if ctx.FilterSpace != nil {
+		additionalQuery = append(additionalQuery, "filter[space]="+ctx.FilterSpace.String())
+	}
+
 	return application.Transactional(c.db, func(appl application.Application) error {
 		//return transaction.Do(c.ts, func() error {
-		result, c, err := appl.SearchItems().SearchFullText(ctx.Context, ctx.Q, &offset, &limit)
+		result, c, err := appl.SearchItems().SearchFullText(ctx.Context, ctx.Q, scope, &offset, &limit)
 		count := int(c)
 		if err != nil {
 			cause := errs.Cause(err)
@@ -60,6 +71,8 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 			case errors.BadParameterError:
 				jerrors, _ := jsonapi.ErrorToJSONAPIErrors(goa.ErrBadRequest(fmt.Sprintf("Error listing work items: %s", err.Error())))
 				return ctx.BadRequest(jerrors)
+			case errors.UnauthorizedError:
+				return jsonapi.JSONErrorResponse(ctx, err)
 			default:
 				log.Error(ctx, map[string]interface{}{
 					"err": err,
@@ -69,13 +82,31 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 			}
 		}
 
+		facets, err := appl.SearchItems().SearchFacets(ctx.Context, ctx.Q, scope)
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err": err,
+			}, "unable to count the work items")
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+
+		workitems := make([]workitem.WorkItem, len(result))
+		matches := make(map[string]*app.SearchMatch, len(result))
+		for i, r := range result {
+			workitems[i] = r.WorkItem
+			match := &app.SearchMatch{Relevance: r.Relevance, Highlights: r.Highlights}
+			for _, cm := range r.Comments {
+				match.Comments = append(match.Comments, &app.SearchCommentMatch{ID: cm.ID, Snippet: cm.Snippet})
+			}
+			matches[r.ID] = match
+		}
 		response := app.SearchWorkItemList{
 			Links: &app.PagingLinks{},
-			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-			Data:  ConvertWorkItems(ctx.RequestData, result),
+			Meta:  &app.SearchWorkItemListMeta{TotalCount: count, Matches: matches, Facets: facets},
+			Data:  ConvertWorkItems(ctx.RequestData, workitems),
 		}
 
-		setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(result), offset, limit, count, "q="+ctx.Q)
+		setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(result), offset, limit, count, append(additionalQuery, "q="+ctx.Q)...)
 		return ctx.OK(&response)
 	})
 }
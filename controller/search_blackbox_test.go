@@ -11,6 +11,7 @@ import (
 	"github.com/almighty/almighty-core/account"
 	"github.com/almighty/almighty-core/app"
 	"github.com/almighty/almighty-core/app/test"
+	"github.com/almighty/almighty-core/comment"
 	config "github.com/almighty/almighty-core/configuration"
 	. "github.com/almighty/almighty-core/controller"
 	"github.com/almighty/almighty-core/gormapplication"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/goatest"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -95,7 +97,7 @@ func (s *searchBlackBoxTest) TestSearchWorkItems() {
 	require.Nil(s.T(), err)
 	// when
 	q := "specialwordforsearch"
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -118,7 +120,7 @@ func (s *searchBlackBoxTest) TestSearchPagination() {
 	require.Nil(s.T(), err)
 	// when
 	q := "specialwordforsearch2"
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	// defaults in paging.go is 'pageSizeDefault = 20'
 	assert.Equal(s.T(), "http:///api/search?page[offset]=0&page[limit]=20&q=specialwordforsearch2", *sr.Links.First)
@@ -143,7 +145,7 @@ func (s *searchBlackBoxTest) TestSearchWithEmptyValue() {
 	require.Nil(s.T(), err)
 	// when
 	q := ""
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	require.NotNil(s.T(), sr.Data)
 	assert.Empty(s.T(), sr.Data)
@@ -165,7 +167,7 @@ func (s *searchBlackBoxTest) TestSearchWithDomainPortCombination() {
 	require.Nil(s.T(), err)
 	// when
 	q := `"http://localhost:8080/detail/154687364529310"`
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -189,7 +191,7 @@ func (s *searchBlackBoxTest) TestSearchURLWithoutPort() {
 	require.Nil(s.T(), err)
 	// when
 	q := `"http://localhost/detail/876394"`
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -213,7 +215,7 @@ func (s *searchBlackBoxTest) TestUnregisteredURLWithPort() {
 	require.Nil(s.T(), err)
 	// when
 	q := `http://some-other-domain:8080/different-path/`
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	require.NotEmpty(s.T(), sr.Data)
 	r := sr.Data[0]
@@ -238,12 +240,149 @@ func (s *searchBlackBoxTest) TestUnwantedCharactersRelatedToSearchLogic() {
 	// when
 	// add url: in the query, that is not expected by the code hence need to make sure it gives expected result.
 	q := `http://url:some-random-other-domain:8080/different-path/`
-	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, q)
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, q)
 	// then
 	require.NotNil(s.T(), sr.Data)
 	assert.Empty(s.T(), sr.Data)
 }
 
+func (s *searchBlackBoxTest) TestSearchRankedWithHighlights() {
+	// given
+	inDescription, err := s.wiRepo.Create(
+		s.ctx,
+		space.SystemSpace,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle:       "some title",
+			workitem.SystemDescription: rendering.NewMarkupContentFromLegacy("mentions rankedsearchterm once"),
+			workitem.SystemState:       workitem.SystemStateNew,
+		},
+		s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	inTitle, err := s.wiRepo.Create(
+		s.ctx,
+		space.SystemSpace,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "RankedSearchTerm in the title",
+			workitem.SystemState: workitem.SystemStateNew,
+		},
+		s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, "rankedsearchterm")
+	// then matches in the title are more relevant than matches in the description
+	require.Len(s.T(), sr.Data, 2)
+	assert.Equal(s.T(), inTitle.ID, *sr.Data[0].ID)
+	assert.Equal(s.T(), inDescription.ID, *sr.Data[1].ID)
+	require.NotNil(s.T(), sr.Meta.Matches[inTitle.ID])
+	require.NotNil(s.T(), sr.Meta.Matches[inDescription.ID])
+	assert.True(s.T(), sr.Meta.Matches[inTitle.ID].Relevance > sr.Meta.Matches[inDescription.ID].Relevance)
+	assert.Equal(s.T(), map[string]string{
+		workitem.SystemTitle: "<b>RankedSearchTerm</b> in the title",
+	}, sr.Meta.Matches[inTitle.ID].Highlights)
+	highlights := sr.Meta.Matches[inDescription.ID].Highlights
+	assert.NotContains(s.T(), highlights, workitem.SystemTitle)
+	assert.Contains(s.T(), highlights[workitem.SystemDescription], "<b>rankedsearchterm</b>")
+}
+
+func (s *searchBlackBoxTest) TestSearchFacets() {
+	// given
+	for _, state := range []string{workitem.SystemStateNew, workitem.SystemStateNew, workitem.SystemStateOpen} {
+		_, err := s.wiRepo.Create(
+			s.ctx,
+			space.SystemSpace,
+			workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: "facetedsearchterm",
+				workitem.SystemState: state,
+			},
+			s.testIdentity.ID)
+		require.Nil(s.T(), err)
+	}
+	// when only the first page is requested
+	pageLimit := 1
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, &pageLimit, nil, "facetedsearchterm")
+	// then the facets count all matching work items
+	require.Len(s.T(), sr.Data, 1)
+	assert.Equal(s.T(), map[string]int{workitem.SystemStateNew: 2, workitem.SystemStateOpen: 1}, sr.Meta.Facets[search.FacetState])
+	assert.Equal(s.T(), map[string]int{workitem.SystemBug.String(): 3}, sr.Meta.Facets[search.FacetWorkItemType])
+}
+
+func (s *searchBlackBoxTest) TestSearchQualifiers() {
+	// given
+	assigned, err := s.wiRepo.Create(
+		s.ctx,
+		space.SystemSpace,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle:     "qualifiedsearchterm assigned",
+			workitem.SystemState:     workitem.SystemStateOpen,
+			workitem.SystemAssignees: []string{s.testIdentity.ID.String()},
+		},
+		s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	unassigned, err := s.wiRepo.Create(
+		s.ctx,
+		space.SystemSpace,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "qualifiedsearchterm unassigned",
+			workitem.SystemState: workitem.SystemStateClosed,
+		},
+		s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	searchIDs := func(q string, spaceID *uuid.UUID) []string {
+		_, sr := test.ShowSearchOK(s.T(), s.svc.Context, s.svc, s.controller, spaceID, nil, nil, q)
+		var ids []string
+		for _, wi := range sr.Data {
+			ids = append(ids, *wi.ID)
+		}
+		return ids
+	}
+	otherSpace := uuid.NewV4()
+	// then
+	assert.Equal(s.T(), []string{assigned.ID}, searchIDs("qualifiedsearchterm assignee:me", nil))
+	assert.Equal(s.T(), []string{unassigned.ID}, searchIDs("qualifiedsearchterm -assignee:me", nil))
+	assert.Equal(s.T(), []string{unassigned.ID}, searchIDs("qualifiedsearchterm state:closed", nil))
+	assert.Equal(s.T(), []string{assigned.ID}, searchIDs("qualifiedsearchterm -state:closed", nil))
+	assert.Equal(s.T(), []string{unassigned.ID}, searchIDs("qualifiedsearchterm -assigned", nil))
+	assert.Equal(s.T(), []string{assigned.ID}, searchIDs(fmt.Sprintf(`assignee:"%s" state:open qualifiedsearchterm`, s.testIdentity.Username), nil))
+	assert.Len(s.T(), searchIDs("qualifiedsearchterm", &space.SystemSpace), 2)
+	assert.Empty(s.T(), searchIDs("qualifiedsearchterm", &otherSpace))
+	assert.Empty(s.T(), searchIDs("qualifiedsearchterm space:"+otherSpace.String(), nil))
+}
+
+func (s *searchBlackBoxTest) TestSearchQualifiersAnonymous() {
+	// when the current user is referenced without a token
+	test.ShowSearchUnauthorized(s.T(), nil, nil, s.controller, nil, nil, nil, "assignee:me")
+}
+
+func (s *searchBlackBoxTest) TestSearchComments() {
+	// given
+	wi, err := s.wiRepo.Create(
+		s.ctx,
+		space.SystemSpace,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "some title",
+			workitem.SystemState: workitem.SystemStateNew,
+		},
+		s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	c := &comment.Comment{ParentID: wi.ID, Body: "try clearing the commentedsearchterm cache"}
+	require.Nil(s.T(), comment.NewRepository(s.DB).Create(s.ctx, c, s.testIdentity.ID))
+	// when
+	_, sr := test.ShowSearchOK(s.T(), nil, nil, s.controller, nil, nil, nil, "commentedsearchterm")
+	// then the work item is returned with the matching comment
+	require.Len(s.T(), sr.Data, 1)
+	assert.Equal(s.T(), wi.ID, *sr.Data[0].ID)
+	require.NotNil(s.T(), sr.Meta.Matches[wi.ID])
+	require.Len(s.T(), sr.Meta.Matches[wi.ID].Comments, 1)
+	assert.Equal(s.T(), c.ID, sr.Meta.Matches[wi.ID].Comments[0].ID)
+	assert.Contains(s.T(), sr.Meta.Matches[wi.ID].Comments[0].Snippet, "<b>commentedsearchterm</b>")
+}
+
 func (s *searchBlackBoxTest) getWICreatePayload() *app.CreateWorkitemPayload {
 	spaceSelfURL := rest.AbsoluteURL(&goa.RequestData{
 		Request: &http.Request{Host: "api.service.domain.org"},
@@ -88,6 +88,9 @@ func (c *SpaceController) Create(ctx *app.CreateSpaceContext) error {
 		if reqSpace.Attributes.Description != nil {
 			newSpace.Description = *reqSpace.Attributes.Description
 		}
+		if reqSpace.Attributes.KeyPrefix != nil {
+			newSpace.KeyPrefix = *reqSpace.Attributes.KeyPrefix
+		}
 
 		rSpace, err := appl.Spaces().Create(ctx, &newSpace)
 		if err != nil {
@@ -265,6 +268,9 @@ func (c *SpaceController) Update(ctx *app.UpdateSpaceContext) error {
 		if ctx.Payload.Data.Attributes.Description != nil {
 			s.Description = *ctx.Payload.Data.Attributes.Description
 		}
+		if ctx.Payload.Data.Attributes.KeyPrefix != nil {
+			s.KeyPrefix = *ctx.Payload.Data.Attributes.KeyPrefix
+		}
 
 		s, err = appl.Spaces().Save(ctx.Context, s)
 		if err != nil {
@@ -335,6 +341,9 @@ func ConvertSpaceToModel(appSpace app.Space) space.Space {
 		if appSpace.Attributes.Description != nil {
 			modelSpace.Description = *appSpace.Attributes.Description
 		}
+		if appSpace.Attributes.KeyPrefix != nil {
+			modelSpace.KeyPrefix = *appSpace.Attributes.KeyPrefix
+		}
 	}
 	if appSpace.Relationships != nil && appSpace.Relationships.OwnedBy != nil &&
 		appSpace.Relationships.OwnedBy.Data != nil && appSpace.Relationships.OwnedBy.Data.ID != nil {
@@ -384,6 +393,7 @@ func ConvertSpaceFromModel(ctx context.Context, db application.DB, request *goa.
 		Attributes: &app.SpaceAttributes{
 			Name:        &p.Name,
 			Description: &p.Description,
+			KeyPrefix:   &p.KeyPrefix,
 			CreatedAt:   &p.CreatedAt,
 			UpdatedAt:   &p.UpdatedAt,
 			Version:     &p.Version,
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeDependencyAnalysis is the JSONAPI type of a dependency analysis
+const APIStringTypeDependencyAnalysis = "dependency-analyses"
+
+// SpaceDependenciesController implements the space_dependencies resource.
+type SpaceDependenciesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceDependenciesController creates a space_dependencies controller.
+func NewSpaceDependenciesController(service *goa.Service, db application.DB) *SpaceDependenciesController {
+	return &SpaceDependenciesController{Controller: service.NewController("SpaceDependenciesController"), db: db}
+}
+
+// Show runs the show action.
+func (c *SpaceDependenciesController) Show(ctx *app.ShowSpaceDependenciesContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		_, err := appl.Spaces().Load(ctx, spaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		blocked, err := appl.WorkItemLinks().ListBlocked(ctx, spaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error listing blocked work items"))
+		}
+		misplanned, err := appl.WorkItemLinks().ListMisplannedDependencies(ctx, spaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error listing misplanned dependencies"))
+		}
+		attributes := &app.DependencyAnalysisAttributes{
+			Blocked:    []*app.BlockedWorkItem{},
+			Misplanned: []*app.MisplannedDependency{},
+		}
+		for _, b := range blocked {
+			attributes.Blocked = append(attributes.Blocked, &app.BlockedWorkItem{
+				ID:       b.WorkItemID,
+				Blockers: b.BlockerIDs,
+			})
+		}
+		for _, m := range misplanned {
+			attributes.Misplanned = append(attributes.Misplanned, &app.MisplannedDependency{
+				Link:             m.LinkID,
+				Blocker:          m.BlockerID,
+				BlockerIteration: m.BlockerIterationID,
+				Blocked:          m.BlockedID,
+				BlockedIteration: m.BlockedIterationID,
+			})
+		}
+		if ctx.Root != nil {
+			rootID, err := resolveWorkItemID(ctx, appl, spaceID, *ctx.Root)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, err)
+			}
+			attributes.CriticalPath, err = appl.WorkItemLinks().CriticalPath(ctx, spaceID, rootID)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, err)
+			}
+		}
+		return ctx.OK(&app.DependencyAnalysisSingle{
+			Data: &app.DependencyAnalysis{
+				Type:       APIStringTypeDependencyAnalysis,
+				Attributes: attributes,
+			},
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app/test"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/link"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSpaceDependenciesREST struct {
+	gormtestsupport.DBTestSuite
+	db       *gormapplication.GormDB
+	clean    func()
+	identity account.Identity
+	ctx      context.Context
+	svc      *goa.Service
+	ctrl     *SpaceDependenciesController
+	spaceID  uuid.UUID
+	blocker  *workitem.WorkItem
+	blocked  *workitem.WorkItem
+}
+
+func TestRunSpaceDependenciesREST(t *testing.T) {
+	suite.Run(t, &TestSpaceDependenciesREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestSpaceDependenciesREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	var err error
+	rest.identity, err = testsupport.CreateTestIdentity(rest.DB, "TestSpaceDependenciesREST user", "test provider")
+	require.Nil(rest.T(), err)
+	req := &http.Request{Host: "localhost"}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, url.Values{})
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	rest.svc = testsupport.ServiceAsUser("SpaceDependencies-Service", almtoken.NewManagerWithPrivateKey(priv), rest.identity)
+	rest.ctrl = NewSpaceDependenciesController(rest.svc, rest.db)
+	testSpace, err := space.NewRepository(rest.DB).Create(rest.ctx, &space.Space{
+		Name: "TestSpaceDependenciesREST " + uuid.NewV4().String(),
+	})
+	require.Nil(rest.T(), err)
+	rest.spaceID = testSpace.ID
+	categoryName := "TestSpaceDependenciesREST " + uuid.NewV4().String()
+	category, err := link.NewWorkItemLinkCategoryRepository(rest.DB).Create(rest.ctx, &categoryName, nil)
+	require.Nil(rest.T(), err)
+	blocksType, err := link.NewWorkItemLinkTypeRepository(rest.DB).Create(rest.ctx, "blocks "+uuid.NewV4().String(), nil, workitem.SystemBug, workitem.SystemBug, "blocks", "is blocked by", link.TopologyDependency, category.ID, rest.spaceID)
+	require.Nil(rest.T(), err)
+	rest.blocker = rest.createWorkItem("Blocker")
+	rest.blocked = rest.createWorkItem("Blocked")
+	_, err = rest.db.WorkItemLinks().Create(rest.ctx, rest.parseID(rest.blocker.ID), rest.parseID(rest.blocked.ID), blocksType.ID, rest.identity.ID)
+	require.Nil(rest.T(), err)
+}
+
+func (rest *TestSpaceDependenciesREST) TearDownTest() {
+	rest.clean()
+}
+
+func (rest *TestSpaceDependenciesREST) createWorkItem(title string) *workitem.WorkItem {
+	wi, err := rest.db.WorkItems().Create(
+		rest.ctx,
+		rest.spaceID,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: title,
+			workitem.SystemState: workitem.SystemStateNew,
+		},
+		rest.identity.ID)
+	require.Nil(rest.T(), err)
+	return wi
+}
+
+func (rest *TestSpaceDependenciesREST) parseID(id string) uint64 {
+	result, err := strconv.ParseUint(id, 10, 64)
+	require.Nil(rest.T(), err)
+	return result
+}
+
+func (rest *TestSpaceDependenciesREST) TestShowDependencies() {
+	// when
+	_, analysis := test.ShowSpaceDependenciesOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), nil)
+	// then
+	require.NotNil(rest.T(), analysis.Data.Attributes)
+	require.Len(rest.T(), analysis.Data.Attributes.Blocked, 1)
+	assert.Equal(rest.T(), rest.blocked.ID, analysis.Data.Attributes.Blocked[0].ID)
+	assert.Equal(rest.T(), []string{rest.blocker.ID}, analysis.Data.Attributes.Blocked[0].Blockers)
+	assert.Empty(rest.T(), analysis.Data.Attributes.Misplanned)
+	assert.Nil(rest.T(), analysis.Data.Attributes.CriticalPath)
+}
+
+func (rest *TestSpaceDependenciesREST) TestShowCriticalPath() {
+	// when the root is given by its key
+	_, analysis := test.ShowSpaceDependenciesOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), &rest.blocked.Key)
+	// then
+	assert.Equal(rest.T(), []string{rest.blocker.ID, rest.blocked.ID}, analysis.Data.Attributes.CriticalPath)
+}
+
+func (rest *TestSpaceDependenciesREST) TestShowDependenciesNotFound() {
+	test.ShowSpaceDependenciesNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, uuid.NewV4().String(), nil)
+	root := "4242424242"
+	test.ShowSpaceDependenciesNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), &root)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/login"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// deletedAtAttribute is the attribute holding the deletion time of the work items in the trash
+const deletedAtAttribute = "system.deleted_at"
+
+// SpaceTrashController implements the space_trash resource.
+type SpaceTrashController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewSpaceTrashController creates a space_trash controller.
+func NewSpaceTrashController(service *goa.Service, db application.DB) *SpaceTrashController {
+	return &SpaceTrashController{Controller: service.NewController("SpaceTrashController"), db: db}
+}
+
+// List runs the list action.
+func (c *SpaceTrashController) List(ctx *app.ListSpaceTrashContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	offset, limit := computePagingLimts(ctx.PageOffset, ctx.PageLimit)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		_, err := appl.Spaces().Load(ctx, spaceID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		deleted, tc, err := appl.WorkItems().ListDeleted(ctx, spaceID, &offset, &limit)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error listing deleted work items"))
+		}
+		count := int(tc)
+		res := &app.WorkItemList{
+			Data:  []*app.WorkItem{},
+			Links: &app.PagingLinks{},
+			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+		}
+		for _, wi := range deleted {
+			res.Data = append(res.Data, ConvertWorkItem(ctx.RequestData, wi.WorkItem, workItemDeletedAt(wi)))
+		}
+		setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(deleted), offset, limit, count)
+		return ctx.OK(res)
+	})
+}
+
+// Restore runs the restore action.
+func (c *SpaceTrashController) Restore(ctx *app.RestoreSpaceTrashContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	// errors are returned from the transaction so that a conflicting link rolls back the whole restoration
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		deleted, err := appl.WorkItems().LoadDeleted(ctx, spaceID, ctx.WiID)
+		if err != nil {
+			return errs.Wrap(err, fmt.Sprintf("Fail to load deleted work item with id %v", ctx.WiID))
+		}
+		wi, err = appl.WorkItems().Restore(ctx, spaceID, deleted.ID, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrap(err, "Error restoring work item")
+		}
+		err = appl.WorkItemLinks().RestoreRelatedLinks(ctx, deleted.ID, deleted.DeletedAt, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to restore work item links related to work item %s", ctx.WiID)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	resp := &app.WorkItemSingle{
+		Data: ConvertWorkItem(ctx.RequestData, *wi),
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(resp)
+}
+
+// workItemDeletedAt adds the deletion time of the given deleted work item to its attributes
+func workItemDeletedAt(deleted workitem.DeletedWorkItem) WorkItemConvertFunc {
+	return func(request *goa.RequestData, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		wi2.Attributes[deletedAtAttribute] = deleted.DeletedAt
+	}
+}
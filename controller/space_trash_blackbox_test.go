@@ -0,0 +1,121 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app/test"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSpaceTrashREST struct {
+	gormtestsupport.DBTestSuite
+	db       *gormapplication.GormDB
+	clean    func()
+	identity account.Identity
+	ctx      context.Context
+	svc      *goa.Service
+	ctrl     *SpaceTrashController
+	spaceID  uuid.UUID
+	kept     *workitem.WorkItem
+	deleted  *workitem.WorkItem
+}
+
+func TestRunSpaceTrashREST(t *testing.T) {
+	suite.Run(t, &TestSpaceTrashREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestSpaceTrashREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	var err error
+	rest.identity, err = testsupport.CreateTestIdentity(rest.DB, "TestSpaceTrashREST user", "test provider")
+	require.Nil(rest.T(), err)
+	req := &http.Request{Host: "localhost"}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, url.Values{})
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	rest.svc = testsupport.ServiceAsUser("SpaceTrash-Service", almtoken.NewManagerWithPrivateKey(priv), rest.identity)
+	rest.ctrl = NewSpaceTrashController(rest.svc, rest.db)
+	testSpace, err := space.NewRepository(rest.DB).Create(rest.ctx, &space.Space{
+		Name: "TestSpaceTrashREST " + uuid.NewV4().String(),
+	})
+	require.Nil(rest.T(), err)
+	rest.spaceID = testSpace.ID
+	rest.kept = rest.createWorkItem("Kept")
+	rest.deleted = rest.createWorkItem("Deleted")
+	err = rest.db.WorkItems().Delete(rest.ctx, rest.spaceID, rest.deleted.ID, rest.identity.ID)
+	require.Nil(rest.T(), err)
+}
+
+func (rest *TestSpaceTrashREST) TearDownTest() {
+	rest.clean()
+}
+
+func (rest *TestSpaceTrashREST) createWorkItem(title string) *workitem.WorkItem {
+	wi, err := rest.db.WorkItems().Create(
+		rest.ctx,
+		rest.spaceID,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: title,
+			workitem.SystemState: workitem.SystemStateNew,
+		},
+		rest.identity.ID)
+	require.Nil(rest.T(), err)
+	return wi
+}
+
+func (rest *TestSpaceTrashREST) TestListTrash() {
+	// when
+	_, list := test.ListSpaceTrashOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), nil, nil)
+	// then
+	require.Len(rest.T(), list.Data, 1)
+	assert.Equal(rest.T(), 1, list.Meta.TotalCount)
+	assert.Equal(rest.T(), rest.deleted.ID, *list.Data[0].ID)
+	assert.Equal(rest.T(), "Deleted", list.Data[0].Attributes[workitem.SystemTitle])
+	assert.NotNil(rest.T(), list.Data[0].Attributes["system.deleted_at"])
+	test.ListSpaceTrashNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, uuid.NewV4().String(), nil, nil)
+}
+
+func (rest *TestSpaceTrashREST) TestRestoreWorkItem() {
+	// when
+	_, wi := test.RestoreSpaceTrashOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), rest.deleted.ID)
+	// then
+	assert.Equal(rest.T(), rest.deleted.ID, *wi.Data.ID)
+	assert.Equal(rest.T(), rest.deleted.Version+1, wi.Data.Attributes["version"])
+	_, list := test.ListSpaceTrashOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), nil, nil)
+	assert.Empty(rest.T(), list.Data)
+	_, err := rest.db.WorkItems().Load(rest.ctx, rest.spaceID, rest.deleted.ID)
+	require.Nil(rest.T(), err)
+}
+
+func (rest *TestSpaceTrashREST) TestRestoreWorkItemFailures() {
+	test.RestoreSpaceTrashConflict(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), rest.kept.ID)
+	test.RestoreSpaceTrashNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), "4242424242")
+	test.RestoreSpaceTrashNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, space.SystemSpace.String(), rest.deleted.ID)
+}
+
+func (rest *TestSpaceTrashREST) TestRestoreWorkItemUnauthorized() {
+	svc := goa.New("SpaceTrash-Service")
+	ctrl := NewSpaceTrashController(svc, rest.db)
+	test.RestoreSpaceTrashUnauthorized(rest.T(), svc.Context, svc, ctrl, rest.spaceID.String(), rest.deleted.ID)
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/almighty/almighty-core/application"
 	"github.com/almighty/almighty-core/jsonapi"
 	"github.com/almighty/almighty-core/log"
-	query "github.com/almighty/almighty-core/query/simple"
+	"github.com/almighty/almighty-core/query"
 	"github.com/almighty/almighty-core/remoteworkitem"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
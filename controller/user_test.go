@@ -14,12 +14,16 @@ import (
 	"github.com/almighty/almighty-core/codebase"
 	"github.com/almighty/almighty-core/comment"
 	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/filter"
 	"github.com/almighty/almighty-core/iteration"
+	"github.com/almighty/almighty-core/label"
 	"github.com/almighty/almighty-core/resource"
 	"github.com/almighty/almighty-core/space"
 	almtoken "github.com/almighty/almighty-core/token"
 	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
 	"github.com/almighty/almighty-core/workitem/link"
+	"github.com/almighty/almighty-core/workitem/template"
 	token "github.com/dgrijalva/jwt-go"
 	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/middleware/security/jwt"
@@ -261,6 +265,31 @@ func (g *GormTestBase) Codebases() codebase.Repository {
 	return nil
 }
 
+// WorkItemRevisions returns a work item revision repository
+func (g *GormTestBase) WorkItemRevisions() workitem.RevisionRepository {
+	return nil
+}
+
+// SavedFilters returns a saved filter repository
+func (g *GormTestBase) SavedFilters() filter.SavedFilterRepository {
+	return nil
+}
+
+// WorkItemTemplates returns a work item template repository
+func (g *GormTestBase) WorkItemTemplates() template.TemplateRepository {
+	return nil
+}
+
+// Attachments returns a work item attachment repository
+func (g *GormTestBase) Attachments() attachment.Repository {
+	return nil
+}
+
+// Labels returns a label repository
+func (g *GormTestBase) Labels() label.Repository {
+	return nil
+}
+
 func (g *GormTestBase) DB() *gorm.DB {
 	return nil
 }
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/log"
+	"github.com/almighty/almighty-core/login"
+	"github.com/almighty/almighty-core/rest"
+	"github.com/almighty/almighty-core/workitem/attachment"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// defaultAttachmentContentType is the media type of uploads without a Content-Type header
+const defaultAttachmentContentType = "application/octet-stream"
+
+// downloadableContentTypes are the media types that attachments are sent with, content of any other
+// type is sent as defaultAttachmentContentType so that browsers cannot be made to render it, e.g. as html
+var downloadableContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/gif":       true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"text/plain":      true,
+}
+
+// downloadContentType returns the media type that the content of the given attachment is sent with
+func downloadContentType(a attachment.Attachment) string {
+	mediaType, _, err := mime.ParseMediaType(a.ContentType)
+	if err != nil || !downloadableContentTypes[mediaType] {
+		return defaultAttachmentContentType
+	}
+	return a.ContentType
+}
+
+// WorkItemAttachmentsController implements the work_item_attachments resource.
+type WorkItemAttachmentsController struct {
+	*goa.Controller
+	db     application.DB
+	store  attachment.BlobStore
+	config WorkItemAttachmentsControllerConfig
+}
+
+// WorkItemAttachmentsControllerConfig the config interface for the WorkItemAttachmentsController
+type WorkItemAttachmentsControllerConfig interface {
+	GetAttachmentsMaxSize() int64
+}
+
+// NewWorkItemAttachmentsController creates a work_item_attachments controller keeping the content of
+// the attachments in the given blob store.
+func NewWorkItemAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore, config WorkItemAttachmentsControllerConfig) *WorkItemAttachmentsController {
+	return &WorkItemAttachmentsController{
+		Controller: service.NewController("WorkItemAttachmentsController"),
+		db:         db,
+		store:      store,
+		config:     config,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemAttachmentsController) List(ctx *app.ListWorkItemAttachmentsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wiID, err := loadAttachmentWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		attachments, err := appl.Attachments().List(ctx, wiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.WorkItemAttachmentList{
+			Data: []*app.WorkItemAttachment{},
+		}
+		for _, a := range attachments {
+			res.Data = append(res.Data, ConvertWorkItemAttachment(ctx.RequestData, spaceID, ctx.WiID, a))
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Show runs the show action.
+func (c *WorkItemAttachmentsController) Show(ctx *app.ShowWorkItemAttachmentsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		a, err := loadAttachment(ctx, appl, spaceID, ctx.WiID, ctx.AttachmentID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.WorkItemAttachmentSingle{
+			Data: ConvertWorkItemAttachment(ctx.RequestData, spaceID, ctx.WiID, *a),
+		})
+	})
+}
+
+// Download runs the download action, it responds with the content of the attachment.
+func (c *WorkItemAttachmentsController) Download(ctx *app.DownloadWorkItemAttachmentsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	var a *attachment.Attachment
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		a, err = loadAttachment(ctx, appl, spaceID, ctx.WiID, ctx.AttachmentID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	content, err := c.store.Get(ctx, a.ID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to read the content of attachment %s", a.ID))
+	}
+	defer content.Close()
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	header := ctx.ResponseData.Header()
+	header.Set("Content-Type", downloadContentType(*a))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Length", strconv.FormatInt(a.Size, 10))
+	header.Set("Content-Disposition", disposition)
+	header.Set("ETag", strconv.Quote(a.Checksum))
+	ctx.ResponseData.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(ctx.ResponseData, content); err != nil {
+		// the status is already sent, the client sees a truncated content
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": a.ID,
+			"err":           err,
+		}, "unable to send the content of the attachment")
+	}
+	return nil
+}
+
+// Create runs the create action. The content is stored outside of the transactions, before the
+// metadata, and it is removed again if the metadata cannot be stored.
+func (c *WorkItemAttachmentsController) Create(ctx *app.CreateWorkItemAttachmentsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	contentType := ctx.RequestData.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = defaultAttachmentContentType
+	}
+	a := attachment.Attachment{
+		ID:          uuid.NewV4(),
+		Name:        ctx.Name,
+		ContentType: contentType,
+		CreatedBy:   *currentUserIdentityID,
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		a.WorkItemID, err = loadAttachmentWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := attachment.Upload(ctx, c.store, &a, ctx.RequestData.Body, c.config.GetAttachmentsMaxSize()); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Attachments().Create(ctx, &a)
+	})
+	if err != nil {
+		c.deleteContent(ctx, a.ID)
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorkItemAttachmentSingle{
+		Data: ConvertWorkItemAttachment(ctx.RequestData, spaceID, ctx.WiID, a),
+	}
+	ctx.ResponseData.Header().Set("Location", *res.Data.Links.Self)
+	return ctx.Created(res)
+}
+
+// Delete runs the delete action, only the uploader may delete an attachment. The content is removed
+// once the metadata is deleted.
+func (c *WorkItemAttachmentsController) Delete(ctx *app.DeleteWorkItemAttachmentsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		a, err := loadAttachment(ctx, appl, spaceID, ctx.WiID, ctx.AttachmentID)
+		if err != nil {
+			return err
+		}
+		if *identityID != a.CreatedBy {
+			// need to use the goa.NewErrorClass() func as there is no native support for 403 in goa
+			return goa.NewErrorClass("forbidden", 403)("User is not the uploader of the attachment")
+		}
+		return appl.Attachments().Delete(ctx, a.WorkItemID, a.ID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	c.deleteContent(ctx, ctx.AttachmentID)
+	return ctx.OK([]byte{})
+}
+
+// deleteContent removes the content of the given attachment from the blob store. Failures only
+// leave unreachable content behind, so they are logged but not reported.
+func (c *WorkItemAttachmentsController) deleteContent(ctx context.Context, id uuid.UUID) {
+	if err := c.store.Delete(ctx, id.String()); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": id,
+			"err":           err,
+		}, "unable to delete the content of the attachment")
+	}
+}
+
+// loadAttachmentWorkItemID returns the internal ID of the given work item of the space
+func loadAttachmentWorkItemID(ctx context.Context, appl application.Application, spaceID uuid.UUID, wiID string) (uint64, error) {
+	resolvedID, err := resolveWorkItemID(ctx, appl, spaceID, wiID)
+	if err != nil {
+		return 0, errs.Wrapf(err, "failed to load work item %s", wiID)
+	}
+	wi, err := appl.WorkItems().Load(ctx, spaceID, resolvedID)
+	if err != nil {
+		return 0, errs.Wrapf(err, "failed to load work item %s", wiID)
+	}
+	id, err := strconv.ParseUint(wi.ID, 10, 64)
+	if err != nil {
+		return 0, errors.NewNotFoundError("work item", wiID)
+	}
+	return id, nil
+}
+
+// loadAttachment loads the attachment with the given ID of the given work item of the space
+func loadAttachment(ctx context.Context, appl application.Application, spaceID uuid.UUID, wiID string, id uuid.UUID) (*attachment.Attachment, error) {
+	workItemID, err := loadAttachmentWorkItemID(ctx, appl, spaceID, wiID)
+	if err != nil {
+		return nil, err
+	}
+	return appl.Attachments().Load(ctx, workItemID, id)
+}
+
+// ConvertWorkItemAttachment converts between internal and external REST representation
+func ConvertWorkItemAttachment(request *goa.RequestData, spaceID uuid.UUID, wiID string, a attachment.Attachment) *app.WorkItemAttachment {
+	wiURL := rest.AbsoluteURL(request, app.WorkitemHref(spaceID, wiID))
+	selfURL := wiURL + "/attachments/" + a.ID.String()
+	contentURL := selfURL + "/content"
+	wiType := APIStringTypeWorkItem
+	size := int(a.Size)
+	return &app.WorkItemAttachment{
+		Type: attachment.APIStringTypeAttachment,
+		ID:   a.ID,
+		Attributes: &app.WorkItemAttachmentAttributes{
+			Name:        a.Name,
+			Size:        size,
+			ContentType: a.ContentType,
+			Checksum:    a.Checksum,
+			CreatedAt:   a.CreatedAt,
+		},
+		Relationships: &app.WorkItemAttachmentRelationships{
+			Creator: &app.RelationGeneric{
+				Data: ConvertUserSimple(request, a.CreatedBy),
+			},
+			Workitem: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &wiType,
+					ID:   &wiID,
+				},
+				Links: &app.GenericLinks{
+					Related: &wiURL,
+				},
+			},
+		},
+		Links: &app.WorkItemAttachmentLinks{
+			Self:    &selfURL,
+			Content: &contentURL,
+		},
+	}
+}
@@ -0,0 +1,201 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/app/test"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentsConfig struct {
+	maxSize int64
+}
+
+func (c attachmentsConfig) GetAttachmentsMaxSize() int64 {
+	return c.maxSize
+}
+
+type TestWorkItemAttachmentsREST struct {
+	gormtestsupport.DBTestSuite
+	db       *gormapplication.GormDB
+	clean    func()
+	identity account.Identity
+	ctx      context.Context
+	svc      *goa.Service
+	ctrl     *WorkItemAttachmentsController
+	storeDir string
+	store    attachment.BlobStore
+	spaceID  string
+	wiID     string
+}
+
+func TestRunWorkItemAttachmentsREST(t *testing.T) {
+	suite.Run(t, &TestWorkItemAttachmentsREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestWorkItemAttachmentsREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	var err error
+	rest.identity, err = testsupport.CreateTestIdentity(rest.DB, "TestWorkItemAttachmentsREST user", "test provider")
+	require.Nil(rest.T(), err)
+	req := &http.Request{Host: "localhost"}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, url.Values{})
+	rest.storeDir, err = ioutil.TempDir("", "attachments")
+	require.Nil(rest.T(), err)
+	rest.store, err = attachment.NewFileSystemBlobStore(rest.storeDir)
+	require.Nil(rest.T(), err)
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	rest.svc = testsupport.ServiceAsUser("WorkItemAttachments-Service", almtoken.NewManagerWithPrivateKey(priv), rest.identity)
+	rest.ctrl = NewWorkItemAttachmentsController(rest.svc, rest.db, rest.store, attachmentsConfig{maxSize: 16})
+	testSpace, err := space.NewRepository(rest.DB).Create(rest.ctx, &space.Space{
+		Name: "TestWorkItemAttachmentsREST " + uuid.NewV4().String(),
+	})
+	require.Nil(rest.T(), err)
+	rest.spaceID = testSpace.ID.String()
+	wi, err := workitem.NewWorkItemRepository(rest.DB).Create(rest.ctx, testSpace.ID, workitem.SystemBug, map[string]interface{}{
+		workitem.SystemTitle: "Crash on startup",
+		workitem.SystemState: workitem.SystemStateNew,
+	}, rest.identity.ID)
+	require.Nil(rest.T(), err)
+	rest.wiID = wi.ID
+}
+
+func (rest *TestWorkItemAttachmentsREST) TearDownTest() {
+	rest.clean()
+	os.RemoveAll(rest.storeDir)
+}
+
+// upload runs the create action with the given request body, which the generated test helpers
+// cannot send as the action has no payload
+func (rest *TestWorkItemAttachmentsREST) upload(name, contentType, content string) *httptest.ResponseRecorder {
+	rw := httptest.NewRecorder()
+	query := url.Values{"name": {name}}
+	u := &url.URL{
+		Path:     fmt.Sprintf("/api/spaces/%s/workitems/%s/attachments", rest.spaceID, rest.wiID),
+		RawQuery: query.Encode(),
+	}
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(content))
+	require.Nil(rest.T(), err)
+	req.Header.Set("Content-Type", contentType)
+	prms := url.Values{"id": {rest.spaceID}, "wiID": {rest.wiID}, "name": {name}}
+	goaCtx := goa.NewContext(goa.WithAction(rest.svc.Context, "WorkItemAttachmentsTest"), rw, req, prms)
+	createCtx, err := app.NewCreateWorkItemAttachmentsContext(goaCtx, req, rest.svc)
+	require.Nil(rest.T(), err)
+	require.Nil(rest.T(), rest.ctrl.Create(createCtx))
+	return rw
+}
+
+func (rest *TestWorkItemAttachmentsREST) TestUploadDownloadAndDelete() {
+	// when
+	rw := rest.upload("crash.log", "text/plain", "panic: oops")
+	// then
+	require.Equal(rest.T(), http.StatusCreated, rw.Code)
+	var created app.WorkItemAttachmentSingle
+	require.Nil(rest.T(), json.Unmarshal(rw.Body.Bytes(), &created))
+	assert.Equal(rest.T(), "crash.log", created.Data.Attributes.Name)
+	assert.Equal(rest.T(), "text/plain", created.Data.Attributes.ContentType)
+	assert.Equal(rest.T(), len("panic: oops"), created.Data.Attributes.Size)
+	assert.Len(rest.T(), created.Data.Attributes.Checksum, 64)
+	assert.Equal(rest.T(), rest.identity.ID.String(), *created.Data.Relationships.Creator.Data.ID)
+	assert.Equal(rest.T(), *created.Data.Links.Self, rw.Header().Get("Location"))
+	_, shown := test.ShowWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	assert.Equal(rest.T(), created.Data.Attributes.Checksum, shown.Data.Attributes.Checksum)
+	_, list := test.ListWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID)
+	require.Len(rest.T(), list.Data, 1)
+	assert.Equal(rest.T(), created.Data.ID, list.Data[0].ID)
+	// when
+	res := test.DownloadWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	// then
+	assert.Equal(rest.T(), "panic: oops", res.(*httptest.ResponseRecorder).Body.String())
+	assert.Equal(rest.T(), "text/plain", res.Header().Get("Content-Type"))
+	assert.Equal(rest.T(), "nosniff", res.Header().Get("X-Content-Type-Options"))
+	assert.Equal(rest.T(), `attachment; filename=crash.log`, res.Header().Get("Content-Disposition"))
+	// when
+	test.DeleteWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	// then
+	test.ShowWorkItemAttachmentsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	test.DownloadWorkItemAttachmentsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	_, err := rest.store.Get(rest.ctx, created.Data.ID.String())
+	assert.NotNil(rest.T(), err)
+}
+
+func (rest *TestWorkItemAttachmentsREST) TestDownloadActiveContent() {
+	// given
+	rw := rest.upload("page.html", "text/html", "<script>")
+	require.Equal(rest.T(), http.StatusCreated, rw.Code)
+	var created app.WorkItemAttachmentSingle
+	require.Nil(rest.T(), json.Unmarshal(rw.Body.Bytes(), &created))
+	assert.Equal(rest.T(), "text/html", created.Data.Attributes.ContentType)
+	// when
+	res := test.DownloadWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	// then the content cannot be rendered by browsers
+	assert.Equal(rest.T(), "application/octet-stream", res.Header().Get("Content-Type"))
+	assert.Equal(rest.T(), "nosniff", res.Header().Get("X-Content-Type-Options"))
+}
+
+func (rest *TestWorkItemAttachmentsREST) TestDeleteByOtherUser() {
+	// given an attachment of the test user
+	rw := rest.upload("crash.log", "text/plain", "panic: oops")
+	require.Equal(rest.T(), http.StatusCreated, rw.Code)
+	var created app.WorkItemAttachmentSingle
+	require.Nil(rest.T(), json.Unmarshal(rw.Body.Bytes(), &created))
+	other, err := testsupport.CreateTestIdentity(rest.DB, "TestWorkItemAttachmentsREST other user", "test provider")
+	require.Nil(rest.T(), err)
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	otherSvc := testsupport.ServiceAsUser("WorkItemAttachments-Service", almtoken.NewManagerWithPrivateKey(priv), other)
+	otherCtrl := NewWorkItemAttachmentsController(otherSvc, rest.db, rest.store, attachmentsConfig{maxSize: 16})
+	// when
+	test.DeleteWorkItemAttachmentsForbidden(rest.T(), otherSvc.Context, otherSvc, otherCtrl, rest.spaceID, rest.wiID, created.Data.ID)
+	// then the attachment and its content are kept
+	test.ShowWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, created.Data.ID)
+	content, err := rest.store.Get(rest.ctx, created.Data.ID.String())
+	require.Nil(rest.T(), err)
+	content.Close()
+}
+
+func (rest *TestWorkItemAttachmentsREST) TestUploadTooLarge() {
+	// when
+	rw := rest.upload("huge.bin", "application/octet-stream", strings.Repeat("x", 17))
+	// then nothing is stored
+	assert.Equal(rest.T(), http.StatusBadRequest, rw.Code)
+	_, list := test.ListWorkItemAttachmentsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID)
+	assert.Empty(rest.T(), list.Data)
+	files, err := ioutil.ReadDir(rest.storeDir)
+	require.Nil(rest.T(), err)
+	assert.Empty(rest.T(), files)
+}
+
+func (rest *TestWorkItemAttachmentsREST) TestAttachmentsOfUnknownWorkItem() {
+	test.ListWorkItemAttachmentsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, "4242424242")
+	test.ListWorkItemAttachmentsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, space.SystemSpace.String(), rest.wiID)
+	test.DeleteWorkItemAttachmentsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.wiID, uuid.NewV4())
+}
@@ -2,6 +2,7 @@ package controller_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"testing"
@@ -46,6 +47,7 @@ type workItemChildSuite struct {
 	bug1ID      uint64
 	bug3        *app.WorkItemSingle
 	userSpaceID uuid.UUID
+	linkTypeID  uuid.UUID
 
 	// Store IDs of resources that need to be removed at the beginning or end of a test
 	testIdentity account.Identity
@@ -172,6 +174,7 @@ func (s *workItemChildSuite) SetupTest() {
 	_, workItemLinkType := test.CreateWorkItemLinkTypeCreated(s.T(), s.svc.Context, s.svc, s.workItemLinkTypeCtrl, s.userSpaceID.String(), createLinkTypePayload)
 	require.NotNil(s.T(), workItemLinkType)
 	bugBlockerLinkTypeID := *workItemLinkType.Data.ID
+	s.linkTypeID = bugBlockerLinkTypeID
 	s.T().Logf("Created link type with ID: %s\n", *workItemLinkType.Data.ID)
 
 	createPayload := CreateWorkItemLink(s.bug1ID, bug2ID, bugBlockerLinkTypeID)
@@ -293,3 +296,45 @@ func (s *workItemChildSuite) TestListChildrenNotModifiedUsingIfNoneMatchHeader()
 	// then
 	assertResponseHeaders(s.T(), res)
 }
+
+func (s *workItemChildSuite) TestCreateLinkConflictsWithTreeTopology() {
+	// given
+	bug3ID, err := strconv.ParseUint(*s.bug3.Data.ID, 10, 64)
+	require.Nil(s.T(), err)
+	// when bug3 becomes the parent of its own parent
+	createPayload := CreateWorkItemLink(bug3ID, s.bug1ID, s.linkTypeID)
+	_, jerrs := test.CreateWorkItemLinkConflict(s.T(), s.svc.Context, s.svc, s.workItemLinkCtrl, createPayload)
+	// then the error names the cycle
+	require.NotNil(s.T(), jerrs)
+	require.Len(s.T(), jerrs.Errors, 1)
+	assert.Contains(s.T(), jerrs.Errors[0].Detail, fmt.Sprintf("%d -> %d -> %d", bug3ID, s.bug1ID, bug3ID))
+}
+
+func (s *workItemChildSuite) TestTraverseOK() {
+	// given
+	workItemID1 := strconv.FormatUint(s.bug1ID, 10)
+	// when
+	_, traversal := test.TraverseWorkitemOK(s.T(), s.svc.Context, s.svc, s.workItemCtrl, s.userSpaceID.String(), workItemID1, nil, nil, nil, s.linkTypeID)
+	// then the children are returned with their paths
+	require.Len(s.T(), traversal.Data, 2)
+	assert.Equal(s.T(), 2, traversal.Meta.TotalCount)
+	for _, node := range traversal.Data {
+		assert.Equal(s.T(), 1, node.Depth)
+		assert.Equal(s.T(), []string{workItemID1, *node.Workitem.ID}, node.Path)
+	}
+}
+
+func (s *workItemChildSuite) TestTraverseReverseOK() {
+	// given
+	direction := string(link.TraversalReverse)
+	// when
+	_, traversal := test.TraverseWorkitemOK(s.T(), s.svc.Context, s.svc, s.workItemCtrl, s.userSpaceID.String(), *s.bug3.Data.ID, nil, &direction, nil, s.linkTypeID)
+	// then the parent is returned
+	require.Len(s.T(), traversal.Data, 1)
+	assert.Equal(s.T(), strconv.FormatUint(s.bug1ID, 10), *traversal.Data[0].Workitem.ID)
+}
+
+func (s *workItemChildSuite) TestTraverseUnknownWorkItem() {
+	// when
+	test.TraverseWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.workItemCtrl, s.userSpaceID.String(), "4242424242", nil, nil, nil, s.linkTypeID)
+}
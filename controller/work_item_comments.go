@@ -28,7 +28,7 @@ func NewWorkItemCommentsController(service *goa.Service, db application.DB) *Wor
 // Create runs the create action.
 func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsContext) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
-		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := loadWorkItemByIDOrKey(ctx, appl, ctx.ID, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
@@ -41,7 +41,7 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 		reqComment := ctx.Payload.Data
 		markup := rendering.NilSafeGetMarkup(reqComment.Attributes.Markup)
 		newComment := comment.Comment{
-			ParentID:  ctx.WiID,
+			ParentID:  wi.ID,
 			Body:      reqComment.Attributes.Body,
 			Markup:    markup,
 			CreatedBy: *currentUserIdentityID,
@@ -63,7 +63,7 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext) error {
 	offset, limit := computePagingLimts(ctx.PageOffset, ctx.PageLimit)
 	return application.Transactional(c.db, func(appl application.Application) error {
-		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := loadWorkItemByIDOrKey(ctx, appl, ctx.ID, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
@@ -71,15 +71,26 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 		res := &app.CommentList{}
 		res.Data = []*app.Comment{}
 
-		comments, tc, err := appl.Comments().List(ctx, ctx.WiID, &offset, &limit)
+		var comments []*comment.Comment
+		var tc uint64
+		var next *string
+		if ctx.PageCursor != nil {
+			comments, tc, next, err = appl.Comments().ListAfter(ctx, wi.ID, ctx.PageCursor, limit)
+		} else {
+			comments, tc, err = appl.Comments().List(ctx, wi.ID, &offset, &limit)
+		}
 		count := int(tc)
 		if err != nil {
-			return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal(err.Error()))
+			return jsonapi.JSONErrorResponse(ctx, err)
 		}
 		res.Meta = &app.CommentListMeta{TotalCount: count}
 		res.Data = ConvertComments(ctx.RequestData, comments)
 		res.Links = &app.PagingLinks{}
-		setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(comments), offset, limit, count)
+		if ctx.PageCursor != nil {
+			setCursorPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), limit, next)
+		} else {
+			setPagingLinks(res.Links, buildAbsoluteURL(ctx.RequestData), len(comments), offset, limit, count)
+		}
 
 		return ctx.OK(res)
 	})
@@ -90,12 +101,12 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 func (c *WorkItemCommentsController) Relations(ctx *app.RelationsWorkItemCommentsContext) error {
 	offset, limit := computePagingLimts(ctx.PageOffset, ctx.PageLimit)
 	return application.Transactional(c.db, func(appl application.Application) error {
-		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := loadWorkItemByIDOrKey(ctx, appl, ctx.ID, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
 
-		comments, tc, err := appl.Comments().List(ctx, ctx.WiID, &offset, &limit)
+		comments, tc, err := appl.Comments().List(ctx, wi.ID, &offset, &limit)
 		count := int(tc)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal(err.Error()))
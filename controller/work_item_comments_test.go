@@ -163,18 +163,36 @@ func (rest *TestCommentREST) TestListCommentsByParentWorkItem() {
 	svc, ctrl := rest.UnSecuredController()
 	offset := "0"
 	limit := 3
-	_, cs := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.ID, &limit, &offset)
+	_, cs := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.ID, nil, &limit, &offset)
 	// then
 	require.Equal(rest.T(), 3, len(cs.Data))
 	rest.assertComment(cs.Data[0], "Test 3", rendering.SystemMarkupDefault) // items are returned in reverse order or creation
 	// given
 	wi2 := rest.createDefaultWorkItem()
 	// when
-	_, cs2 := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi2.SpaceID.String(), wi2.ID, &limit, &offset)
+	_, cs2 := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi2.SpaceID.String(), wi2.ID, nil, &limit, &offset)
 	// then
 	assert.Equal(rest.T(), 0, len(cs2.Data))
 }
 
+func (rest *TestCommentREST) TestCreateAndListCommentsByWorkItemKey() {
+	// given
+	wi := rest.createDefaultWorkItem()
+	require.NotEmpty(rest.T(), wi.Key)
+	// when
+	p := rest.newCreateWorkItemCommentsPayload("Test", nil)
+	svc, ctrl := rest.SecuredController()
+	test.CreateWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.Key, p)
+	offset := "0"
+	limit := 10
+	_, cs := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.Key, nil, &limit, &offset)
+	// then
+	require.Equal(rest.T(), 1, len(cs.Data))
+	_, cs = test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.ID, nil, &limit, &offset)
+	require.Equal(rest.T(), 1, len(cs.Data))
+	rest.assertComment(cs.Data[0], "Test", rendering.SystemMarkupDefault)
+}
+
 func (rest *TestCommentREST) TestEmptyListCommentsByParentWorkItem() {
 	// given
 	wi := rest.createDefaultWorkItem()
@@ -182,7 +200,7 @@ func (rest *TestCommentREST) TestEmptyListCommentsByParentWorkItem() {
 	svc, ctrl := rest.UnSecuredController()
 	offset := "0"
 	limit := 1
-	_, cs := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.ID, &limit, &offset)
+	_, cs := test.ListWorkItemCommentsOK(rest.T(), svc.Context, svc, ctrl, wi.SpaceID.String(), wi.ID, nil, &limit, &offset)
 	// then
 	assert.Equal(rest.T(), 0, len(cs.Data))
 }
@@ -222,5 +240,5 @@ func (rest *TestCommentREST) TestListCommentsByMissingParentWorkItem() {
 	// when/then
 	offset := "0"
 	limit := 1
-	test.ListWorkItemCommentsNotFound(rest.T(), svc.Context, svc, ctrl, "0000000", "0000000", &limit, &offset)
+	test.ListWorkItemCommentsNotFound(rest.T(), svc.Context, svc, ctrl, "0000000", "0000000", nil, &limit, &offset)
 }
@@ -282,8 +282,11 @@ func (c *WorkItemLinkController) Create(ctx *app.CreateWorkItemLinkContext) erro
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
 	}
-	linkCtx := newWorkItemLinkContext(ctx.Context, c.db, c.db, ctx.RequestData, ctx.ResponseData, app.WorkItemLinkHref, currentUserIdentityID)
-	return createWorkItemLink(linkCtx, ctx, ctx.Payload)
+	// the topology of the link type is checked in the same transaction in which the link is created
+	return application.Transactional(c.db, func(appl application.Application) error {
+		linkCtx := newWorkItemLinkContext(ctx.Context, appl, c.db, ctx.RequestData, ctx.ResponseData, app.WorkItemLinkHref, currentUserIdentityID)
+		return createWorkItemLink(linkCtx, ctx, ctx.Payload)
+	})
 }
 
 type deleteWorkItemLinkFuncs interface {
@@ -46,20 +46,21 @@ func (c *WorkItemRelationshipsLinksController) Create(ctx *app.CreateWorkItemRel
 	}
 	return application.Transactional(c.db, func(appl application.Application) error {
 		// Check that current work item does indeed exist
-		if _, err := appl.WorkItems().LoadByID(ctx.Context, ctx.WiID); err != nil {
+		wi, err := loadWorkItemByIDOrKey(ctx.Context, appl, ctx.ID, ctx.WiID)
+		if err != nil {
 			jerrors, httpStatusCode := jsonapi.ErrorToJSONAPIErrors(err)
 			return ctx.ResponseData.Service.Send(ctx.Context, httpStatusCode, jerrors)
 		}
 		// Check that the source ID of the link is the same as the current work
 		// item ID.
 		src, _ := getSrcTgt(ctx.Payload.Data)
-		if src != nil && *src != ctx.WiID {
-			jerrors, _ := jsonapi.ErrorToJSONAPIErrors(goa.ErrBadRequest(fmt.Sprintf("data.relationships.source.data.id is \"%s\" but must be \"%s\"", ctx.Payload.Data.Relationships.Source.Data.ID, ctx.WiID)))
+		if src != nil && *src != wi.ID && *src != ctx.WiID {
+			jerrors, _ := jsonapi.ErrorToJSONAPIErrors(goa.ErrBadRequest(fmt.Sprintf("data.relationships.source.data.id is \"%s\" but must be \"%s\"", ctx.Payload.Data.Relationships.Source.Data.ID, wi.ID)))
 			return ctx.BadRequest(jerrors)
 		}
 		// If no source is specified we pre-fill the source field of the payload
 		// with the current work item ID from the URL. This is for convenience.
-		if src == nil {
+		if src == nil || *src != wi.ID {
 			if ctx.Payload.Data.Relationships == nil {
 				ctx.Payload.Data.Relationships = &app.WorkItemLinkRelationships{}
 			}
@@ -69,7 +70,7 @@ func (c *WorkItemRelationshipsLinksController) Create(ctx *app.CreateWorkItemRel
 			if ctx.Payload.Data.Relationships.Source.Data == nil {
 				ctx.Payload.Data.Relationships.Source.Data = &app.RelationWorkItemData{}
 			}
-			ctx.Payload.Data.Relationships.Source.Data.ID = ctx.WiID
+			ctx.Payload.Data.Relationships.Source.Data.ID = wi.ID
 			ctx.Payload.Data.Relationships.Source.Data.Type = link.EndpointWorkItems
 		}
 		linkCtx := newWorkItemLinkContext(ctx.Context, appl, c.db, ctx.RequestData, ctx.ResponseData, app.WorkItemLinkHref, currentUserIdentityID)
@@ -80,8 +81,13 @@ func (c *WorkItemRelationshipsLinksController) Create(ctx *app.CreateWorkItemRel
 // List runs the list action.
 func (c *WorkItemRelationshipsLinksController) List(ctx *app.ListWorkItemRelationshipsLinksContext) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
+		wi, err := loadWorkItemByIDOrKey(ctx.Context, appl, ctx.ID, ctx.WiID)
+		if err != nil {
+			jerrors, httpStatusCode := jsonapi.ErrorToJSONAPIErrors(err)
+			return ctx.ResponseData.Service.Send(ctx.Context, httpStatusCode, jerrors)
+		}
 		linkCtx := newWorkItemLinkContext(ctx.Context, appl, c.db, ctx.RequestData, ctx.ResponseData, app.WorkItemLinkHref, nil)
-		return listWorkItemLink(linkCtx, ctx, &ctx.WiID)
+		return listWorkItemLink(linkCtx, ctx, &wi.ID)
 	})
 }
 
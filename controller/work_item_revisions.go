@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/log"
+	"github.com/almighty/almighty-core/login"
+	"github.com/almighty/almighty-core/rendering"
+	"github.com/almighty/almighty-core/space"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// APIStringTypeWorkItemRevision is the JSONAPI type of work item revisions
+const APIStringTypeWorkItemRevision = "workitemrevisions"
+
+// revisionTypeNames maps the revision types to their names in the API
+var revisionTypeNames = map[workitem.RevisionType]string{
+	workitem.RevisionTypeCreate:  "create",
+	workitem.RevisionTypeUpdate:  "update",
+	workitem.RevisionTypeDelete:  "delete",
+	workitem.RevisionTypeRestore: "restore",
+}
+
+// WorkItemRevisionsController implements the work-item-revisions resource.
+type WorkItemRevisionsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemRevisionsController creates a work-item-revisions controller.
+func NewWorkItemRevisionsController(service *goa.Service, db application.DB) *WorkItemRevisionsController {
+	return &WorkItemRevisionsController{Controller: service.NewController("WorkItemRevisionsController"), db: db}
+}
+
+// List runs the list action.
+func (c *WorkItemRevisionsController) List(ctx *app.ListWorkItemRevisionsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Fail to load work item with id %v", ctx.WiID)))
+		}
+		wi, err := appl.WorkItems().Load(ctx, spaceID, wiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Fail to load work item with id %v", ctx.WiID)))
+		}
+		revisions, err := appl.WorkItemRevisions().List(ctx, wi.ID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		renderer := newRevisionRenderer(ctx, appl, spaceID)
+		res := &app.WorkItemRevisionList{
+			Data: []*app.WorkItemRevision{},
+			Meta: &app.WorkItemRevisionListMeta{TotalCount: len(revisions)},
+		}
+		for _, revision := range workitem.ComputeRevisionChanges(revisions) {
+			res.Data = append(res.Data, renderer.convertRevision(ctx.RequestData, revision))
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Revert runs the revert action.
+func (c *WorkItemRevisionsController) Revert(ctx *app.RevertWorkItemRevisionsContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error reverting work item"))
+		}
+		wi, err := appl.WorkItems().Revert(ctx, spaceID, wiID, ctx.RevisionID, ctx.Version, *currentUserIdentityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error reverting work item"))
+		}
+		resp := &app.WorkItemSingle{
+			Data: ConvertWorkItem(ctx.RequestData, *wi),
+		}
+		ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+		return ctx.OK(resp)
+	})
+}
+
+// revisionRenderer converts revisions to their API representation. Stored field values are rendered in a
+// readable form according to the type of the field: markup as content, markup and rendered HTML, users
+// with their name and instants as times. Work item types and users are loaded once per request.
+type revisionRenderer struct {
+	ctx     context.Context
+	appl    application.Application
+	spaceID uuid.UUID
+	types   map[uuid.UUID]map[string]workitem.FieldDefinition
+	users   map[string]string
+}
+
+func newRevisionRenderer(ctx context.Context, appl application.Application, spaceID uuid.UUID) *revisionRenderer {
+	return &revisionRenderer{
+		ctx:     ctx,
+		appl:    appl,
+		spaceID: spaceID,
+		types:   map[uuid.UUID]map[string]workitem.FieldDefinition{},
+		users:   map[string]string{},
+	}
+}
+
+func (r *revisionRenderer) convertRevision(request *goa.RequestData, revision workitem.RevisionChanges) *app.WorkItemRevision {
+	modifier := revision.ModifierIdentity.String()
+	modifierName := r.userName(modifier)
+	changes := make([]*app.WorkItemFieldChange, len(revision.Changes))
+	for i, change := range revision.Changes {
+		changes[i] = &app.WorkItemFieldChange{
+			Field:    change.Name,
+			OldValue: r.renderValue(revision.WorkItemTypeID, change.Name, change.OldValue),
+			NewValue: r.renderValue(revision.WorkItemTypeID, change.Name, change.NewValue),
+		}
+	}
+	return &app.WorkItemRevision{
+		Type: APIStringTypeWorkItemRevision,
+		ID:   revision.ID,
+		Attributes: &app.WorkItemRevisionAttributes{
+			RevisionType: revisionTypeNames[revision.Type],
+			Time:         revision.Time,
+			Version:      revision.WorkItemVersion,
+			Modifier:     &modifierName,
+			Changes:      changes,
+		},
+		Relationships: &app.WorkItemRevisionRelationships{
+			Modifier: &app.RelationGeneric{
+				Data: ConvertUserSimple(request, modifier),
+			},
+		},
+	}
+}
+
+// fieldTypes returns the field definitions of the given work item type. Types that are not found in the space
+// of the work item are looked up in the system space, unknown types have no fields.
+func (r *revisionRenderer) fieldTypes(typeID uuid.UUID) map[string]workitem.FieldDefinition {
+	if fields, ok := r.types[typeID]; ok {
+		return fields
+	}
+	fields := map[string]workitem.FieldDefinition{}
+	wit, err := r.appl.WorkItemTypes().Load(r.ctx, r.spaceID, typeID)
+	if err != nil {
+		wit, err = r.appl.WorkItemTypes().Load(r.ctx, space.SystemSpace, typeID)
+	}
+	if err != nil {
+		log.Error(r.ctx, map[string]interface{}{
+			"wit_id": typeID,
+			"err":    err,
+		}, "unable to load the work item type of a revision")
+	} else {
+		fields = wit.Fields
+	}
+	r.types[typeID] = fields
+	return fields
+}
+
+// renderValue renders the stored value of the given field in a readable form
+func (r *revisionRenderer) renderValue(typeID uuid.UUID, fieldName string, value interface{}) interface{} {
+	fieldDef, ok := r.fieldTypes(typeID)[fieldName]
+	if !ok {
+		return value
+	}
+	return r.renderTypedValue(fieldDef.Type, value)
+}
+
+func (r *revisionRenderer) renderTypedValue(fieldType workitem.FieldType, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch t := fieldType.(type) {
+	case workitem.ListType:
+		elements, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		result := make([]interface{}, len(elements))
+		for i, element := range elements {
+			result[i] = r.renderTypedValue(t.ComponentType, element)
+		}
+		return result
+	case workitem.EnumType:
+		return r.renderTypedValue(t.BaseType, value)
+	}
+	switch fieldType.GetKind() {
+	case workitem.KindMarkup:
+		markup := rendering.NewMarkupContentFromValue(value)
+		if markup == nil {
+			return value
+		}
+		return map[string]interface{}{
+			rendering.ContentKey: markup.Content,
+			rendering.MarkupKey:  markup.Markup,
+			"rendered":           rendering.RenderMarkupToHTML(html.EscapeString(markup.Content), markup.Markup),
+		}
+	case workitem.KindUser:
+		id := fmt.Sprint(value)
+		return map[string]interface{}{
+			"id":   id,
+			"name": r.userName(id),
+		}
+	case workitem.KindInstant:
+		switch v := value.(type) {
+		case float64:
+			return time.Unix(0, int64(v)).UTC()
+		case int64:
+			return time.Unix(0, v).UTC()
+		}
+	}
+	return value
+}
+
+// userName returns the full name of the user with the given identity ID, or the username of the identity if the
+// user has no name. The ID itself is returned for unknown identities.
+func (r *revisionRenderer) userName(identityID string) string {
+	if name, ok := r.users[identityID]; ok {
+		return name
+	}
+	name := identityID
+	if id, err := uuid.FromString(identityID); err == nil {
+		if identity, err := r.appl.Identities().Load(r.ctx, id); err == nil {
+			if identity.Username != "" {
+				name = identity.Username
+			}
+			if identity.UserID.Valid {
+				if user, err := r.appl.Users().Load(r.ctx, identity.UserID.UUID); err == nil && user.FullName != "" {
+					name = user.FullName
+				}
+			}
+		}
+	}
+	r.users[identityID] = name
+	return name
+}
@@ -0,0 +1,231 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/app/test"
+	"github.com/almighty/almighty-core/application"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/rendering"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemRevisionsREST struct {
+	gormtestsupport.DBTestSuite
+	db        *gormapplication.GormDB
+	clean     func()
+	creator   account.Identity
+	modifier  account.Identity
+	ctx       context.Context
+	svc       *goa.Service
+	ctrl      *WorkItemRevisionsController
+	workItem  *workitem.WorkItem
+	spaceID   string
+	workItems workitem.WorkItemRepository
+}
+
+func TestRunWorkItemRevisionsREST(t *testing.T) {
+	suite.Run(t, &TestWorkItemRevisionsREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestWorkItemRevisionsREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	var err error
+	rest.creator, err = testsupport.CreateTestIdentity(rest.DB, "TestWorkItemRevisionsREST creator", "test provider")
+	require.Nil(rest.T(), err)
+	rest.modifier, err = testsupport.CreateTestIdentity(rest.DB, "TestWorkItemRevisionsREST modifier", "test provider")
+	require.Nil(rest.T(), err)
+	req := &http.Request{Host: "localhost"}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, url.Values{})
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	rest.svc = testsupport.ServiceAsUser("WorkItemRevisions-Service", almtoken.NewManagerWithPrivateKey(priv), rest.modifier)
+	rest.ctrl = NewWorkItemRevisionsController(rest.svc, rest.db)
+	rest.workItems = rest.db.WorkItems()
+	rest.workItem, err = rest.workItems.Create(
+		rest.ctx,
+		space.SystemSpace,
+		workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "A",
+			workitem.SystemState: workitem.SystemStateNew,
+		},
+		rest.creator.ID)
+	require.Nil(rest.T(), err)
+	rest.spaceID = space.SystemSpace.String()
+}
+
+func (rest *TestWorkItemRevisionsREST) TearDownTest() {
+	rest.clean()
+}
+
+// update applies the given field values to the work item as the modifier
+func (rest *TestWorkItemRevisionsREST) update(fields map[string]interface{}) {
+	err := application.Transactional(rest.db, func(appl application.Application) error {
+		for name, value := range fields {
+			rest.workItem.Fields[name] = value
+		}
+		wi, err := appl.WorkItems().Save(rest.ctx, space.SystemSpace, *rest.workItem, rest.modifier.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		rest.workItem = wi
+		return nil
+	})
+	require.Nil(rest.T(), err)
+}
+
+func (rest *TestWorkItemRevisionsREST) findChange(revision *app.WorkItemRevision, field string) *app.WorkItemFieldChange {
+	for _, change := range revision.Attributes.Changes {
+		if change.Field == field {
+			return change
+		}
+	}
+	return nil
+}
+
+func (rest *TestWorkItemRevisionsREST) TestListRevisionsOfCreatedWorkItem() {
+	// when
+	_, revisions := test.ListWorkItemRevisionsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.workItem.ID)
+	// then
+	require.Len(rest.T(), revisions.Data, 1)
+	assert.Equal(rest.T(), 1, revisions.Meta.TotalCount)
+	revision := revisions.Data[0]
+	assert.Equal(rest.T(), APIStringTypeWorkItemRevision, revision.Type)
+	assert.Equal(rest.T(), "create", revision.Attributes.RevisionType)
+	assert.Equal(rest.T(), 0, revision.Attributes.Version)
+	assert.Equal(rest.T(), rest.creator.Username, *revision.Attributes.Modifier)
+	assert.Equal(rest.T(), rest.creator.ID.String(), *revision.Relationships.Modifier.Data.ID)
+	title := rest.findChange(revision, workitem.SystemTitle)
+	require.NotNil(rest.T(), title)
+	assert.Nil(rest.T(), title.OldValue)
+	assert.Equal(rest.T(), "A", title.NewValue)
+	creator := rest.findChange(revision, workitem.SystemCreator)
+	require.NotNil(rest.T(), creator)
+	assert.Equal(rest.T(), map[string]interface{}{"id": rest.creator.ID.String(), "name": rest.creator.Username}, creator.NewValue)
+}
+
+func (rest *TestWorkItemRevisionsREST) TestListRevisionsWithFieldChanges() {
+	// given
+	rest.update(map[string]interface{}{
+		workitem.SystemState:       workitem.SystemStateClosed,
+		workitem.SystemAssignees:   []interface{}{rest.modifier.ID.String()},
+		workitem.SystemDescription: rendering.NewMarkupContent("**closed**", rendering.SystemMarkupMarkdown),
+	})
+	rest.update(map[string]interface{}{
+		workitem.SystemTitle: "B",
+	})
+	// when
+	_, revisions := test.ListWorkItemRevisionsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.workItem.ID)
+	// then
+	require.Len(rest.T(), revisions.Data, 3)
+	closing := revisions.Data[1]
+	assert.Equal(rest.T(), "update", closing.Attributes.RevisionType)
+	assert.Equal(rest.T(), 1, closing.Attributes.Version)
+	assert.Equal(rest.T(), rest.modifier.Username, *closing.Attributes.Modifier)
+	require.Len(rest.T(), closing.Attributes.Changes, 3)
+	state := rest.findChange(closing, workitem.SystemState)
+	require.NotNil(rest.T(), state)
+	assert.Equal(rest.T(), workitem.SystemStateNew, state.OldValue)
+	assert.Equal(rest.T(), workitem.SystemStateClosed, state.NewValue)
+	assignees := rest.findChange(closing, workitem.SystemAssignees)
+	require.NotNil(rest.T(), assignees)
+	assert.Nil(rest.T(), assignees.OldValue)
+	assert.Equal(rest.T(), []interface{}{
+		map[string]interface{}{"id": rest.modifier.ID.String(), "name": rest.modifier.Username},
+	}, assignees.NewValue)
+	description := rest.findChange(closing, workitem.SystemDescription)
+	require.NotNil(rest.T(), description)
+	assert.Equal(rest.T(), map[string]interface{}{
+		rendering.ContentKey: "**closed**",
+		rendering.MarkupKey:  rendering.SystemMarkupMarkdown,
+		"rendered":           rendering.RenderMarkupToHTML("**closed**", rendering.SystemMarkupMarkdown),
+	}, description.NewValue)
+	renaming := revisions.Data[2]
+	require.Len(rest.T(), renaming.Attributes.Changes, 1)
+	assert.Equal(rest.T(), workitem.SystemTitle, renaming.Attributes.Changes[0].Field)
+	assert.Equal(rest.T(), "A", renaming.Attributes.Changes[0].OldValue)
+	assert.Equal(rest.T(), "B", renaming.Attributes.Changes[0].NewValue)
+}
+
+func (rest *TestWorkItemRevisionsREST) TestListRevisionsOfUnknownWorkItem() {
+	test.ListWorkItemRevisionsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, "4242424242")
+}
+
+func (rest *TestWorkItemRevisionsREST) TestShowWorkItemAtVersionAndTime() {
+	// given
+	rest.update(map[string]interface{}{workitem.SystemTitle: "B"})
+	revisions, err := rest.db.WorkItemRevisions().List(rest.ctx, rest.workItem.ID)
+	require.Nil(rest.T(), err)
+	require.Len(rest.T(), revisions, 2)
+	ctrl := NewWorkitemController(rest.svc, rest.db, rest.Configuration)
+	// when
+	version := 0
+	_, wi := test.ShowWorkitemOK(rest.T(), rest.svc.Context, rest.svc, ctrl, rest.spaceID, rest.workItem.ID, nil, &version, nil, nil)
+	// then
+	assert.Equal(rest.T(), "A", wi.Data.Attributes[workitem.SystemTitle])
+	assert.Equal(rest.T(), 0, wi.Data.Attributes["version"])
+	// when
+	at := revisions[1].Time
+	_, wi = test.ShowWorkitemOK(rest.T(), rest.svc.Context, rest.svc, ctrl, rest.spaceID, rest.workItem.ID, &at, nil, nil, nil)
+	// then
+	assert.Equal(rest.T(), "B", wi.Data.Attributes[workitem.SystemTitle])
+	// when
+	before := revisions[0].Time.Add(-time.Hour)
+	test.ShowWorkitemNotFound(rest.T(), rest.svc.Context, rest.svc, ctrl, rest.spaceID, rest.workItem.ID, &before, nil, nil, nil)
+	test.ShowWorkitemBadRequest(rest.T(), rest.svc.Context, rest.svc, ctrl, rest.spaceID, rest.workItem.ID, &at, &version, nil, nil)
+}
+
+func (rest *TestWorkItemRevisionsREST) TestRevertWorkItem() {
+	// given
+	rest.update(map[string]interface{}{
+		workitem.SystemTitle: "B",
+		workitem.SystemState: workitem.SystemStateClosed,
+	})
+	revisions, err := rest.db.WorkItemRevisions().List(rest.ctx, rest.workItem.ID)
+	require.Nil(rest.T(), err)
+	// when
+	test.RevertWorkItemRevisionsBadRequest(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.workItem.ID, revisions[0].ID, 0)
+	test.RevertWorkItemRevisionsNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.workItem.ID, uuid.NewV4(), rest.workItem.Version)
+	_, wi := test.RevertWorkItemRevisionsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.workItem.ID, revisions[0].ID, rest.workItem.Version)
+	// then
+	assert.Equal(rest.T(), "A", wi.Data.Attributes[workitem.SystemTitle])
+	assert.Equal(rest.T(), workitem.SystemStateNew, wi.Data.Attributes[workitem.SystemState])
+	assert.Equal(rest.T(), rest.workItem.Version+1, wi.Data.Attributes["version"])
+	_, list := test.ListWorkItemRevisionsOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID, rest.workItem.ID)
+	require.Len(rest.T(), list.Data, 3)
+	reverting := list.Data[2]
+	assert.Equal(rest.T(), rest.modifier.Username, *reverting.Attributes.Modifier)
+	title := rest.findChange(reverting, workitem.SystemTitle)
+	require.NotNil(rest.T(), title)
+	assert.Equal(rest.T(), "B", title.OldValue)
+	assert.Equal(rest.T(), "A", title.NewValue)
+}
+
+func (rest *TestWorkItemRevisionsREST) TestRevertWorkItemUnauthorized() {
+	svc := goa.New("WorkItemRevisions-Service")
+	ctrl := NewWorkItemRevisionsController(svc, rest.db)
+	test.RevertWorkItemRevisionsUnauthorized(rest.T(), svc.Context, svc, ctrl, rest.spaceID, rest.workItem.ID, uuid.NewV4(), rest.workItem.Version)
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/login"
+	"github.com/almighty/almighty-core/rest"
+	"github.com/almighty/almighty-core/space"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/template"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemTemplateController implements the work_item_template resource.
+type WorkItemTemplateController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemTemplateController creates a work_item_template controller.
+func NewWorkItemTemplateController(service *goa.Service, db application.DB) *WorkItemTemplateController {
+	return &WorkItemTemplateController{Controller: service.NewController("WorkItemTemplateController"), db: db}
+}
+
+// List runs the list action.
+func (c *WorkItemTemplateController) List(ctx *app.ListWorkItemTemplateContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, spaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		templates, err := appl.WorkItemTemplates().List(ctx, spaceID, ctx.FilterWorkitemtype)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.WorkItemTemplateList{
+			Data: []*app.WorkItemTemplate{},
+		}
+		for _, t := range templates {
+			res.Data = append(res.Data, ConvertWorkItemTemplate(ctx.RequestData, t))
+		}
+		return ctx.OK(res)
+	})
+}
+
+// Show runs the show action.
+func (c *WorkItemTemplateController) Show(ctx *app.ShowWorkItemTemplateContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		t, err := appl.WorkItemTemplates().Load(ctx, spaceID, ctx.TemplateID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.WorkItemTemplateSingle{
+			Data: ConvertWorkItemTemplate(ctx.RequestData, *t),
+		})
+	})
+}
+
+// Create runs the create action.
+func (c *WorkItemTemplateController) Create(ctx *app.CreateWorkItemTemplateContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	_, err = login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	rel := ctx.Payload.Data.Relationships
+	if rel == nil || rel.BaseType == nil || rel.BaseType.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.relationships.baseType", nil).Expected("not nil"))
+	}
+	t := template.Template{
+		SpaceID:        spaceID,
+		WorkItemTypeID: rel.BaseType.Data.ID,
+	}
+	ConvertJSONAPIToWorkItemTemplate(*ctx.Payload.Data, &t)
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.Spaces().Load(ctx, spaceID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		if err := appl.WorkItemTemplates().Create(ctx, &t); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		res := &app.WorkItemTemplateSingle{
+			Data: ConvertWorkItemTemplate(ctx.RequestData, t),
+		}
+		ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.RequestData, app.WorkItemTemplateHref(spaceID, t.ID)))
+		return ctx.Created(res)
+	})
+}
+
+// Update runs the update action.
+func (c *WorkItemTemplateController) Update(ctx *app.UpdateWorkItemTemplateContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	_, err = login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	if ctx.Payload.Data.Attributes.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		t, err := appl.WorkItemTemplates().Load(ctx, spaceID, ctx.TemplateID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		t.Version = *ctx.Payload.Data.Attributes.Version
+		ConvertJSONAPIToWorkItemTemplate(*ctx.Payload.Data, t)
+		t, err = appl.WorkItemTemplates().Save(ctx, *t)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK(&app.WorkItemTemplateSingle{
+			Data: ConvertWorkItemTemplate(ctx.RequestData, *t),
+		})
+	})
+}
+
+// Delete runs the delete action.
+func (c *WorkItemTemplateController) Delete(ctx *app.DeleteWorkItemTemplateContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	_, err = login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.WorkItemTemplates().Delete(ctx, spaceID, ctx.TemplateID); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		return ctx.OK([]byte{})
+	})
+}
+
+// loadWorkItemTemplate loads the template referenced by the given relationship of a new work item
+// and verifies that it pre-fills work items of the given type, which is returned as well. Types that
+// are not found in the space are looked up in the system space.
+func loadWorkItemTemplate(ctx context.Context, appl application.Application, spaceID uuid.UUID, witID uuid.UUID, rel *app.RelationGeneric) (*template.Template, *workitem.WorkItemType, error) {
+	if rel.Data == nil || rel.Data.ID == nil {
+		return nil, nil, errors.NewBadParameterError("data.relationships.template.data.id", nil).Expected("not nil")
+	}
+	templateID, err := uuid.FromString(*rel.Data.ID)
+	if err != nil {
+		return nil, nil, errors.NewBadParameterError("data.relationships.template.data.id", *rel.Data.ID).Expected("a UUID")
+	}
+	t, err := appl.WorkItemTemplates().Load(ctx, spaceID, templateID)
+	if err != nil {
+		return nil, nil, errors.NewBadParameterError("data.relationships.template.data.id", *rel.Data.ID).Expected("a template of the space")
+	}
+	if t.WorkItemTypeID != witID {
+		return nil, nil, errors.NewBadParameterError("data.relationships.template.data.id", *rel.Data.ID).Expected("a template of the work item type " + witID.String())
+	}
+	wit, err := appl.WorkItemTypes().Load(ctx, spaceID, witID)
+	if err != nil {
+		wit, err = appl.WorkItemTypes().Load(ctx, space.SystemSpace, witID)
+	}
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	return t, wit, nil
+}
+
+// ConvertJSONAPIToWorkItemTemplate copies the attributes of the given JSONAPI work item template to
+// the target. Attributes that are not set are left as is.
+func ConvertJSONAPIToWorkItemTemplate(source app.WorkItemTemplate, target *template.Template) {
+	if source.Attributes.Name != nil {
+		target.Name = *source.Attributes.Name
+	}
+	if source.Attributes.Description != nil {
+		target.Description = *source.Attributes.Description
+	}
+	if source.Attributes.Fields != nil {
+		target.Fields = source.Attributes.Fields
+	}
+}
+
+// ConvertWorkItemTemplate converts between internal and external REST representation
+func ConvertWorkItemTemplate(request *goa.RequestData, t template.Template) *app.WorkItemTemplate {
+	selfURL := rest.AbsoluteURL(request, app.WorkItemTemplateHref(t.SpaceID, t.ID))
+	spaceType := space.SpaceType
+	spaceID := t.SpaceID.String()
+	spaceURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	witURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(spaceID, t.WorkItemTypeID))
+	fields := t.Fields
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	return &app.WorkItemTemplate{
+		Type: template.APIStringTypeTemplate,
+		ID:   &t.ID,
+		Attributes: &app.WorkItemTemplateAttributes{
+			Name:        &t.Name,
+			Description: &t.Description,
+			Fields:      fields,
+			CreatedAt:   &t.CreatedAt,
+			Version:     &t.Version,
+		},
+		Relationships: &app.WorkItemTemplateRelationships{
+			BaseType: &app.RelationBaseType{
+				Data: &app.BaseTypeData{
+					ID:   t.WorkItemTypeID,
+					Type: APIStringTypeWorkItemType,
+				},
+				Links: &app.GenericLinks{
+					Self: &witURL,
+				},
+			},
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &spaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Related: &spaceURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package controller_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/app"
+	"github.com/almighty/almighty-core/app/test"
+	. "github.com/almighty/almighty-core/controller"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/rendering"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	almtoken "github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/template"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemTemplateREST struct {
+	gormtestsupport.DBTestSuite
+	db       *gormapplication.GormDB
+	clean    func()
+	identity account.Identity
+	ctx      context.Context
+	svc      *goa.Service
+	ctrl     *WorkItemTemplateController
+	spaceID  uuid.UUID
+}
+
+func TestRunWorkItemTemplateREST(t *testing.T) {
+	suite.Run(t, &TestWorkItemTemplateREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestWorkItemTemplateREST) SetupTest() {
+	resource.Require(rest.T(), resource.Database)
+	rest.db = gormapplication.NewGormDB(rest.DB)
+	rest.clean = cleaner.DeleteCreatedEntities(rest.DB)
+	var err error
+	rest.identity, err = testsupport.CreateTestIdentity(rest.DB, "TestWorkItemTemplateREST user", "test provider")
+	require.Nil(rest.T(), err)
+	req := &http.Request{Host: "localhost"}
+	rest.ctx = goa.NewContext(context.Background(), nil, req, url.Values{})
+	priv, _ := almtoken.ParsePrivateKey([]byte(almtoken.RSAPrivateKey))
+	rest.svc = testsupport.ServiceAsUser("WorkItemTemplate-Service", almtoken.NewManagerWithPrivateKey(priv), rest.identity)
+	rest.ctrl = NewWorkItemTemplateController(rest.svc, rest.db)
+	testSpace, err := space.NewRepository(rest.DB).Create(rest.ctx, &space.Space{
+		Name: "TestWorkItemTemplateREST " + uuid.NewV4().String(),
+	})
+	require.Nil(rest.T(), err)
+	rest.spaceID = testSpace.ID
+}
+
+func (rest *TestWorkItemTemplateREST) TearDownTest() {
+	rest.clean()
+}
+
+func newWorkItemTemplatePayload(name string, wit uuid.UUID, fields map[string]interface{}) *app.CreateWorkItemTemplatePayload {
+	return &app.CreateWorkItemTemplatePayload{
+		Data: &app.WorkItemTemplate{
+			Type: template.APIStringTypeTemplate,
+			Attributes: &app.WorkItemTemplateAttributes{
+				Name:   &name,
+				Fields: fields,
+			},
+			Relationships: &app.WorkItemTemplateRelationships{
+				BaseType: newRelationBaseType(space.SystemSpace, wit),
+			},
+		},
+	}
+}
+
+func (rest *TestWorkItemTemplateREST) createTemplate(name string, wit uuid.UUID, fields map[string]interface{}) *app.WorkItemTemplate {
+	_, created := test.CreateWorkItemTemplateCreated(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), newWorkItemTemplatePayload(name, wit, fields))
+	return created.Data
+}
+
+func (rest *TestWorkItemTemplateREST) TestCreateShowAndList() {
+	// when
+	created := rest.createTemplate("bug report", workitem.SystemBug, map[string]interface{}{
+		workitem.SystemState: workitem.SystemStateOpen,
+	})
+	rest.createTemplate("feature request", workitem.SystemFeature, nil)
+	// then
+	require.NotNil(rest.T(), created.ID)
+	assert.Equal(rest.T(), "bug report", *created.Attributes.Name)
+	assert.Equal(rest.T(), workitem.SystemStateOpen, created.Attributes.Fields[workitem.SystemState])
+	assert.Equal(rest.T(), workitem.SystemBug, created.Relationships.BaseType.Data.ID)
+	_, shown := test.ShowWorkItemTemplateOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+	assert.Equal(rest.T(), *created.ID, *shown.Data.ID)
+	_, list := test.ListWorkItemTemplateOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), nil)
+	assert.Len(rest.T(), list.Data, 2)
+	_, list = test.ListWorkItemTemplateOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), &workitem.SystemBug)
+	require.Len(rest.T(), list.Data, 1)
+	assert.Equal(rest.T(), *created.ID, *list.Data[0].ID)
+	test.ShowWorkItemTemplateNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, space.SystemSpace.String(), *created.ID)
+}
+
+func (rest *TestWorkItemTemplateREST) TestCreateInvalid() {
+	test.CreateWorkItemTemplateBadRequest(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), newWorkItemTemplatePayload("unknown field", workitem.SystemBug, map[string]interface{}{"foo": "bar"}))
+	test.CreateWorkItemTemplateBadRequest(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), newWorkItemTemplatePayload("invalid state", workitem.SystemBug, map[string]interface{}{workitem.SystemState: "foo"}))
+	test.CreateWorkItemTemplateNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, uuid.NewV4().String(), newWorkItemTemplatePayload("unknown space", workitem.SystemBug, nil))
+	svc := goa.New("WorkItemTemplate-Service")
+	test.CreateWorkItemTemplateUnauthorized(rest.T(), svc.Context, svc, NewWorkItemTemplateController(svc, rest.db), rest.spaceID.String(), newWorkItemTemplatePayload("unauthorized", workitem.SystemBug, nil))
+}
+
+func (rest *TestWorkItemTemplateREST) TestUpdateAndDelete() {
+	// given
+	created := rest.createTemplate("bug report", workitem.SystemBug, nil)
+	name := "crash report"
+	payload := &app.UpdateWorkItemTemplatePayload{
+		Data: &app.WorkItemTemplate{
+			Type: template.APIStringTypeTemplate,
+			ID:   created.ID,
+			Attributes: &app.WorkItemTemplateAttributes{
+				Name:    &name,
+				Version: created.Attributes.Version,
+			},
+		},
+	}
+	// when
+	_, updated := test.UpdateWorkItemTemplateOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID, payload)
+	// then
+	assert.Equal(rest.T(), name, *updated.Data.Attributes.Name)
+	assert.Equal(rest.T(), *created.Attributes.Version+1, *updated.Data.Attributes.Version)
+	test.UpdateWorkItemTemplateBadRequest(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID, payload)
+	// when
+	test.DeleteWorkItemTemplateOK(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+	// then
+	test.ShowWorkItemTemplateNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+	test.DeleteWorkItemTemplateNotFound(rest.T(), rest.svc.Context, rest.svc, rest.ctrl, rest.spaceID.String(), *created.ID)
+}
+
+func (rest *TestWorkItemTemplateREST) TestCreateWorkItemWithTemplate() {
+	// given
+	created := rest.createTemplate("bug report", workitem.SystemBug, map[string]interface{}{
+		workitem.SystemState: workitem.SystemStateOpen,
+		workitem.SystemDescription: map[string]interface{}{
+			"content": "## Steps to reproduce",
+			"markup":  rendering.SystemMarkupMarkdown,
+		},
+	})
+	templateID := created.ID.String()
+	payload := minimumRequiredCreateWithType(workitem.SystemBug)
+	payload.Data.Attributes[workitem.SystemTitle] = "Crash on startup"
+	payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+	payload.Data.Relationships.Template = &app.RelationGeneric{
+		Data: &app.GenericData{ID: &templateID},
+	}
+	wiCtrl := NewWorkitemController(rest.svc, rest.db, rest.Configuration)
+	// when
+	_, wi := test.CreateWorkitemCreated(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), &payload)
+	// then the payload takes precedence over the template
+	assert.Equal(rest.T(), workitem.SystemStateNew, wi.Data.Attributes[workitem.SystemState])
+	assert.Equal(rest.T(), "## Steps to reproduce", wi.Data.Attributes[workitem.SystemDescription])
+	assert.Equal(rest.T(), rendering.SystemMarkupMarkdown, wi.Data.Attributes[workitem.SystemDescriptionMarkup])
+	// when using a template of another work item type
+	payload = minimumRequiredCreateWithType(workitem.SystemFeature)
+	payload.Data.Attributes[workitem.SystemTitle] = "New feature"
+	payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+	payload.Data.Relationships.Template = &app.RelationGeneric{
+		Data: &app.GenericData{ID: &templateID},
+	}
+	// then
+	test.CreateWorkitemBadRequest(rest.T(), rest.svc.Context, rest.svc, wiCtrl, rest.spaceID.String(), &payload)
+}
@@ -3,6 +3,8 @@ package controller
 import (
 	"fmt"
 	"html"
+	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -13,13 +15,16 @@ import (
 	"github.com/almighty/almighty-core/codebase"
 	"github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/filter"
 	"github.com/almighty/almighty-core/jsonapi"
+	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/login"
-	query "github.com/almighty/almighty-core/query/simple"
+	"github.com/almighty/almighty-core/query"
 	"github.com/almighty/almighty-core/rendering"
 	"github.com/almighty/almighty-core/rest"
 	"github.com/almighty/almighty-core/space"
 	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/link"
 
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
@@ -107,10 +112,65 @@ func (c *WorkitemController) List(ctx *app.ListWorkitemContext) error {
 		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemState), criteria.Literal(string(*ctx.FilterWorkitemstate))))
 		additionalQuery = append(additionalQuery, "filter[workitemstate]="+*ctx.FilterWorkitemstate)
 	}
+	if ctx.FilterLabel != nil {
+		var labelID uuid.UUID
+		err = application.Transactional(c.db, func(tx application.Application) error {
+			labelID, err = loadLabelID(ctx, tx, spaceID, "filter[label]", *ctx.FilterLabel)
+			return err
+		})
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		exp = criteria.And(exp, criteria.Equals(criteria.Field(workitem.SystemLabels), criteria.Literal([]string{labelID.String()})))
+		additionalQuery = append(additionalQuery, "filter[label]="+url.QueryEscape(*ctx.FilterLabel))
+	}
+	sortParam := ctx.Sort
+	if ctx.FilterSaved != nil {
+		identityID, err := optionalIdentity(ctx, ctx.RequestData)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		var saved *filter.SavedFilter
+		err = application.Transactional(c.db, func(tx application.Application) error {
+			saved, err = loadVisibleSavedFilter(ctx, tx, *ctx.FilterSaved, identityID)
+			return err
+		})
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		savedExp, err := query.Parse(&saved.Query)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("could not parse saved filter", err))
+		}
+		exp = criteria.And(exp, savedExp)
+		additionalQuery = append(additionalQuery, "filter[saved]="+ctx.FilterSaved.String())
+		// the order of the saved filter applies unless the request has its own
+		if sortParam == nil && saved.Sort != "" {
+			sortParam = &saved.Sort
+		}
+	}
+	sort, err := workitem.ParseSort(sortParam)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if len(sort) > 0 {
+		additionalQuery = append(additionalQuery, "sort="+url.QueryEscape(workitem.FormatSort(sort)))
+	}
 
+	log.Debug(ctx, map[string]interface{}{
+		"space_id": spaceID,
+		"filter":   criteria.String(exp),
+	}, "listing work items")
 	offset, limit := computePagingLimts(ctx.PageOffset, ctx.PageLimit)
 	return application.Transactional(c.db, func(tx application.Application) error {
-		workitems, tc, err := tx.WorkItems().List(ctx.Context, spaceID, exp, &offset, &limit)
+		var workitems []workitem.WorkItem
+		var tc uint64
+		var next *string
+		if ctx.PageCursor != nil {
+			workitems, tc, next, err = tx.WorkItems().ListAfter(ctx.Context, spaceID, exp, sort, ctx.PageCursor, limit)
+		} else {
+			workitems, tc, err = tx.WorkItems().List(ctx.Context, spaceID, exp, sort, &offset, &limit)
+		}
 		count := int(tc)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, "Error listing work items"))
@@ -121,7 +181,11 @@ func (c *WorkitemController) List(ctx *app.ListWorkitemContext) error {
 				Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
 				Data:  ConvertWorkItems(ctx.RequestData, workitems),
 			}
-			setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(workitems), offset, limit, count, additionalQuery...)
+			if ctx.PageCursor != nil {
+				setCursorPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), limit, next, additionalQuery...)
+			} else {
+				setPagingLinks(response.Links, buildAbsoluteURL(ctx.RequestData), len(workitems), offset, limit, count, additionalQuery...)
+			}
 			addFilterLinks(response.Links, ctx.RequestData)
 			return ctx.OK(&response)
 		})
@@ -145,7 +209,11 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 		if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.ID == nil {
 			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data.ID element in request", nil))
 		}
-		wi, err := appl.WorkItems().Load(ctx, spaceID, *ctx.Payload.Data.ID)
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, *ctx.Payload.Data.ID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Failed to load work item with id %v", *ctx.Payload.Data.ID)))
+		}
+		wi, err := appl.WorkItems().Load(ctx, spaceID, wiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Failed to load work item with id %v", *ctx.Payload.Data.ID)))
 		}
@@ -217,6 +285,133 @@ func (c *WorkitemController) Reorder(ctx *app.ReorderWorkitemContext) error {
 	})
 }
 
+// maxBulkUpdateWorkItems is the maximum number of work items that can be changed by a single bulk update
+const maxBulkUpdateWorkItems = 500
+
+// errBulkUpdateFailed rolls back the transaction of a bulk update in which some work items could not be updated
+var errBulkUpdateFailed = errs.New("bulk update failed")
+
+// BulkUpdate does PATCH workitem/bulk
+func (c *WorkitemController) BulkUpdate(ctx *app.BulkUpdateWorkitemContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	data := ctx.Payload.Data
+	if (len(data.Items) == 0) == (data.Filter == nil) {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data", "items and filter").Expected("either items or a filter"))
+	}
+	if len(data.Items) > maxBulkUpdateWorkItems {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.items", len(data.Items)).Expected(fmt.Sprintf("at most %d work items", maxBulkUpdateWorkItems)))
+	}
+	var updated []workitem.WorkItem
+	var failures []*app.JSONAPIError
+	// errors are returned from the transaction so that a single failing work item rolls back the whole update
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		items := data.Items
+		if data.Filter != nil {
+			items, err = bulkUpdateFilterItems(ctx, appl, spaceID, *data.Filter)
+			if err != nil {
+				return err
+			}
+		}
+		for i, item := range items {
+			wi, err := bulkUpdateWorkItem(ctx, appl, spaceID, *item, data, *currentUserIdentityID)
+			if err == nil {
+				updated = append(updated, *wi)
+				continue
+			}
+			jerr, status := jsonapi.ErrorToJSONAPIError(err)
+			if status == http.StatusInternalServerError {
+				return err
+			}
+			jerr.Meta = map[string]interface{}{"workitem_id": item.ID}
+			if data.Filter == nil {
+				jerr.Source = map[string]interface{}{"pointer": fmt.Sprintf("/data/items/%d", i)}
+			}
+			failures = append(failures, &jerr)
+		}
+		if len(failures) > 0 {
+			return errBulkUpdateFailed
+		}
+		return nil
+	})
+	if err == errBulkUpdateFailed {
+		return ctx.BadRequest(&app.JSONAPIErrors{Errors: failures})
+	}
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorkItemList{
+		Data: ConvertWorkItems(ctx.RequestData, updated),
+		Meta: &app.WorkItemListResponseMeta{TotalCount: len(updated)},
+	})
+}
+
+// bulkUpdateFilterItems returns the work items of the space matching the given filter of a bulk update, at
+// their current version
+func bulkUpdateFilterItems(ctx context.Context, appl application.Application, spaceID uuid.UUID, filter string) ([]*app.WorkItemBulkUpdateItem, error) {
+	exp, err := query.Parse(&filter)
+	if err != nil {
+		return nil, errors.NewBadParameterError("could not parse filter", err)
+	}
+	start, limit := 0, maxBulkUpdateWorkItems+1
+	wis, _, err := appl.WorkItems().List(ctx, spaceID, exp, nil, &start, &limit)
+	if err != nil {
+		return nil, errs.Wrap(err, "Error listing work items")
+	}
+	if len(wis) > maxBulkUpdateWorkItems {
+		return nil, errors.NewBadParameterError("data.filter", filter).Expected(fmt.Sprintf("a filter matching at most %d work items", maxBulkUpdateWorkItems))
+	}
+	items := make([]*app.WorkItemBulkUpdateItem, len(wis))
+	for i, wi := range wis {
+		version := wi.Version
+		items[i] = &app.WorkItemBulkUpdateItem{ID: wi.ID, Version: &version}
+	}
+	return items, nil
+}
+
+// bulkUpdateWorkItem applies the changes of a bulk update to a single work item
+func bulkUpdateWorkItem(ctx context.Context, appl application.Application, spaceID uuid.UUID, item app.WorkItemBulkUpdateItem, data *app.WorkItemBulkUpdateData, modifierID uuid.UUID) (*workitem.WorkItem, error) {
+	wiID, err := resolveWorkItemID(ctx, appl, spaceID, item.ID)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("Failed to load work item with id %v", item.ID))
+	}
+	wi, err := appl.WorkItems().Load(ctx, spaceID, wiID)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("Failed to load work item with id %v", item.ID))
+	}
+	version := wi.Version
+	if item.Version != nil {
+		version = *item.Version
+	}
+	source := app.WorkItem{
+		Attributes:    map[string]interface{}{"version": version},
+		Relationships: data.Relationships,
+	}
+	for key, val := range data.Attributes {
+		if key != "version" {
+			source.Attributes[key] = val
+		}
+	}
+	// Type changes of WI are not allowed, just like with a single update
+	oldType := wi.Type
+	err = ConvertJSONAPIToWorkItem(appl, source, wi)
+	if err != nil {
+		return nil, err
+	}
+	wi.Type = oldType
+	wi, err = appl.WorkItems().Save(ctx, spaceID, *wi, modifierID)
+	if err != nil {
+		return nil, errs.Wrap(err, "Error updating work item")
+	}
+	return wi, nil
+}
+
 // Create does POST workitem
 func (c *WorkitemController) Create(ctx *app.CreateWorkitemContext) error {
 	spaceID, err := uuid.FromString(ctx.ID)
@@ -260,6 +455,14 @@ func (c *WorkitemController) Create(ctx *app.CreateWorkitemContext) error {
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Error creating work item")))
 		}
+		// the fields of the payload take precedence over the ones pre-filled by the template
+		if ctx.Payload.Data.Relationships.Template != nil {
+			t, witModel, err := loadWorkItemTemplate(ctx, appl, spaceID, *wit, ctx.Payload.Data.Relationships.Template)
+			if err != nil {
+				return jsonapi.JSONErrorResponse(ctx, err)
+			}
+			t.Apply(*witModel, wi.Fields)
+		}
 		wi, err := appl.WorkItems().Create(ctx, spaceID, *wit, wi.Fields, *currentUserIdentityID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Error creating work item")))
@@ -285,8 +488,17 @@ func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	}
 
 	return application.Transactional(c.db, func(appl application.Application) error {
-		comments := WorkItemIncludeCommentsAndTotal(ctx, c.db, ctx.WiID)
-		wi, err := appl.WorkItems().Load(ctx, spaceID, ctx.WiID)
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Fail to load work item with id %v", ctx.WiID)))
+		}
+		comments := WorkItemIncludeCommentsAndTotal(ctx, c.db, wiID)
+		var wi *workitem.WorkItem
+		if ctx.At != nil || ctx.Version != nil {
+			wi, err = loadWorkItemRevision(ctx, appl, spaceID, wiID, ctx.At, ctx.Version)
+		} else {
+			wi, err = appl.WorkItems().Load(ctx, spaceID, wiID)
+		}
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrap(err, fmt.Sprintf("Fail to load work item with id %v", ctx.WiID)))
 		}
@@ -301,6 +513,53 @@ func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	})
 }
 
+// resolveWorkItemID returns the ID of the work item identified by the given ID or human-readable
+// key, e.g. "PLAT-42", in the given space
+func resolveWorkItemID(ctx context.Context, appl application.Application, spaceID uuid.UUID, idOrKey string) (string, error) {
+	if _, ok := workitem.ParseKey(idOrKey); !ok {
+		return idOrKey, nil
+	}
+	wi, err := appl.WorkItems().LoadByKey(ctx, spaceID, idOrKey)
+	if err != nil {
+		return "", errs.WithStack(err)
+	}
+	return wi.ID, nil
+}
+
+// loadWorkItemByIDOrKey returns the work item identified by the given ID or human-readable key.
+// Keys are resolved in the space with the given ID.
+func loadWorkItemByIDOrKey(ctx context.Context, appl application.Application, spaceID string, idOrKey string) (*workitem.WorkItem, error) {
+	if _, ok := workitem.ParseKey(idOrKey); !ok {
+		return appl.WorkItems().LoadByID(ctx, idOrKey)
+	}
+	spaceUUID, err := uuid.FromString(spaceID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("spaceID", spaceID)
+	}
+	return appl.WorkItems().LoadByKey(ctx, spaceUUID, idOrKey)
+}
+
+// loadWorkItemRevision returns the work item as it was at the given time or in the given version
+func loadWorkItemRevision(ctx context.Context, appl application.Application, spaceID uuid.UUID, wiID string, at *time.Time, version *int) (*workitem.WorkItem, error) {
+	if at != nil && version != nil {
+		return nil, errors.NewBadParameterError("version", *version).Expected("no version when 'at' is given")
+	}
+	wi, err := appl.WorkItems().Load(ctx, spaceID, wiID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	var revision *workitem.Revision
+	if at != nil {
+		revision, err = appl.WorkItemRevisions().LoadAt(ctx, wi.ID, *at)
+	} else {
+		revision, err = appl.WorkItemRevisions().LoadVersion(ctx, wi.ID, *version)
+	}
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return appl.WorkItems().LoadRevision(ctx, spaceID, *revision)
+}
+
 // Delete does DELETE workitem
 func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 
@@ -318,11 +577,15 @@ func (c *WorkitemController) Delete(ctx *app.DeleteWorkitemContext) error {
 		return ctx.Unauthorized(jerrors)
 	}
 	return application.Transactional(c.db, func(appl application.Application) error {
-		err := appl.WorkItems().Delete(ctx, spaceID, ctx.WiID, *currentUserIdentityID)
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, ctx.WiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "error deleting work item %s", ctx.WiID))
 		}
-		if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
+		err = appl.WorkItems().Delete(ctx, spaceID, wiID, *currentUserIdentityID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "error deleting work item %s", ctx.WiID))
+		}
+		if err := appl.WorkItemLinks().DeleteRelatedLinks(ctx, wiID, *currentUserIdentityID); err != nil {
 			return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to delete work item links related to work item %s", ctx.WiID))
 		}
 		return ctx.OK([]byte{})
@@ -415,6 +678,33 @@ func ConvertJSONAPIToWorkItem(appl application.Application, source app.WorkItem,
 			target.Fields[workitem.SystemArea] = areaUUID.String()
 		}
 	}
+	if source.Relationships != nil && source.Relationships.Labels != nil {
+		if source.Relationships.Labels.Data == nil {
+			delete(target.Fields, workitem.SystemLabels)
+		} else {
+			// the labels belong to the space of the work item, which is only given by the
+			// relationship for new work items
+			spaceID := target.SpaceID
+			if spaceID == uuid.Nil && source.Relationships.Space != nil && source.Relationships.Space.Data != nil && source.Relationships.Space.Data.ID != nil {
+				spaceID = *source.Relationships.Space.Data.ID
+			}
+			var ids []string
+			for _, d := range source.Relationships.Labels.Data {
+				if d.ID == nil {
+					return errors.NewBadParameterError("data.relationships.labels.data.id", nil)
+				}
+				labelUUID, err := uuid.FromString(*d.ID)
+				if err != nil {
+					return errors.NewBadParameterError("data.relationships.labels.data.id", *d.ID)
+				}
+				if _, err = appl.Labels().Load(context.Background(), spaceID, labelUUID); err != nil {
+					return errors.NewBadParameterError("data.relationships.labels.data.id", *d.ID).Expected("a label of the space")
+				}
+				ids = append(ids, labelUUID.String())
+			}
+			target.Fields[workitem.SystemLabels] = ids
+		}
+	}
 	if source.Relationships != nil && source.Relationships.BaseType != nil {
 		if source.Relationships.BaseType.Data != nil {
 			target.Type = source.Relationships.BaseType.Data.ID
@@ -423,7 +713,10 @@ func ConvertJSONAPIToWorkItem(appl application.Application, source app.WorkItem,
 
 	for key, val := range source.Attributes {
 		// convert legacy description to markup content
-		if key == workitem.SystemDescription {
+		if key == workitem.SystemKey {
+			// the key is given by the space when the work item is created and never changes
+			continue
+		} else if key == workitem.SystemDescription {
 			if m := rendering.NewMarkupContentFromValue(val); m != nil {
 				target.Fields[key] = *m
 			}
@@ -485,7 +778,8 @@ func ConvertWorkItem(request *goa.RequestData, wi workitem.WorkItem, additional
 		ID:   &wi.ID,
 		Type: APIStringTypeWorkItem,
 		Attributes: map[string]interface{}{
-			"version": wi.Version,
+			"version":          wi.Version,
+			workitem.SystemKey: wi.Key,
 		},
 		Relationships: &app.WorkItemRelationships{
 			BaseType: &app.RelationBaseType{
@@ -538,6 +832,13 @@ func ConvertWorkItem(request *goa.RequestData, wi workitem.WorkItem, additional
 					Data: ConvertAreaSimple(request, valStr),
 				}
 			}
+		case workitem.SystemLabels:
+			if val != nil {
+				valArr := val.([]interface{})
+				op.Relationships.Labels = &app.RelationGenericList{
+					Data: ConvertLabelsSimple(request, wi.SpaceID, valArr),
+				}
+			}
 
 		case workitem.SystemTitle:
 			// 'HTML escape' the title to prevent script injection
@@ -573,6 +874,9 @@ func ConvertWorkItem(request *goa.RequestData, wi workitem.WorkItem, additional
 	if op.Relationships.Area == nil {
 		op.Relationships.Area = &app.RelationGeneric{Data: nil}
 	}
+	if op.Relationships.Labels == nil {
+		op.Relationships.Labels = &app.RelationGenericList{Data: nil}
+	}
 	// Always include Comments Link, but optionally use WorkItemIncludeCommentsAndTotal
 	WorkItemIncludeComments(request, &wi, op)
 	WorkItemIncludeChildren(request, &wi, op)
@@ -587,8 +891,16 @@ func (c *WorkitemController) ListChildren(ctx *app.ListChildrenWorkitemContext)
 	// WorkItemChildrenController_List: start_implement
 
 	// Put your logic here
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
 	return application.Transactional(c.db, func(appl application.Application) error {
-		result, err := appl.WorkItemLinks().ListWorkItemChildren(ctx, ctx.WiID)
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
+		}
+		result, err := appl.WorkItemLinks().ListWorkItemChildren(ctx, wiID)
 		if err != nil {
 			return jsonapi.JSONErrorResponse(ctx, goa.ErrNotFound(err.Error()))
 		}
@@ -601,6 +913,44 @@ func (c *WorkitemController) ListChildren(ctx *app.ListChildrenWorkitemContext)
 	})
 }
 
+// Traverse runs the traverse action.
+func (c *WorkitemController) Traverse(ctx *app.TraverseWorkitemContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	exp, err := query.Parse(ctx.Filter)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("could not parse filter", err))
+	}
+	direction := link.TraversalForward
+	if ctx.Direction != nil {
+		direction = link.TraversalDirection(*ctx.Direction)
+	}
+	return application.Transactional(c.db, func(appl application.Application) error {
+		wiID, err := resolveWorkItemID(ctx, appl, spaceID, ctx.WiID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		nodes, err := appl.WorkItemLinks().Traverse(ctx, spaceID, wiID, ctx.LinkType, direction, ctx.Depth, exp)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+		response := app.TraversedWorkItemList{
+			Data: make([]*app.TraversedWorkItem, len(nodes)),
+			Meta: &app.WorkItemListResponseMeta{TotalCount: len(nodes)},
+		}
+		for i, node := range nodes {
+			response.Data[i] = &app.TraversedWorkItem{
+				Depth:    node.Depth,
+				Path:     node.Path,
+				Workitem: ConvertWorkItem(ctx.RequestData, node.WorkItem),
+			}
+		}
+		return ctx.OK(&response)
+	})
+}
+
 // WorkItemIncludeChildren adds relationship about children to workitem (include totalCount)
 func WorkItemIncludeChildren(request *goa.RequestData, wi *workitem.WorkItem, wi2 *app.WorkItem) {
 	childrenRelated := rest.AbsoluteURL(request, app.WorkitemHref(wi.SpaceID, wi.ID)) + "/children"
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"html"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -98,7 +99,7 @@ func (s *WorkItemSuite) SetupTest() {
 
 func (s *WorkItemSuite) TestGetWorkItemWithLegacyDescription() {
 	// given
-	_, wi := test.ShowWorkitemOK(s.T(), nil, nil, s.controller, s.wi.Relationships.Space.Data.ID.String(), *s.wi.ID, nil, nil)
+	_, wi := test.ShowWorkitemOK(s.T(), nil, nil, s.controller, s.wi.Relationships.Space.Data.ID.String(), *s.wi.ID, nil, nil, nil, nil)
 	require.NotNil(s.T(), wi)
 	assert.Equal(s.T(), s.wi.ID, wi.Data.ID)
 	assert.NotNil(s.T(), wi.Data.Attributes[workitem.SystemCreatedAt])
@@ -350,17 +351,98 @@ func (s *WorkItemSuite) TestListByFields() {
 	filter := "{\"system.title\":\"run integration test\"}"
 	offset := "0"
 	limit := 1
-	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, payload.Data.Relationships.Space.Data.ID.String(), &filter, nil, nil, nil, nil, nil, &limit, &offset, nil, nil)
+	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, payload.Data.Relationships.Space.Data.ID.String(), &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), result)
 	require.Equal(s.T(), 1, len(result.Data))
 	// when
 	filter = fmt.Sprintf("{\"system.creator\":\"%s\"}", s.testIdentity.ID.String())
 	// then
-	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, payload.Data.Relationships.Space.Data.ID.String(), &filter, nil, nil, nil, nil, nil, &limit, &offset, nil, nil)
+	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, payload.Data.Relationships.Space.Data.ID.String(), &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
 	require.NotNil(s.T(), result)
 	require.Equal(s.T(), 1, len(result.Data))
+	// when
+	filter = fmt.Sprintf(`system.title = "run integration test" AND (system.state = "%s" OR NOT system.creator = "%s")`, workitem.SystemStateClosed, s.testIdentity.ID.String())
+	// then
+	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, payload.Data.Relationships.Space.Data.ID.String(), &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
+	require.NotNil(s.T(), result)
+	require.Equal(s.T(), 1, len(result.Data))
+}
+
+func (s *WorkItemSuite) TestListByInvalidFilter() {
+	// given
+	payload := minimumRequiredCreateWithType(workitem.SystemBug)
+	filter := `system.title = "run integration test" AND`
+	// when/then
+	test.ListWorkitemBadRequest(s.T(), nil, nil, s.controller, payload.Data.Relationships.Space.Data.ID.String(), &filter, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+}
+
+func (s *WorkItemSuite) TestListSorted() {
+	// given
+	marker := uuid.NewV4().String()
+	for _, title := range []string{"b", "a"} {
+		payload := minimumRequiredCreateWithType(workitem.SystemBug)
+		payload.Data.Attributes[workitem.SystemTitle] = marker + " " + title
+		payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+		test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.controller, space.SystemSpace.String(), &payload)
+	}
+	filter := fmt.Sprintf(`system.title CONTAINS "%s"`, marker)
+	sort := "system.title"
+	offset := "0"
+	limit := 1
+	// when
+	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, space.SystemSpace.String(), &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, &sort, nil, nil)
+	// then
+	require.Len(s.T(), result.Data, 1)
+	assert.Equal(s.T(), marker+" a", result.Data[0].Attributes[workitem.SystemTitle])
+	require.NotNil(s.T(), result.Links.Next)
+	assert.Contains(s.T(), *result.Links.Next, "sort=system.title")
+	// when
+	sort = "-system.title"
+	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, space.SystemSpace.String(), &filter, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, &sort, nil, nil)
+	// then
+	require.Len(s.T(), result.Data, 1)
+	assert.Equal(s.T(), marker+" b", result.Data[0].Attributes[workitem.SystemTitle])
+	// when/then
+	sort = "system.title,"
+	test.ListWorkitemBadRequest(s.T(), nil, nil, s.controller, space.SystemSpace.String(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &sort, nil, nil)
+}
+
+func (s *WorkItemSuite) TestListWithCursor() {
+	// given
+	marker := uuid.NewV4().String()
+	for _, title := range []string{"b", "a"} {
+		payload := minimumRequiredCreateWithType(workitem.SystemBug)
+		payload.Data.Attributes[workitem.SystemTitle] = marker + " " + title
+		payload.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+		test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.controller, space.SystemSpace.String(), &payload)
+	}
+	filter := fmt.Sprintf(`system.title CONTAINS "%s"`, marker)
+	sort := "system.title"
+	cursor := ""
+	limit := 1
+	// when
+	_, result := test.ListWorkitemOK(s.T(), nil, nil, s.controller, space.SystemSpace.String(), &filter, nil, nil, nil, nil, nil, nil, nil, &cursor, &limit, nil, &sort, nil, nil)
+	// then
+	require.Len(s.T(), result.Data, 1)
+	assert.Equal(s.T(), marker+" a", result.Data[0].Attributes[workitem.SystemTitle])
+	assert.Equal(s.T(), 2, result.Meta.TotalCount)
+	require.NotNil(s.T(), result.Links.Next)
+	next, err := url.Parse(*result.Links.Next)
+	require.Nil(s.T(), err)
+	cursor = next.Query().Get("page[cursor]")
+	require.NotEmpty(s.T(), cursor)
+	// when
+	_, result = test.ListWorkitemOK(s.T(), nil, nil, s.controller, space.SystemSpace.String(), &filter, nil, nil, nil, nil, nil, nil, nil, &cursor, &limit, nil, &sort, nil, nil)
+	// then
+	require.Len(s.T(), result.Data, 1)
+	assert.Equal(s.T(), marker+" b", result.Data[0].Attributes[workitem.SystemTitle])
+	assert.Nil(s.T(), result.Links.Next)
+	// when/then
+	cursor = "invalid"
+	test.ListWorkitemBadRequest(s.T(), nil, nil, s.controller, space.SystemSpace.String(), &filter, nil, nil, nil, nil, nil, nil, nil, &cursor, &limit, nil, &sort, nil, nil)
 }
+
 func getWorkItemTestDataFunc(config configuration.ConfigurationData) func(t *testing.T) []testSecureAPI {
 	return func(t *testing.T) []testSecureAPI {
 		privatekey, err := jwt.ParseRSAPrivateKeyFromPEM(config.GetTokenPrivateKey())
@@ -507,7 +589,7 @@ func createPagingTest(t *testing.T, ctx context.Context, controller *WorkitemCon
 		repo.ListReturns(makeWorkItems(count), uint64(totalCount), nil)
 		offset := strconv.Itoa(start)
 
-		_, response := test.ListWorkitemOK(t, ctx, nil, controller, spaceID, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil)
+		_, response := test.ListWorkitemOK(t, ctx, nil, controller, spaceID, nil, nil, nil, nil, nil, nil, nil, nil, nil, &limit, &offset, nil, nil, nil)
 		assertLink(t, "first", first, response.Links.First)
 		assertLink(t, "last", last, response.Links.Last)
 		assertLink(t, "prev", prev, response.Links.Prev)
@@ -1201,7 +1283,7 @@ func (s *WorkItem2Suite) TestWI2ListByAssigneeFilter() {
 	assert.Len(s.T(), wi.Data.Relationships.Assignees.Data, 1)
 	assert.Equal(s.T(), newUser.ID.String(), *wi.Data.Relationships.Assignees.Data[0].ID)
 	newUserID := newUser.ID.String()
-	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), nil, nil, &newUserID, nil, nil, nil, nil, nil, nil, nil)
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), nil, nil, &newUserID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Len(s.T(), list.Data, 1)
 	assert.Equal(s.T(), newUser.ID.String(), *list.Data[0].Relationships.Assignees.Data[0].ID)
 	assert.True(s.T(), strings.Contains(*list.Links.First, "filter[assignee]"))
@@ -1219,7 +1301,7 @@ func (s *WorkItem2Suite) TestWI2ListByWorkitemtypeFilter() {
 	assert.NotNil(s.T(), expected.Data)
 	require.NotNil(s.T(), expected.Data.ID)
 	require.NotNil(s.T(), expected.Data.Type)
-	_, actual := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, nil, nil, &workitem.SystemBug, nil, nil, nil, nil)
+	_, actual := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, nil, nil, nil, nil, &workitem.SystemBug, nil, nil, nil, nil, nil, nil)
 	require.NotNil(s.T(), actual)
 	require.True(s.T(), len(actual.Data) > 1)
 	assert.Contains(s.T(), *actual.Links.First, fmt.Sprintf("filter[workitemtype]=%s", workitem.SystemBug))
@@ -1252,7 +1334,7 @@ func (s *WorkItem2Suite) TestWI2ListByWorkitemstateFilter() {
 	dataArray = append(dataArray, expected)
 	wiNew := workitem.SystemStateNew
 	// var foundExpected bool
-	_, actual := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), nil, nil, nil, nil, &wiNew, nil, nil, nil, nil, nil)
+	_, actual := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), nil, nil, nil, nil, nil, nil, &wiNew, nil, nil, nil, nil, nil, nil, nil)
 
 	require.NotNil(s.T(), actual)
 	require.True(s.T(), len(actual.Data) > 1)
@@ -1304,7 +1386,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOK() {
 	// given
 	spaceID, areaID, _ := s.setupAreaWorkItem(true)
 	// when
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	// then
 	assertAreaWorkItems(s.T(), areaID, workitems)
 	assertResponseHeaders(s.T(), res)
@@ -1314,7 +1396,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOKEmptyList() {
 	// given
 	spaceID, areaID, _ := s.setupAreaWorkItem(false)
 	// when
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), *workitems)
 	require.Empty(s.T(), workitems.Data)
@@ -1330,7 +1412,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOKUsingExpiredIfModifiedSinceHea
 	// when
 	updatedAt := wi.Data.Attributes[workitem.SystemUpdatedAt].(time.Time)
 	ifModifiedSince := app.ToHTTPTime(updatedAt.Add(-1 * time.Hour))
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
 	// then
 	assertAreaWorkItems(s.T(), areaID, workitems)
 	assertResponseHeaders(s.T(), res)
@@ -1341,7 +1423,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterOKUsingExpiredIfNoneMatchHeader(
 	spaceID, areaID, _ := s.setupAreaWorkItem(true)
 	// when
 	ifNoneMatch := "foo"
-	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
+	res, workitems := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
 	// then
 	assertAreaWorkItems(s.T(), areaID, workitems)
 	assertResponseHeaders(s.T(), res)
@@ -1353,7 +1435,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterNotModifiedUsingIfModifiedSinceH
 	// when
 	updatedAt := wi.Data.Attributes[workitem.SystemUpdatedAt].(time.Time)
 	ifModifiedSince := app.ToHTTPTime(updatedAt)
-	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
+	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifModifiedSince, nil)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1363,7 +1445,7 @@ func (s *WorkItem2Suite) TestWI2ListByAreaFilterNotModifiedUsingIfNoneMatchHeade
 	spaceID, areaID, wi := s.setupAreaWorkItem(true)
 	// when
 	ifNoneMatch := app.GenerateEntityTag(convertWorkItemToConditionalResponseEntity(*wi))
-	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
+	res := test.ListWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID, nil, &areaID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, &ifNoneMatch)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1389,7 +1471,7 @@ func (s *WorkItem2Suite) TestWI2ListByIterationFilter() {
 	require.NotNil(s.T(), wi.Data.Relationships.Iteration)
 	assert.Equal(s.T(), iterationID, *wi.Data.Relationships.Iteration.Data.ID)
 
-	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), nil, nil, nil, &iterationID, nil, nil, nil, nil, nil, nil)
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), nil, nil, nil, &iterationID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 1)
 	assert.Equal(s.T(), iterationID, *list.Data[0].Relationships.Iteration.Data.ID)
 	assert.True(s.T(), strings.Contains(*list.Links.First, "filter[iteration]"))
@@ -1466,12 +1548,53 @@ func (s *WorkItem2Suite) TestWI2ShowOK() {
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
 	// when
-	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	// then
 	assertSingleWorkItem(s.T(), *createdWI, *fetchedWI)
 	assertResponseHeaders(s.T(), res)
 }
 
+func (s *WorkItem2Suite) TestWI2ShowByKey() {
+	// given
+	c := minimumRequiredCreatePayload()
+	c.Data.Attributes[workitem.SystemTitle] = "Title"
+	c.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
+	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
+	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
+	key, ok := createdWI.Data.Attributes[workitem.SystemKey].(string)
+	require.True(s.T(), ok)
+	require.NotEmpty(s.T(), key)
+	// when
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), strings.ToLower(key), nil, nil, nil, nil)
+	// then
+	assert.Equal(s.T(), *createdWI.Data.ID, *fetchedWI.Data.ID)
+	assert.Equal(s.T(), key, fetchedWI.Data.Attributes[workitem.SystemKey])
+	// the key cannot be changed
+	u := minimumRequiredUpdatePayload()
+	u.Data.ID = createdWI.Data.ID
+	u.Data.Attributes["version"] = fetchedWI.Data.Attributes["version"]
+	u.Data.Attributes[workitem.SystemKey] = "FOO-1"
+	_, updatedWI := test.UpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), *createdWI.Data.ID, &u)
+	assert.Equal(s.T(), key, updatedWI.Data.Attributes[workitem.SystemKey])
+	// unknown keys are not found
+	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), "NOPE-1", nil, nil, nil, nil)
+}
+
+func (s *WorkItem2Suite) TestWI2UpdateByKey() {
+	// given
+	key, ok := s.wi.Attributes[workitem.SystemKey].(string)
+	require.True(s.T(), ok)
+	s.minimumPayload.Data.ID = &key
+	s.minimumPayload.Data.Attributes[workitem.SystemTitle] = "Updated by key"
+	// when
+	_, updatedWI := test.UpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, s.wi.Relationships.Space.Data.ID.String(), key, s.minimumPayload)
+	// then
+	assert.Equal(s.T(), *s.wi.ID, *updatedWI.Data.ID)
+	assert.Equal(s.T(), "Updated by key", updatedWI.Data.Attributes[workitem.SystemTitle])
+	// children are listed by key too
+	test.ListChildrenWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, s.wi.Relationships.Space.Data.ID.String(), key, nil, nil)
+}
+
 func (s *WorkItem2Suite) TestWI2ShowOKUsingExpiredIfModifiedSinceHeader() {
 	// given
 	c := minimumRequiredCreatePayload()
@@ -1481,7 +1604,7 @@ func (s *WorkItem2Suite) TestWI2ShowOKUsingExpiredIfModifiedSinceHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
 	// when
 	ifModifiedSince := app.ToHTTPTime(createdWI.Data.Attributes[workitem.SystemUpdatedAt].(time.Time).Add(-10 * time.Hour))
-	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, &ifModifiedSince, nil)
+	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, &ifModifiedSince, nil)
 	// then
 	assertSingleWorkItem(s.T(), *createdWI, *fetchedWI)
 	assertResponseHeaders(s.T(), res)
@@ -1496,7 +1619,7 @@ func (s *WorkItem2Suite) TestWI2ShowOKUsingExpiredIfNoneMatchHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
 	// when
 	ifNoneMatch := "foo"
-	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, &ifNoneMatch)
+	res, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, &ifNoneMatch)
 	// then
 	assertSingleWorkItem(s.T(), *createdWI, *fetchedWI)
 	assertResponseHeaders(s.T(), res)
@@ -1511,7 +1634,7 @@ func (s *WorkItem2Suite) TestWI2ShowNotModifiedUsingIfModifiedSinceHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
 	// when
 	ifModifiedSince := app.ToHTTPTime(createdWI.Data.Attributes[workitem.SystemUpdatedAt].(time.Time))
-	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, &ifModifiedSince, nil)
+	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, &ifModifiedSince, nil)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1525,7 +1648,7 @@ func (s *WorkItem2Suite) TestWI2ShowNotModifiedUsingIfNoneMatchHeader() {
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
 	// when
 	ifNoneMatch := app.GenerateEntityTag(convertWorkItemToConditionalResponseEntity(*createdWI))
-	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, &ifNoneMatch)
+	res := test.ShowWorkitemNotModified(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, &ifNoneMatch)
 	// then
 	assertResponseHeaders(s.T(), res)
 }
@@ -1549,7 +1672,7 @@ func assertResponseHeaders(t *testing.T, res http.ResponseWriter) {
 
 // Temporarly disabled, See https://github.com/almighty/almighty-core/issues/1036
 func (s *WorkItem2Suite) xTestWI2FailShowMissing() {
-	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), "00000000", nil, nil)
+	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), "00000000", nil, nil, nil, nil)
 }
 
 // Temporarly disabled, See https://github.com/almighty/almighty-core/issues/1036
@@ -1560,7 +1683,7 @@ func (s *WorkItem2Suite) TestWI2FailOnDelete() {
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
-	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	test.DeleteWorkitemMethodNotAllowed(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID)
 }
 
@@ -1572,9 +1695,9 @@ func (s *WorkItem2Suite) xTestWI2SuccessDelete() {
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
-	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	test.DeleteWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID)
-	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	test.ShowWorkitemNotFound(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 }
 
 // TestWI2DeleteLinksOnWIDeletionOK creates two work items (WI1 and WI2) and
@@ -1623,7 +1746,7 @@ func (s *WorkItem2Suite) xTestWI2DeleteLinksOnWIDeletionOK() {
 	test.ShowWorkItemLinkNotFound(s.T(), s.svc.Context, s.svc, s.linkCtrl, *workItemLink.Data.ID)
 
 	// Check that we can query for wi2 without problems
-	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, wi2.Data.Relationships.Space.Data.ID.String(), *wi2.Data.ID, nil, nil)
+	test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, wi2.Data.Relationships.Space.Data.ID.String(), *wi2.Data.ID, nil, nil, nil, nil)
 }
 
 // Temporarly disabled, See https://github.com/almighty/almighty-core/issues/1036
@@ -1842,7 +1965,7 @@ func (s *WorkItem2Suite) TestWI2SuccessCreateAndPreventJavascriptInjectionWithLe
 	c.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
 	assert.Equal(s.T(), html.EscapeString(title), fetchedWI.Data.Attributes[workitem.SystemTitle])
@@ -1858,7 +1981,7 @@ func (s *WorkItem2Suite) TestWI2SuccessCreateAndPreventJavascriptInjectionWithPl
 	c.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
 	assert.Equal(s.T(), html.EscapeString(title), fetchedWI.Data.Attributes[workitem.SystemTitle])
@@ -1874,7 +1997,7 @@ func (s *WorkItem2Suite) TestWI2SuccessCreateAndPreventJavascriptInjectionWithMa
 	c.Data.Attributes[workitem.SystemState] = workitem.SystemStateNew
 	c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	require.NotNil(s.T(), fetchedWI.Data)
 	require.NotNil(s.T(), fetchedWI.Data.Attributes)
 	assert.Equal(s.T(), html.EscapeString(title), fetchedWI.Data.Attributes[workitem.SystemTitle])
@@ -1902,7 +2025,7 @@ func (s *WorkItem2Suite) TestCreateWIWithCodebase() {
 	c.Data.Attributes[workitem.SystemCodebase] = cbase.ToMap()
 	_, createdWI := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, c.Data.Relationships.Space.Data.ID.String(), &c)
 	require.NotNil(t, createdWI)
-	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil)
+	_, fetchedWI := test.ShowWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, createdWI.Data.Relationships.Space.Data.ID.String(), *createdWI.Data.ID, nil, nil, nil, nil)
 	require.NotNil(t, fetchedWI.Data)
 	require.NotNil(t, fetchedWI.Data.Attributes)
 	assert.Equal(t, title, fetchedWI.Data.Attributes[workitem.SystemTitle])
@@ -2012,7 +2135,7 @@ func (s *WorkItem2Suite) xTestWI2IfModifiedSince() {
 			return nil
 		}
 	})
-	test.ShowWorkitemNotModified(t, s.svc.Context, s.svc, s.wi2Ctrl, wi.Data.Relationships.Space.Data.ID.String(), *wi.Data.ID, nil, nil)
+	test.ShowWorkitemNotModified(t, s.svc.Context, s.svc, s.wi2Ctrl, wi.Data.Relationships.Space.Data.ID.String(), *wi.Data.ID, nil, nil, nil, nil)
 }
 
 func (s *WorkItem2Suite) TestWI2ListForChildIteration() {
@@ -2083,15 +2206,15 @@ func (s *WorkItem2Suite) TestWI2ListForChildIteration() {
 	}
 
 	// list workitems for grandParentIteration
-	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, &grandParentIterationID, nil, nil, nil, nil, nil, nil)
+	_, list := test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, &grandParentIterationID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 7)
 
 	// list workitems for parentIteration
-	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, &parentIterationID, nil, nil, nil, nil, nil, nil)
+	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, &parentIterationID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 4)
 
 	// list workitems for childIteraiton
-	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, &childIteraitonID, nil, nil, nil, nil, nil, nil)
+	_, list = test.ListWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), nil, nil, nil, &childIteraitonID, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	require.Len(s.T(), list.Data, 2)
 }
 
@@ -2104,3 +2227,143 @@ func convertWorkItemToConditionalResponseEntity(appWI app.WorkItemSingle) app.Co
 		},
 	}
 }
+
+// createBulkUpdateWorkItems creates the given number of open bugs in the space of the given iteration
+func (s *WorkItem2Suite) createBulkUpdateWorkItems(spaceID uuid.UUID, count int) []*app.WorkItem {
+	var wis []*app.WorkItem
+	for i := 0; i < count; i++ {
+		c := minimumRequiredCreatePayload()
+		c.Data.Attributes[workitem.SystemTitle] = fmt.Sprintf("Bulk update %d", i)
+		c.Data.Attributes[workitem.SystemState] = workitem.SystemStateOpen
+		c.Data.Relationships.BaseType = newRelationBaseType(space.SystemSpace, workitem.SystemBug)
+		_, wi := test.CreateWorkitemCreated(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, spaceID.String(), &c)
+		wis = append(wis, wi.Data)
+	}
+	return wis
+}
+
+func (s *WorkItem2Suite) TestWI2BulkUpdateItems() {
+	// given
+	itr := createSpaceAndIteration(s.T(), gormapplication.NewGormDB(s.DB))
+	wis := s.createBulkUpdateWorkItems(itr.SpaceID, 3)
+	iterationID := itr.ID.String()
+	version := wis[0].Attributes["version"].(int)
+	payload := app.WorkItemBulkUpdatePayload{
+		Data: &app.WorkItemBulkUpdateData{
+			Items: []*app.WorkItemBulkUpdateItem{
+				{ID: *wis[0].ID, Version: &version},
+				// work items can be identified by their keys as well
+				{ID: wis[1].Attributes[workitem.SystemKey].(string)},
+			},
+			Attributes: map[string]interface{}{
+				workitem.SystemState: workitem.SystemStateClosed,
+			},
+			Relationships: &app.WorkItemRelationships{
+				Iteration: &app.RelationGeneric{
+					Data: &app.GenericData{ID: &iterationID},
+				},
+			},
+		},
+	}
+	// when
+	_, list := test.BulkUpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, itr.SpaceID.String(), &payload)
+	// then
+	require.Len(s.T(), list.Data, 2)
+	assert.Equal(s.T(), 2, list.Meta.TotalCount)
+	for i, wi := range list.Data {
+		assert.Equal(s.T(), *wis[i].ID, *wi.ID)
+		assert.Equal(s.T(), workitem.SystemStateClosed, wi.Attributes[workitem.SystemState])
+		assert.Equal(s.T(), "Bulk update "+strconv.Itoa(i), wi.Attributes[workitem.SystemTitle])
+		assert.Equal(s.T(), version+1, wi.Attributes["version"])
+		require.NotNil(s.T(), wi.Relationships.Iteration)
+		assert.Equal(s.T(), iterationID, *wi.Relationships.Iteration.Data.ID)
+		revisions, err := gormapplication.NewGormDB(s.DB).WorkItemRevisions().List(context.Background(), *wi.ID)
+		require.Nil(s.T(), err)
+		require.Len(s.T(), revisions, 2)
+		assert.Equal(s.T(), workitem.RevisionTypeUpdate, revisions[1].Type)
+	}
+	untouched, err := gormapplication.NewGormDB(s.DB).WorkItems().Load(context.Background(), itr.SpaceID, *wis[2].ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.SystemStateOpen, untouched.Fields[workitem.SystemState])
+}
+
+func (s *WorkItem2Suite) TestWI2BulkUpdateFilter() {
+	// given
+	itr := createSpaceAndIteration(s.T(), gormapplication.NewGormDB(s.DB))
+	wis := s.createBulkUpdateWorkItems(itr.SpaceID, 2)
+	filter := fmt.Sprintf(`{"%s": "%s"}`, workitem.SystemTitle, wis[1].Attributes[workitem.SystemTitle])
+	payload := app.WorkItemBulkUpdatePayload{
+		Data: &app.WorkItemBulkUpdateData{
+			Filter: &filter,
+			Attributes: map[string]interface{}{
+				workitem.SystemState: workitem.SystemStateResolved,
+			},
+		},
+	}
+	// when
+	_, list := test.BulkUpdateWorkitemOK(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, itr.SpaceID.String(), &payload)
+	// then
+	require.Len(s.T(), list.Data, 1)
+	assert.Equal(s.T(), *wis[1].ID, *list.Data[0].ID)
+	assert.Equal(s.T(), workitem.SystemStateResolved, list.Data[0].Attributes[workitem.SystemState])
+}
+
+func (s *WorkItem2Suite) TestWI2BulkUpdateRollsBackOnFailure() {
+	// given
+	itr := createSpaceAndIteration(s.T(), gormapplication.NewGormDB(s.DB))
+	wis := s.createBulkUpdateWorkItems(itr.SpaceID, 3)
+	staleVersion := wis[1].Attributes["version"].(int) + 1
+	payload := app.WorkItemBulkUpdatePayload{
+		Data: &app.WorkItemBulkUpdateData{
+			Items: []*app.WorkItemBulkUpdateItem{
+				{ID: *wis[0].ID},
+				{ID: *wis[1].ID, Version: &staleVersion},
+				{ID: "4242424242"},
+				{ID: "NOPE-4242"},
+			},
+			Attributes: map[string]interface{}{
+				workitem.SystemState: workitem.SystemStateClosed,
+			},
+		},
+	}
+	// when
+	_, jerrs := test.BulkUpdateWorkitemBadRequest(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, itr.SpaceID.String(), &payload)
+	// then
+	require.Len(s.T(), jerrs.Errors, 3)
+	assert.Equal(s.T(), jsonapi.ErrorCodeVersionConflict, *jerrs.Errors[0].Code)
+	assert.Equal(s.T(), "/data/items/1", jerrs.Errors[0].Source["pointer"])
+	assert.Equal(s.T(), *wis[1].ID, jerrs.Errors[0].Meta["workitem_id"])
+	assert.Equal(s.T(), jsonapi.ErrorCodeNotFound, *jerrs.Errors[1].Code)
+	assert.Equal(s.T(), "/data/items/2", jerrs.Errors[1].Source["pointer"])
+	assert.Equal(s.T(), jsonapi.ErrorCodeNotFound, *jerrs.Errors[2].Code)
+	assert.Equal(s.T(), "NOPE-4242", jerrs.Errors[2].Meta["workitem_id"])
+	wi, err := gormapplication.NewGormDB(s.DB).WorkItems().Load(context.Background(), itr.SpaceID, *wis[0].ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.SystemStateOpen, wi.Fields[workitem.SystemState])
+	assert.Equal(s.T(), wis[0].Attributes["version"], wi.Version)
+}
+
+func (s *WorkItem2Suite) TestWI2BulkUpdateBadPayload() {
+	filter := `{"system.state": "open"}`
+	payload := app.WorkItemBulkUpdatePayload{
+		Data: &app.WorkItemBulkUpdateData{
+			Items:  []*app.WorkItemBulkUpdateItem{{ID: *s.wi.ID}},
+			Filter: &filter,
+		},
+	}
+	test.BulkUpdateWorkitemBadRequest(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), &payload)
+	payload.Data.Items = nil
+	payload.Data.Filter = nil
+	test.BulkUpdateWorkitemBadRequest(s.T(), s.svc.Context, s.svc, s.wi2Ctrl, space.SystemSpace.String(), &payload)
+}
+
+func (s *WorkItem2Suite) TestWI2BulkUpdateUnauthorized() {
+	svc := goa.New("TestBulkUpdateWI2-Service")
+	ctrl := NewWorkitemController(svc, gormapplication.NewGormDB(s.DB), s.Configuration)
+	payload := app.WorkItemBulkUpdatePayload{
+		Data: &app.WorkItemBulkUpdateData{
+			Items: []*app.WorkItemBulkUpdateItem{{ID: *s.wi.ID}},
+		},
+	}
+	test.BulkUpdateWorkitemUnauthorized(s.T(), svc.Context, svc, ctrl, space.SystemSpace.String(), &payload)
+}
@@ -69,7 +69,9 @@ func (c *WorkitemtypeController) Create(ctx *app.CreateWorkitemtypeContext) erro
 		return errors.NewNotFoundError("spaceID", ctx.ID)
 	}
 
-	return application.Transactional(c.db, func(appl application.Application) error {
+	var witTypeModel *workitem.WorkItemType
+	// errors are returned from the transaction so that an invalid workflow rolls back the creation of the type
+	err = application.Transactional(c.db, func(appl application.Application) error {
 		var fields = map[string]app.FieldDefinition{}
 		for key, fd := range ctx.Payload.Data.Attributes.Fields {
 			fields[key] = *fd
@@ -82,9 +84,9 @@ func (c *WorkitemtypeController) Create(ctx *app.CreateWorkitemtypeContext) erro
 		}
 		modelFields, err := ConvertFieldDefinitionsToModel(fields)
 		if err != nil {
-			return jsonapi.JSONErrorResponse(ctx, err)
+			return err
 		}
-		witTypeModel, err := appl.WorkItemTypes().Create(
+		witTypeModel, err = appl.WorkItemTypes().Create(
 			ctx.Context,
 			*ctx.Payload.Data.Relationships.Space.Data.ID,
 			ctx.Payload.Data.ID,
@@ -94,13 +96,52 @@ func (c *WorkitemtypeController) Create(ctx *app.CreateWorkitemtypeContext) erro
 			ctx.Payload.Data.Attributes.Icon,
 			modelFields)
 		if err != nil {
-			return jsonapi.JSONErrorResponse(ctx, err)
+			return err
+		}
+		if ctx.Payload.Data.Attributes.Workflow != nil {
+			witTypeModel, err = appl.WorkItemTypes().SetWorkflow(ctx.Context, witTypeModel.SpaceID, witTypeModel.ID, witTypeModel.Version, ConvertWorkflowToModel(*ctx.Payload.Data.Attributes.Workflow))
+			if err != nil {
+				return err
+			}
 		}
-		witData := ConvertWorkItemTypeFromModel(ctx.RequestData, witTypeModel)
-		wit := &app.WorkItemTypeSingle{Data: &witData}
-		ctx.ResponseData.Header().Set("Location", app.WorkitemtypeHref(*ctx.Payload.Data.Relationships.Space.Data.ID, wit.Data.ID))
-		return ctx.Created(wit)
+		return nil
 	})
+	if witTypeModel != nil {
+		// the type is reloaded on next use, also when it was cached within the rolled back transaction
+		workitem.RemoveWorkItemTypeFromCache(witTypeModel.ID)
+	}
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.RequestData, witTypeModel)
+	wit := &app.WorkItemTypeSingle{Data: &witData}
+	ctx.ResponseData.Header().Set("Location", app.WorkitemtypeHref(*ctx.Payload.Data.Relationships.Space.Data.ID, wit.Data.ID))
+	return ctx.Created(wit)
+}
+
+// SetWorkflow runs the set-workflow action.
+func (c *WorkitemtypeController) SetWorkflow(ctx *app.SetWorkflowWorkitemtypeContext) error {
+	spaceID, err := uuid.FromString(ctx.ID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("spaceID", ctx.ID))
+	}
+	var workflow workitem.Workflow
+	if ctx.Payload.Workflow != nil {
+		workflow = ConvertWorkflowToModel(*ctx.Payload.Workflow)
+	}
+	var witTypeModel *workitem.WorkItemType
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		witTypeModel, err = appl.WorkItemTypes().SetWorkflow(ctx.Context, spaceID, ctx.WitID, ctx.Payload.Version, workflow)
+		return err
+	})
+	// the type is reloaded on next use, the cache is only touched once the transaction has ended
+	workitem.RemoveWorkItemTypeFromCache(ctx.WitID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.RequestData, witTypeModel)
+	return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
 }
 
 // List runs the list action
@@ -242,15 +283,92 @@ func ConvertWorkItemTypeFromModel(request *goa.RequestData, t *workitem.WorkItem
 	for name, def := range t.Fields {
 		ct := convertFieldTypeFromModel(def.Type)
 		converted.Attributes.Fields[name] = &app.FieldDefinition{
-			Required:    def.Required,
-			Label:       def.Label,
-			Description: def.Description,
-			Type:        &ct,
+			Required:     def.Required,
+			Label:        def.Label,
+			Description:  def.Description,
+			Type:         &ct,
+			Rules:        convertFieldRulesFromModel(def.Rules),
+			DefaultValue: def.DefaultValue,
 		}
 	}
+	if !t.Workflow.IsEmpty() {
+		converted.Attributes.Workflow = convertWorkflowFromModel(t.Workflow)
+	}
 	return converted
 }
 
+// converts the field rules from model to app representation
+func convertFieldRulesFromModel(r *workitem.FieldRules) *app.FieldRules {
+	if r == nil {
+		return nil
+	}
+	return &app.FieldRules{
+		Min:        r.Min,
+		Max:        r.Max,
+		MaxLength:  r.MaxLength,
+		Pattern:    r.Pattern,
+		URLSchemes: r.URLSchemes,
+		NotBefore:  r.NotBefore,
+		NotAfter:   r.NotAfter,
+		MinItems:   r.MinItems,
+		MaxItems:   r.MaxItems,
+	}
+}
+
+// converts the field rules from app to model representation
+func convertFieldRulesToModel(r *app.FieldRules) *workitem.FieldRules {
+	if r == nil {
+		return nil
+	}
+	return &workitem.FieldRules{
+		Min:        r.Min,
+		Max:        r.Max,
+		MaxLength:  r.MaxLength,
+		Pattern:    r.Pattern,
+		URLSchemes: r.URLSchemes,
+		NotBefore:  r.NotBefore,
+		NotAfter:   r.NotAfter,
+		MinItems:   r.MinItems,
+		MaxItems:   r.MaxItems,
+	}
+}
+
+// converts the workflow from model to app representation
+func convertWorkflowFromModel(w workitem.Workflow) *app.WorkItemTypeWorkflow {
+	field := w.Field
+	result := &app.WorkItemTypeWorkflow{
+		Field:       &field,
+		Transitions: make([]*app.WorkItemTypeTransition, len(w.Transitions)),
+	}
+	for i, transition := range w.Transitions {
+		result.Transitions[i] = &app.WorkItemTypeTransition{
+			From:           transition.From,
+			To:             transition.To,
+			RequiredFields: transition.RequiredFields,
+		}
+	}
+	return result
+}
+
+// ConvertWorkflowToModel converts the workflow from app to model representation, the workflow applies
+// to the state if no field is given
+func ConvertWorkflowToModel(w app.WorkItemTypeWorkflow) workitem.Workflow {
+	result := workitem.Workflow{
+		Field: workitem.SystemState,
+	}
+	if w.Field != nil {
+		result.Field = *w.Field
+	}
+	for _, transition := range w.Transitions {
+		result.Transitions = append(result.Transitions, workitem.Transition{
+			From:           transition.From,
+			To:             transition.To,
+			RequiredFields: transition.RequiredFields,
+		})
+	}
+	return result
+}
+
 // converts the field type from modesl to app representation
 func convertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 	result := app.FieldType{}
@@ -263,6 +381,14 @@ func convertFieldTypeFromModel(t workitem.FieldType) app.FieldType {
 		kind := string(t2.BaseType.GetKind())
 		result.BaseType = &kind
 		result.Values = t2.Values
+	case workitem.RollUpType:
+		result.RollUp = &app.FieldRollUp{
+			Function:   string(t2.Function),
+			DoneValues: t2.DoneValues,
+		}
+		if t2.Field != "" {
+			result.RollUp.Field = &t2.Field
+		}
 	}
 
 	return result
@@ -301,6 +427,19 @@ func convertFieldTypeToModel(t app.FieldType) (workitem.FieldType, error) {
 			return nil, errs.WithStack(err)
 		}
 		return workitem.EnumType{workitem.SimpleType{*kind}, baseType, converted}, nil
+	case workitem.KindRollUp:
+		if t.RollUp == nil {
+			return nil, errors.NewBadParameterError("type.rollUp", nil).Expected("the aggregation of a roll-up type")
+		}
+		rollUp := workitem.RollUpType{
+			SimpleType: workitem.SimpleType{Kind: *kind},
+			Function:   workitem.RollUpFunction(t.RollUp.Function),
+			DoneValues: t.RollUp.DoneValues,
+		}
+		if t.RollUp.Field != nil {
+			rollUp.Field = *t.RollUp.Field
+		}
+		return rollUp, nil
 	default:
 		return workitem.SimpleType{*kind}, nil
 	}
@@ -315,10 +454,12 @@ func ConvertFieldDefinitionsToModel(fields map[string]app.FieldDefinition) (map[
 			return nil, errs.WithStack(err)
 		}
 		converted := workitem.FieldDefinition{
-			Label:       definition.Label,
-			Description: definition.Description,
-			Required:    definition.Required,
-			Type:        ct,
+			Label:        definition.Label,
+			Description:  definition.Description,
+			Required:     definition.Required,
+			Type:         ct,
+			Rules:        convertFieldRulesToModel(definition.Rules),
+			DefaultValue: definition.DefaultValue,
 		}
 		modelFields[field] = converted
 	}
@@ -270,6 +270,143 @@ func (s *workItemTypeSuite) TestCreateWorkItemType() {
 	_, _ = s.createWorkItemTypePerson()
 }
 
+// workItemTypeWithWorkflowPayload returns the payload to create a work item type "ticket" whose "status"
+// enum field is restricted by the given workflow
+func workItemTypeWithWorkflowPayload(workflow *app.WorkItemTypeWorkflow) app.CreateWorkitemtypePayload {
+	stString := "string"
+	spaceSelfURL := rest.AbsoluteURL(&goa.RequestData{
+		Request: &http.Request{Host: "api.service.domain.org"},
+	}, app.SpaceHref(space.SystemSpace.String()))
+	id := uuid.NewV4()
+	return app.CreateWorkitemtypePayload{
+		Data: &app.WorkItemTypeData{
+			Type: "workitemtypes",
+			ID:   &id,
+			Attributes: &app.WorkItemTypeAttributes{
+				Name: "ticket",
+				Icon: "fa-ticket",
+				Fields: map[string]*app.FieldDefinition{
+					"status": {
+						Required: true,
+						Type: &app.FieldType{
+							BaseType: &stString,
+							Kind:     "enum",
+							Values:   []interface{}{"todo", "doing", "done"},
+						},
+					},
+					"owner": {
+						Type: &app.FieldType{Kind: "user"},
+					},
+				},
+				Workflow: workflow,
+			},
+			Relationships: &app.WorkItemTypeRelationships{
+				Space: app.NewSpaceRelation(space.SystemSpace, spaceSelfURL),
+			},
+		},
+	}
+}
+
+func (s *workItemTypeSuite) TestCreateWorkItemTypeWithWorkflow() {
+	// given
+	field := "status"
+	workflow := &app.WorkItemTypeWorkflow{
+		Field: &field,
+		Transitions: []*app.WorkItemTypeTransition{
+			{From: "todo", To: "doing", RequiredFields: []string{"owner"}},
+			{From: "doing", To: "done"},
+		},
+	}
+	payload := workItemTypeWithWorkflowPayload(workflow)
+	// when
+	_, created := test.CreateWorkitemtypeCreated(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &payload)
+	// then
+	require.NotNil(s.T(), created.Data.Attributes.Workflow)
+	assert.Equal(s.T(), workflow, created.Data.Attributes.Workflow)
+	_, shown := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *created.Data.ID, nil, nil, nil, nil)
+	assert.Equal(s.T(), workflow, shown.Data.Attributes.Workflow)
+	// the system types have no workflow
+	_, bug := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), workitem.SystemBug, nil, nil, nil, nil)
+	assert.Nil(s.T(), bug.Data.Attributes.Workflow)
+}
+
+func (s *workItemTypeSuite) TestCreateWorkItemTypeWithInvalidWorkflow() {
+	// given a workflow on the state, which the type does not have
+	payload := workItemTypeWithWorkflowPayload(&app.WorkItemTypeWorkflow{
+		Transitions: []*app.WorkItemTypeTransition{
+			{From: "todo", To: "doing"},
+		},
+	})
+	// when/then
+	test.CreateWorkitemtypeBadRequest(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &payload)
+}
+
+func (s *workItemTypeSuite) TestCreateWorkItemTypeWithFieldRules() {
+	// given
+	maxLength := 80
+	pattern := "^[A-Z]"
+	payload := workItemTypeWithWorkflowPayload(nil)
+	payload.Data.Attributes.Fields["summary"] = &app.FieldDefinition{
+		Label:       "Summary",
+		Description: "A short summary",
+		Type:        &app.FieldType{Kind: "string"},
+		Rules:       &app.FieldRules{MaxLength: &maxLength, Pattern: &pattern},
+	}
+	// when
+	_, created := test.CreateWorkitemtypeCreated(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &payload)
+	// then
+	require.NotNil(s.T(), created.Data.Attributes.Fields["summary"].Rules)
+	assert.Equal(s.T(), payload.Data.Attributes.Fields["summary"].Rules, created.Data.Attributes.Fields["summary"].Rules)
+	assert.Nil(s.T(), created.Data.Attributes.Fields["status"].Rules)
+}
+
+func (s *workItemTypeSuite) TestCreateWorkItemTypeWithInvalidFieldRules() {
+	// given a length restriction on an enum field
+	maxLength := 80
+	payload := workItemTypeWithWorkflowPayload(nil)
+	payload.Data.Attributes.Fields["status"].Rules = &app.FieldRules{MaxLength: &maxLength}
+	// when/then
+	test.CreateWorkitemtypeBadRequest(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &payload)
+}
+
+func (s *workItemTypeSuite) TestSetWorkflowOfExistingWorkItemType() {
+	// given a type without a workflow
+	payload := workItemTypeWithWorkflowPayload(nil)
+	_, created := test.CreateWorkitemtypeCreated(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &payload)
+	require.Nil(s.T(), created.Data.Attributes.Workflow)
+	field := "status"
+	workflow := &app.WorkItemTypeWorkflow{
+		Field: &field,
+		Transitions: []*app.WorkItemTypeTransition{
+			{From: "todo", To: "doing"},
+		},
+	}
+	// when
+	_, updated := test.SetWorkflowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *created.Data.ID, &app.SetWorkflowWorkitemtypePayload{
+		Version:  *created.Data.Attributes.Version,
+		Workflow: workflow,
+	})
+	// then
+	assert.Equal(s.T(), workflow, updated.Data.Attributes.Workflow)
+	assert.Equal(s.T(), *created.Data.Attributes.Version+1, *updated.Data.Attributes.Version)
+	_, shown := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *created.Data.ID, nil, nil, nil, nil)
+	assert.Equal(s.T(), workflow, shown.Data.Attributes.Workflow)
+	// when the version is outdated
+	test.SetWorkflowWorkitemtypeBadRequest(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *created.Data.ID, &app.SetWorkflowWorkitemtypePayload{
+		Version: *created.Data.Attributes.Version,
+	})
+	// when the workflow is removed
+	_, updated = test.SetWorkflowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *created.Data.ID, &app.SetWorkflowWorkitemtypePayload{
+		Version: *updated.Data.Attributes.Version,
+	})
+	// then
+	assert.Nil(s.T(), updated.Data.Attributes.Workflow)
+	// when the type does not exist
+	test.SetWorkflowWorkitemtypeNotFound(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), uuid.NewV4(), &app.SetWorkflowWorkitemtypePayload{
+		Workflow: workflow,
+	})
+}
+
 // TestShowWorkItemType200OK tests if we can fetch the work item type "animal".
 func (s *workItemTypeSuite) TestShowWorkItemType200OK() {
 	// given
@@ -279,7 +416,7 @@ func (s *workItemTypeSuite) TestShowWorkItemType200OK() {
 	require.NotNil(s.T(), wit.Data)
 	require.NotNil(s.T(), wit.Data.ID)
 	// when
-	res, wit2 := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, nil)
+	res, wit2 := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), wit2)
 	assert.EqualValues(s.T(), wit, wit2)
@@ -302,7 +439,7 @@ func (s *workItemTypeSuite) TestShowWorkItemType200UsingExpiredLastModifiedHeade
 	require.NotNil(s.T(), wit.Data.ID)
 	// when
 	lastModified := app.ToHTTPTime(time.Now().Add(-1 * time.Hour))
-	res, wit2 := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *wit.Data.ID, &lastModified, nil)
+	res, wit2 := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), *wit.Data.ID, nil, nil, &lastModified, nil)
 	// then
 	require.NotNil(s.T(), wit2)
 	assert.EqualValues(s.T(), wit, wit2)
@@ -325,7 +462,7 @@ func (s *workItemTypeSuite) TestShowWorkItemType200UsingExpiredETagHeader() {
 	require.NotNil(s.T(), wit.Data.ID)
 	// when
 	etag := "foo"
-	res, wit2 := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, &etag)
+	res, wit2 := test.ShowWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, nil, nil, &etag)
 	// then
 	require.NotNil(s.T(), wit2)
 	assert.EqualValues(s.T(), wit, wit2)
@@ -348,7 +485,7 @@ func (s *workItemTypeSuite) TestShowWorkItemType304UsingIfModifiedSinceHeader()
 	require.NotNil(s.T(), wit.Data.ID)
 	// when/then
 	lastModified := app.ToHTTPTime(wit.Data.Attributes.UpdatedAt.Add(1 * time.Second))
-	test.ShowWorkitemtypeNotModified(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, &lastModified, nil)
+	test.ShowWorkitemtypeNotModified(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, nil, &lastModified, nil)
 }
 
 // TestShowWorkItemType304UsingIfNoneMatchHeader tests
@@ -362,7 +499,7 @@ func (s *workItemTypeSuite) TestShowWorkItemType304UsingIfNoneMatchHeader() {
 	require.NotNil(s.T(), wit.Data.ID)
 	// when/then
 	etag := generateWorkItemTypeTag(*wit)
-	test.ShowWorkitemtypeNotModified(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, &etag)
+	test.ShowWorkitemtypeNotModified(s.T(), nil, nil, s.typeCtrl, wit.Data.Relationships.Space.Data.ID.String(), *wit.Data.ID, nil, nil, nil, &etag)
 }
 
 // TestListWorkItemTypeOK200 tests if we can find the work item types
@@ -378,7 +515,7 @@ func (s *workItemTypeSuite) TestListWorkItemType200OK() {
 	// Fetch a single work item type
 	// Paging in the format <start>,<limit>"
 	page := "0,-1"
-	res, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &page, nil, nil)
+	res, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, nil, s.typeCtrl, nil, space.SystemSpace.String(), &page, nil, nil, nil)
 	// then
 	require.NotNil(s.T(), witCollection)
 	require.Nil(s.T(), witCollection.Validate())
@@ -407,7 +544,7 @@ func (s *workItemTypeSuite) TestListWorkItemType200UsingExpiredIfModifiedSinceHe
 	// Paging in the format <start>,<limit>"
 	lastModified := app.ToHTTPTime(time.Now().Add(-1 * time.Hour))
 	page := "0,-1"
-	res, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &page, &lastModified, nil)
+	res, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, nil, s.typeCtrl, nil, space.SystemSpace.String(), &page, nil, &lastModified, nil)
 	// then
 	require.NotNil(s.T(), witCollection)
 	require.Nil(s.T(), witCollection.Validate())
@@ -435,7 +572,7 @@ func (s *workItemTypeSuite) TestListWorkItemType200UsingExpiredIfNoneMatchHeader
 	// Paging in the format <start>,<limit>"
 	etag := "foo"
 	page := "0,-1"
-	res, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &page, nil, &etag)
+	res, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, nil, s.typeCtrl, nil, space.SystemSpace.String(), &page, nil, nil, &etag)
 	// then
 	require.NotNil(s.T(), witCollection)
 	require.Nil(s.T(), witCollection.Validate())
@@ -463,7 +600,7 @@ func (s *workItemTypeSuite) TestListWorkItemType304UsingIfModifiedSinceHeader()
 	// Paging in the format <start>,<limit>"
 	lastModified := app.ToHTTPTime(getWorkItemTypeUpdatedAt(*witPerson))
 	page := "0,-1"
-	test.ListWorkitemtypeNotModified(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &page, &lastModified, nil)
+	test.ListWorkitemtypeNotModified(s.T(), nil, nil, nil, s.typeCtrl, nil, space.SystemSpace.String(), &page, nil, &lastModified, nil)
 }
 
 // TestListWorkItemType304UsingIfNoneMatchHeader tests if we can find the work item types
@@ -477,12 +614,12 @@ func (s *workItemTypeSuite) TestListWorkItemType304UsingIfNoneMatchHeader() {
 	require.NotNil(s.T(), witPerson)
 	// Paging in the format <start>,<limit>"
 	page := "0,-1"
-	_, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &page, nil, nil)
+	_, witCollection := test.ListWorkitemtypeOK(s.T(), nil, nil, nil, s.typeCtrl, nil, space.SystemSpace.String(), &page, nil, nil, nil)
 	require.NotNil(s.T(), witCollection)
 	// when/then
 	// Fetch a single work item type
 	ifNoneMatch := generateWorkItemTypesTag(*witCollection)
-	test.ListWorkitemtypeNotModified(s.T(), nil, nil, s.typeCtrl, space.SystemSpace.String(), &page, nil, &ifNoneMatch)
+	test.ListWorkitemtypeNotModified(s.T(), nil, nil, nil, s.typeCtrl, nil, space.SystemSpace.String(), &page, nil, nil, &ifNoneMatch)
 }
 
 //-----------------------------------------------------------------------------
@@ -35,6 +35,12 @@ type BinaryExpression interface {
 	Right() Expression
 }
 
+// UnaryExpression represents expressions with a single child
+type UnaryExpression interface {
+	Expression
+	Operand() Expression
+}
+
 // ExpressionVisitor is an implementation of the visitor pattern for expressions
 type ExpressionVisitor interface {
 	Field(t *FieldExpression) interface{}
@@ -44,6 +50,15 @@ type ExpressionVisitor interface {
 	Parameter(v *ParameterExpression) interface{}
 	Literal(c *LiteralExpression) interface{}
 	Not(e *NotExpression) interface{}
+	LessThan(e *LessThanExpression) interface{}
+	LessThanOrEqual(e *LessThanOrEqualExpression) interface{}
+	GreaterThan(e *GreaterThanExpression) interface{}
+	GreaterThanOrEqual(e *GreaterThanOrEqualExpression) interface{}
+	In(e *InExpression) interface{}
+	IsNull(e *IsNullExpression) interface{}
+	IsNotNull(e *IsNotNullExpression) interface{}
+	Substring(e *SubstringExpression) interface{}
+	ILike(e *ILikeExpression) interface{}
 }
 
 type expression struct {
@@ -216,3 +231,177 @@ func (t *NotExpression) Accept(visitor ExpressionVisitor) interface{} {
 func Not(left Expression, right Expression) Expression {
 	return reparent(&NotExpression{binaryExpression{expression{}, left, right}})
 }
+
+// <
+
+// LessThanExpression represents the "less than" operator
+type LessThanExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *LessThanExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.LessThan(t)
+}
+
+// LessThan constructs a LessThanExpression
+func LessThan(left Expression, right Expression) Expression {
+	return reparent(&LessThanExpression{binaryExpression{expression{}, left, right}})
+}
+
+// <=
+
+// LessThanOrEqualExpression represents the "less than or equal" operator
+type LessThanOrEqualExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *LessThanOrEqualExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.LessThanOrEqual(t)
+}
+
+// LessThanOrEqual constructs a LessThanOrEqualExpression
+func LessThanOrEqual(left Expression, right Expression) Expression {
+	return reparent(&LessThanOrEqualExpression{binaryExpression{expression{}, left, right}})
+}
+
+// >
+
+// GreaterThanExpression represents the "greater than" operator
+type GreaterThanExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *GreaterThanExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.GreaterThan(t)
+}
+
+// GreaterThan constructs a GreaterThanExpression
+func GreaterThan(left Expression, right Expression) Expression {
+	return reparent(&GreaterThanExpression{binaryExpression{expression{}, left, right}})
+}
+
+// >=
+
+// GreaterThanOrEqualExpression represents the "greater than or equal" operator
+type GreaterThanOrEqualExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *GreaterThanOrEqualExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.GreaterThanOrEqual(t)
+}
+
+// GreaterThanOrEqual constructs a GreaterThanOrEqualExpression
+func GreaterThanOrEqual(left Expression, right Expression) Expression {
+	return reparent(&GreaterThanOrEqualExpression{binaryExpression{expression{}, left, right}})
+}
+
+// In
+
+// InExpression represents the membership operator. The right hand side is
+// expected to be a literal holding a slice of values.
+type InExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *InExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.In(t)
+}
+
+// In constructs an InExpression
+func In(left Expression, right Expression) Expression {
+	return reparent(&InExpression{binaryExpression{expression{}, left, right}})
+}
+
+// Substring
+
+// SubstringExpression tests whether the left term contains the right term.
+// The comparison is case-insensitive.
+type SubstringExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *SubstringExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.Substring(t)
+}
+
+// Substring constructs a SubstringExpression
+func Substring(left Expression, right Expression) Expression {
+	return reparent(&SubstringExpression{binaryExpression{expression{}, left, right}})
+}
+
+// ILike
+
+// ILikeExpression matches the left term against the pattern in the right term,
+// ignoring case. "%" matches any sequence of characters, "_" matches a single
+// character and a backslash escapes the following character.
+type ILikeExpression struct {
+	binaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *ILikeExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.ILike(t)
+}
+
+// ILike constructs an ILikeExpression
+func ILike(left Expression, right Expression) Expression {
+	return reparent(&ILikeExpression{binaryExpression{expression{}, left, right}})
+}
+
+// unaryExpression is an "abstract" type for unary expressions.
+type unaryExpression struct {
+	expression
+	operand Expression
+}
+
+// Operand implements UnaryExpression
+func (exp *unaryExpression) Operand() Expression {
+	return exp.operand
+}
+
+// make sure the child has the correct parent
+func reparentUnary(parent UnaryExpression) Expression {
+	parent.Operand().setParent(parent)
+	return parent
+}
+
+// IsNull
+
+// IsNullExpression tests whether its operand has no value
+type IsNullExpression struct {
+	unaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *IsNullExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.IsNull(t)
+}
+
+// IsNull constructs an IsNullExpression
+func IsNull(operand Expression) Expression {
+	return reparentUnary(&IsNullExpression{unaryExpression{expression{}, operand}})
+}
+
+// IsNotNull
+
+// IsNotNullExpression tests whether its operand has a value
+type IsNotNullExpression struct {
+	unaryExpression
+}
+
+// Accept implements ExpressionVisitor
+func (t *IsNotNullExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.IsNotNull(t)
+}
+
+// IsNotNull constructs an IsNotNullExpression
+func IsNotNull(operand Expression) Expression {
+	return reparentUnary(&IsNotNullExpression{unaryExpression{expression{}, operand}})
+}
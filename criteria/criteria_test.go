@@ -15,3 +15,18 @@ func TestGetParent(t *testing.T) {
 		t.Errorf("parent should be %v, but is %v", expr, l.Parent())
 	}
 }
+
+func TestGetParentUnary(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	f := Field("a")
+	expr := IsNotNull(f)
+	if f.Parent() != expr {
+		t.Errorf("parent should be %v, but is %v", expr, f.Parent())
+	}
+	l := Field("a")
+	r := Literal([]string{"x", "y"})
+	expr = In(l, r)
+	if l.Parent() != expr || r.Parent() != expr {
+		t.Errorf("parent should be %v", expr)
+	}
+}
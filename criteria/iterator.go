@@ -39,6 +39,49 @@ func (i *postOrderIterator) Not(exp *NotExpression) interface{} {
 	return i.binary(exp)
 }
 
+func (i *postOrderIterator) LessThan(exp *LessThanExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) LessThanOrEqual(exp *LessThanOrEqualExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) GreaterThan(exp *GreaterThanExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) GreaterThanOrEqual(exp *GreaterThanOrEqualExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) In(exp *InExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) IsNull(exp *IsNullExpression) interface{} {
+	return i.unary(exp)
+}
+
+func (i *postOrderIterator) IsNotNull(exp *IsNotNullExpression) interface{} {
+	return i.unary(exp)
+}
+
+func (i *postOrderIterator) Substring(exp *SubstringExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) ILike(exp *ILikeExpression) interface{} {
+	return i.binary(exp)
+}
+
+func (i *postOrderIterator) unary(exp UnaryExpression) bool {
+	if exp.Operand().Accept(i) == false {
+		return false
+	}
+	return i.visit(exp)
+}
+
 func (i *postOrderIterator) binary(exp BinaryExpression) bool {
 	if exp.Left().Accept(i) == false {
 		return false
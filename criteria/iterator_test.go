@@ -41,3 +41,23 @@ func TestIterator(t *testing.T) {
 	}
 
 }
+
+func TestIteratorUnary(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+
+	visited := []Expression{}
+	f := Field("a")
+	isNull := IsNull(f)
+	l := Field("b")
+	r := Literal(5)
+	lt := LessThan(l, r)
+	expr := Or(isNull, lt)
+	expected := []Expression{f, isNull, l, r, lt, expr}
+	IteratePostOrder(expr, func(expr Expression) bool {
+		visited = append(visited, expr)
+		return true
+	})
+	if !reflect.DeepEqual(expected, visited) {
+		t.Errorf("Visited should be %v, but is %v", expected, visited)
+	}
+}
@@ -0,0 +1,369 @@
+package criteria
+
+import (
+	"encoding/json"
+	"time"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The canonical form of an expression is a JSON document in which every node
+// is an object with an "op" member naming the node type:
+//
+//	{"op":"field","name":"system.state"}
+//	{"op":"literal","type":"string","value":"open"}
+//	{"op":"literal","type":"instant","value":"2017-03-01T12:00:00Z"}
+//	{"op":"parameter"}
+//	{"op":"eq","left":{...},"right":{...}}
+//	{"op":"is_null","operand":{...}}
+//
+// Binary expressions use the ops "and", "or", "eq", "ne", "lt", "le", "gt",
+// "ge", "in", "contains" and "ilike", unary expressions "is_null" and
+// "is_not_null". Literals carry the Go type of their value, so that they are
+// read back unchanged (see literalTypes). Members are always written in the
+// same order, which makes the printed form suitable for comparing and storing
+// expressions. Annotations are not part of the canonical form.
+
+// Op names of the canonical form
+const (
+	opField     = "field"
+	opLiteral   = "literal"
+	opParameter = "parameter"
+	opIsNull    = "is_null"
+	opIsNotNull = "is_not_null"
+)
+
+// binaryOps maps the op names of binary expressions to their constructors
+var binaryOps = map[string]func(left Expression, right Expression) Expression{
+	"and":      And,
+	"or":       Or,
+	"eq":       Equals,
+	"ne":       Not,
+	"lt":       LessThan,
+	"le":       LessThanOrEqual,
+	"gt":       GreaterThan,
+	"ge":       GreaterThanOrEqual,
+	"in":       In,
+	"contains": Substring,
+	"ilike":    ILike,
+}
+
+// literalTypes maps the type names of literals to functions that decode a value of that type.
+// Lists of type "list" hold typed elements and are decoded by jsonLiteral.decode.
+var literalTypes = map[string]func(value json.RawMessage) (interface{}, error){
+	"null": func(value json.RawMessage) (interface{}, error) {
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil || v != nil {
+			return nil, errs.Errorf("expected null but found %s", value)
+		}
+		return nil, nil
+	},
+	"string": func(value json.RawMessage) (interface{}, error) {
+		var v string
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"bool": func(value json.RawMessage) (interface{}, error) {
+		var v bool
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"int": func(value json.RawMessage) (interface{}, error) {
+		var v int
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"int64": func(value json.RawMessage) (interface{}, error) {
+		var v int64
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"float": func(value json.RawMessage) (interface{}, error) {
+		var v float64
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"uuid": func(value json.RawMessage) (interface{}, error) {
+		var v uuid.UUID
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"instant": func(value json.RawMessage) (interface{}, error) {
+		var v string
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, v)
+	},
+	"strings": func(value json.RawMessage) (interface{}, error) {
+		v := []string{}
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+	"uuids": func(value json.RawMessage) (interface{}, error) {
+		v := []uuid.UUID{}
+		err := json.Unmarshal(value, &v)
+		return v, err
+	},
+}
+
+// jsonExpression is a node of the canonical form
+type jsonExpression struct {
+	Op string `json:"op"`
+	// Name is the name of a field
+	Name string `json:"name,omitempty"`
+	jsonLiteral
+	Left    *jsonExpression `json:"left,omitempty"`
+	Right   *jsonExpression `json:"right,omitempty"`
+	Operand *jsonExpression `json:"operand,omitempty"`
+}
+
+// jsonLiteral is the typed value of a literal, it is also used for the elements of lists
+type jsonLiteral struct {
+	Type  string          `json:"type,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func encodeLiteral(value interface{}) (*jsonLiteral, error) {
+	var typeName string
+	switch v := value.(type) {
+	case nil:
+		typeName = "null"
+	case string:
+		typeName = "string"
+	case bool:
+		typeName = "bool"
+	case int:
+		typeName = "int"
+	case int64:
+		typeName = "int64"
+	case float64:
+		typeName = "float"
+	case uuid.UUID:
+		typeName = "uuid"
+	case time.Time:
+		// instants are written in UTC as RFC 3339 timestamps
+		value = v.UTC().Format(time.RFC3339Nano)
+		typeName = "instant"
+	case []string:
+		typeName = "strings"
+	case []uuid.UUID:
+		typeName = "uuids"
+	case []interface{}:
+		elements := make([]*jsonLiteral, len(v))
+		for i, element := range v {
+			encoded, err := encodeLiteral(element)
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = encoded
+		}
+		value = elements
+		typeName = "list"
+	default:
+		return nil, errs.Errorf("unsupported literal type %T", value)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to encode literal %v", value)
+	}
+	return &jsonLiteral{Type: typeName, Value: raw}, nil
+}
+
+func (l jsonLiteral) decode() (interface{}, error) {
+	if len(l.Value) == 0 {
+		return nil, errs.Errorf("literal of type '%s' has no value", l.Type)
+	}
+	if l.Type == "list" {
+		return decodeList(l.Value)
+	}
+	decode, ok := literalTypes[l.Type]
+	if !ok {
+		return nil, errs.Errorf("unknown literal type '%s'", l.Type)
+	}
+	result, err := decode(l.Value)
+	if err != nil {
+		return nil, errs.Wrapf(err, "invalid literal of type '%s'", l.Type)
+	}
+	return result, nil
+}
+
+func decodeList(value json.RawMessage) (interface{}, error) {
+	var elements []jsonLiteral
+	if err := json.Unmarshal(value, &elements); err != nil {
+		return nil, errs.Wrap(err, "invalid literal of type 'list'")
+	}
+	result := make([]interface{}, len(elements))
+	for i, element := range elements {
+		decoded, err := element.decode()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = decoded
+	}
+	return result, nil
+}
+
+// Marshal returns the canonical form of the given expression.
+// Fails if a literal holds a value of an unsupported type.
+func Marshal(exp Expression) ([]byte, error) {
+	m := &marshaller{}
+	node := exp.Accept(m).(*jsonExpression)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return json.Marshal(node)
+}
+
+// String returns the canonical form of the given expression for use in log
+// messages. Literals of unsupported types are replaced by a description of
+// the error.
+func String(exp Expression) string {
+	result, err := Marshal(exp)
+	if err != nil {
+		return "invalid expression: " + err.Error()
+	}
+	return string(result)
+}
+
+// Unmarshal reads an expression from its canonical form
+func Unmarshal(data []byte) (Expression, error) {
+	var node jsonExpression
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, errs.Wrap(err, "invalid expression")
+	}
+	return node.expression()
+}
+
+func (n *jsonExpression) expression() (Expression, error) {
+	if n == nil {
+		return nil, errs.New("missing expression")
+	}
+	switch n.Op {
+	case opField:
+		return Field(n.Name), nil
+	case opParameter:
+		return Parameter(), nil
+	case opLiteral:
+		value, err := n.jsonLiteral.decode()
+		if err != nil {
+			return nil, err
+		}
+		return Literal(value), nil
+	case opIsNull, opIsNotNull:
+		operand, err := n.Operand.expression()
+		if err != nil {
+			return nil, errs.Wrapf(err, "invalid operand of '%s'", n.Op)
+		}
+		if n.Op == opIsNull {
+			return IsNull(operand), nil
+		}
+		return IsNotNull(operand), nil
+	}
+	constructor, ok := binaryOps[n.Op]
+	if !ok {
+		return nil, errs.Errorf("unknown op '%s'", n.Op)
+	}
+	left, err := n.Left.expression()
+	if err != nil {
+		return nil, errs.Wrapf(err, "invalid left side of '%s'", n.Op)
+	}
+	right, err := n.Right.expression()
+	if err != nil {
+		return nil, errs.Wrapf(err, "invalid right side of '%s'", n.Op)
+	}
+	return constructor(left, right), nil
+}
+
+// implements ExpressionVisitor, the visitor methods return *jsonExpression
+type marshaller struct {
+	// err is the first error encountered
+	err error
+}
+
+func (m *marshaller) binary(op string, exp BinaryExpression) interface{} {
+	return &jsonExpression{
+		Op:    op,
+		Left:  exp.Left().Accept(m).(*jsonExpression),
+		Right: exp.Right().Accept(m).(*jsonExpression),
+	}
+}
+
+func (m *marshaller) unary(op string, exp UnaryExpression) interface{} {
+	return &jsonExpression{
+		Op:      op,
+		Operand: exp.Operand().Accept(m).(*jsonExpression),
+	}
+}
+
+func (m *marshaller) Field(exp *FieldExpression) interface{} {
+	return &jsonExpression{Op: opField, Name: exp.FieldName}
+}
+
+func (m *marshaller) And(exp *AndExpression) interface{} {
+	return m.binary("and", exp)
+}
+
+func (m *marshaller) Or(exp *OrExpression) interface{} {
+	return m.binary("or", exp)
+}
+
+func (m *marshaller) Equals(exp *EqualsExpression) interface{} {
+	return m.binary("eq", exp)
+}
+
+func (m *marshaller) Parameter(exp *ParameterExpression) interface{} {
+	return &jsonExpression{Op: opParameter}
+}
+
+func (m *marshaller) Literal(exp *LiteralExpression) interface{} {
+	literal, err := encodeLiteral(exp.Value)
+	if err != nil {
+		if m.err == nil {
+			m.err = err
+		}
+		return &jsonExpression{Op: opLiteral}
+	}
+	return &jsonExpression{Op: opLiteral, jsonLiteral: *literal}
+}
+
+func (m *marshaller) Not(exp *NotExpression) interface{} {
+	return m.binary("ne", exp)
+}
+
+func (m *marshaller) LessThan(exp *LessThanExpression) interface{} {
+	return m.binary("lt", exp)
+}
+
+func (m *marshaller) LessThanOrEqual(exp *LessThanOrEqualExpression) interface{} {
+	return m.binary("le", exp)
+}
+
+func (m *marshaller) GreaterThan(exp *GreaterThanExpression) interface{} {
+	return m.binary("gt", exp)
+}
+
+func (m *marshaller) GreaterThanOrEqual(exp *GreaterThanOrEqualExpression) interface{} {
+	return m.binary("ge", exp)
+}
+
+func (m *marshaller) In(exp *InExpression) interface{} {
+	return m.binary("in", exp)
+}
+
+func (m *marshaller) IsNull(exp *IsNullExpression) interface{} {
+	return m.unary(opIsNull, exp)
+}
+
+func (m *marshaller) IsNotNull(exp *IsNotNullExpression) interface{} {
+	return m.unary(opIsNotNull, exp)
+}
+
+func (m *marshaller) Substring(exp *SubstringExpression) interface{} {
+	return m.binary("contains", exp)
+}
+
+func (m *marshaller) ILike(exp *ILikeExpression) interface{} {
+	return m.binary("ilike", exp)
+}
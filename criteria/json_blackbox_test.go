@@ -0,0 +1,162 @@
+package criteria_test
+
+import (
+	"testing"
+	"time"
+
+	c "github.com/almighty/almighty-core/criteria"
+	"github.com/almighty/almighty-core/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTrip(t *testing.T, exp c.Expression) {
+	printed, err := c.Marshal(exp)
+	require.Nil(t, err, "failed to print %v", exp)
+	parsed, err := c.Unmarshal(printed)
+	require.Nil(t, err, "failed to parse %s", printed)
+	assert.Equal(t, exp, parsed, "round trip of %s", printed)
+	// the canonical form is stable
+	reprinted, err := c.Marshal(parsed)
+	require.Nil(t, err)
+	assert.Equal(t, string(printed), string(reprinted))
+}
+
+func TestRoundTripNodes(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	f := func() c.Expression { return c.Field("system.state") }
+	l := func() c.Expression { return c.Literal("open") }
+	for _, exp := range []c.Expression{
+		c.Field("system.state"),
+		c.Field(""),
+		c.Parameter(),
+		c.Literal("open"),
+		c.And(c.Equals(f(), l()), c.Literal(true)),
+		c.Or(c.Equals(f(), l()), c.Literal(false)),
+		c.Equals(f(), l()),
+		c.Not(f(), l()),
+		c.LessThan(f(), l()),
+		c.LessThanOrEqual(f(), l()),
+		c.GreaterThan(f(), l()),
+		c.GreaterThanOrEqual(f(), l()),
+		c.In(f(), c.Literal([]interface{}{"open", "new"})),
+		c.Substring(f(), l()),
+		c.ILike(f(), l()),
+		c.IsNull(f()),
+		c.IsNotNull(f()),
+		c.Equals(c.Parameter(), c.Field("system.title")),
+		c.Or(c.And(c.IsNull(f()), c.Not(f(), l())), c.In(f(), c.Literal([]string{"a"}))),
+	} {
+		roundTrip(t, exp)
+	}
+}
+
+func TestRoundTripLiterals(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	id := uuid.NewV4()
+	instant := time.Date(2017, 3, 1, 12, 30, 0, 500, time.UTC)
+	for _, value := range []interface{}{
+		nil,
+		"",
+		`say "hi" \ <b>`,
+		true,
+		false,
+		0,
+		-42,
+		int64(1499999999999999999),
+		2.5,
+		float64(2),
+		id,
+		[]string{},
+		[]string{"a", "b"},
+		[]string(nil),
+		[]uuid.UUID{id, uuid.Nil},
+		[]interface{}{},
+		[]interface{}{"a", 1, 2.5, nil, true, []interface{}{id}},
+		instant,
+		[]interface{}{instant, "a"},
+	} {
+		roundTrip(t, c.Literal(value))
+	}
+}
+
+func TestMarshalCanonicalForm(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	exp := c.And(
+		c.Equals(c.Field("system.state"), c.Literal("open")),
+		c.IsNull(c.Field("system.assignees")),
+	)
+	expected := `{"op":"and",` +
+		`"left":{"op":"eq","left":{"op":"field","name":"system.state"},"right":{"op":"literal","type":"string","value":"open"}},` +
+		`"right":{"op":"is_null","operand":{"op":"field","name":"system.assignees"}}}`
+	printed, err := c.Marshal(exp)
+	require.Nil(t, err)
+	assert.Equal(t, expected, string(printed))
+	assert.Equal(t, expected, c.String(exp))
+	assert.Equal(t, `{"op":"literal","type":"list","value":[{"type":"int","value":1},{"type":"null","value":null}]}`,
+		c.String(c.Literal([]interface{}{1, nil})))
+	// members may come in any order
+	parsed, err := c.Unmarshal([]byte(`{"right":{"value":5,"type":"int","op":"literal"},"left":{"name":"a","op":"field"},"op":"gt"}`))
+	require.Nil(t, err)
+	assert.Equal(t, c.GreaterThan(c.Field("a"), c.Literal(5)), parsed)
+}
+
+func TestMarshalInstant(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given an instant with an offset
+	instant := time.Date(2017, 3, 1, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	// when
+	printed, err := c.Marshal(c.Literal(instant))
+	// then it is written in UTC
+	require.Nil(t, err)
+	assert.Equal(t, `{"op":"literal","type":"instant","value":"2017-03-01T12:30:00Z"}`, string(printed))
+	// when an instant with an offset is read
+	parsed, err := c.Unmarshal([]byte(`{"op":"literal","type":"instant","value":"2017-03-01T14:30:00+02:00"}`))
+	// then it is the same instant
+	require.Nil(t, err)
+	value, ok := parsed.(*c.LiteralExpression).Value.(time.Time)
+	require.True(t, ok)
+	assert.True(t, instant.Equal(value))
+}
+
+func TestMarshalUnsupportedLiteral(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	exp := c.Equals(c.Field("a"), c.Literal(map[string]interface{}{"b": 1}))
+	_, err := c.Marshal(exp)
+	assert.NotNil(t, err)
+	assert.Contains(t, c.String(exp), "invalid expression")
+	_, err = c.Marshal(c.Literal([]interface{}{struct{}{}}))
+	assert.NotNil(t, err)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	for _, invalid := range []string{
+		``,
+		`[]`,
+		`{"op":"eq"`,
+		`{"op":"xor","left":{"op":"parameter"},"right":{"op":"parameter"}}`,
+		`{"op":"eq","left":{"op":"field","name":"a"}}`,
+		`{"op":"is_null"}`,
+		`{"op":"literal"}`,
+		`{"op":"literal","type":"string"}`,
+		`{"op":"literal","type":"string","value":5}`,
+		`{"op":"literal","type":"int","value":2.5}`,
+		`{"op":"literal","type":"null","value":"x"}`,
+		`{"op":"literal","type":"uuid","value":"not a uuid"}`,
+		`{"op":"literal","type":"instant","value":"yesterday"}`,
+		`{"op":"literal","type":"instant","value":1488371400}`,
+		`{"op":"literal","type":"map","value":{}}`,
+		`{"op":"literal","type":"list","value":[{"type":"int","value":"1"}]}`,
+	} {
+		_, err := c.Unmarshal([]byte(invalid))
+		assert.NotNil(t, err, "expected error for %s", invalid)
+	}
+}
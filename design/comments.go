@@ -172,6 +172,8 @@ var _ = a.Resource("work_item_comments", func() {
 		a.Params(func() {
 			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
 			the beginning of pagination.  The value starts from 0 onwards.`)
+			a.Param("page[cursor]", d.String, `Paging start position as an opaque token taken from the links
+			of a previous page, an empty value starts at the newest comment. Takes precedence over page[offset].`)
 			a.Param("page[limit]", d.Integer, `Paging size is the number of items in a page`)
 		})
 		a.Response(d.OK, func() {
@@ -0,0 +1,81 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var dependencyAnalysis = a.Type("DependencyAnalysis", func() {
+	a.Description(`JSONAPI store for the analysis of the dependencies between the work items of a space. In
+a link type with the dependency topology the source of a link blocks the target of the link. Work items that are
+neither resolved nor closed are open.`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("dependency-analyses")
+	})
+	a.Attribute("attributes", dependencyAnalysisAttributes)
+	a.Required("type", "attributes")
+})
+
+var dependencyAnalysisAttributes = a.Type("DependencyAnalysisAttributes", func() {
+	a.Attribute("blocked", a.ArrayOf(blockedWorkItem), "The open work items that are blocked by open work items")
+	a.Attribute("misplanned", a.ArrayOf(misplannedDependency), `The direct and transitive dependencies between open work
+items where the blocking work item is planned in an iteration that starts after the iteration of the blocked work item`)
+	a.Attribute("critical-path", a.ArrayOf(d.String), `IDs of the work items on the longest chain of open work items
+blocking the root work item, ending with the root work item; only given if a root is requested`, func() {
+		a.Example([]string{"12", "27", "42"})
+	})
+	a.Required("blocked", "misplanned")
+})
+
+var blockedWorkItem = a.Type("BlockedWorkItem", func() {
+	a.Attribute("id", d.String, "ID of the blocked work item", func() {
+		a.Example("42")
+	})
+	a.Attribute("blockers", a.ArrayOf(d.String), "IDs of the open work items blocking the work item", func() {
+		a.Example([]string{"12", "27"})
+	})
+	a.Required("id", "blockers")
+})
+
+var misplannedDependency = a.Type("MisplannedDependency", func() {
+	a.Attribute("link", d.UUID, "ID of the work item link from the blocking work item on the shortest chain of dependencies to the blocked work item")
+	a.Attribute("blocker", d.String, "ID of the blocking work item", func() {
+		a.Example("12")
+	})
+	a.Attribute("blocker-iteration", d.UUID, "ID of the iteration of the blocking work item")
+	a.Attribute("blocked", d.String, "ID of the blocked work item", func() {
+		a.Example("42")
+	})
+	a.Attribute("blocked-iteration", d.UUID, "ID of the iteration of the blocked work item")
+	a.Required("link", "blocker", "blocker-iteration", "blocked", "blocked-iteration")
+})
+
+var dependencyAnalysisSingle = JSONSingle(
+	"DependencyAnalysis", "Holds the analysis of the dependencies between the work items of a space",
+	dependencyAnalysis,
+	nil)
+
+var _ = a.Resource("space_dependencies", func() {
+	a.Parent("space")
+	a.BasePath("/dependencies")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description(`Analyze the dependencies between the work items of the space using the link types with the
+dependency topology: the blocked work items, the dependencies planned in the wrong order of iterations and,
+for a given root work item, the critical path. The critical path cannot be computed if the work items blocking
+the root work item block each other through several dependency link types.`)
+		a.Params(func() {
+			a.Param("root", d.String, "ID or key of the work item to compute the critical path for", func() {
+				a.Example("PLAT-42")
+			})
+		})
+		a.Response(d.OK, dependencyAnalysisSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
@@ -10,7 +10,9 @@ var filter = a.Type("filters", func() {
 	a.Attribute("type", d.String, func() {
 		a.Enum("filters")
 	})
+	a.Attribute("id", d.UUID, "ID of the saved filter, built-in filters have no ID")
 	a.Attribute("attributes", filterAttributes)
+	a.Attribute("links", genericLinks)
 	a.Required("type", "attributes")
 })
 
@@ -31,6 +33,52 @@ var filterAttributes = a.Type("filterAttributes", func() {
 	a.Required("type", "title", "description", "query")
 })
 
+var savedFilter = a.Type("SavedFilter", func() {
+	a.Description(`JSONAPI store for the data of a saved filter. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("savedfilters")
+	})
+	a.Attribute("id", d.UUID, "ID of the saved filter", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", savedFilterAttributes)
+	a.Attribute("relationships", savedFilterRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var savedFilterAttributes = a.Type("SavedFilterAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a saved filter. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("title", d.String, "The name of the filter", func() {
+		a.Example("My open bugs")
+	})
+	a.Attribute("description", d.String, "The description of the filter", func() {
+		a.Example("Open bugs assigned to me")
+	})
+	a.Attribute("query", d.String, "A query language expression restricting the set of found work items", func() {
+		a.Example(`system.state = "open"`)
+	})
+	a.Attribute("sort", d.String, "The order of the found work items, e.g. -system.updated_at", func() {
+		a.Example("-system.updated_at")
+	})
+	a.Attribute("created-at", d.DateTime, "When the filter was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(0)
+	})
+})
+
+var savedFilterRelationships = a.Type("SavedFilterRelationships", func() {
+	a.Attribute("owner", relationGeneric, "The user who saved the filter")
+	a.Attribute("space", relationGeneric, "The space the filter is shared with, filters without a space are private")
+})
+
+var savedFilterSingle = JSONSingle(
+	"SavedFilter", "Holds a single saved filter",
+	savedFilter,
+	nil)
+
 var filterList = JSONList(
 	"filter", "Holds the list of Filters",
 	filter,
@@ -49,14 +97,88 @@ var _ = a.Resource("filter", func() {
 		a.Routing(
 			a.GET(""),
 		)
-		a.Description("List work items.")
+		a.Description("List the built-in filters and the saved filters of the current user and of a space.")
 		a.Params(func() {
 			a.Param("filter", d.String, "a query language expression restricting the set of found work items")
+			a.Param("filter[space]", d.UUID, "Include the saved filters shared with this space")
 		})
 		a.Response(d.OK, func() {
 			a.Media(filterList)
 		})
 		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
+
+var _ = a.Resource("saved_filter", func() {
+	a.BasePath("/savedfilters")
+
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:filterID"),
+		)
+		a.Description("Retrieve the saved filter with the given ID.")
+		a.Params(func() {
+			a.Param("filterID", d.UUID, "ID of the saved filter")
+		})
+		a.Response(d.OK, func() {
+			a.Media(savedFilterSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Save a filter, which is private unless it has a space relationship.")
+		a.Payload(savedFilterSingle)
+		a.Response(d.Created, "/savedfilters/.*", func() {
+			a.Media(savedFilterSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:filterID"),
+		)
+		a.Description("Update the saved filter with the given ID.")
+		a.Params(func() {
+			a.Param("filterID", d.UUID, "ID of the saved filter")
+		})
+		a.Payload(savedFilterSingle)
+		a.Response(d.OK, func() {
+			a.Media(savedFilterSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:filterID"),
+		)
+		a.Description("Delete the saved filter with the given ID.")
+		a.Params(func() {
+			a.Param("filterID", d.UUID, "ID of the saved filter")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 })
@@ -0,0 +1,139 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var label = a.Type("Label", func() {
+	a.Description(`JSONAPI store for the data of a label. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("labels")
+	})
+	a.Attribute("id", d.UUID, "ID of the label", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", labelAttributes)
+	a.Attribute("relationships", labelRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var labelAttributes = a.Type("LabelAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a label. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The name of the label, unique in a space", func() {
+		a.Example("needs-triage")
+	})
+	a.Attribute("color", d.String, "The color of the label as a hex RGB value, #cccccc if not set during creating", func() {
+		a.Pattern("^#[0-9a-fA-F]{6}$")
+		a.Example("#ff8800")
+	})
+	a.Attribute("description", d.String, "The description of the label", func() {
+		a.Example("Work items that are not triaged yet")
+	})
+	a.Attribute("created-at", d.DateTime, "When the label was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the label was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(0)
+	})
+})
+
+var labelRelationships = a.Type("LabelRelationships", func() {
+	a.Attribute("space", relationGeneric, "The space that defines the label")
+})
+
+var labelSingle = JSONSingle(
+	"Label", "Holds a single label",
+	label,
+	nil)
+
+var labelList = JSONList(
+	"Label", "Holds the list of labels of a space",
+	label,
+	nil,
+	nil)
+
+var _ = a.Resource("label", func() {
+	a.Parent("space")
+	a.BasePath("/labels")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the labels of the space, ordered by name.")
+		a.Response(d.OK, func() {
+			a.Media(labelList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:labelID"),
+		)
+		a.Description("Retrieve the label with the given ID.")
+		a.Params(func() {
+			a.Param("labelID", d.UUID, "ID of the label")
+		})
+		a.Response(d.OK, func() {
+			a.Media(labelSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a label in the space.")
+		a.Payload(labelSingle)
+		a.Response(d.Created, "/labels/.*", func() {
+			a.Media(labelSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:labelID"),
+		)
+		a.Description("Update the label with the given ID. The work items with the label show the new name and color.")
+		a.Params(func() {
+			a.Param("labelID", d.UUID, "ID of the label")
+		})
+		a.Payload(labelSingle)
+		a.Response(d.OK, func() {
+			a.Media(labelSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:labelID"),
+		)
+		a.Description("Delete the label with the given ID and remove it from the work items of the space.")
+		a.Params(func() {
+			a.Param("labelID", d.UUID, "ID of the label")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,103 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemRevision = a.Type("WorkItemRevision", func() {
+	a.Description(`JSONAPI store for the data of a work item revision. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemrevisions")
+	})
+	a.Attribute("id", d.UUID, "ID of the revision", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemRevisionAttributes)
+	a.Attribute("relationships", workItemRevisionRelationships)
+	a.Required("type", "id", "attributes")
+})
+
+var workItemRevisionAttributes = a.Type("WorkItemRevisionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a work item revision. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("revision-type", d.String, "The kind of modification", func() {
+		a.Enum("create", "update", "delete", "restore")
+	})
+	a.Attribute("time", d.DateTime, "When the work item was modified", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "The version of the work item created by the modification", func() {
+		a.Example(1)
+	})
+	a.Attribute("modifier", d.String, "The name of the user who modified the work item", func() {
+		a.Example("John Doe")
+	})
+	a.Attribute("changes", a.ArrayOf(workItemFieldChange), "The fields changed by the modification")
+	a.Required("revision-type", "time", "version", "changes")
+})
+
+var workItemFieldChange = a.Type("WorkItemFieldChange", func() {
+	a.Description(`The old and new value of a single field. Markup fields are rendered as objects holding the
+content, the markup and the rendered HTML, user fields as objects holding the ID and the name of the user.`)
+	a.Attribute("field", d.String, "The name of the field", func() {
+		a.Example("system.state")
+	})
+	a.Attribute("old-value", d.Any, "The value before the modification, missing if the field was not set", func() {
+		a.Example("open")
+	})
+	a.Attribute("new-value", d.Any, "The value after the modification, missing if the field was unset", func() {
+		a.Example("closed")
+	})
+	a.Required("field")
+})
+
+var workItemRevisionRelationships = a.Type("WorkItemRevisionRelationships", func() {
+	a.Attribute("modifier", relationGeneric, "This defines the user who modified the work item")
+})
+
+var workItemRevisionListMeta = a.Type("WorkItemRevisionListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Required("totalCount")
+})
+
+var workItemRevisionList = JSONList(
+	"WorkItemRevision", "Holds the revisions of a work item",
+	workItemRevision,
+	nil,
+	workItemRevisionListMeta,
+)
+
+var _ = a.Resource("work_item_revisions", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("revisions"),
+		)
+		a.Description("List the revisions of the given work item with the fields changed by each revision, oldest first")
+		a.Response(d.OK, func() {
+			a.Media(workItemRevisionList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+	a.Action("revert", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("revisions/:revisionId/revert"),
+		)
+		a.Description(`Restore the fields of the work item from the given revision. The restored fields are stored
+as a new revision, the type of the work item is not changed.`)
+		a.Params(func() {
+			a.Param("revisionId", d.UUID, "ID of the revision to restore")
+			a.Param("version", d.Integer, "The current version of the work item, for optimistic concurrency control")
+			a.Required("version")
+		})
+		a.Response(d.OK, workItemSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
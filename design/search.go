@@ -5,11 +5,43 @@ import (
 	a "github.com/goadesign/goa/design/apidsl"
 )
 
+// searchCommentMatch describes a comment that matched a search query
+var searchCommentMatch = a.Type("SearchCommentMatch", func() {
+	a.Description("A comment of a found work item that matched a search query")
+	a.Attribute("id", d.UUID, "ID of the comment")
+	a.Attribute("snippet", d.String, "HTML escaped excerpt of the comment body with the search terms enclosed in <b></b>", func() {
+		a.Example("the <b>login</b> page works again after the restart")
+	})
+	a.Required("id", "snippet")
+})
+
+// searchMatch describes why a work item matched a search query
+var searchMatch = a.Type("SearchMatch", func() {
+	a.Description("Describes why a work item matched a search query")
+	a.Attribute("relevance", d.Number, "Rank of the work item for the search query, higher values are more relevant")
+	a.Attribute("highlights", a.HashOf(d.String, d.String),
+		"HTML escaped excerpts of the matched fields with the search terms enclosed in <b></b>, by field name", func() {
+			a.Example(map[string]string{"system.title": "Fix the <b>login</b> page"})
+		})
+	a.Attribute("comments", a.ArrayOf(searchCommentMatch), "The most relevant comments of the work item that matched the search query")
+	a.Required("relevance")
+})
+
+var searchWorkItemListMeta = a.Type("SearchWorkItemListMeta", func() {
+	a.Attribute("totalCount", d.Integer)
+	a.Attribute("matches", a.HashOf(d.String, searchMatch), "Relevance and highlights of the found work items by work item ID")
+	a.Attribute("facets", a.HashOf(d.String, a.HashOf(d.String, d.Integer)),
+		"Number of all found work items per workitemtype, system.state, system.assignees, system.iteration and system.area value, by facet name", func() {
+			a.Example(map[string]map[string]int{"system.state": {"open": 3, "closed": 1}})
+		})
+	a.Required("totalCount")
+})
+
 var searchWorkItemList = JSONList(
 	"SearchWorkItem", "Holds the paginated response to a search request",
 	workItem,
 	pagingLinks,
-	meta)
+	searchWorkItemListMeta)
 
 var searchSpaceList = JSONList(
 	"SearchSpace", "Holds the paginated response to a search request",
@@ -31,7 +63,14 @@ var _ = a.Resource("search", func() {
 				1) "id:100" :- Look for work item hainvg id 100
 				2) "url:http://demo.almighty.io/details/500" :- Search on WI having id 500 and check 
 					if this URL is mentioned in searchable columns of work item
-				3) "simple keywords separated by space" :- Search in Work Items based on these keywords.`)
+				3) "simple keywords separated by space" :- Search in Work Items based on these keywords.
+					Keywords prefixed with "-" exclude the work items that contain them.
+				4) "state:open assignee:me iteration:\"Sprint 4\"" :- Restrict the results to work items
+					with the given state, assignee, creator, iteration, area or space. Users are given by
+					username or ID ("me" is the logged in user), iterations, areas and spaces by name or ID.
+					Qualifiers prefixed with "-" exclude the work items with the given value.
+				Results are ordered by relevance.`)
+			a.Param("filter[space]", d.UUID, "Restrict the results to the work items of a space")
 			a.Param("page[offset]", d.String, "Paging start position") // #428
 			a.Param("page[limit]", d.Integer, "Paging size")
 			a.Required("q")
@@ -40,6 +79,7 @@ var _ = a.Resource("search", func() {
 			a.Media(searchWorkItemList)
 		})
 		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 	a.Action("spaces", func() {
@@ -60,6 +60,11 @@ var spaceAttributes = a.Type("SpaceAttributes", func() {
 	a.Attribute("description", d.String, "Description for the space", func() {
 		a.Example("This is the foobar collaboration space")
 	})
+	a.Attribute("key-prefix", d.String, `Prefix of the human-readable keys of the work items in the space, e.g. PLAT
+for PLAT-42. It is derived from the name of the space if it is not given during creating and cannot be changed afterwards.`, func() {
+		a.Pattern("^[A-Z][A-Z0-9]{0,9}$")
+		a.Example("PLAT")
+	})
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
 		a.Example(23)
 	})
@@ -0,0 +1,44 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = a.Resource("space_trash", func() {
+	a.Parent("space")
+	a.BasePath("/trash")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description(`List the deleted work items of the space, most recently deleted first. The time of the
+deletion is given in the 'system.deleted_at' attribute of the work items.`)
+		a.Params(func() {
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, workItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("restore", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiId/restore"),
+		)
+		a.Description(`Restore a deleted work item along with the links that were deleted with it. Links to work
+items that are still deleted are not restored.`)
+		a.Params(func() {
+			a.Param("wiId", d.String, "ID of the deleted work item")
+		})
+		a.Response(d.OK, workItemSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
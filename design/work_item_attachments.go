@@ -0,0 +1,143 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemAttachment = a.Type("WorkItemAttachment", func() {
+	a.Description(`JSONAPI store for the metadata of a file attached to a work item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemattachments")
+	})
+	a.Attribute("id", d.UUID, "ID of the attachment", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemAttachmentAttributes)
+	a.Attribute("relationships", workItemAttachmentRelationships)
+	a.Attribute("links", workItemAttachmentLinks)
+	a.Required("type", "id", "attributes")
+})
+
+var workItemAttachmentAttributes = a.Type("WorkItemAttachmentAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a work item attachment. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The file name of the attachment", func() {
+		a.Example("server.log")
+	})
+	a.Attribute("size", d.Integer, "The size of the content in bytes", func() {
+		a.Example(2048)
+	})
+	a.Attribute("content-type", d.String, "The media type of the content", func() {
+		a.Example("text/plain")
+	})
+	a.Attribute("checksum", d.String, "The hex encoded SHA-256 digest of the content", func() {
+		a.Example("9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08")
+	})
+	a.Attribute("created-at", d.DateTime, "When the file was attached", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Required("name", "size", "content-type", "checksum", "created-at")
+})
+
+var workItemAttachmentRelationships = a.Type("WorkItemAttachmentRelationships", func() {
+	a.Attribute("creator", relationGeneric, "The user who attached the file")
+	a.Attribute("workitem", relationGeneric, "The work item the file is attached to")
+})
+
+var workItemAttachmentLinks = a.Type("WorkItemAttachmentLinks", func() {
+	a.Attribute("self", d.String)
+	a.Attribute("content", d.String, "URL to download the content of the attachment")
+})
+
+var workItemAttachmentSingle = JSONSingle(
+	"WorkItemAttachment", "Holds a single work item attachment",
+	workItemAttachment,
+	nil)
+
+var workItemAttachmentList = JSONList(
+	"WorkItemAttachment", "Holds the attachments of a work item",
+	workItemAttachment,
+	nil,
+	nil)
+
+var _ = a.Resource("work_item_attachments", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("attachments"),
+		)
+		a.Description("List the files attached to the given work item, oldest first.")
+		a.Response(d.OK, func() {
+			a.Media(workItemAttachmentList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("attachments/:attachmentID"),
+		)
+		a.Description("Retrieve the metadata of the attachment with the given ID.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemAttachmentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("download", func() {
+		a.Routing(
+			a.GET("attachments/:attachmentID/content"),
+		)
+		a.Description("Download the content of the attachment with the given ID. Content that is not a PDF, a plain text or a GIF, JPEG or PNG image is sent as application/octet-stream.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("attachments"),
+		)
+		a.Description(`Attach a file to the given work item. The request body is the content of the file and
+its media type is taken from the Content-Type header. The size of the content is limited by the configuration.`)
+		a.Params(func() {
+			a.Param("name", d.String, "The file name of the attachment", func() {
+				a.MinLength(1)
+			})
+			a.Required("name")
+		})
+		a.Response(d.Created, "/attachments/.*", func() {
+			a.Media(workItemAttachmentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("attachments/:attachmentID"),
+		)
+		a.Description("Delete the attachment with the given ID along with its content, only its uploader may delete it.")
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
@@ -183,6 +183,9 @@ func createWorkItemLink() {
 		a.Media(workItemLink)
 	})
 	a.Response(d.BadRequest, JSONAPIErrors)
+	a.Response(d.Conflict, JSONAPIErrors, func() {
+		a.Description("This error arises when the link would give a work item a second parent in a tree or close a cycle in a tree or dependency topology.")
+	})
 	a.Response(d.InternalServerError, JSONAPIErrors)
 	a.Response(d.Unauthorized, JSONAPIErrors)
 }
@@ -217,6 +220,9 @@ func updateWorkItemLink() {
 		a.Media(workItemLink)
 	})
 	a.Response(d.BadRequest, JSONAPIErrors)
+	a.Response(d.Conflict, JSONAPIErrors, func() {
+		a.Description("This error arises when the link would give a work item a second parent in a tree or close a cycle in a tree or dependency topology.")
+	})
 	a.Response(d.InternalServerError, JSONAPIErrors)
 	a.Response(d.NotFound, JSONAPIErrors)
 	a.Response(d.Unauthorized, JSONAPIErrors)
@@ -0,0 +1,139 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var workItemTemplate = a.Type("WorkItemTemplate", func() {
+	a.Description(`JSONAPI store for the data of a work item template. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemtemplates")
+	})
+	a.Attribute("id", d.UUID, "ID of the work item template", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemTemplateAttributes)
+	a.Attribute("relationships", workItemTemplateRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var workItemTemplateAttributes = a.Type("WorkItemTemplateAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a work item template. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The name of the template, unique for a work item type in a space", func() {
+		a.Example("Bug report")
+	})
+	a.Attribute("description", d.String, "The description of the template", func() {
+		a.Example("Steps to reproduce, expected and actual behavior")
+	})
+	a.Attribute("fields", a.HashOf(d.String, d.Any), "The field values pre-filled by the template, as in the attributes of a work item", func() {
+		a.Example(map[string]interface{}{"system.description": "## Steps to reproduce"})
+	})
+	a.Attribute("created-at", d.DateTime, "When the template was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(0)
+	})
+})
+
+var workItemTemplateRelationships = a.Type("WorkItemTemplateRelationships", func() {
+	a.Attribute("baseType", relationBaseType, "The type of the work items pre-filled by the template")
+	a.Attribute("space", relationGeneric, "The space that defines the template")
+})
+
+var workItemTemplateSingle = JSONSingle(
+	"WorkItemTemplate", "Holds a single work item template",
+	workItemTemplate,
+	nil)
+
+var workItemTemplateList = JSONList(
+	"WorkItemTemplate", "Holds the list of work item templates of a space",
+	workItemTemplate,
+	nil,
+	nil)
+
+var _ = a.Resource("work_item_template", func() {
+	a.Parent("space")
+	a.BasePath("/templates")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the work item templates of the space, ordered by name.")
+		a.Params(func() {
+			a.Param("filter[workitemtype]", d.UUID, "Only list the templates of this work item type")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemTemplateList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:templateID"),
+		)
+		a.Description("Retrieve the work item template with the given ID.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "ID of the work item template")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a work item template for the work item type given in the baseType relationship.")
+		a.Payload(workItemTemplateSingle)
+		a.Response(d.Created, "/templates/.*", func() {
+			a.Media(workItemTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:templateID"),
+		)
+		a.Description("Update the work item template with the given ID. The work item type of a template cannot change.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "ID of the work item template")
+		})
+		a.Payload(workItemTemplateSingle)
+		a.Response(d.OK, func() {
+			a.Media(workItemTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:templateID"),
+		)
+		a.Description("Delete the work item template with the given ID.")
+		a.Params(func() {
+			a.Param("templateID", d.UUID, "ID of the work item template")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
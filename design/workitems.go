@@ -45,6 +45,8 @@ var workItemRelationships = a.Type("WorkItemRelationships", func() {
 	a.Attribute("area", relationGeneric, "This defines the area this work item belongs to")
 	a.Attribute("children", relationGeneric, "This defines the children of this work item")
 	a.Attribute("space", relationSpaces, "This defines the owning space of this work item.")
+	a.Attribute("template", relationGeneric, "The template of the space that pre-fills the fields of a new work item")
+	a.Attribute("labels", relationGenericList, "This defines the labels of the space attached to the work item")
 })
 
 // relationBaseType is top level block for WorkItemType relationship
@@ -78,6 +80,25 @@ var workItemList = JSONList(
 	pagingLinks,
 	meta)
 
+// traversedWorkItem is a work item reached by following links transitively from a start work item
+var traversedWorkItem = a.Type("TraversedWorkItem", func() {
+	a.Attribute("depth", d.Integer, "Number of links between the start work item and this work item", func() {
+		a.Example(2)
+	})
+	a.Attribute("path", a.ArrayOf(d.String), "IDs of the work items on the shortest path from the start work item to this work item, both included", func() {
+		a.Example([]string{"42", "43", "51"})
+	})
+	a.Attribute("workitem", workItem)
+	a.Required("depth", "path", "workitem")
+})
+
+// traversedWorkItemList holds the work items reached by a link traversal, closest first
+var traversedWorkItemList = JSONList(
+	"TraversedWorkItem", "Holds the work items reached by following links of a type from a work item",
+	traversedWorkItem,
+	nil,
+	meta)
+
 // workItemSingle is the media type for work items
 var workItemSingle = JSONSingle(
 	"WorkItem", "A work item holds field values according to a given field type in JSONAPI form",
@@ -105,6 +126,36 @@ var workItemReorder = Reorder(
 	workItem,
 	position)
 
+// workItemBulkUpdatePayload wraps the data of a bulk update request
+var workItemBulkUpdatePayload = a.Type("WorkItemBulkUpdatePayload", func() {
+	a.Attribute("data", workItemBulkUpdateData)
+	a.Required("data")
+})
+
+// workItemBulkUpdateData selects the work items to update either by their IDs or by a filter and holds
+// the changes to apply to each of them
+var workItemBulkUpdateData = a.Type("WorkItemBulkUpdateData", func() {
+	a.Attribute("items", a.ArrayOf(workItemBulkUpdateItem), "The work items to update, cannot be combined with a filter")
+	a.Attribute("filter", d.String, "A query language expression selecting the work items of the space to update", func() {
+		a.Example(`{"system.iteration": "f1b2dd93-0b45-4b3b-8a3e-48a1e6f4d6b2"}`)
+	})
+	a.Attribute("attributes", a.HashOf(d.String, d.Any), "The field values to set on every work item", func() {
+		a.Example(map[string]interface{}{"system.state": "closed"})
+	})
+	a.Attribute("relationships", workItemRelationships, "The iteration, area or assignees to set on every work item")
+})
+
+// workItemBulkUpdateItem identifies a work item to update in a bulk update
+var workItemBulkUpdateItem = a.Type("WorkItemBulkUpdateItem", func() {
+	a.Attribute("id", d.String, "ID of the work item", func() {
+		a.Example("42")
+	})
+	a.Attribute("version", d.Integer, "The version the work item is expected to have, any version is accepted if missing", func() {
+		a.Example(3)
+	})
+	a.Required("id")
+})
+
 // new version of "list" for migration
 var _ = a.Resource("workitem", func() {
 	a.Parent("space")
@@ -113,9 +164,16 @@ var _ = a.Resource("workitem", func() {
 		a.Routing(
 			a.GET("/:wiId"),
 		)
-		a.Description("Retrieve work item with given id.")
+		a.Description(`Retrieve work item with given id or human-readable key, e.g. PLAT-42. The work item is returned
+as it was at the given time or in the given version if one of the 'at' and 'version' parameters is set.`)
 		a.Params(func() {
-			a.Param("wiId", d.String, "wiId")
+			a.Param("wiId", d.String, "ID or key of the work item", func() {
+				a.Example("PLAT-42")
+			})
+			a.Param("at", d.DateTime, "Return the work item as it was at the given time", func() {
+				a.Example("2016-11-29T23:18:14Z")
+			})
+			a.Param("version", d.Integer, "Return the work item as it was in the given version")
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, workItemSingle)
@@ -132,17 +190,25 @@ var _ = a.Resource("workitem", func() {
 		a.Params(func() {
 			a.Param("filter", d.String, "a query language expression restricting the set of found work items")
 			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[cursor]", d.String, `Paging start position as an opaque token taken from the links
+of a previous page, an empty value starts at the first work item. Takes precedence over page[offset].`)
 			a.Param("page[limit]", d.Integer, "Paging size")
 			a.Param("filter[assignee]", d.String, "Work Items assigned to the given user")
 			a.Param("filter[iteration]", d.String, "IterationID to filter work items")
 			a.Param("filter[workitemtype]", d.UUID, "ID of work item type to filter work items by")
 			a.Param("filter[area]", d.String, "AreaID to filter work items")
 			a.Param("filter[workitemstate]", d.String, "work item state to filter work items by")
+			a.Param("filter[saved]", d.UUID, "ID of a saved filter whose query restricts the found work items")
+			a.Param("filter[label]", d.String, "ID or name of a label of the space to filter work items by")
+			a.Param("sort", d.String, `comma separated list of fields to sort the work items by,
+a field prefixed with "-" is sorted in descending order (e.g. "-system.updated_at,system.title")`)
 		})
 		a.UseTrait("conditional")
 		a.Response(d.OK, workItemList)
 		a.Response(d.NotModified)
 		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 	a.Action("list-children", func() {
@@ -161,6 +227,33 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
 
+	a.Action("traverse", func() {
+		a.Routing(
+			a.GET("/:wiId/traverse"),
+		)
+		a.Description(`List the work items reached by following the links of the given type transitively,
+e.g. all descendants of an epic, all ancestors of a task or everything blocked by a work item`)
+		a.Params(func() {
+			a.Param("wiId", d.String, "ID or key of the work item to start from", func() {
+				a.Example("PLAT-42")
+			})
+			a.Param("linkType", d.UUID, "ID of the work item link type to follow")
+			a.Param("direction", d.String, `"forward" follows the links from their source to their target,
+e.g. to the children, "reverse" from their target to their source, e.g. to the parents; defaults to "forward"`, func() {
+				a.Enum("forward", "reverse")
+			})
+			a.Param("depth", d.Integer, "Maximum number of links to follow, unlimited if missing", func() {
+				a.Minimum(1)
+			})
+			a.Param("filter", d.String, "a query language expression restricting the set of returned work items")
+			a.Required("linkType")
+		})
+		a.Response(d.OK, traversedWorkItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
 	a.Action("create", func() {
 		a.Security("jwt")
 		a.Routing(
@@ -224,6 +317,21 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 		a.Response(d.Unauthorized, JSONAPIErrors)
 	})
+	a.Action("bulk-update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/bulk"),
+		)
+		a.Description(`Apply the same changes to several work items at once. The work items are updated in a single
+transaction: if one of them cannot be updated then none is, and the response holds an error for each
+work item that failed, with the ID of the work item in the meta object of the error.`)
+		a.Payload(workItemBulkUpdatePayload)
+		a.Response(d.OK, workItemList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
 })
 
 // new version of "list" for migration
@@ -235,6 +343,8 @@ var _ = a.Resource("redirect_workitem", func() {
 		)
 		a.Params(func() {
 			a.Param("wiId", d.String, "wiId")
+			a.Param("at", d.DateTime, "Return the work item as it was at the given time")
+			a.Param("version", d.Integer, "Return the work item as it was in the given version")
 		})
 		a.Response(d.MovedPermanently)
 	})
@@ -246,12 +356,18 @@ var _ = a.Resource("redirect_workitem", func() {
 		a.Params(func() {
 			a.Param("filter", d.String, "a query language expression restricting the set of found work items")
 			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[cursor]", d.String, `Paging start position as an opaque token taken from the links
+of a previous page, an empty value starts at the first work item. Takes precedence over page[offset].`)
 			a.Param("page[limit]", d.Integer, "Paging size")
 			a.Param("filter[assignee]", d.String, "Work Items assigned to the given user")
 			a.Param("filter[iteration]", d.String, "IterationID to filter work items")
 			a.Param("filter[workitemtype]", d.UUID, "ID of work item type to filter work items by")
 			a.Param("filter[area]", d.String, "AreaID to filter work items")
 			a.Param("filter[workitemstate]", d.String, "work item state to filter work items by")
+			a.Param("filter[saved]", d.UUID, "ID of a saved filter whose query restricts the found work items")
+			a.Param("filter[label]", d.String, "ID or name of a label of the space to filter work items by")
+			a.Param("sort", d.String, `comma separated list of fields to sort the work items by,
+a field prefixed with "-" is sorted in descending order (e.g. "-system.updated_at,system.title")`)
 
 		})
 		a.Response(d.MovedPermanently)
@@ -12,10 +12,56 @@ var fieldType = a.Type("fieldType", func() {
 	a.Attribute("componentType", d.String, "The kind of type of the individual elements for a list type. Required for list types. Must be a simple type, not  enum or list")
 	a.Attribute("baseType", d.String, "The kind of type of the enumeration values for an enum type. Required for enum types. Must be a simple type, not  enum or list")
 	a.Attribute("values", a.ArrayOf(d.Any), "The possible values for an enum type. The values must be of a type convertible to the base type")
+	a.Attribute("rollUp", fieldRollUp, "The aggregation computing the values of a roll-up type. Required for roll-up types")
 
 	a.Required("kind")
 })
 
+// fieldRollUp describes how the value of a computed field is aggregated from the children of a work item
+var fieldRollUp = a.Type("fieldRollUp", func() {
+	a.Description(`A fieldRollUp computes the value of a field from the work items linked as children with the
+"Parent child item" link type. The value is kept up to date when the children or the links change.`)
+	a.Attribute("function", d.String, "The aggregation of the children", func() {
+		a.Enum("sum", "min", "max", "count", "percent_complete")
+	})
+	a.Attribute("field", d.String, "The aggregated field of the children, the state field for percent_complete (defaults to system.state)", func() {
+		a.Example("storypoints")
+	})
+	a.Attribute("doneValues", a.ArrayOf(d.String), "The states of complete children for percent_complete (defaults to closed)", func() {
+		a.Example([]string{"closed", "resolved"})
+	})
+	a.Required("function")
+})
+
+// fieldRules are the constraints on the values of a field in addition to its type
+var fieldRules = a.Type("fieldRules", func() {
+	a.Description(`The fieldRules constrain the values of a field. Each rule only applies to some kinds of
+fields and a value violating a rule is rejected with an error naming the field.`)
+	a.Attribute("min", d.Number, "The minimum value of an integer or float field", func() {
+		a.Example(0)
+	})
+	a.Attribute("max", d.Number, "The maximum value of an integer or float field", func() {
+		a.Example(100)
+	})
+	a.Attribute("maxLength", d.Integer, "The maximum number of characters of a string field", func() {
+		a.Example(255)
+	})
+	a.Attribute("pattern", d.String, "A regular expression the values of a string field must match", func() {
+		a.Example("^[A-Z]+-[0-9]+$")
+	})
+	a.Attribute("urlSchemes", a.ArrayOf(d.String), "The allowed schemes of the values of a URL field", func() {
+		a.Example([]string{"https"})
+	})
+	a.Attribute("notBefore", d.DateTime, "The earliest value of an instant field")
+	a.Attribute("notAfter", d.DateTime, "The latest value of an instant field")
+	a.Attribute("minItems", d.Integer, "The minimum number of elements of a list field", func() {
+		a.Example(1)
+	})
+	a.Attribute("maxItems", d.Integer, "The maximum number of elements of a list field", func() {
+		a.Example(3)
+	})
+})
+
 // fieldDefinition defines the possible values for a field in a work item type
 var fieldDefinition = a.Type("fieldDefinition", func() {
 	a.Description("A fieldDefinition aggregates a fieldType and additional field metadata")
@@ -29,9 +75,47 @@ var fieldDefinition = a.Type("fieldDefinition", func() {
 		a.Example("The iteration field tells to which iteration a work item belongs.")
 		a.MinLength(1)
 	})
+	a.Attribute("rules", fieldRules, "Optional constraints on the values of the field")
+	a.Attribute("defaultValue", d.Any, "The value of the field when a work item is created without it", func() {
+		a.Example("open")
+	})
 	a.Required("required", "type", "label", "description")
 })
 
+// workItemTypeWorkflow restricts the changes of an enum field of the work items of a type
+var workItemTypeWorkflow = a.Type("WorkItemTypeWorkflow", func() {
+	a.Description(`A workflow restricts the changes of the value of an enum field, usually the state, to a set
+of transitions. A change of the field that is not one of the transitions is rejected.`)
+	a.Attribute("field", d.String, "The enum field whose changes are restricted, 'system.state' if missing", func() {
+		a.Example("system.state")
+	})
+	a.Attribute("transitions", a.ArrayOf(workItemTypeTransition), "The allowed changes of the field")
+	a.Required("transitions")
+})
+
+// workItemTypeTransition is an allowed change of the value of the workflow field
+var workItemTypeTransition = a.Type("WorkItemTypeTransition", func() {
+	a.Description("A transition allows to change the value of the workflow field from one enum value to another")
+	a.Attribute("from", d.String, "The current value of the field", func() {
+		a.Example("open")
+	})
+	a.Attribute("to", d.String, "The new value of the field", func() {
+		a.Example("in progress")
+	})
+	a.Attribute("requiredFields", a.ArrayOf(d.String), "The fields that must have a value for the transition to be taken", func() {
+		a.Example([]string{"system.assignees"})
+	})
+	a.Required("from", "to")
+})
+
+// workItemTypeWorkflowUpdate replaces the workflow of an existing work item type
+var workItemTypeWorkflowUpdate = a.Type("WorkItemTypeWorkflowUpdate", func() {
+	a.Description("Replaces the workflow of a work item type, a missing workflow removes it")
+	a.Attribute("version", d.Integer, "Version of the work item type for optimistic concurrency control")
+	a.Attribute("workflow", workItemTypeWorkflow, "The new workflow of the work item type")
+	a.Required("version")
+})
+
 var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 	a.Description("A work item type describes the values a work item type instance can hold.")
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control")
@@ -55,6 +139,7 @@ var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 		})
 		a.MinLength(1)
 	})
+	a.Attribute("workflow", workItemTypeWorkflow, "The optional workflow of the work item type, inherited from the extended type if missing when creating. It is changed with the set-workflow action later on.")
 
 	// TODO: Maybe this needs to be abandoned at some point
 	a.Attribute("extendedTypeName", d.UUID, "If newly created type extends any existing type (This is never present in any response and is only optional when creating.)")
@@ -145,6 +230,22 @@ var _ = a.Resource("workitemtype", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 		a.Response(d.Unauthorized, JSONAPIErrors)
 	})
+	a.Action("set-workflow", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PUT("/:witID/workflow"),
+		)
+		a.Description("Replace the workflow of the work item type with the given ID, which applies to existing types as well.")
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+		})
+		a.Payload(workItemTypeWorkflowUpdate)
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
 	a.Action("list", func() {
 		a.Routing(
 			a.GET(""),
@@ -51,6 +51,17 @@ func NewVersionConflictError(msg string) VersionConflictError {
 	return VersionConflictError{simpleError{msg}}
 }
 
+// DataConflictError means that the operation cannot be performed because it conflicts with the
+// current state of the data
+type DataConflictError struct {
+	simpleError
+}
+
+// NewDataConflictError returns the custom defined error of type DataConflictError.
+func NewDataConflictError(msg string) DataConflictError {
+	return DataConflictError{simpleError{msg}}
+}
+
 // BadParameterError means that a parameter was not as required
 type BadParameterError struct {
 	parameter        string
@@ -56,3 +56,12 @@ func TestNewUnauthorizedError(t *testing.T) {
 
 	assert.Equal(t, msg, err.Error())
 }
+
+func TestNewDataConflictError(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	msg := "Work item is not deleted"
+	err := errors.NewDataConflictError(msg)
+
+	assert.Equal(t, msg, err.Error())
+}
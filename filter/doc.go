@@ -0,0 +1,3 @@
+// Package filter provides the saved work item filters, which are named
+// queries that users keep for themselves or share with a space.
+package filter
@@ -0,0 +1,188 @@
+package filter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
+	"github.com/almighty/almighty-core/log"
+	"github.com/almighty/almighty-core/query"
+	"github.com/almighty/almighty-core/space"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// APIStringTypeSavedFilter is the JSON API type of saved filters
+const APIStringTypeSavedFilter = "savedfilters"
+
+// SavedFilter is a named work item query with an optional sort order
+type SavedFilter struct {
+	gormsupport.Lifecycle
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	Title       string
+	Description string
+	// Query is an expression of the work item query language
+	Query string
+	// Sort is the order of the found work items in the syntax of the "sort" parameter
+	Sort    string
+	OwnerID uuid.UUID `sql:"type:uuid"`
+	// SpaceID is the space the filter is shared with, filters without a space are private
+	SpaceID *uuid.UUID `sql:"type:uuid"`
+	Version int
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (f SavedFilter) TableName() string {
+	return "saved_filters"
+}
+
+// VisibleTo returns true if the given identity may use the filter. Private filters are visible
+// to their owner only, shared filters to the members of the given space they are shared with as
+// well. The owner of a space is its only member for now.
+func (f SavedFilter) VisibleTo(identityID *uuid.UUID, sharedWith *space.Space) bool {
+	if identityID == nil {
+		return false
+	}
+	if *identityID == f.OwnerID {
+		return true
+	}
+	return f.SpaceID != nil && sharedWith != nil && sharedWith.ID == *f.SpaceID && sharedWith.OwnerId == *identityID
+}
+
+// validate checks that the filter has a title and that its query and sort order can be parsed
+func (f SavedFilter) validate() error {
+	if strings.TrimSpace(f.Title) == "" {
+		return errors.NewBadParameterError("title", f.Title).Expected("not empty")
+	}
+	if _, err := query.Parse(&f.Query); err != nil {
+		return errors.NewBadParameterError("query", f.Query).Expected(err.Error())
+	}
+	if _, err := workitem.ParseSort(&f.Sort); err != nil {
+		return errs.WithStack(err)
+	}
+	return nil
+}
+
+// SavedFilterRepository encapsulates storage & retrieval of saved filters
+type SavedFilterRepository interface {
+	Create(ctx context.Context, f *SavedFilter) error
+	Load(ctx context.Context, id uuid.UUID) (*SavedFilter, error)
+	Save(ctx context.Context, f SavedFilter) (*SavedFilter, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, ownerID *uuid.UUID, spaceID *uuid.UUID) ([]SavedFilter, error)
+}
+
+// NewSavedFilterRepository creates a new storage type.
+func NewSavedFilterRepository(db *gorm.DB) SavedFilterRepository {
+	return &GormSavedFilterRepository{db: db}
+}
+
+// GormSavedFilterRepository is the implementation of the storage interface for saved filters.
+type GormSavedFilterRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new saved filter
+// returns BadParameterError or InternalError
+func (r *GormSavedFilterRepository) Create(ctx context.Context, f *SavedFilter) error {
+	defer goa.MeasureSince([]string{"goa", "db", "savedfilter", "create"}, time.Now())
+	if err := f.validate(); err != nil {
+		return errs.WithStack(err)
+	}
+	f.ID = uuid.NewV4()
+	if err := r.db.Create(f).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"saved_filter_id": f.ID,
+			"err":             err,
+		}, "unable to create the saved filter")
+		return errors.NewInternalError(err.Error())
+	}
+	return nil
+}
+
+// Load returns the saved filter with the given ID
+// returns NotFoundError or InternalError
+func (r *GormSavedFilterRepository) Load(ctx context.Context, id uuid.UUID) (*SavedFilter, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "savedfilter", "get"}, time.Now())
+	var result SavedFilter
+	tx := r.db.Where("id = ?", id).First(&result)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("saved filter", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	return &result, nil
+}
+
+// Save updates the given saved filter. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormSavedFilterRepository) Save(ctx context.Context, f SavedFilter) (*SavedFilter, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "savedfilter", "save"}, time.Now())
+	if err := f.validate(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if _, err := r.Load(ctx, f.ID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	oldVersion := f.Version
+	f.Version++
+	tx := r.db.Where("version = ?", oldVersion).Save(&f)
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"saved_filter_id": f.ID,
+	}, "saved filter updated successfully")
+	return &f, nil
+}
+
+// Delete deletes the saved filter with the given ID
+// returns NotFoundError or InternalError
+func (r *GormSavedFilterRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "savedfilter", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("saved filter", id.String())
+	}
+	tx := r.db.Delete(&SavedFilter{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("saved filter", id.String())
+	}
+	return nil
+}
+
+// List returns the filters owned by the given identity and the filters shared with the given
+// space, ordered by title. Nil arguments are ignored.
+func (r *GormSavedFilterRepository) List(ctx context.Context, ownerID *uuid.UUID, spaceID *uuid.UUID) ([]SavedFilter, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "savedfilter", "list"}, time.Now())
+	result := []SavedFilter{}
+	if ownerID == nil && spaceID == nil {
+		return result, nil
+	}
+	db := r.db.Order("title, id")
+	switch {
+	case ownerID != nil && spaceID != nil:
+		db = db.Where("owner_id = ? OR space_id = ?", *ownerID, *spaceID)
+	case ownerID != nil:
+		db = db.Where("owner_id = ?", *ownerID)
+	default:
+		db = db.Where("space_id = ?", *spaceID)
+	}
+	if err := db.Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return result, nil
+}
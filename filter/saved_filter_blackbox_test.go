@@ -0,0 +1,179 @@
+package filter_test
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	localerror "github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/filter"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSavedFilterRepository struct {
+	gormtestsupport.DBTestSuite
+
+	clean func()
+}
+
+func TestRunSavedFilterRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestSavedFilterRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (test *TestSavedFilterRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+}
+
+func (test *TestSavedFilterRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestSavedFilterRepository) createOwner(name string) uuid.UUID {
+	identity, err := testsupport.CreateTestIdentity(test.DB, name+uuid.NewV4().String(), "test provider")
+	require.Nil(test.T(), err)
+	return identity.ID
+}
+
+func (test *TestSavedFilterRepository) TestCreateAndLoad() {
+	// given
+	repo := filter.NewSavedFilterRepository(test.DB)
+	f := filter.SavedFilter{
+		Title:   "open bugs",
+		Query:   `{"system.state": "open"}`,
+		Sort:    "-system.updated_at",
+		OwnerID: test.createOwner("TestCreateAndLoad"),
+	}
+	// when
+	err := repo.Create(context.Background(), &f)
+	// then
+	require.Nil(test.T(), err)
+	require.NotEqual(test.T(), uuid.Nil, f.ID)
+	loaded, err := repo.Load(context.Background(), f.ID)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), f.Title, loaded.Title)
+	assert.Equal(test.T(), f.Query, loaded.Query)
+	assert.Equal(test.T(), f.Sort, loaded.Sort)
+	assert.Equal(test.T(), f.OwnerID, loaded.OwnerID)
+	assert.Nil(test.T(), loaded.SpaceID)
+}
+
+func (test *TestSavedFilterRepository) TestCreateInvalid() {
+	repo := filter.NewSavedFilterRepository(test.DB)
+	ownerID := test.createOwner("TestCreateInvalid")
+	for _, f := range []filter.SavedFilter{
+		{Title: " ", Query: `{"system.state": "open"}`, OwnerID: ownerID},
+		{Title: "broken query", Query: `{"system.state": `, OwnerID: ownerID},
+		{Title: "broken sort", Query: `{"system.state": "open"}`, Sort: "system.title,", OwnerID: ownerID},
+	} {
+		err := repo.Create(context.Background(), &f)
+		require.NotNil(test.T(), err, "expected error for %s", f.Title)
+		_, ok := errors.Cause(err).(localerror.BadParameterError)
+		assert.True(test.T(), ok, "expected bad parameter error for %s", f.Title)
+	}
+}
+
+func (test *TestSavedFilterRepository) TestSaveAndDelete() {
+	// given
+	repo := filter.NewSavedFilterRepository(test.DB)
+	f := filter.SavedFilter{
+		Title:   "mine",
+		Query:   `{"system.state": "open"}`,
+		OwnerID: test.createOwner("TestSaveAndDelete"),
+	}
+	require.Nil(test.T(), repo.Create(context.Background(), &f))
+	// when
+	f.Title = "still mine"
+	updated, err := repo.Save(context.Background(), f)
+	// then
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), "still mine", updated.Title)
+	assert.Equal(test.T(), f.Version+1, updated.Version)
+	// when saving the outdated version
+	_, err = repo.Save(context.Background(), f)
+	// then
+	_, ok := errors.Cause(err).(localerror.VersionConflictError)
+	assert.True(test.T(), ok)
+	// when
+	err = repo.Delete(context.Background(), f.ID)
+	// then
+	require.Nil(test.T(), err)
+	_, err = repo.Load(context.Background(), f.ID)
+	_, ok = errors.Cause(err).(localerror.NotFoundError)
+	assert.True(test.T(), ok)
+	err = repo.Delete(context.Background(), f.ID)
+	_, ok = errors.Cause(err).(localerror.NotFoundError)
+	assert.True(test.T(), ok)
+}
+
+func (test *TestSavedFilterRepository) TestList() {
+	// given
+	repo := filter.NewSavedFilterRepository(test.DB)
+	s, err := space.NewRepository(test.DB).Create(context.Background(), &space.Space{Name: "TestList " + uuid.NewV4().String()})
+	require.Nil(test.T(), err)
+	owner := test.createOwner("TestList owner")
+	other := test.createOwner("TestList other")
+	create := func(title string, ownerID uuid.UUID, spaceID *uuid.UUID) filter.SavedFilter {
+		f := filter.SavedFilter{Title: title, Query: `{"system.state": "open"}`, OwnerID: ownerID, SpaceID: spaceID}
+		require.Nil(test.T(), repo.Create(context.Background(), &f))
+		return f
+	}
+	privateOfOwner := create("b private", owner, nil)
+	sharedByOther := create("a shared", other, &s.ID)
+	create("c private of other", other, nil)
+	titles := func(filters []filter.SavedFilter) []string {
+		result := []string{}
+		for _, f := range filters {
+			result = append(result, f.Title)
+		}
+		return result
+	}
+	// when listing by owner and space
+	result, err := repo.List(context.Background(), &owner, &s.ID)
+	// then
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), []string{sharedByOther.Title, privateOfOwner.Title}, titles(result))
+	// when listing by space only
+	result, err = repo.List(context.Background(), nil, &s.ID)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), []string{sharedByOther.Title}, titles(result))
+	// when listing by owner only
+	result, err = repo.List(context.Background(), &owner, nil)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), []string{privateOfOwner.Title}, titles(result))
+	// when listing without arguments
+	result, err = repo.List(context.Background(), nil, nil)
+	require.Nil(test.T(), err)
+	assert.Empty(test.T(), result)
+}
+
+func TestSavedFilterVisibleTo(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given
+	owner := uuid.NewV4()
+	member := uuid.NewV4()
+	outsider := uuid.NewV4()
+	s := space.Space{ID: uuid.NewV4(), OwnerId: member}
+	private := filter.SavedFilter{OwnerID: owner}
+	shared := filter.SavedFilter{OwnerID: owner, SpaceID: &s.ID}
+	// then
+	assert.True(t, private.VisibleTo(&owner, nil))
+	assert.False(t, private.VisibleTo(&member, &s))
+	assert.False(t, private.VisibleTo(nil, nil))
+	assert.True(t, shared.VisibleTo(&owner, nil))
+	assert.True(t, shared.VisibleTo(&member, &s))
+	assert.False(t, shared.VisibleTo(&member, nil))
+	assert.False(t, shared.VisibleTo(&outsider, &s))
+	assert.False(t, shared.VisibleTo(nil, &s))
+}
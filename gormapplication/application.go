@@ -10,12 +10,16 @@ import (
 	"github.com/almighty/almighty-core/auth"
 	"github.com/almighty/almighty-core/codebase"
 	"github.com/almighty/almighty-core/comment"
+	"github.com/almighty/almighty-core/filter"
 	"github.com/almighty/almighty-core/iteration"
+	"github.com/almighty/almighty-core/label"
 	"github.com/almighty/almighty-core/remoteworkitem"
 	"github.com/almighty/almighty-core/search"
 	"github.com/almighty/almighty-core/space"
 	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
 	"github.com/almighty/almighty-core/workitem/link"
+	"github.com/almighty/almighty-core/workitem/template"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 )
@@ -70,6 +74,10 @@ func (g *GormBase) WorkItems() workitem.WorkItemRepository {
 	return workitem.NewWorkItemRepository(g.db)
 }
 
+func (g *GormBase) WorkItemRevisions() workitem.RevisionRepository {
+	return workitem.NewRevisionRepository(g.db)
+}
+
 func (g *GormBase) WorkItemTypes() workitem.WorkItemTypeRepository {
 	return workitem.NewWorkItemTypeRepository(g.db)
 }
@@ -143,6 +151,26 @@ func (g *GormBase) Codebases() codebase.Repository {
 	return codebase.NewCodebaseRepository(g.db)
 }
 
+// SavedFilters returns a saved filter repository
+func (g *GormBase) SavedFilters() filter.SavedFilterRepository {
+	return filter.NewSavedFilterRepository(g.db)
+}
+
+// WorkItemTemplates returns a work item template repository
+func (g *GormBase) WorkItemTemplates() template.TemplateRepository {
+	return template.NewTemplateRepository(g.db)
+}
+
+// Attachments returns a work item attachment repository
+func (g *GormBase) Attachments() attachment.Repository {
+	return attachment.NewRepository(g.db)
+}
+
+// Labels returns a label repository
+func (g *GormBase) Labels() label.Repository {
+	return label.NewLabelRepository(g.db)
+}
+
 func (g *GormBase) DB() *gorm.DB {
 	return g.db
 }
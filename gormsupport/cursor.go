@@ -0,0 +1,189 @@
+package gormsupport
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/almighty/almighty-core/errors"
+)
+
+// Cursor is the position of an item in a list that is ordered by one or more
+// keys, the last of which identifies the item. Cursors are handed to clients as
+// opaque tokens, so they can fetch the items following a page without using an
+// offset. Unlike offsets, cursors are not affected by items being inserted,
+// deleted or reordered while a client is paging.
+type Cursor struct {
+	// Order identifies the order of the list the cursor belongs to
+	Order string `json:"o,omitempty"`
+	// Keys holds the values of the order terms for the item
+	Keys []interface{} `json:"k"`
+}
+
+// Encode returns the cursor as an opaque token
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a token created by Cursor.Encode. Numbers are decoded
+// as json.Number in order to keep the precision of large integers.
+func DecodeCursor(token string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.NewBadParameterError("page[cursor]", token)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var result Cursor
+	if err := decoder.Decode(&result); err != nil {
+		return nil, errors.NewBadParameterError("page[cursor]", token)
+	}
+	return &result, nil
+}
+
+// KeyType is the type of the values of an order term
+type KeyType int
+
+// Key types
+const (
+	// KeyText is the type of text and UUID values
+	KeyText KeyType = iota
+	// KeyInteger is the type of integer values
+	KeyInteger
+	// KeyFloat is the type of floating point values
+	KeyFloat
+	// KeyTimestamp is the type of timestamp values
+	KeyTimestamp
+)
+
+// ConvertKeys returns a copy of the cursor whose keys are converted to the types of the given order
+// terms. It returns a BadParameterError if the keys do not match the terms.
+func (c Cursor) ConvertKeys(terms []OrderTerm) (*Cursor, error) {
+	invalid := errors.NewBadParameterError("page[cursor]", c.Encode()).Expected("cursor of the requested order")
+	if len(c.Keys) != len(terms) {
+		return nil, invalid
+	}
+	keys := make([]interface{}, len(terms))
+	for i, t := range terms {
+		key := c.Keys[i]
+		if key == nil {
+			if !t.Nullable {
+				return nil, invalid
+			}
+			continue
+		}
+		var err error
+		switch t.Key {
+		case KeyInteger:
+			n, ok := key.(json.Number)
+			if !ok {
+				return nil, invalid
+			}
+			keys[i], err = n.Int64()
+		case KeyFloat:
+			n, ok := key.(json.Number)
+			if !ok {
+				return nil, invalid
+			}
+			keys[i], err = n.Float64()
+		case KeyTimestamp:
+			s, ok := key.(string)
+			if !ok {
+				return nil, invalid
+			}
+			keys[i], err = time.Parse(time.RFC3339Nano, s)
+		default:
+			s, ok := key.(string)
+			if !ok {
+				return nil, invalid
+			}
+			keys[i] = s
+		}
+		if err != nil {
+			return nil, invalid
+		}
+	}
+	return &Cursor{Order: c.Order, Keys: keys}, nil
+}
+
+// OrderTerm is one term of an order clause of a list that supports keyset pagination
+type OrderTerm struct {
+	Expression string
+	Descending bool
+	// Nullable terms are sorted with null values last in both directions
+	Nullable bool
+	// Key is the type of the values of the term
+	Key KeyType
+}
+
+// String returns the term for use in an order clause
+func (t OrderTerm) String() string {
+	result := t.Expression
+	if t.Descending {
+		result += " desc"
+	} else {
+		result += " asc"
+	}
+	if t.Nullable {
+		result += " nulls last"
+	}
+	return result
+}
+
+// OrderClause returns the order clause for use with gorm.DB.Order()
+func OrderClause(terms []OrderTerm) string {
+	result := make([]string, len(terms))
+	for i, t := range terms {
+		result[i] = t.String()
+	}
+	return strings.Join(result, ", ")
+}
+
+// KeysetCondition returns a where clause and its parameters that select the
+// items following the cursor in the order given by the terms. The cursor must
+// hold a key for every term and the terms must identify an item uniquely.
+func KeysetCondition(terms []OrderTerm, cursor Cursor) (string, []interface{}, error) {
+	if len(cursor.Keys) != len(terms) {
+		return "", nil, errors.NewBadParameterError("page[cursor]", cursor.Encode()).Expected("cursor of the requested order")
+	}
+	var alternatives []string
+	var parameters []interface{}
+	for i, t := range terms {
+		key := cursor.Keys[i]
+		if key == nil {
+			// nulls are sorted last, nothing follows them in this term
+			continue
+		}
+		var conditions []string
+		for j := 0; j < i; j++ {
+			if cursor.Keys[j] == nil {
+				conditions = append(conditions, terms[j].Expression+" IS NULL")
+			} else {
+				conditions = append(conditions, terms[j].Expression+" = ?")
+				parameters = append(parameters, cursor.Keys[j])
+			}
+		}
+		op := " > ?"
+		if t.Descending {
+			op = " < ?"
+		}
+		if t.Nullable {
+			conditions = append(conditions, "("+t.Expression+op+" OR "+t.Expression+" IS NULL)")
+		} else {
+			conditions = append(conditions, t.Expression+op)
+		}
+		parameters = append(parameters, key)
+		if len(conditions) == 1 {
+			alternatives = append(alternatives, conditions[0])
+		} else {
+			alternatives = append(alternatives, "("+strings.Join(conditions, " AND ")+")")
+		}
+	}
+	if len(alternatives) == 0 {
+		return "false", parameters, nil
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", parameters, nil
+}
@@ -0,0 +1,101 @@
+package gormsupport_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/almighty/almighty-core/gormsupport"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	c := gormsupport.Cursor{Order: "-system.title", Keys: []interface{}{"foo", nil, int64(1499999999999999999), 2.5}}
+	decoded, err := gormsupport.DecodeCursor(c.Encode())
+	require.Nil(t, err)
+	assert.Equal(t, "-system.title", decoded.Order)
+	// numbers keep their precision
+	assert.Equal(t, []interface{}{"foo", nil, json.Number("1499999999999999999"), json.Number("2.5")}, decoded.Keys)
+
+	for _, invalid := range []string{"not a cursor", "bm90IGpzb24"} {
+		_, err := gormsupport.DecodeCursor(invalid)
+		assert.NotNil(t, err, "expected error for %q", invalid)
+	}
+}
+
+func TestCursorConvertKeys(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	terms := []gormsupport.OrderTerm{
+		{Expression: "title", Nullable: true},
+		{Expression: "created_at", Key: gormsupport.KeyTimestamp},
+		{Expression: "execution_order", Key: gormsupport.KeyFloat},
+		{Expression: "id", Key: gormsupport.KeyInteger},
+	}
+	createdAt := time.Date(2017, 4, 1, 12, 30, 0, 500, time.UTC)
+	// when
+	decoded, err := gormsupport.DecodeCursor(gormsupport.Cursor{Keys: []interface{}{nil, createdAt, 2.5, 42}}.Encode())
+	require.Nil(t, err)
+	c, err := decoded.ConvertKeys(terms)
+	// then
+	require.Nil(t, err)
+	require.Len(t, c.Keys, 4)
+	assert.Nil(t, c.Keys[0])
+	assert.True(t, createdAt.Equal(c.Keys[1].(time.Time)))
+	assert.Equal(t, 2.5, c.Keys[2])
+	assert.Equal(t, int64(42), c.Keys[3])
+	// when the keys do not match the terms
+	for _, keys := range [][]interface{}{
+		{"foo", createdAt, 2.5},
+		{"foo", nil, 2.5, 42},
+		{"foo", "yesterday", 2.5, 42},
+		{"foo", createdAt, "fast", 42},
+		{"foo", createdAt, 2.5, 4.2},
+		{1, createdAt, 2.5, 42},
+	} {
+		decoded, err := gormsupport.DecodeCursor(gormsupport.Cursor{Keys: keys}.Encode())
+		require.Nil(t, err)
+		_, err = decoded.ConvertKeys(terms)
+		// then
+		assert.NotNil(t, err, "expected error for keys %v", keys)
+	}
+}
+
+func TestOrderClause(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	terms := []gormsupport.OrderTerm{
+		{Expression: "title", Nullable: true},
+		{Expression: "created_at", Descending: true},
+	}
+	assert.Equal(t, "title asc nulls last, created_at desc", gormsupport.OrderClause(terms))
+}
+
+func TestKeysetCondition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	terms := []gormsupport.OrderTerm{
+		{Expression: "title", Nullable: true},
+		{Expression: "id", Descending: true},
+	}
+	// when
+	where, parameters, err := gormsupport.KeysetCondition(terms, gormsupport.Cursor{Keys: []interface{}{"foo", 42}})
+	// then
+	require.Nil(t, err)
+	assert.Equal(t, "((title > ? OR title IS NULL) OR (title = ? AND id < ?))", where)
+	assert.Equal(t, []interface{}{"foo", "foo", 42}, parameters)
+	// when the key of a nullable term is null
+	where, parameters, err = gormsupport.KeysetCondition(terms, gormsupport.Cursor{Keys: []interface{}{nil, 42}})
+	// then
+	require.Nil(t, err)
+	assert.Equal(t, "((title IS NULL AND id < ?))", where)
+	assert.Equal(t, []interface{}{42}, parameters)
+	// when the cursor does not match the terms
+	_, _, err = gormsupport.KeysetCondition(terms, gormsupport.Cursor{Keys: []interface{}{"foo"}})
+	// then
+	assert.NotNil(t, err)
+}
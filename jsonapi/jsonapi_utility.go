@@ -19,6 +19,7 @@ const (
 	ErrorCodeInternalError     = "internal_error"
 	ErrorCodeUnauthorizedError = "unauthorized_error"
 	ErrorCodeJWTSecurityError  = "jwt_security_error"
+	ErrorCodeDataConflict      = "data_conflict"
 )
 
 // ErrorToJSONAPIError returns the JSONAPI representation
@@ -48,6 +49,10 @@ func ErrorToJSONAPIError(err error) (app.JSONAPIError, int) {
 		code = ErrorCodeVersionConflict
 		title = "Version conflict error"
 		statusCode = http.StatusBadRequest
+	case errors.DataConflictError:
+		code = ErrorCodeDataConflict
+		title = "Data conflict error"
+		statusCode = http.StatusConflict
 	case errors.InternalError:
 		code = ErrorCodeInternalError
 		title = "Internal error"
@@ -119,6 +124,11 @@ type Forbidden interface {
 	Forbidden(*app.JSONAPIErrors) error
 }
 
+// Conflict represent a Context that can return a Conflict HTTP status
+type Conflict interface {
+	Conflict(*app.JSONAPIErrors) error
+}
+
 // JSONErrorResponse auto maps the provided error to the correct response type
 // If all else fails, InternalServerError is returned
 func JSONErrorResponse(x InternalServerError, err error) error {
@@ -140,6 +150,10 @@ func JSONErrorResponse(x InternalServerError, err error) error {
 		if ctx, ok := x.(Forbidden); ok {
 			return errs.WithStack(ctx.Forbidden(jsonErr))
 		}
+	case http.StatusConflict:
+		if ctx, ok := x.(Conflict); ok {
+			return errs.WithStack(ctx.Conflict(jsonErr))
+		}
 	default:
 		return errs.WithStack(x.InternalServerError(jsonErr))
 	}
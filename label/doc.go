@@ -0,0 +1,3 @@
+// Package label provides the labels, which are named and colored tags that a space
+// defines to categorize its work items without dedicated work item types or fields.
+package label
@@ -0,0 +1,201 @@
+package label
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
+	"github.com/almighty/almighty-core/log"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// APIStringTypeLabels is the JSON API type of labels
+const APIStringTypeLabels = "labels"
+
+// DefaultColor is the color of labels created without one
+const DefaultColor = "#cccccc"
+
+// colorPattern matches the colors of labels, as hex RGB values like "#ff8800"
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label is a named and colored tag of the work items of a space. Work items refer to
+// their labels by ID in the system.labels field, so that renaming a label applies to
+// all of them.
+type Label struct {
+	gormsupport.Lifecycle
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID     uuid.UUID `sql:"type:uuid"`
+	Name        string
+	Color       string
+	Description string
+	Version     int
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (l Label) TableName() string {
+	return "labels"
+}
+
+// validate checks the name and the color of the label
+func (l Label) validate() error {
+	if l.Name == "" {
+		return errors.NewBadParameterError("name", l.Name).Expected("not empty")
+	}
+	if !colorPattern.MatchString(l.Color) {
+		return errors.NewBadParameterError("color", l.Color).Expected("a hex RGB color like #ff8800")
+	}
+	return nil
+}
+
+// Repository encapsulates storage & retrieval of labels
+type Repository interface {
+	Create(ctx context.Context, l *Label) error
+	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Label, error)
+	LoadByName(ctx context.Context, spaceID uuid.UUID, name string) (*Label, error)
+	Save(ctx context.Context, l Label) (*Label, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error
+	List(ctx context.Context, spaceID uuid.UUID) ([]Label, error)
+}
+
+// NewLabelRepository creates a new storage type.
+func NewLabelRepository(db *gorm.DB) Repository {
+	return &GormLabelRepository{db: db}
+}
+
+// GormLabelRepository is the implementation of the storage interface for labels.
+type GormLabelRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new label, with the default color if it has none
+// returns BadParameterError or InternalError
+func (m *GormLabelRepository) Create(ctx context.Context, l *Label) error {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "create"}, time.Now())
+	if l.Color == "" {
+		l.Color = DefaultColor
+	}
+	if err := l.validate(); err != nil {
+		return errs.WithStack(err)
+	}
+	l.ID = uuid.NewV4()
+	if err := m.db.Create(l).Error; err != nil {
+		// (space_id, name) needs to be unique
+		if gormsupport.IsUniqueViolation(err, "labels_name_idx") {
+			return errors.NewBadParameterError("name", l.Name).Expected("unique")
+		}
+		log.Error(ctx, map[string]interface{}{
+			"label_id": l.ID,
+			"err":      err,
+		}, "unable to create the label")
+		return errors.NewInternalError(err.Error())
+	}
+	return nil
+}
+
+// Load returns the label of the given space with the given ID
+// returns NotFoundError or InternalError
+func (m *GormLabelRepository) Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "get"}, time.Now())
+	return m.load(m.db.Where("space_id = ? AND id = ?", spaceID, id), id.String())
+}
+
+// LoadByName returns the label of the given space with the given name
+// returns NotFoundError or InternalError
+func (m *GormLabelRepository) LoadByName(ctx context.Context, spaceID uuid.UUID, name string) (*Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "getbyname"}, time.Now())
+	return m.load(m.db.Where("space_id = ? AND name = ?", spaceID, name), name)
+}
+
+func (m *GormLabelRepository) load(db *gorm.DB, key string) (*Label, error) {
+	var result Label
+	tx := db.First(&result)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("label", key)
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	return &result, nil
+}
+
+// Save updates the given label. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (m *GormLabelRepository) Save(ctx context.Context, l Label) (*Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "save"}, time.Now())
+	if _, err := m.Load(ctx, l.SpaceID, l.ID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := l.validate(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	oldVersion := l.Version
+	l.Version++
+	tx := m.db.Where("version = ?", oldVersion).Save(&l)
+	if tx.Error != nil {
+		if gormsupport.IsUniqueViolation(tx.Error, "labels_name_idx") {
+			return nil, errors.NewBadParameterError("name", l.Name).Expected("unique")
+		}
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"label_id": l.ID,
+	}, "label updated successfully")
+	return &l, nil
+}
+
+// Delete deletes the label of the given space with the given ID and removes it from the
+// labels of the work items of the space, including those in the trash
+// returns NotFoundError or InternalError
+func (m *GormLabelRepository) Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("label", id.String())
+	}
+	tx := m.db.Where("space_id = ?", spaceID).Delete(&Label{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("label", id.String())
+	}
+	// the label is removed without a new revision of the work items, as when a referenced
+	// entity is deleted by the database
+	query := fmt.Sprintf(`UPDATE %[1]s SET fields = jsonb_set(fields, '{%[2]s}', (fields->'%[2]s') - ?::text)
+		WHERE space_id = ? AND fields->'%[2]s' @> ?::jsonb`, workitem.WorkItemStorage{}.TableName(), workitem.SystemLabels)
+	tx = m.db.Exec(query, id.String(), spaceID, fmt.Sprintf(`[%q]`, id.String()))
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"label_id": id,
+			"err":      tx.Error,
+		}, "unable to remove the label from the work items")
+		return errors.NewInternalError(tx.Error.Error())
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"label_id":   id,
+		"work_items": tx.RowsAffected,
+	}, "Label deleted")
+	return nil
+}
+
+// List returns the labels of the given space ordered by name
+// returns InternalError
+func (m *GormLabelRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Label, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "label", "list"}, time.Now())
+	result := []Label{}
+	if err := m.db.Where("space_id = ?", spaceID).Order("name, id").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return result, nil
+}
@@ -0,0 +1,166 @@
+package label_test
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/criteria"
+	localerror "github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/label"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestLabelRepository struct {
+	gormtestsupport.DBTestSuite
+
+	clean    func()
+	ctx      context.Context
+	repo     label.Repository
+	spaceID  uuid.UUID
+	identity account.Identity
+}
+
+func TestRunLabelRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestLabelRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+// SetupSuite overrides the DBTestSuite's function but calls it before doing anything else
+// so that the system work item types exist.
+func (test *TestLabelRepository) SetupSuite() {
+	test.DBTestSuite.SetupSuite()
+	test.ctx = migration.NewMigrationContext(context.Background())
+	test.DBTestSuite.PopulateDBTestSuite(test.ctx)
+}
+
+func (test *TestLabelRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+	test.repo = label.NewLabelRepository(test.DB)
+	s, err := space.NewRepository(test.DB).Create(test.ctx, &space.Space{
+		Name: "TestLabelRepository " + uuid.NewV4().String(),
+	})
+	require.Nil(test.T(), err)
+	test.spaceID = s.ID
+	test.identity, err = testsupport.CreateTestIdentity(test.DB, "TestLabelRepository user", "test provider")
+	require.Nil(test.T(), err)
+}
+
+func (test *TestLabelRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestLabelRepository) createLabel(name string) label.Label {
+	l := label.Label{SpaceID: test.spaceID, Name: name}
+	require.Nil(test.T(), test.repo.Create(test.ctx, &l))
+	return l
+}
+
+func (test *TestLabelRepository) TestCreateAndLoad() {
+	// when
+	l := test.createLabel("needs-triage")
+	// then
+	require.NotEqual(test.T(), uuid.Nil, l.ID)
+	assert.Equal(test.T(), label.DefaultColor, l.Color)
+	loaded, err := test.repo.Load(test.ctx, test.spaceID, l.ID)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), "needs-triage", loaded.Name)
+	loaded, err = test.repo.LoadByName(test.ctx, test.spaceID, "needs-triage")
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), l.ID, loaded.ID)
+	// labels are scoped to their space
+	_, err = test.repo.Load(test.ctx, space.SystemSpace, l.ID)
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+	_, err = test.repo.LoadByName(test.ctx, test.spaceID, "unknown")
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+}
+
+func (test *TestLabelRepository) TestCreateInvalid() {
+	test.createLabel("needs-triage")
+	// duplicate name in the space
+	l := label.Label{SpaceID: test.spaceID, Name: "needs-triage"}
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(test.repo.Create(test.ctx, &l)))
+	// missing name
+	l = label.Label{SpaceID: test.spaceID}
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(test.repo.Create(test.ctx, &l)))
+	// invalid color
+	l = label.Label{SpaceID: test.spaceID, Name: "blocked", Color: "red"}
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(test.repo.Create(test.ctx, &l)))
+}
+
+func (test *TestLabelRepository) TestList() {
+	// given
+	test.createLabel("ux")
+	test.createLabel("backend")
+	// when
+	labels, err := test.repo.List(test.ctx, test.spaceID)
+	// then
+	require.Nil(test.T(), err)
+	require.Len(test.T(), labels, 2)
+	assert.Equal(test.T(), "backend", labels[0].Name)
+	assert.Equal(test.T(), "ux", labels[1].Name)
+}
+
+func (test *TestLabelRepository) TestSave() {
+	// given
+	l := test.createLabel("needs-triage")
+	l.Name = "triaged"
+	l.Color = "#00ff00"
+	// when
+	saved, err := test.repo.Save(test.ctx, l)
+	// then
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), "triaged", saved.Name)
+	assert.Equal(test.T(), "#00ff00", saved.Color)
+	assert.Equal(test.T(), l.Version+1, saved.Version)
+	// saving the stale version fails
+	_, err = test.repo.Save(test.ctx, l)
+	assert.IsType(test.T(), localerror.VersionConflictError{}, errors.Cause(err))
+}
+
+func (test *TestLabelRepository) TestDeleteRemovesLabelFromWorkItems() {
+	// given
+	triage := test.createLabel("needs-triage")
+	ux := test.createLabel("ux")
+	wir := workitem.NewWorkItemRepository(test.DB)
+	wi, err := wir.Create(test.ctx, test.spaceID, workitem.SystemBug, map[string]interface{}{
+		workitem.SystemTitle:  "Crash on startup",
+		workitem.SystemState:  workitem.SystemStateNew,
+		workitem.SystemLabels: []string{triage.ID.String(), ux.ID.String()},
+	}, test.identity.ID)
+	require.Nil(test.T(), err)
+	byLabel := func(l label.Label) []workitem.WorkItem {
+		result, _, err := wir.List(test.ctx, test.spaceID, criteria.Equals(criteria.Field(workitem.SystemLabels), criteria.Literal([]string{l.ID.String()})), nil, nil, nil)
+		require.Nil(test.T(), err)
+		return result
+	}
+	require.Len(test.T(), byLabel(triage), 1)
+	// when
+	err = test.repo.Delete(test.ctx, test.spaceID, triage.ID)
+	// then
+	require.Nil(test.T(), err)
+	_, err = test.repo.Load(test.ctx, test.spaceID, triage.ID)
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+	assert.Empty(test.T(), byLabel(triage))
+	require.Len(test.T(), byLabel(ux), 1)
+	loaded, err := wir.LoadByID(test.ctx, wi.ID)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), []interface{}{ux.ID.String()}, loaded.Fields[workitem.SystemLabels])
+	// the name of a deleted label can be reused
+	test.createLabel("needs-triage")
+	err = test.repo.Delete(test.ctx, test.spaceID, triage.ID)
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+}
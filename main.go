@@ -29,7 +29,9 @@ import (
 	"github.com/almighty/almighty-core/remoteworkitem"
 	"github.com/almighty/almighty-core/space"
 	"github.com/almighty/almighty-core/token"
+	"github.com/almighty/almighty-core/trash"
 	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
 	"github.com/almighty/almighty-core/workitem/link"
 
 	"github.com/goadesign/goa"
@@ -187,6 +189,24 @@ func main() {
 
 	appDB := gormapplication.NewGormDB(db)
 
+	// Blob store keeping the content of the work item attachments
+	attachmentStore, err := attachment.NewFileSystemBlobStore(configuration.GetAttachmentsStoragePath())
+	if err != nil {
+		log.Panic(nil, map[string]interface{}{
+			"path": configuration.GetAttachmentsStoragePath(),
+			"err":  err,
+		}, "failed to set up the attachment store")
+	}
+
+	// Job to purge the expired work items from the trash
+	trashPurger := trash.NewPurger(appDB, configuration, attachmentStore)
+	if err := trashPurger.Start(); err != nil {
+		log.Panic(nil, map[string]interface{}{
+			"err": err,
+		}, "failed to schedule the trash purge job")
+	}
+	defer trashPurger.Stop()
+
 	loginService := login.NewKeycloakOAuthProvider(oauth, identityRepository, userRepository, tokenManager, appDB)
 	loginCtrl := controller.NewLoginController(service, loginService, tokenManager, configuration)
 	app.MountLoginController(service, loginCtrl)
@@ -219,6 +239,22 @@ func main() {
 	workItemCommentsCtrl := controller.NewWorkItemCommentsController(service, appDB)
 	app.MountWorkItemCommentsController(service, workItemCommentsCtrl)
 
+	// Mount "work item revisions" controller
+	workItemRevisionsCtrl := controller.NewWorkItemRevisionsController(service, appDB)
+	app.MountWorkItemRevisionsController(service, workItemRevisionsCtrl)
+
+	// Mount "work item attachments" controller
+	workItemAttachmentsCtrl := controller.NewWorkItemAttachmentsController(service, appDB, attachmentStore, configuration)
+	app.MountWorkItemAttachmentsController(service, workItemAttachmentsCtrl)
+
+	// Mount "space trash" controller
+	spaceTrashCtrl := controller.NewSpaceTrashController(service, appDB)
+	app.MountSpaceTrashController(service, spaceTrashCtrl)
+
+	// Mount "space dependencies" controller
+	spaceDependenciesCtrl := controller.NewSpaceDependenciesController(service, appDB)
+	app.MountSpaceDependenciesController(service, spaceDependenciesCtrl)
+
 	// Mount "work item relationships links" controller
 	workItemRelationshipsLinksCtrl := controller.NewWorkItemRelationshipsLinksController(service, appDB)
 	app.MountWorkItemRelationshipsLinksController(service, workItemRelationshipsLinksCtrl)
@@ -278,9 +314,21 @@ func main() {
 	spaceAreaCtrl := controller.NewSpaceAreasController(service, appDB, configuration)
 	app.MountSpaceAreasController(service, spaceAreaCtrl)
 
-	filterCtrl := controller.NewFilterController(service)
+	filterCtrl := controller.NewFilterController(service, appDB)
 	app.MountFilterController(service, filterCtrl)
 
+	// Mount "saved_filter" controller
+	savedFilterCtrl := controller.NewSavedFilterController(service, appDB)
+	app.MountSavedFilterController(service, savedFilterCtrl)
+
+	// Mount "work_item_template" controller
+	workItemTemplateCtrl := controller.NewWorkItemTemplateController(service, appDB)
+	app.MountWorkItemTemplateController(service, workItemTemplateCtrl)
+
+	// Mount "label" controller
+	labelCtrl := controller.NewLabelController(service, appDB)
+	app.MountLabelController(service, labelCtrl)
+
 	// Mount "namedspaces" controller
 	namedSpacesCtrl := controller.NewNamedspacesController(service, appDB)
 	app.MountNamedspacesController(service, namedSpacesCtrl)
@@ -261,6 +261,27 @@ func getMigrations() migrations {
 	// Version 47
 	m = append(m, steps{executeSQLFile("047-codebases.sql")})
 
+	// Version 48
+	m = append(m, steps{executeSQLFile("048-comments-tsv.sql")})
+
+	// Version 49
+	m = append(m, steps{executeSQLFile("049-saved-filters.sql")})
+
+	// Version 50
+	m = append(m, steps{executeSQLFile("050-work-item-type-workflows.sql")})
+
+	// Version 51
+	m = append(m, steps{executeSQLFile("051-work-item-templates.sql")})
+
+	// Version 52
+	m = append(m, steps{executeSQLFile("052-work-item-attachments.sql")})
+
+	// Version 53
+	m = append(m, steps{executeSQLFile("053-labels.sql")})
+
+	// Version 54
+	m = append(m, steps{executeSQLFile("054-work-item-keys.sql")})
+
 	// Version N
 	//
 	// In order to add an upgrade, simply append an array of MigrationFunc to the
@@ -628,6 +649,14 @@ func createOrUpdateSystemPlannerItemType(ctx context.Context, witr *workitem.Gor
 			Label:       "Assignees",
 			Description: "The users that are assigned to the work item",
 		},
+		workitem.SystemLabels: {
+			Type: &workitem.ListType{
+				SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+				ComponentType: workitem.SimpleType{Kind: workitem.KindLabel}},
+			Required:    false,
+			Label:       "Labels",
+			Description: "The labels of the space that are attached to the work item",
+		},
 		workitem.SystemState: {
 			Type: &workitem.EnumType{
 				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
@@ -0,0 +1,17 @@
+-- add support for Full Text Search on comment bodies, the body is weighted
+-- lower than the fields of the work items the comments belong to
+ALTER TABLE comments ADD COLUMN tsv tsvector;
+
+UPDATE comments SET tsv = setweight(to_tsvector('english', coalesce(body, '')), 'D');
+
+CREATE INDEX comments_fulltext_search_index ON comments USING GIN (tsv);
+
+CREATE FUNCTION comment_tsv_trigger() RETURNS trigger AS $$
+begin
+  new.tsv := setweight(to_tsvector('english', coalesce(new.body, '')), 'D');
+  return new;
+end
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER upd_comment_tsvector BEFORE INSERT OR UPDATE OF body ON comments
+FOR EACH ROW EXECUTE PROCEDURE comment_tsv_trigger();
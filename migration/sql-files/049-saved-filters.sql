@@ -0,0 +1,18 @@
+-- saved filters are named work item queries, which are either private to their
+-- owner or shared with the members of a space
+CREATE TABLE saved_filters (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    title text NOT NULL CHECK (title <> ''),
+    description text,
+    query text NOT NULL,
+    sort text,
+    owner_id uuid NOT NULL REFERENCES identities (id) ON DELETE CASCADE,
+    space_id uuid REFERENCES spaces (id) ON DELETE CASCADE,
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX ix_saved_filters_owner_id ON saved_filters USING btree (owner_id);
+CREATE INDEX ix_saved_filters_space_id ON saved_filters USING btree (space_id);
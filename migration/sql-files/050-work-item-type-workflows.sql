@@ -0,0 +1,3 @@
+-- the optional workflow of a work item type restricts the transitions between
+-- the values of one of its enum fields
+ALTER TABLE work_item_types ADD COLUMN workflow jsonb;
@@ -0,0 +1,16 @@
+-- work item templates are named sets of field values that pre-fill the work items
+-- of a type created in a space
+CREATE TABLE work_item_templates (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    work_item_type_id uuid NOT NULL REFERENCES work_item_types (id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (name <> ''),
+    description text,
+    fields jsonb,
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE UNIQUE INDEX work_item_templates_name_idx ON work_item_templates (space_id, work_item_type_id, name) WHERE deleted_at IS NULL;
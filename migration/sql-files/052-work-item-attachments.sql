@@ -0,0 +1,16 @@
+-- attachments hold the metadata of the files attached to work items, their content is kept
+-- in a blob store. They are removed along with their work item when it is purged from the trash.
+CREATE TABLE work_item_attachments (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    work_item_id bigint NOT NULL,
+    name text NOT NULL CHECK (name <> ''),
+    size bigint NOT NULL,
+    content_type text NOT NULL,
+    checksum text NOT NULL,
+    created_by uuid NOT NULL
+);
+
+CREATE INDEX work_item_attachments_work_item_id_idx ON work_item_attachments (work_item_id);
@@ -0,0 +1,15 @@
+-- labels are named and colored tags of the work items of a space, the work items
+-- refer to them by ID in the system.labels field
+CREATE TABLE labels (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    name text NOT NULL CHECK (name <> ''),
+    color text NOT NULL,
+    description text,
+    version integer DEFAULT 0 NOT NULL
+);
+
+CREATE UNIQUE INDEX labels_name_idx ON labels (space_id, name) WHERE deleted_at IS NULL;
@@ -0,0 +1,46 @@
+-- every space has a unique key prefix for the human-readable keys of its work items, e.g. PLAT-42
+ALTER TABLE spaces ADD COLUMN key_prefix text;
+
+-- the prefixes of the existing spaces are derived from their names like for new spaces: the first
+-- four letters and digits, followed by a number if an older space already uses the prefix
+DO $$
+DECLARE
+    s record;
+    base text;
+    candidate text;
+    n integer;
+BEGIN
+    FOR s IN SELECT id, name FROM spaces ORDER BY created_at, id LOOP
+        base := left(upper(regexp_replace(s.name, '^[^A-Za-z]+|[^A-Za-z0-9]', '', 'g')), 4);
+        IF base = '' THEN
+            base := 'SPACE';
+        END IF;
+        candidate := base;
+        n := 1;
+        WHILE EXISTS (SELECT 1 FROM spaces WHERE key_prefix = candidate) LOOP
+            n := n + 1;
+            candidate := base || n;
+        END LOOP;
+        UPDATE spaces SET key_prefix = candidate WHERE id = s.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE spaces ALTER COLUMN key_prefix SET NOT NULL;
+ALTER TABLE spaces ADD CONSTRAINT spaces_key_prefix_check CHECK (key_prefix ~ '^[A-Z][A-Z0-9]{0,9}$');
+CREATE UNIQUE INDEX spaces_key_prefix_idx ON spaces (key_prefix);
+
+-- the last number given to a work item of each space
+CREATE TABLE work_item_numbers (
+    space_id uuid primary key REFERENCES spaces (id) ON DELETE CASCADE,
+    last_number bigint NOT NULL
+);
+
+-- the existing work items, including the deleted ones, are numbered in the order of their creation
+ALTER TABLE work_items ADD COLUMN key text;
+UPDATE work_items wi SET key = s.key_prefix || '-' || n.number
+    FROM (SELECT id, row_number() OVER (PARTITION BY space_id ORDER BY id) AS number FROM work_items) n, spaces s
+    WHERE wi.id = n.id AND s.id = wi.space_id;
+INSERT INTO work_item_numbers (space_id, last_number)
+    SELECT space_id, count(*) FROM work_items GROUP BY space_id;
+ALTER TABLE work_items ALTER COLUMN key SET NOT NULL;
+CREATE UNIQUE INDEX work_items_key_idx ON work_items (key);
@@ -0,0 +1,29 @@
+// Package query implements the textual query language used to filter work
+// items. A query is parsed into a criteria.Expression which can then be
+// compiled or evaluated by any consumer of the criteria package.
+//
+// The language supports comparisons of fields against literal values,
+// combined with AND, OR and NOT and grouped with parentheses:
+//
+//	system.state = "open" AND (system.title != 'foo' OR NOT system.order = 5)
+//
+// Besides "=" and "!=" the comparison operators "<", "<=", ">" and ">=" are
+// available, as well as membership, null and text matching tests:
+//
+//	system.state IN ("new", "open") AND system.assignees IS NULL
+//	system.title CONTAINS "crash" OR system.title ILIKE "fix%"
+//
+// Negations are pushed down to the comparisons. CONTAINS and ILIKE can
+// therefore not be negated.
+//
+// Keywords are case insensitive. Field names consist of letters, digits, "_",
+// "-" and "." and must start with a letter or "_". Field names that do not
+// follow these rules can be written as quoted strings. String literals are
+// delimited with either double or single quotes and support backslash escapes.
+// Numbers, true and false, and lists of strings (e.g. ["a", "b"]) are also
+// accepted as values.
+//
+// For backwards compatibility a query that starts with "{" is interpreted as a
+// flat JSON object of the form {"attribute1":value1, "attribute2":value2},
+// which is equivalent to attribute1 = value1 AND attribute2 = value2.
+package query
@@ -0,0 +1,287 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind identifies the different kinds of tokens produced by the lexer
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenEquals
+	tokenNotEquals
+	tokenLess
+	tokenLessOrEqual
+	tokenGreater
+	tokenGreaterOrEqual
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenTrue
+	tokenFalse
+	tokenIn
+	tokenIs
+	tokenNull
+	tokenContains
+	tokenILike
+)
+
+// String returns a human readable name of the token kind for use in error messages
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of input"
+	case tokenIdent:
+		return "field name"
+	case tokenString:
+		return "string"
+	case tokenNumber:
+		return "number"
+	case tokenLParen:
+		return "'('"
+	case tokenRParen:
+		return "')'"
+	case tokenLBracket:
+		return "'['"
+	case tokenRBracket:
+		return "']'"
+	case tokenComma:
+		return "','"
+	case tokenEquals:
+		return "'='"
+	case tokenNotEquals:
+		return "'!='"
+	case tokenLess:
+		return "'<'"
+	case tokenLessOrEqual:
+		return "'<='"
+	case tokenGreater:
+		return "'>'"
+	case tokenGreaterOrEqual:
+		return "'>='"
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	case tokenTrue:
+		return "true"
+	case tokenFalse:
+		return "false"
+	case tokenIn:
+		return "IN"
+	case tokenIs:
+		return "IS"
+	case tokenNull:
+		return "NULL"
+	case tokenContains:
+		return "CONTAINS"
+	case tokenILike:
+		return "ILIKE"
+	}
+	return "unknown token"
+}
+
+// keywords maps the lower case spelling of a keyword to its token kind
+var keywords = map[string]tokenKind{
+	"and":      tokenAnd,
+	"or":       tokenOr,
+	"not":      tokenNot,
+	"true":     tokenTrue,
+	"false":    tokenFalse,
+	"in":       tokenIn,
+	"is":       tokenIs,
+	"null":     tokenNull,
+	"contains": tokenContains,
+	"ilike":    tokenILike,
+}
+
+// token is a single lexical element of a query
+type token struct {
+	kind tokenKind
+	// text holds the identifier, the unquoted string or the number literal
+	text string
+	// pos is the 1-based position of the first character of the token
+	pos int
+}
+
+// lexer splits a query string into tokens
+type lexer struct {
+	input string
+	pos   int // current byte offset into input
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: input}
+}
+
+// column returns the 1-based character position for the given byte offset
+func (l *lexer) column(offset int) int {
+	return utf8.RuneCountInString(l.input[:offset]) + 1
+}
+
+func (l *lexer) errorf(offset int, format string, args ...interface{}) error {
+	return newParseError(l.column(offset), format, args...)
+}
+
+func (l *lexer) peekRune() (rune, int) {
+	if l.pos >= len(l.input) {
+		return utf8.RuneError, 0
+	}
+	return utf8.DecodeRuneInString(l.input[l.pos:])
+}
+
+// next returns the next token from the input or a ParseError
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) {
+		r, size := l.peekRune()
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += size
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: l.column(start)}, nil
+	}
+	r, size := l.peekRune()
+	single := func(kind tokenKind) (token, error) {
+		l.pos += size
+		return token{kind: kind, text: string(r), pos: l.column(start)}, nil
+	}
+	switch {
+	case r == '(':
+		return single(tokenLParen)
+	case r == ')':
+		return single(tokenRParen)
+	case r == '[':
+		return single(tokenLBracket)
+	case r == ']':
+		return single(tokenRBracket)
+	case r == ',':
+		return single(tokenComma)
+	case r == '=':
+		l.pos += size
+		// accept "==" as an alias for "="
+		if strings.HasPrefix(l.input[l.pos:], "=") {
+			l.pos++
+		}
+		return token{kind: tokenEquals, text: l.input[start:l.pos], pos: l.column(start)}, nil
+	case r == '<':
+		return l.relational(tokenLess, tokenLessOrEqual)
+	case r == '>':
+		return l.relational(tokenGreater, tokenGreaterOrEqual)
+	case r == '!':
+		if strings.HasPrefix(l.input[l.pos:], "!=") {
+			l.pos += 2
+			return token{kind: tokenNotEquals, text: "!=", pos: l.column(start)}, nil
+		}
+		return token{}, l.errorf(start, "unexpected character '!'")
+	case r == '"' || r == '\'':
+		return l.quoted(r)
+	case r == '-' || r == '+' || unicode.IsDigit(r):
+		return l.number()
+	case r == '_' || unicode.IsLetter(r):
+		return l.identifier()
+	}
+	return token{}, l.errorf(start, "unexpected character '%c'", r)
+}
+
+// relational reads a single character relational operator which may be followed by "="
+func (l *lexer) relational(kind tokenKind, orEqualKind tokenKind) (token, error) {
+	start := l.pos
+	l.pos++
+	if strings.HasPrefix(l.input[l.pos:], "=") {
+		l.pos++
+		kind = orEqualKind
+	}
+	return token{kind: kind, text: l.input[start:l.pos], pos: l.column(start)}, nil
+}
+
+// quoted reads a string delimited by the given quote character
+func (l *lexer) quoted(quote rune) (token, error) {
+	start := l.pos
+	l.pos++ // opening quote
+	var value []rune
+	for l.pos < len(l.input) {
+		r, size := l.peekRune()
+		l.pos += size
+		switch r {
+		case quote:
+			return token{kind: tokenString, text: string(value), pos: l.column(start)}, nil
+		case '\\':
+			if l.pos >= len(l.input) {
+				return token{}, l.errorf(start, "unterminated string")
+			}
+			escaped, escapedSize := l.peekRune()
+			l.pos += escapedSize
+			switch escaped {
+			case 'n':
+				value = append(value, '\n')
+			case 't':
+				value = append(value, '\t')
+			case 'r':
+				value = append(value, '\r')
+			default:
+				value = append(value, escaped)
+			}
+		default:
+			value = append(value, r)
+		}
+	}
+	return token{}, l.errorf(start, "unterminated string")
+}
+
+// number reads an optionally signed integer or decimal number
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	if l.input[l.pos] == '-' || l.input[l.pos] == '+' {
+		l.pos++
+	}
+	digits := 0
+	seenDot := false
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		if c >= '0' && c <= '9' {
+			digits++
+		} else if c == '.' && !seenDot {
+			seenDot = true
+		} else {
+			break
+		}
+		l.pos++
+	}
+	if digits == 0 {
+		return token{}, l.errorf(start, "invalid number '%s'", l.input[start:l.pos])
+	}
+	return token{kind: tokenNumber, text: l.input[start:l.pos], pos: l.column(start)}, nil
+}
+
+// identifier reads a field name or keyword
+func (l *lexer) identifier() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) {
+		r, size := l.peekRune()
+		if !(r == '_' || r == '.' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			break
+		}
+		l.pos += size
+	}
+	text := l.input[start:l.pos]
+	if kind, ok := keywords[strings.ToLower(text)]; ok {
+		return token{kind: kind, text: text, pos: l.column(start)}, nil
+	}
+	return token{kind: tokenIdent, text: text, pos: l.column(start)}, nil
+}
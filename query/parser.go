@@ -0,0 +1,443 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/almighty/almighty-core/criteria"
+	errs "github.com/pkg/errors"
+)
+
+// ParseError describes a syntax error in a query together with the position
+// at which it was detected
+type ParseError struct {
+	// Pos is the 1-based character position of the offending input
+	Pos int
+	Msg string
+}
+
+// Error implements the error interface
+func (err ParseError) Error() string {
+	return fmt.Sprintf("%s at position %d", err.Msg, err.Pos)
+}
+
+func newParseError(pos int, format string, args ...interface{}) ParseError {
+	return ParseError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Parse parses the given query into an expression.
+// Returns the expression "true" if the query is nil or empty.
+// Syntax errors are reported as ParseError.
+func Parse(exp *string) (criteria.Expression, error) {
+	if exp == nil || len(strings.TrimSpace(*exp)) == 0 {
+		return criteria.Literal(true), nil
+	}
+	if strings.HasPrefix(strings.TrimSpace(*exp), "{") {
+		return parseJSON(*exp)
+	}
+	p := parser{lex: newLexer(*exp)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return result, nil
+}
+
+// parseJSON parses strings of the form { "attribute1":value1,"attribute2":value2} into an expression
+// of the form "attribute1=value1 and attribute2=value2"
+func parseJSON(exp string) (criteria.Expression, error) {
+	var unmarshalled map[string]interface{}
+	err := json.Unmarshal([]byte(exp), &unmarshalled)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if len(unmarshalled) == 0 {
+		return criteria.Literal(true), nil
+	}
+	// sort the keys so that the resulting expression is deterministic
+	keys := make([]string, 0, len(unmarshalled))
+	for key := range unmarshalled {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var result criteria.Expression
+	for _, key := range keys {
+		current := criteria.Equals(criteria.Field(key), criteria.Literal(unmarshalled[key]))
+		if result == nil {
+			result = current
+		} else {
+			result = criteria.And(result, current)
+		}
+	}
+	return result, nil
+}
+
+// parser is a recursive descent parser for the query language. The grammar is:
+//
+//	or         := and { OR and }
+//	and        := unary { AND unary }
+//	unary      := NOT unary | primary
+//	primary    := "(" or ")" | TRUE | FALSE | comparison
+//	comparison := field ( operator value | [ NOT ] IN "(" value { "," value } ")" |
+//	              IS [ NOT ] NULL | CONTAINS STRING | ILIKE STRING )
+//	operator   := "=" | "!=" | "<" | "<=" | ">" | ">="
+//	field      := IDENT | STRING
+//	value      := STRING | NUMBER | TRUE | FALSE | "[" [ STRING { "," STRING } ] "]"
+type parser struct {
+	lex *lexer
+	tok token // the current lookahead token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return newParseError(p.tok.pos, "unexpected end of input")
+	}
+	return newParseError(p.tok.pos, "unexpected %s '%s'", p.tok.kind, p.tok.text)
+}
+
+func (p *parser) expect(kind tokenKind) error {
+	if p.tok.kind != kind {
+		if p.tok.kind == tokenEOF {
+			return newParseError(p.tok.pos, "expected %s but reached end of input", kind)
+		}
+		return newParseError(p.tok.pos, "expected %s but found '%s'", kind, p.tok.text)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseOr() (criteria.Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = criteria.Or(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (criteria.Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokenAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = criteria.And(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (criteria.Expression, error) {
+	if p.tok.kind != tokenNot {
+		return p.parsePrimary()
+	}
+	pos := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	negated := negate(operand)
+	if negated == nil {
+		return nil, newParseError(pos, "expression cannot be negated")
+	}
+	return negated, nil
+}
+
+func (p *parser) parsePrimary() (criteria.Expression, error) {
+	switch p.tok.kind {
+	case tokenLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		result, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+		return result, nil
+	case tokenTrue, tokenFalse:
+		value := p.tok.kind == tokenTrue
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return criteria.Literal(value), nil
+	case tokenIdent, tokenString:
+		return p.parseComparison()
+	}
+	return nil, p.unexpected()
+}
+
+// comparisonOperators maps the tokens of binary comparison operators to the constructors of their expressions
+var comparisonOperators = map[tokenKind]func(left criteria.Expression, right criteria.Expression) criteria.Expression{
+	tokenEquals:         criteria.Equals,
+	tokenNotEquals:      criteria.Not,
+	tokenLess:           criteria.LessThan,
+	tokenLessOrEqual:    criteria.LessThanOrEqual,
+	tokenGreater:        criteria.GreaterThan,
+	tokenGreaterOrEqual: criteria.GreaterThanOrEqual,
+}
+
+func (p *parser) parseComparison() (criteria.Expression, error) {
+	field := criteria.Field(p.tok.text)
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	op := p.tok
+	constructor, isComparison := comparisonOperators[op.kind]
+	switch op.kind {
+	case tokenIn, tokenNot, tokenIs, tokenContains, tokenILike:
+	default:
+		if !isComparison {
+			if op.kind == tokenEOF {
+				return nil, newParseError(op.pos, "expected comparison operator but reached end of input")
+			}
+			return nil, newParseError(op.pos, "expected comparison operator but found '%s'", op.text)
+		}
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	switch op.kind {
+	case tokenIn:
+		return p.parseIn(field)
+	case tokenNot:
+		if p.tok.kind != tokenIn {
+			return nil, newParseError(p.tok.pos, "expected IN after NOT")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		in, err := p.parseIn(field)
+		if err != nil {
+			return nil, err
+		}
+		return negate(in), nil
+	case tokenIs:
+		negated := false
+		if p.tok.kind == tokenNot {
+			negated = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(tokenNull); err != nil {
+			return nil, err
+		}
+		if negated {
+			return criteria.IsNotNull(field), nil
+		}
+		return criteria.IsNull(field), nil
+	case tokenContains, tokenILike:
+		if p.tok.kind != tokenString {
+			return nil, p.expect(tokenString)
+		}
+		value := criteria.Literal(p.tok.text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if op.kind == tokenContains {
+			return criteria.Substring(field, value), nil
+		}
+		return criteria.ILike(field, value), nil
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return constructor(field, value), nil
+}
+
+// parseIn reads the parenthesized list of values of an IN expression
+func (p *parser) parseIn(field criteria.Expression) (criteria.Expression, error) {
+	if err := p.expect(tokenLParen); err != nil {
+		return nil, err
+	}
+	values := []interface{}{}
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value.(*criteria.LiteralExpression).Value)
+		if p.tok.kind != tokenComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(tokenRParen); err != nil {
+		return nil, err
+	}
+	return criteria.In(field, criteria.Literal(values)), nil
+}
+
+func (p *parser) parseValue() (criteria.Expression, error) {
+	tok := p.tok
+	var value interface{}
+	switch tok.kind {
+	case tokenString:
+		value = tok.text
+	case tokenTrue:
+		value = true
+	case tokenFalse:
+		value = false
+	case tokenNumber:
+		number, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, newParseError(tok.pos, "invalid number '%s'", tok.text)
+		}
+		value = number
+	case tokenLBracket:
+		return p.parseList()
+	default:
+		if tok.kind == tokenEOF {
+			return nil, newParseError(tok.pos, "expected value but reached end of input")
+		}
+		return nil, newParseError(tok.pos, "expected value but found '%s'", tok.text)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return criteria.Literal(value), nil
+}
+
+// parseList reads a bracketed list of strings, e.g. ["a", "b"]
+func (p *parser) parseList() (criteria.Expression, error) {
+	if err := p.expect(tokenLBracket); err != nil {
+		return nil, err
+	}
+	values := []string{}
+	for p.tok.kind != tokenRBracket {
+		if len(values) > 0 {
+			if err := p.expect(tokenComma); err != nil {
+				return nil, err
+			}
+		}
+		if p.tok.kind != tokenString {
+			if p.tok.kind == tokenEOF {
+				return nil, newParseError(p.tok.pos, "expected string but reached end of input")
+			}
+			return nil, newParseError(p.tok.pos, "only strings are allowed in lists but found '%s'", p.tok.text)
+		}
+		values = append(values, p.tok.text)
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return criteria.Literal(values), nil
+}
+
+// parseNumber converts integers to int and everything else to float64
+func parseNumber(text string) (interface{}, error) {
+	if !strings.Contains(text, ".") {
+		if i, err := strconv.Atoi(text); err == nil {
+			return i, nil
+		}
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// negate pushes a negation down to the comparisons of the given expression
+// using De Morgan's laws. Returns nil if the expression cannot be negated.
+func negate(exp criteria.Expression) criteria.Expression {
+	switch t := exp.(type) {
+	case *criteria.AndExpression:
+		left, right := negate(t.Left()), negate(t.Right())
+		if left == nil || right == nil {
+			return nil
+		}
+		return criteria.Or(left, right)
+	case *criteria.OrExpression:
+		left, right := negate(t.Left()), negate(t.Right())
+		if left == nil || right == nil {
+			return nil
+		}
+		return criteria.And(left, right)
+	case *criteria.EqualsExpression:
+		return criteria.Not(t.Left(), t.Right())
+	case *criteria.NotExpression:
+		return criteria.Equals(t.Left(), t.Right())
+	case *criteria.LessThanExpression:
+		return criteria.GreaterThanOrEqual(t.Left(), t.Right())
+	case *criteria.LessThanOrEqualExpression:
+		return criteria.GreaterThan(t.Left(), t.Right())
+	case *criteria.GreaterThanExpression:
+		return criteria.LessThanOrEqual(t.Left(), t.Right())
+	case *criteria.GreaterThanOrEqualExpression:
+		return criteria.LessThan(t.Left(), t.Right())
+	case *criteria.IsNullExpression:
+		return criteria.IsNotNull(t.Operand())
+	case *criteria.IsNotNullExpression:
+		return criteria.IsNull(t.Operand())
+	case *criteria.InExpression:
+		// "a not in (x, y)" is the same as "a != x and a != y"
+		field, isField := t.Left().(*criteria.FieldExpression)
+		values, isList := literalValue(t.Right()).([]interface{})
+		if !isField || !isList {
+			return nil
+		}
+		var result criteria.Expression = criteria.Literal(true)
+		for i, value := range values {
+			current := criteria.Not(criteria.Field(field.FieldName), criteria.Literal(value))
+			if i == 0 {
+				result = current
+			} else {
+				result = criteria.And(result, current)
+			}
+		}
+		return result
+	case *criteria.LiteralExpression:
+		if value, ok := t.Value.(bool); ok {
+			return criteria.Literal(!value)
+		}
+	}
+	return nil
+}
+
+func literalValue(exp criteria.Expression) interface{} {
+	if l, ok := exp.(*criteria.LiteralExpression); ok {
+		return l.Value
+	}
+	return nil
+}
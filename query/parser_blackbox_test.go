@@ -0,0 +1,192 @@
+package query_test
+
+import (
+	"testing"
+
+	c "github.com/almighty/almighty-core/criteria"
+	"github.com/almighty/almighty-core/query"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, q string) c.Expression {
+	result, err := query.Parse(&q)
+	require.Nil(t, err, "failed to parse %q", q)
+	return result
+}
+
+func TestParseEmpty(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	result, err := query.Parse(nil)
+	require.Nil(t, err)
+	assert.Equal(t, c.Literal(true), result)
+	assert.Equal(t, c.Literal(true), parse(t, "   "))
+}
+
+func TestParseComparison(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, c.Equals(c.Field("system.state"), c.Literal("open")), parse(t, `system.state = "open"`))
+	assert.Equal(t, c.Equals(c.Field("system.state"), c.Literal("open")), parse(t, `system.state=='open'`))
+	assert.Equal(t, c.Not(c.Field("system.order"), c.Literal(5)), parse(t, `system.order != 5`))
+	assert.Equal(t, c.Equals(c.Field("system.order"), c.Literal(-2.5)), parse(t, `system.order = -2.5`))
+	assert.Equal(t, c.Equals(c.Field("flag"), c.Literal(false)), parse(t, `flag = FALSE`))
+	assert.Equal(t, c.Equals(c.Field("odd name"), c.Literal(`say "hi"`)), parse(t, `"odd name" = "say \"hi\""`))
+	assert.Equal(t, c.Equals(c.Field("system.assignees"), c.Literal([]string{"a", "b"})), parse(t, `system.assignees = ["a", "b"]`))
+	assert.Equal(t, c.Equals(c.Field("system.assignees"), c.Literal([]string{})), parse(t, `system.assignees = []`))
+}
+
+func TestParseBooleanOperators(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	a := func() c.Expression { return c.Equals(c.Field("a"), c.Literal(1)) }
+	b := func() c.Expression { return c.Equals(c.Field("b"), c.Literal(2)) }
+	d := func() c.Expression { return c.Equals(c.Field("d"), c.Literal(3)) }
+
+	// AND binds tighter than OR, both are left associative
+	assert.Equal(t, c.Or(a(), c.And(b(), d())), parse(t, `a = 1 or b = 2 and d = 3`))
+	assert.Equal(t, c.And(c.And(a(), b()), d()), parse(t, `a = 1 AND b = 2 AND d = 3`))
+	assert.Equal(t, c.And(c.Or(a(), b()), d()), parse(t, `(a = 1 OR b = 2) AND d = 3`))
+	assert.Equal(t, c.Literal(true), parse(t, `((true))`))
+}
+
+func TestParseNot(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, c.Not(c.Field("a"), c.Literal(1)), parse(t, `NOT a = 1`))
+	assert.Equal(t, c.Equals(c.Field("a"), c.Literal(1)), parse(t, `not a != 1`))
+	assert.Equal(t, c.Equals(c.Field("a"), c.Literal(1)), parse(t, `not not a = 1`))
+	assert.Equal(t, c.Literal(false), parse(t, `not true`))
+	// negation is pushed down to the comparisons
+	assert.Equal(t,
+		c.And(c.Not(c.Field("a"), c.Literal(1)), c.Equals(c.Field("b"), c.Literal(2))),
+		parse(t, `NOT (a = 1 OR b != 2)`))
+	assert.Equal(t,
+		c.Or(c.Not(c.Field("a"), c.Literal(1)), c.Not(c.Field("b"), c.Literal(2))),
+		parse(t, `NOT (a = 1 AND b = 2)`))
+}
+
+func TestParseLegacyJSON(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t,
+		c.And(c.Equals(c.Field("a"), c.Literal("x")), c.Equals(c.Field("b"), c.Literal(float64(2)))),
+		parse(t, `{"b": 2, "a": "x"}`))
+	assert.Equal(t, c.Literal(true), parse(t, `{}`))
+	q := `{"a": }`
+	_, err := query.Parse(&q)
+	assert.NotNil(t, err)
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	testData := []struct {
+		query string
+		pos   int
+	}{
+		{`system.state`, 13},
+		{`system.state = `, 16},
+		{`system.state = "open`, 16},
+		{`system.state ! "open"`, 14},
+		{`a = 1 b = 2`, 7},
+		{`(a = 1`, 7},
+		{`a = 1)`, 6},
+		{`a = 1 AND`, 10},
+		{`a = [1]`, 6},
+		{`a = $`, 5},
+		{`= 1`, 1},
+		{`a = -`, 5},
+		{`é = 1 OR`, 9},
+	}
+	for _, d := range testData {
+		q := d.query
+		_, err := query.Parse(&q)
+		require.NotNil(t, err, "expected error for %q", q)
+		parseErr, ok := err.(query.ParseError)
+		require.True(t, ok, "expected ParseError for %q but got %T", q, err)
+		assert.Equal(t, d.pos, parseErr.Pos, "wrong position for %q: %s", q, parseErr.Error())
+	}
+}
+
+func TestParseRelationalOperators(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, c.LessThan(c.Field("a"), c.Literal(1)), parse(t, `a < 1`))
+	assert.Equal(t, c.LessThanOrEqual(c.Field("a"), c.Literal(1)), parse(t, `a<=1`))
+	assert.Equal(t, c.GreaterThan(c.Field("system.created_at"), c.Literal("2017-01-01")), parse(t, `system.created_at > "2017-01-01"`))
+	assert.Equal(t, c.GreaterThanOrEqual(c.Field("a"), c.Literal(1.5)), parse(t, `a >= 1.5`))
+	assert.Equal(t, c.In(c.Field("system.state"), c.Literal([]interface{}{"new", "open", 3})), parse(t, `system.state IN ("new", 'open', 3)`))
+	assert.Equal(t, c.IsNull(c.Field("system.assignees")), parse(t, `system.assignees is null`))
+	assert.Equal(t, c.IsNotNull(c.Field("system.assignees")), parse(t, `system.assignees IS NOT NULL`))
+	assert.Equal(t, c.Substring(c.Field("system.title"), c.Literal("foo")), parse(t, `system.title contains "foo"`))
+	assert.Equal(t, c.ILike(c.Field("system.title"), c.Literal("f%o")), parse(t, `system.title ILIKE "f%o"`))
+}
+
+func TestParseNegatedRelationalOperators(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, c.GreaterThanOrEqual(c.Field("a"), c.Literal(1)), parse(t, `NOT a < 1`))
+	assert.Equal(t, c.GreaterThan(c.Field("a"), c.Literal(1)), parse(t, `NOT a <= 1`))
+	assert.Equal(t, c.LessThanOrEqual(c.Field("a"), c.Literal(1)), parse(t, `NOT a > 1`))
+	assert.Equal(t, c.LessThan(c.Field("a"), c.Literal(1)), parse(t, `NOT a >= 1`))
+	assert.Equal(t, c.IsNotNull(c.Field("a")), parse(t, `NOT a IS NULL`))
+	assert.Equal(t, c.IsNull(c.Field("a")), parse(t, `NOT a IS NOT NULL`))
+	assert.Equal(t,
+		c.And(c.Not(c.Field("a"), c.Literal("x")), c.Not(c.Field("a"), c.Literal("y"))),
+		parse(t, `a NOT IN ("x", "y")`))
+	assert.Equal(t, c.Equals(c.Field("a"), c.Literal("x")), parse(t, `NOT a NOT IN ("x")`))
+
+	q := `NOT system.title CONTAINS "foo"`
+	_, err := query.Parse(&q)
+	require.NotNil(t, err)
+	assert.Equal(t, 1, err.(query.ParseError).Pos)
+}
+
+func TestParseRelationalOperatorErrors(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	testData := []struct {
+		query string
+		pos   int
+	}{
+		{`a IN ()`, 7},
+		{`a IN ("x",)`, 11},
+		{`a IN "x"`, 6},
+		{`a NOT "x"`, 7},
+		{`a IS "x"`, 6},
+		{`a IS NOT`, 9},
+		{`a CONTAINS 5`, 12},
+		{`a b $`, 3},
+		{`a <> 1`, 4},
+	}
+	for _, d := range testData {
+		q := d.query
+		_, err := query.Parse(&q)
+		require.NotNil(t, err, "expected error for %q", q)
+		parseErr, ok := err.(query.ParseError)
+		require.True(t, ok, "expected ParseError for %q but got %T", q, err)
+		assert.Equal(t, d.pos, parseErr.Pos, "wrong position for %q: %s", q, parseErr.Error())
+	}
+}
+
+func TestParsedExpressionsRoundTrip(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	for _, q := range []string{
+		`system.state = "open" AND (system.title != 'foo' OR NOT system.order = 5)`,
+		`system.state IN ("new", "open", 3) AND system.assignees IS NULL`,
+		`system.title CONTAINS "crash" OR system.title ILIKE "fix%" OR system.order >= -2.5`,
+		`a NOT IN ("x", "y") AND system.assignees = ["a", "b"] AND flag = false`,
+		`{"b": 2, "a": "x", "c": null, "d": [1, "e"]}`,
+	} {
+		exp := parse(t, q)
+		printed, err := c.Marshal(exp)
+		require.Nil(t, err, "failed to print %q", q)
+		parsed, err := c.Unmarshal(printed)
+		require.Nil(t, err, "failed to read back %s", printed)
+		assert.Equal(t, exp, parsed, "round trip of %q", q)
+	}
+}
@@ -2,7 +2,9 @@ package search
 
 import (
 	"fmt"
+	"html"
 	"sync"
+	"unicode"
 
 	"golang.org/x/net/context"
 
@@ -12,6 +14,8 @@ import (
 
 	"net/url"
 
+	"strconv"
+
 	"github.com/almighty/almighty-core/errors"
 	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/workitem"
@@ -47,7 +51,146 @@ func generateSearchQuery(q string) (string, error) {
 type searchKeyword struct {
 	workItemTypes []uuid.UUID
 	id            []string
+	keys          []string
+	keyWords      []string // the words of the keys, which are searched as text as well
 	words         []string
+	excluded      []string
+	qualifiers    []qualifier
+}
+
+// qualifier restricts the search results to the work items with a given field value,
+// e.g. state:open, or without it if it is negated, e.g. -state:closed
+type qualifier struct {
+	name    string
+	value   string
+	negated bool
+}
+
+// the qualifier value that refers to the current user, e.g. in assignee:me
+const qualifierValueMe = "me"
+
+// qualifierConditions maps the names of the known qualifiers to the conditions they add to the search.
+// Every "?" in a condition is replaced with the value of the qualifier. Iterations, areas and spaces can
+// be given by name or ID, users by username or identity ID.
+var qualifierConditions = map[string]string{
+	"state":     "fields->>'" + workitem.SystemState + "' = ?",
+	"assignee":  "exists (select 1 from identities i where (i.id::text = ? or i.username = ?) and fields->'" + workitem.SystemAssignees + "' @> jsonb_build_array(i.id::text))",
+	"creator":   "fields->>'" + workitem.SystemCreator + "' in (select id::text from identities where id::text = ? or username = ?)",
+	"iteration": "fields->>'" + workitem.SystemIteration + "' in (select id::text from iterations where id::text = ? or name = ?)",
+	"area":      "fields->>'" + workitem.SystemArea + "' in (select id::text from areas where id::text = ? or name = ?)",
+	"space":     "space_id in (select id from spaces where id::text = ? or name = ?)",
+}
+
+// qualifierPattern matches qualifiers like state:open, -assignee:me or iteration:"Sprint 4"
+var qualifierPattern = regexp.MustCompile(`^(-?)([a-z]+):(.*)$`)
+
+// condition returns the where clause and its parameters for the qualifier. The given
+// identity is used for the value "me", which requires a logged in user.
+func (q qualifier) condition(identity *uuid.UUID) (string, []interface{}, error) {
+	value := q.value
+	if value == qualifierValueMe && (q.name == "assignee" || q.name == "creator") {
+		if identity == nil {
+			return "", nil, errors.NewUnauthorizedError(fmt.Sprintf("%s:%s requires a logged in user", q.name, q.value))
+		}
+		value = identity.String()
+	}
+	condition := qualifierConditions[q.name]
+	parameters := make([]interface{}, strings.Count(condition, "?"))
+	for i := range parameters {
+		parameters[i] = value
+	}
+	if q.negated {
+		// work items without a value for the field are not excluded by a negated qualifier
+		condition = "not coalesce((" + condition + "), false)"
+	}
+	return condition, parameters, nil
+}
+
+// Scope restricts a search and resolves the qualifiers that refer to the current user
+type Scope struct {
+	// SpaceID restricts the search to the work items of a space if set
+	SpaceID *uuid.UUID
+	// Identity is the logged in user that "me" refers to, e.g. in assignee:me
+	Identity *uuid.UUID
+}
+
+// Result is a work item found by a full-text search
+type Result struct {
+	workitem.WorkItem
+	// Relevance is the rank of the work item for the search query as computed
+	// by ts_rank_cd, higher values are more relevant
+	Relevance float64
+	// Highlights holds html escaped excerpts of the matched fields with the search
+	// terms enclosed in <b></b>, by field name. Fields without a match are omitted.
+	Highlights map[string]string
+	// Comments holds the most relevant comments of the work item that matched the search terms
+	Comments []CommentMatch
+}
+
+// CommentMatch is a comment that matched the search terms
+type CommentMatch struct {
+	ID uuid.UUID
+	// Snippet is an html escaped excerpt of the comment body with the search terms enclosed in <b></b>
+	Snippet string
+}
+
+// maxCommentMatches is the maximum number of comments returned for a found work item
+const maxCommentMatches = 3
+
+// commentsMatchingQuery selects the comments of a work item that match the search terms
+var commentsMatchingQuery = commentsMatching("query")
+
+// commentsMatching selects the comments of a work item that match the given tsquery expression
+func commentsMatching(tsquery string) string {
+	return fmt.Sprintf("select 1 from comments c where c.parent_id = %[1]s.id::text "+
+		"and c.deleted_at is null and c.tsv @@ %[2]s", workitem.WorkItemStorage{}.TableName(), tsquery)
+}
+
+// rankQuery computes the rank of a work item, which includes the rank of its most relevant comment.
+// Comments are weighted lower than the fields of the work item.
+var rankQuery = fmt.Sprintf(", lateral (select ts_rank_cd(%[1]s.tsv, query) + coalesce(("+
+	"select max(ts_rank_cd(c.tsv, query)) from comments c where c.parent_id = %[1]s.id::text "+
+	"and c.deleted_at is null and c.tsv @@ query), 0) as rank) as ranked", workitem.WorkItemStorage{}.TableName())
+
+// the markers of the search terms in headlines, private use characters are used
+// so that they can be told apart from the text when it is escaped
+const (
+	headlineStartSel = "\ue000"
+	headlineStopSel  = "\ue001"
+)
+
+var (
+	titleHeadlineOptions       = fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", headlineStartSel, headlineStopSel)
+	descriptionHeadlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, MaxFragments=2, MaxWords=20, MinWords=5", headlineStartSel, headlineStopSel)
+	headlineReplacer           = strings.NewReplacer(headlineStartSel, "<b>", headlineStopSel, "</b>")
+)
+
+// match holds the rank and the headlines computed by the database for a search result
+type match struct {
+	rank                float64
+	titleHeadline       string
+	descriptionHeadline string
+}
+
+// highlights returns the highlighted excerpts of the fields that matched the search terms
+func (m match) highlights() map[string]string {
+	result := map[string]string{}
+	if h, ok := highlight(m.titleHeadline); ok {
+		result[workitem.SystemTitle] = h
+	}
+	if h, ok := highlight(m.descriptionHeadline); ok {
+		result[workitem.SystemDescription] = h
+	}
+	return result
+}
+
+// highlight escapes the given headline and replaces the markers of the search terms
+// with <b></b>. It returns false if the headline contains no search terms.
+func highlight(headline string) (string, bool) {
+	if !strings.Contains(headline, headlineStartSel) {
+		return "", false
+	}
+	return headlineReplacer.Replace(html.EscapeString(headline)), true
 }
 
 // KnownURL has a regex string format URL and compiled regex for the same
@@ -191,13 +334,54 @@ func getSearchQueryFromURLString(url string) string {
 	return sanitizeURL(url) + ":*"
 }
 
+// splitSearchString splits the raw search string at white space that is not enclosed in double quotes
+func splitSearchString(rawSearchString string) []string {
+	var result []string
+	var token []rune
+	quoted := false
+	for _, r := range rawSearchString {
+		if unicode.IsSpace(r) && !quoted {
+			if len(token) > 0 {
+				result = append(result, string(token))
+				token = nil
+			}
+			continue
+		}
+		if r == '"' {
+			quoted = !quoted
+		}
+		token = append(token, r)
+	}
+	if len(token) > 0 {
+		result = append(result, string(token))
+	}
+	return result
+}
+
 // parseSearchString accepts a raw string and generates a searchKeyword object
 func parseSearchString(rawSearchString string) (searchKeyword, error) {
 	// TODO remove special characters and exclaimations if any
 	rawSearchString = strings.Trim(rawSearchString, "/") // get rid of trailing slashes
-	rawSearchString = strings.Trim(rawSearchString, "\"")
-	parts := strings.Fields(rawSearchString)
 	var res searchKeyword
+	var parts []string
+	for _, token := range splitSearchString(rawSearchString) {
+		if m := qualifierPattern.FindStringSubmatch(token); m != nil {
+			if _, ok := qualifierConditions[m[2]]; ok {
+				value := strings.Trim(m[3], "\"")
+				if len(value) == 0 {
+					return res, errors.NewBadParameterError("qualifier value must not be empty", token)
+				}
+				res.qualifiers = append(res.qualifiers, qualifier{name: m[2], value: value, negated: m[1] == "-"})
+				continue
+			}
+		}
+		// quotes only group the values of qualifiers
+		for _, part := range strings.Fields(token) {
+			if part = strings.Trim(part, "\""); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
 	for _, part := range parts {
 		// QueryUnescape is required in case of encoded url strings.
 		// And does not harm regular search strings
@@ -212,7 +396,14 @@ func parseSearchString(rawSearchString string) (searchKeyword, error) {
 		}
 		// IF part is for search with id:1234
 		// TODO: need to find out the way to use ID fields.
-		if strings.HasPrefix(part, "id:") {
+		if key, ok := workitem.ParseKey(part); ok {
+			// a key like PLAT-42 finds its work item, but words like UTF-8 look like keys too,
+			// so the key is searched as text as well
+			word := sanitizeURL(strings.ToLower(part)) + ":*"
+			res.keys = append(res.keys, key)
+			res.keyWords = append(res.keyWords, word)
+			res.words = append(res.words, word)
+		} else if strings.HasPrefix(part, "id:") {
 			res.id = append(res.id, strings.TrimPrefix(part, "id:")+":*A")
 		} else if strings.HasPrefix(part, "type:") {
 			typeIDStr := strings.TrimPrefix(part, "type:")
@@ -229,6 +420,11 @@ func parseSearchString(rawSearchString string) (searchKeyword, error) {
 			part = trimProtocolFromURLString(part)
 			searchQueryFromURL := getSearchQueryFromURLString(part)
 			res.words = append(res.words, searchQueryFromURL)
+		} else if len(part) > 1 && strings.HasPrefix(part, "-") {
+			// exclude work items that contain the word
+			part := strings.ToLower(part[1:])
+			part = sanitizeURL(part)
+			res.excluded = append(res.excluded, part+":*")
 		} else {
 			part := strings.ToLower(part)
 			part = sanitizeURL(part)
@@ -250,80 +446,151 @@ func generateSQLSearchInfo(keywords searchKeyword) (sqlParameter string) {
 	return searchStr
 }
 
+// withoutKeys returns the keywords without the words of the keys
+func (k searchKeyword) withoutKeys() searchKeyword {
+	res := k
+	res.words = nil
+	for _, word := range k.words {
+		isKey := false
+		for _, keyWord := range k.keyWords {
+			if word == keyWord {
+				isKey = true
+				break
+			}
+		}
+		if !isKey {
+			res.words = append(res.words, word)
+		}
+	}
+	res.keys = nil
+	res.keyWords = nil
+	return res
+}
+
+// generateSQLExclusionInfo accepts searchKeyword and joins its excluded words in a way that can be used in sql
+func generateSQLExclusionInfo(keywords searchKeyword) string {
+	return strings.Join(keywords.excluded, " | ")
+}
+
+// searchQuery returns the query for the work items that match the given keywords within the given
+// scope. The tsquery of the keywords is available as "query" for further use. Qualifiers and types alone
+// select the matching work items regardless of their text. Keys select their work items in addition to
+// the ones matching the text, as long as the other words match as well.
+func (r *GormSearchRepository) searchQuery(keywords searchKeyword, scope Scope) (*gorm.DB, error) {
+	sqlSearchQueryParameter := generateSQLSearchInfo(keywords)
+	db := r.db.Model(workitem.WorkItemStorage{}).Joins(", to_tsquery('english', ?) as query", sqlSearchQueryParameter)
+	// work items match the search terms themselves or through their comments
+	textCondition := "tsv @@ query OR exists (" + commentsMatchingQuery + ")"
+	if len(keywords.keys) > 0 {
+		keyCondition := workitem.WorkItemStorage{}.TableName() + ".key in (?)"
+		parameters := []interface{}{keywords.keys}
+		if otherWords := generateSQLSearchInfo(keywords.withoutKeys()); otherWords != "" {
+			keyCondition += " AND (tsv @@ to_tsquery('english', ?) OR exists (" + commentsMatching("to_tsquery('english', ?)") + "))"
+			parameters = append(parameters, otherWords, otherWords)
+		}
+		db = db.Where("("+textCondition+" OR ("+keyCondition+"))", parameters...)
+	} else if sqlSearchQueryParameter != "" || (len(keywords.excluded) == 0 && len(keywords.qualifiers) == 0 && len(keywords.workItemTypes) == 0) {
+		db = db.Where("(" + textCondition + ")")
+	}
+	if excluded := generateSQLExclusionInfo(keywords); excluded != "" {
+		// excluded words rule out the work item itself, no matter whether its comments match
+		db = db.Where("not "+workitem.WorkItemStorage{}.TableName()+".tsv @@ to_tsquery('english', ?)", excluded)
+	}
+	if len(keywords.workItemTypes) > 0 {
+		// restrict to all given types and their subtypes
+		query := fmt.Sprintf("%[1]s.type in ("+
+			"select distinct subtype.id from %[2]s subtype "+
+			"join %[2]s supertype on subtype.path <@ supertype.path "+
+			"where supertype.id in (?))", workitem.WorkItemStorage{}.TableName(), workitem.WorkItemType{}.TableName())
+		db = db.Where(query, keywords.workItemTypes)
+	}
+	for _, q := range keywords.qualifiers {
+		condition, parameters, err := q.condition(scope.Identity)
+		if err != nil {
+			return nil, errs.WithStack(err)
+		}
+		db = db.Where(condition, parameters...)
+	}
+	if scope.SpaceID != nil {
+		db = db.Where(workitem.WorkItemStorage{}.TableName()+".space_id = ?", *scope.SpaceID)
+	}
+	return db, nil
+}
+
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
-func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, start *int, limit *int) ([]workitem.WorkItemStorage, uint64, error) {
-	db := r.db.Model(workitem.WorkItemStorage{}).Where("tsv @@ query")
+func (r *GormSearchRepository) search(ctx context.Context, db *gorm.DB, start *int, limit *int) ([]workitem.WorkItemStorage, []match, uint64, error) {
 	if start != nil {
 		if *start < 0 {
-			return nil, 0, errors.NewBadParameterError("start", *start)
+			return nil, nil, 0, errors.NewBadParameterError("start", *start)
 		}
 		db = db.Offset(*start)
 	}
 	if limit != nil {
 		if *limit <= 0 {
-			return nil, 0, errors.NewBadParameterError("limit", *limit)
+			return nil, nil, 0, errors.NewBadParameterError("limit", *limit)
 		}
 		db = db.Limit(*limit)
 	}
-	if len(workItemTypes) > 0 {
-		// restrict to all given types and their subtypes
-		query := fmt.Sprintf("%[1]s.type in ("+
-			"select distinct subtype.id from %[2]s subtype "+
-			"join %[2]s supertype on subtype.path <@ supertype.path "+
-			"where supertype.id in (?))", workitem.WorkItemStorage{}.TableName(), workitem.WorkItemType{}.TableName())
-		db = db.Where(query, workItemTypes)
-	}
 
-	db = db.Select("count(*) over () as cnt2 , *")
-	db = db.Joins(", to_tsquery('english', ?) as query, ts_rank(tsv, query) as rank", sqlSearchQueryParameter)
+	db = db.Select("count(*) over () as cnt2 , *, "+
+		"ts_headline('english', coalesce(fields->>'system.title', ''), query, ?) as title_headline, "+
+		"ts_headline('english', coalesce(fields#>>'{system.description, content}', ''), query, ?) as description_headline",
+		titleHeadlineOptions, descriptionHeadlineOptions)
+	db = db.Joins(rankQuery)
 	db = db.Order(fmt.Sprintf("rank desc,%s.updated_at desc", workitem.WorkItemStorage{}.TableName()))
 
 	rows, err := db.Rows()
 	if err != nil {
-		return nil, 0, errs.WithStack(err)
+		return nil, nil, 0, errs.WithStack(err)
 	}
 	defer rows.Close()
 
 	result := []workitem.WorkItemStorage{}
-	value := workitem.WorkItemStorage{}
+	matches := []match{}
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, 0, errors.NewInternalError(err.Error())
+		return nil, nil, 0, errors.NewInternalError(err.Error())
 	}
 
-	// need to set up a result for Scan() in order to extract total count.
+	// need to set up a result for Scan() in order to extract total count and the match of each row.
 	var count uint64
+	var m match
 	var ignore interface{}
 	columnValues := make([]interface{}, len(columns))
 
-	for index := range columnValues {
-		columnValues[index] = &ignore
+	for index, column := range columns {
+		switch column {
+		case "rank":
+			columnValues[index] = &m.rank
+		case "title_headline":
+			columnValues[index] = &m.titleHeadline
+		case "description_headline":
+			columnValues[index] = &m.descriptionHeadline
+		default:
+			columnValues[index] = &ignore
+		}
 	}
 	columnValues[0] = &count
-	first := true
 
 	for rows.Next() {
+		value := workitem.WorkItemStorage{}
 		db.ScanRows(rows, &value)
-		if first {
-			first = false
-			if err = rows.Scan(columnValues...); err != nil {
-				return nil, 0, errors.NewInternalError(err.Error())
-			}
+		if err = rows.Scan(columnValues...); err != nil {
+			return nil, nil, 0, errors.NewInternalError(err.Error())
 		}
 		result = append(result, value)
-
+		matches = append(matches, m)
 	}
-	if first {
+	if len(result) == 0 {
 		// means 0 rows were returned from the first query,
 		count = 0
 	}
-	return result, count, nil
-	//*/
+	return result, matches, count, nil
 }
 
-// SearchFullText Search returns work items for the given query
-func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchString string, start *int, limit *int) ([]workitem.WorkItem, uint64, error) {
+// SearchFullText Search returns work items for the given query within the given scope, the most relevant first
+func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchString string, scope Scope, start *int, limit *int) ([]Result, uint64, error) {
 	// parse
 	// generateSearchQuery
 	// ....
@@ -332,13 +599,22 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 		return nil, 0, errs.WithStack(err)
 	}
 
-	sqlSearchQueryParameter := generateSQLSearchInfo(parsedSearchDict)
-	var rows []workitem.WorkItemStorage
-	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, start, limit)
+	db, err := r.searchQuery(parsedSearchDict, scope)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
-	result := make([]workitem.WorkItem, len(rows))
+	rows, matches, count, err := r.search(ctx, db, start, limit)
+	if err != nil {
+		return nil, 0, errs.WithStack(err)
+	}
+	result := make([]Result, len(rows))
+	comments := map[string][]CommentMatch{}
+	if sqlSearchQueryParameter := generateSQLSearchInfo(parsedSearchDict); sqlSearchQueryParameter != "" && len(rows) > 0 {
+		comments, err = r.commentMatches(sqlSearchQueryParameter, rows)
+		if err != nil {
+			return nil, 0, errs.WithStack(err)
+		}
+	}
 
 	for index, value := range rows {
 		var err error
@@ -351,12 +627,139 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 		if err != nil {
 			return nil, 0, errors.NewConversionError(err.Error())
 		}
-		result[index] = *wiModel
+		result[index] = Result{
+			WorkItem:   *wiModel,
+			Relevance:  matches[index].rank,
+			Highlights: matches[index].highlights(),
+			Comments:   comments[wiModel.ID],
+		}
 	}
 
 	return result, count, nil
 }
 
+// commentMatches returns the most relevant comments of the given work items that match the given tsquery,
+// by work item ID
+func (r *GormSearchRepository) commentMatches(sqlSearchQueryParameter string, workItems []workitem.WorkItemStorage) (map[string][]CommentMatch, error) {
+	parentIDs := make([]string, len(workItems))
+	for i, wi := range workItems {
+		parentIDs[i] = strconv.FormatUint(wi.ID, 10)
+	}
+	// only the most relevant comments of each work item are kept before the headlines are computed
+	rows, err := r.db.Raw(`select id, parent_id, ts_headline('english', coalesce(body, ''), query, ?) as headline
+		from (
+			select c.id, c.parent_id, c.body, query, row_number() over (
+				partition by c.parent_id order by ts_rank_cd(c.tsv, query) desc, c.created_at desc) as position
+			from comments c, to_tsquery('english', ?) as query
+			where c.parent_id in (?) and c.deleted_at is null and c.tsv @@ query
+		) as ranked
+		where position <= ?
+		order by parent_id, position`,
+		descriptionHeadlineOptions, sqlSearchQueryParameter, parentIDs, maxCommentMatches).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	defer rows.Close()
+	result := map[string][]CommentMatch{}
+	for rows.Next() {
+		var id uuid.UUID
+		var parentID, headline string
+		if err := rows.Scan(&id, &parentID, &headline); err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		snippet, ok := highlight(headline)
+		if !ok {
+			snippet = html.EscapeString(headline)
+		}
+		result[parentID] = append(result[parentID], CommentMatch{ID: id, Snippet: snippet})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return result, nil
+}
+
+// Facets holds the number of work items that match a search for each value of
+// a field, by the name of the field
+type Facets map[string]map[string]int
+
+// The names of the facets of search results
+const (
+	FacetWorkItemType = "workitemtype"
+	FacetState        = workitem.SystemState
+	FacetAssignee     = workitem.SystemAssignees
+	FacetIteration    = workitem.SystemIteration
+	FacetArea         = workitem.SystemArea
+)
+
+// facetNames holds the names of the facets in the order of their columns in the facet query
+var facetNames = []string{FacetWorkItemType, FacetState, FacetAssignee, FacetIteration, FacetArea}
+
+// facetTerms maps the names of the facets to the terms that compute their values
+var facetTerms = map[string]string{
+	FacetWorkItemType: workitem.WorkItemStorage{}.TableName() + ".type::text",
+	FacetState:        "fields->>'" + workitem.SystemState + "'",
+	FacetAssignee:     "assignee",
+	FacetIteration:    "fields->>'" + workitem.SystemIteration + "'",
+	FacetArea:         "fields->>'" + workitem.SystemArea + "'",
+}
+
+// SearchFacets returns the number of work items that match the given query within the given scope for
+// each work item type, state, assignee, iteration and area. Unlike SearchFullText it considers all
+// matching work items. Work items without a value are not counted, every assignee of a work item counts.
+func (r *GormSearchRepository) SearchFacets(ctx context.Context, rawSearchString string, scope Scope) (Facets, error) {
+	parsedSearchDict, err := parseSearchString(rawSearchString)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	db, err := r.searchQuery(parsedSearchDict, scope)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// work items without assignees get a single null assignee, so that they count in the other facets
+	db = db.Joins(", jsonb_array_elements_text(case when jsonb_typeof(fields->'" + workitem.SystemAssignees + "') = 'array' " +
+		"and jsonb_array_length(fields->'" + workitem.SystemAssignees + "') > 0 " +
+		"then fields->'" + workitem.SystemAssignees + "' else '[null]'::jsonb end) as assignee")
+	// all facets are counted in a single pass, the row of a value has null in the columns of the other facets
+	columns := make([]string, len(facetNames))
+	sets := make([]string, len(facetNames))
+	for i, name := range facetNames {
+		columns[i] = facetTerms[name]
+		sets[i] = "(" + facetTerms[name] + ")"
+	}
+	rows, err := db.Select(strings.Join(columns, ", ") + ", count(distinct " + workitem.WorkItemStorage{}.TableName() + ".id)").
+		Group("grouping sets (" + strings.Join(sets, ", ") + ")").Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	defer rows.Close()
+	result := Facets{}
+	for _, name := range facetNames {
+		result[name] = map[string]int{}
+	}
+	values := make([]*string, len(facetNames))
+	var count int
+	destinations := make([]interface{}, len(facetNames)+1)
+	for i := range values {
+		destinations[i] = &values[i]
+	}
+	destinations[len(facetNames)] = &count
+	for rows.Next() {
+		if err := rows.Scan(destinations...); err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		for i, value := range values {
+			if value != nil {
+				result[facetNames[i]][*value] = count
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return result, nil
+}
+
 func init() {
 	// While registering URLs do not include protocol because it will be removed before scanning starts
 	// Please do not include trailing slashes because it will be removed before scanning starts
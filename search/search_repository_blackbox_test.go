@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/almighty/almighty-core/comment"
 	"github.com/almighty/almighty-core/gormsupport/cleaner"
 	"github.com/almighty/almighty-core/gormtestsupport"
 	"github.com/almighty/almighty-core/migration"
@@ -63,7 +64,7 @@ func (s *searchRepositoryBlackboxTest) TestRestrictByType() {
 	params := url.Values{}
 	ctx := goa.NewContext(context.Background(), nil, req, params)
 
-	res, count, err := s.searchRepo.SearchFullText(ctx, "TestRestrictByType", nil, nil)
+	res, count, err := s.searchRepo.SearchFullText(ctx, "TestRestrictByType", search.Scope{}, nil, nil)
 	require.Nil(s.T(), err)
 	require.True(s.T(), count == uint64(len(res))) // safety check for many, many instances of bogus search results.
 	for _, wi := range res {
@@ -100,37 +101,119 @@ func (s *searchRepositoryBlackboxTest) TestRestrictByType() {
 	require.Nil(s.T(), err)
 	require.NotNil(s.T(), wi2)
 
-	res, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType", nil, nil)
+	res, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType", search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(2), count)
 
-	res, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+sub1.ID.String(), nil, nil)
+	res, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+sub1.ID.String(), search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(1), count)
 	if count == 1 {
 		assert.Equal(s.T(), wi1.ID, res[0].ID)
 	}
 
-	res, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+sub2.ID.String(), nil, nil)
+	res, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+sub2.ID.String(), search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(1), count)
 	if count == 1 {
 		assert.Equal(s.T(), wi2.ID, res[0].ID)
 	}
 
-	_, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+base.ID.String(), nil, nil)
+	_, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+base.ID.String(), search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(2), count)
 
-	_, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+sub2.ID.String()+" type:"+sub1.ID.String(), nil, nil)
+	_, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+sub2.ID.String()+" type:"+sub1.ID.String(), search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(2), count)
 
-	_, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+base.ID.String()+" type:"+sub1.ID.String(), nil, nil)
+	_, count, err = s.searchRepo.SearchFullText(ctx, "TestRestrictByType type:"+base.ID.String()+" type:"+sub1.ID.String(), search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(2), count)
 
-	_, count, err = s.searchRepo.SearchFullText(ctx, "TRBTgorxi type:"+base.ID.String(), nil, nil)
+	_, count, err = s.searchRepo.SearchFullText(ctx, "TRBTgorxi type:"+base.ID.String(), search.Scope{}, nil, nil)
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), uint64(0), count)
 }
+
+func (s *searchRepositoryBlackboxTest) TestSearchFacets() {
+	// given
+	ctx := context.Background()
+	assignee := uuid.NewV4().String()
+	create := func(title, state string, assignees []string) *workitem.WorkItem {
+		fields := map[string]interface{}{
+			workitem.SystemTitle: title,
+			workitem.SystemState: state,
+		}
+		if assignees != nil {
+			fields[workitem.SystemAssignees] = assignees
+		}
+		wi, err := s.wiRepo.Create(ctx, space.SystemSpace, workitem.SystemBug, fields, s.modifierID)
+		require.Nil(s.T(), err)
+		return wi
+	}
+	create("TestSearchFacets one", workitem.SystemStateNew, []string{assignee})
+	create("TestSearchFacets two", workitem.SystemStateNew, nil)
+	create("TestSearchFacets three", workitem.SystemStateClosed, []string{assignee})
+	create("unrelated", workitem.SystemStateClosed, []string{assignee})
+	// when
+	facets, err := s.searchRepo.SearchFacets(ctx, "TestSearchFacets", search.Scope{})
+	// then all matching work items are counted
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), map[string]int{workitem.SystemBug.String(): 3}, facets[search.FacetWorkItemType])
+	assert.Equal(s.T(), map[string]int{workitem.SystemStateNew: 2, workitem.SystemStateClosed: 1}, facets[search.FacetState])
+	assert.Equal(s.T(), map[string]int{assignee: 2}, facets[search.FacetAssignee])
+	assert.Empty(s.T(), facets[search.FacetIteration])
+	assert.Empty(s.T(), facets[search.FacetArea])
+	// when nothing matches
+	facets, err = s.searchRepo.SearchFacets(ctx, "TSFnothingmatches", search.Scope{})
+	// then
+	require.Nil(s.T(), err)
+	assert.Empty(s.T(), facets[search.FacetState])
+}
+
+func (s *searchRepositoryBlackboxTest) TestSearchComments() {
+	// given
+	ctx := context.Background()
+	wi, err := s.wiRepo.Create(ctx, space.SystemSpace, workitem.SystemBug, map[string]interface{}{
+		workitem.SystemTitle: "unrelated title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}, s.modifierID)
+	require.Nil(s.T(), err)
+	commentRepo := comment.NewRepository(s.DB)
+	answer := &comment.Comment{ParentID: wi.ID, Body: "the answer is to restart the commentsearchterm service"}
+	require.Nil(s.T(), commentRepo.Create(ctx, answer, s.modifierID))
+	deleted := &comment.Comment{ParentID: wi.ID, Body: "deletedcommentsearchterm"}
+	require.Nil(s.T(), commentRepo.Create(ctx, deleted, s.modifierID))
+	require.Nil(s.T(), commentRepo.Delete(ctx, deleted.ID, s.modifierID))
+	// when
+	res, count, err := s.searchRepo.SearchFullText(ctx, "commentsearchterm", search.Scope{}, nil, nil)
+	// then the work item is found through its comment
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), uint64(1), count)
+	assert.Equal(s.T(), wi.ID, res[0].ID)
+	assert.True(s.T(), res[0].Relevance > 0)
+	assert.Empty(s.T(), res[0].Highlights)
+	require.Len(s.T(), res[0].Comments, 1)
+	assert.Equal(s.T(), answer.ID, res[0].Comments[0].ID)
+	assert.Contains(s.T(), res[0].Comments[0].Snippet, "<b>commentsearchterm</b>")
+	// when the comment is deleted
+	_, count, err = s.searchRepo.SearchFullText(ctx, "deletedcommentsearchterm", search.Scope{}, nil, nil)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(0), count)
+	// when the work item contains an excluded word
+	_, count, err = s.searchRepo.SearchFullText(ctx, "commentsearchterm -unrelated", search.Scope{}, nil, nil)
+	// then it is not found through its comment either
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(0), count)
+	// when more comments match than are returned
+	for i := 0; i < 4; i++ {
+		require.Nil(s.T(), commentRepo.Create(ctx, &comment.Comment{ParentID: wi.ID, Body: "manycommentsearchterm"}, s.modifierID))
+	}
+	res, count, err = s.searchRepo.SearchFullText(ctx, "manycommentsearchterm", search.Scope{}, nil, nil)
+	// then only the most relevant ones are returned
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), uint64(1), count)
+	assert.Len(s.T(), res[0].Comments, 3)
+}
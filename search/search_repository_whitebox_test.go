@@ -187,7 +187,7 @@ func (s *searchRepositoryWhiteboxTest) TestSearchByText() {
 			s.T().Log("using search string: " + searchString)
 			sr := NewGormSearchRepository(tx)
 			var start, limit int = 0, 100
-			workItemList, _, err := sr.SearchFullText(ctx, searchString, &start, &limit)
+			workItemList, _, err := sr.SearchFullText(ctx, searchString, Scope{}, &start, &limit)
 			if err != nil {
 				s.T().Fatal("Error getting search result ", err)
 			}
@@ -294,7 +294,7 @@ func (s *searchRepositoryWhiteboxTest) TestSearchByID() {
 
 		var start, limit int = 0, 100
 		searchString := "id:" + createdWorkItem.ID
-		workItemList, _, err := sr.SearchFullText(ctx, searchString, &start, &limit)
+		workItemList, _, err := sr.SearchFullText(ctx, searchString, Scope{}, &start, &limit)
 		if err != nil {
 			s.T().Fatal("Error gettig search result ", err)
 		}
@@ -309,6 +309,53 @@ func (s *searchRepositoryWhiteboxTest) TestSearchByID() {
 	})
 }
 
+func (s *searchRepositoryWhiteboxTest) TestSearchByKey() {
+	// given
+	ctx := context.Background()
+	wir := workitem.NewWorkItemRepository(s.DB)
+	fields := map[string]interface{}{
+		workitem.SystemTitle: "Search test by key",
+		workitem.SystemState: workitem.SystemStateNew,
+	}
+	created, err := wir.Create(ctx, space.SystemSpace, workitem.SystemBug, fields, s.modifierID)
+	require.Nil(s.T(), err)
+	other, err := wir.Create(ctx, space.SystemSpace, workitem.SystemBug, fields, s.modifierID)
+	require.Nil(s.T(), err)
+	sr := NewGormSearchRepository(s.DB)
+	var start, limit int = 0, 100
+	// when
+	result, _, err := sr.SearchFullText(ctx, strings.ToLower(created.Key), Scope{}, &start, &limit)
+	// then the key selects its work item
+	require.Nil(s.T(), err)
+	keys := map[string]string{}
+	for _, r := range result {
+		keys[r.ID] = r.Key
+	}
+	assert.Equal(s.T(), created.Key, keys[created.ID])
+	assert.NotContains(s.T(), keys, other.ID)
+	// when the key is combined with words that its work item does not contain
+	result, _, err = sr.SearchFullText(ctx, created.Key+" unmatchedsearchword", Scope{}, &start, &limit)
+	// then nothing is found
+	require.Nil(s.T(), err)
+	assert.Empty(s.T(), result)
+	// when the key is combined with words that its work item contains
+	result, _, err = sr.SearchFullText(ctx, created.Key+" search", Scope{}, &start, &limit)
+	// then only the keyed work item is found
+	require.Nil(s.T(), err)
+	require.Len(s.T(), result, 1)
+	assert.Equal(s.T(), created.ID, result[0].ID)
+	// when a word that looks like a key is part of the text
+	marker := "keylike" + strings.Replace(uuid.NewV4().String(), "-", "", -1)
+	fields[workitem.SystemTitle] = "Search test with utf-8 " + marker
+	textual, err := wir.Create(ctx, space.SystemSpace, workitem.SystemBug, fields, s.modifierID)
+	require.Nil(s.T(), err)
+	result, _, err = sr.SearchFullText(ctx, "utf-8 "+marker, Scope{}, &start, &limit)
+	// then the text is found
+	require.Nil(s.T(), err)
+	require.Len(s.T(), result, 1)
+	assert.Equal(s.T(), textual.ID, result[0].ID)
+}
+
 func TestGenerateSQLSearchStringText(t *testing.T) {
 	t.Parallel()
 	resource.Require(t, resource.UnitTest)
@@ -347,6 +394,22 @@ func TestParseSearchString(t *testing.T) {
 	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
 }
 
+func TestParseSearchStringKeys(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	input := "plat-42 login PLAT-0 state:open"
+	op, err := parseSearchString(input)
+	require.Nil(t, err)
+	expectedSearchRes := searchKeyword{
+		keys:       []string{"PLAT-42"},
+		keyWords:   []string{"plat-42:*"},
+		words:      []string{"plat-42:*", "login:*", "plat-0:*"},
+		qualifiers: []qualifier{{name: "state", value: "open"}},
+	}
+	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
+	assert.Equal(t, []string{"login:*", "plat-0:*"}, op.withoutKeys().words)
+}
+
 type searchTestData struct {
 	query    string
 	expected searchKeyword
@@ -499,3 +562,65 @@ func TestGetSearchQueryFromURLString(t *testing.T) {
 	searchQuery = getSearchQueryFromURLString("google.me.io/everything/100")
 	assert.Equal(t, "(100:* | google.me.io/everything/100:*)", searchQuery)
 }
+
+func TestHighlights(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	m := match{
+		titleHeadline:       "fix " + headlineStartSel + "login" + headlineStopSel + " <page>",
+		descriptionHeadline: "no search terms here",
+	}
+	assert.Equal(t, map[string]string{
+		workitem.SystemTitle: "fix <b>login</b> &lt;page&gt;",
+	}, m.highlights())
+}
+
+func TestParseSearchStringQualifiers(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	input := `login state:open -assignee:me iteration:"Sprint 4" -crash "quoted words"`
+	op, err := parseSearchString(input)
+	require.Nil(t, err)
+	expectedSearchRes := searchKeyword{
+		words:    []string{"login:*", "quoted:*", "words:*"},
+		excluded: []string{"crash:*"},
+		qualifiers: []qualifier{
+			{name: "state", value: "open"},
+			{name: "assignee", value: "me", negated: true},
+			{name: "iteration", value: "Sprint 4"},
+		},
+	}
+	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
+
+	_, err = parseSearchString("login state:")
+	assert.NotNil(t, err)
+}
+
+func TestSplitSearchString(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, []string{"a", `area:"x y"`, `"b c"`}, splitSearchString(` a	area:"x y"  "b c" `))
+	assert.Empty(t, splitSearchString("  "))
+}
+
+func TestQualifierCondition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given
+	identity := uuid.NewV4()
+	// when
+	condition, parameters, err := qualifier{name: "state", value: "open", negated: true}.condition(nil)
+	// then
+	require.Nil(t, err)
+	assert.Equal(t, "not coalesce((fields->>'system.state' = ?), false)", condition)
+	assert.Equal(t, []interface{}{"open"}, parameters)
+	// when
+	_, parameters, err = qualifier{name: "creator", value: "me"}.condition(&identity)
+	// then
+	require.Nil(t, err)
+	assert.Equal(t, []interface{}{identity.String(), identity.String()}, parameters)
+	// when nobody is logged in
+	_, _, err = qualifier{name: "assignee", value: "me"}.condition(nil)
+	// then
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,8 @@
 package space
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -20,6 +22,19 @@ var (
 	SpaceType   = "spaces"
 )
 
+// keyPrefixPattern matches the key prefixes of spaces, e.g. PLAT
+var keyPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}$`)
+
+// keyPrefixIgnored matches the characters of a space name that are left out of a derived key prefix
+var keyPrefixIgnored = regexp.MustCompile(`^[^A-Za-z]+|[^A-Za-z0-9]`)
+
+// defaultKeyPrefix is the key prefix derived from names without any letters
+const defaultKeyPrefix = "SPACE"
+
+// maxKeyPrefixAttempts is how often a key prefix is derived for a new space when concurrently
+// created spaces keep taking the derived prefix
+const maxKeyPrefixAttempts = 5
+
 // Space represents a Space on the domain and db layer
 type Space struct {
 	gormsupport.Lifecycle
@@ -28,6 +43,9 @@ type Space struct {
 	Name        string
 	Description string
 	OwnerId     uuid.UUID `sql:"type:uuid"` // Belongs To Identity
+	// KeyPrefix is the unique prefix of the keys of the work items in the space, e.g. PLAT in PLAT-42.
+	// It cannot change once the space is created.
+	KeyPrefix string
 }
 
 // Ensure Fields implements the Equaler interface
@@ -56,6 +74,9 @@ func (p Space) Equal(u convert.Equaler) bool {
 	if !uuid.Equal(p.OwnerId, other.OwnerId) {
 		return false
 	}
+	if p.KeyPrefix != other.KeyPrefix {
+		return false
+	}
 	return true
 }
 
@@ -150,6 +171,12 @@ func (r *GormRepository) Save(ctx context.Context, p *Space) (*Space, error) {
 	if err := tx.Error; err != nil {
 		return nil, errors.NewInternalError(err.Error())
 	}
+	// the keys of the existing work items depend on the key prefix
+	if p.KeyPrefix == "" {
+		p.KeyPrefix = pr.KeyPrefix
+	} else if p.KeyPrefix != pr.KeyPrefix {
+		return nil, errors.NewBadParameterError("KeyPrefix", p.KeyPrefix).Expected("unchanged " + pr.KeyPrefix)
+	}
 	tx = tx.Where("Version = ?", oldVersion).Save(p)
 	if err := tx.Error; err != nil {
 		if gormsupport.IsCheckViolation(tx.Error, "spaces_name_check") {
@@ -177,16 +204,46 @@ func (r *GormRepository) Create(ctx context.Context, space *Space) (*Space, erro
 	if space.ID == uuid.Nil {
 		space.ID = uuid.NewV4()
 	}
+	derivedKeyPrefix := space.KeyPrefix == ""
+	if !derivedKeyPrefix && !keyPrefixPattern.MatchString(space.KeyPrefix) {
+		return nil, errors.NewBadParameterError("KeyPrefix", space.KeyPrefix).Expected("up to 10 upper case letters and digits, starting with a letter")
+	}
 
-	tx := r.db.Create(space)
-	if err := tx.Error; err != nil {
-		if gormsupport.IsCheckViolation(tx.Error, "spaces_name_check") {
-			return nil, errors.NewBadParameterError("Name", space.Name).Expected("not empty")
+	for attempt := 1; ; attempt++ {
+		db := r.db
+		if derivedKeyPrefix {
+			keyPrefix, err := r.deriveKeyPrefix(space.Name)
+			if err != nil {
+				return nil, errs.WithStack(err)
+			}
+			space.KeyPrefix = keyPrefix
+			// a space created concurrently may take the derived prefix, the insert is skipped then
+			// instead of failing so that the transaction can go on with another prefix
+			db = db.Set("gorm:insert_option", "ON CONFLICT (key_prefix) DO NOTHING")
 		}
-		if gormsupport.IsUniqueViolation(tx.Error, "spaces_name_idx") {
-			return nil, errors.NewBadParameterError("Name", space.Name).Expected("unique")
+		tx := db.Create(space)
+		if err := tx.Error; err != nil {
+			if gormsupport.IsCheckViolation(tx.Error, "spaces_name_check") {
+				return nil, errors.NewBadParameterError("Name", space.Name).Expected("not empty")
+			}
+			if gormsupport.IsUniqueViolation(tx.Error, "spaces_name_idx") {
+				return nil, errors.NewBadParameterError("Name", space.Name).Expected("unique")
+			}
+			if gormsupport.IsUniqueViolation(tx.Error, "spaces_key_prefix_idx") {
+				return nil, errors.NewBadParameterError("KeyPrefix", space.KeyPrefix).Expected("unique")
+			}
+			return nil, errors.NewInternalError(err.Error())
 		}
-		return nil, errors.NewInternalError(err.Error())
+		if tx.RowsAffected > 0 {
+			break
+		}
+		if attempt == maxKeyPrefixAttempts {
+			return nil, errors.NewInternalError(fmt.Sprintf("failed to derive an unused key prefix for space %s", space.Name))
+		}
+		log.Info(ctx, map[string]interface{}{
+			"space_id":   space.ID,
+			"key_prefix": space.KeyPrefix,
+		}, "derived key prefix was taken concurrently, deriving it again")
 	}
 
 	log.Info(ctx, map[string]interface{}{
@@ -195,6 +252,34 @@ func (r *GormRepository) Create(ctx context.Context, space *Space) (*Space, erro
 	return space, nil
 }
 
+// deriveKeyPrefix returns an unused key prefix made of the first letters and digits of the given
+// space name, followed by a number if the prefix is used by another space, e.g. PLAT2 for a second
+// space named "Platform". Deleted spaces keep their prefix.
+// returns InternalError
+func (r *GormRepository) deriveKeyPrefix(name string) (string, error) {
+	base := strings.ToUpper(keyPrefixIgnored.ReplaceAllString(name, ""))
+	if len(base) > 4 {
+		base = base[:4]
+	}
+	if base == "" {
+		base = defaultKeyPrefix
+	}
+	var used []string
+	db := r.db.Unscoped().Model(&Space{}).Where("key_prefix ~ ?", "^"+base+"[0-9]*$").Pluck("key_prefix", &used)
+	if db.Error != nil {
+		return "", errors.NewInternalError(db.Error.Error())
+	}
+	taken := make(map[string]bool, len(used))
+	for _, prefix := range used {
+		taken[prefix] = true
+	}
+	keyPrefix := base
+	for n := 2; taken[keyPrefix]; n++ {
+		keyPrefix = fmt.Sprintf("%s%d", base, n)
+	}
+	return keyPrefix, nil
+}
+
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
 func (r *GormRepository) listSpaceFromDB(ctx context.Context, q *string, userId *uuid.UUID, start *int, limit *int) ([]Space, uint64, error) {
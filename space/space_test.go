@@ -1,6 +1,7 @@
 package space_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/almighty/almighty-core/errors"
@@ -46,6 +47,35 @@ func (test *repoBBTest) TestCreate() {
 	expectSpace(test.create(testSpace), test.assertBadParameter())
 }
 
+func (test *repoBBTest) TestCreateKeyPrefix() {
+	suffix := uuid.NewV4().String()
+	p1, _ := expectSpace(test.createWithKeyPrefix("Platform "+suffix, ""), test.requireOk)
+	p2, _ := expectSpace(test.createWithKeyPrefix("plat-form "+suffix, ""), test.requireOk)
+	require.Equal(test.T(), "PLAT", p1.KeyPrefix[:4])
+	assert.NotEqual(test.T(), p1.KeyPrefix, p2.KeyPrefix)
+	assert.Regexp(test.T(), "^PLAT[0-9]*$", p2.KeyPrefix)
+
+	keyPrefix := "X" + strings.ToUpper(suffix[:4])
+	p3, _ := expectSpace(test.createWithKeyPrefix("42 "+suffix, keyPrefix), test.requireOk)
+	assert.Equal(test.T(), keyPrefix, p3.KeyPrefix)
+
+	expectSpace(test.createWithKeyPrefix("Other "+suffix, p3.KeyPrefix), test.assertBadParameter())
+	expectSpace(test.createWithKeyPrefix("Lower "+suffix, "plat"), test.assertBadParameter())
+}
+
+func (test *repoBBTest) TestSaveKeyPrefix() {
+	res, _ := expectSpace(test.create(testSpace), test.requireOk)
+	keyPrefix := res.KeyPrefix
+
+	// the key prefix is kept when it is not given and cannot be changed
+	res.KeyPrefix = ""
+	res2, _ := expectSpace(test.save(*res), test.requireOk)
+	assert.Equal(test.T(), keyPrefix, res2.KeyPrefix)
+
+	res2.KeyPrefix = "CHANGED"
+	expectSpace(test.save(*res2), test.assertBadParameter())
+}
+
 func (test *repoBBTest) TestLoad() {
 	expectSpace(test.load(uuid.NewV4()), test.assertNotFound())
 	res, _ := expectSpace(test.create(testSpace), test.requireOk)
@@ -174,6 +204,15 @@ func (test *repoBBTest) create(name string) func() (*space.Space, error) {
 	return func() (*space.Space, error) { return test.repo.Create(context.Background(), &newSpace) }
 }
 
+func (test *repoBBTest) createWithKeyPrefix(name string, keyPrefix string) func() (*space.Space, error) {
+	newSpace := space.Space{
+		Name:      name,
+		OwnerId:   uuid.Nil,
+		KeyPrefix: keyPrefix,
+	}
+	return func() (*space.Space, error) { return test.repo.Create(context.Background(), &newSpace) }
+}
+
 func (test *repoBBTest) save(p space.Space) func() (*space.Space, error) {
 	return func() (*space.Space, error) { return test.repo.Save(context.Background(), &p) }
 }
@@ -7,10 +7,14 @@ import (
 	"github.com/almighty/almighty-core/auth"
 	"github.com/almighty/almighty-core/codebase"
 	"github.com/almighty/almighty-core/comment"
+	"github.com/almighty/almighty-core/filter"
 	"github.com/almighty/almighty-core/iteration"
+	"github.com/almighty/almighty-core/label"
 	"github.com/almighty/almighty-core/space"
 	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
 	"github.com/almighty/almighty-core/workitem/link"
+	"github.com/almighty/almighty-core/workitem/template"
 )
 
 func NewMockDB() *MockDB {
@@ -80,6 +84,26 @@ func (db *MockDB) Codebases() codebase.Repository {
 	return nil
 }
 
+func (db *MockDB) WorkItemRevisions() workitem.RevisionRepository {
+	return nil
+}
+
+func (db *MockDB) SavedFilters() filter.SavedFilterRepository {
+	return nil
+}
+
+func (db *MockDB) WorkItemTemplates() template.TemplateRepository {
+	return nil
+}
+
+func (db *MockDB) Attachments() attachment.Repository {
+	return nil
+}
+
+func (db *MockDB) Labels() label.Repository {
+	return nil
+}
+
 func (db *MockDB) Commit() error {
 	return nil
 }
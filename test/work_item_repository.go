@@ -3,6 +3,7 @@ package test
 
 import (
 	"sync"
+	"time"
 
 	"github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/workitem"
@@ -22,6 +23,17 @@ type WorkItemRepository struct {
 		result1 *workitem.WorkItem
 		result2 error
 	}
+	LoadByKeyStub        func(ctx context.Context, spaceID uuid.UUID, key string) (*workitem.WorkItem, error)
+	loadByKeyMutex       sync.RWMutex
+	loadByKeyArgsForCall []struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		key     string
+	}
+	loadByKeyReturns struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}
 	LoadByIDStub        func(ctx context.Context, ID string) (*workitem.WorkItem, error)
 	loadByIDMutex       sync.RWMutex
 	loadByIDArgsForCall []struct {
@@ -92,12 +104,13 @@ type WorkItemRepository struct {
 		result1 int
 		result2 error
 	}
-	ListStub        func(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, start *int, length *int) ([]workitem.WorkItem, uint64, error)
+	ListStub        func(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []workitem.SortField, start *int, length *int) ([]workitem.WorkItem, uint64, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
 		ctx      context.Context
 		spaceID  uuid.UUID
 		criteria criteria.Expression
+		sort     []workitem.SortField
 		start    *int
 		length   *int
 	}
@@ -106,6 +119,22 @@ type WorkItemRepository struct {
 		result2 uint64
 		result3 error
 	}
+	ListAfterStub        func(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []workitem.SortField, cursor *string, limit int) ([]workitem.WorkItem, uint64, *string, error)
+	listAfterMutex       sync.RWMutex
+	listAfterArgsForCall []struct {
+		ctx      context.Context
+		spaceID  uuid.UUID
+		criteria criteria.Expression
+		sort     []workitem.SortField
+		cursor   *string
+		limit    int
+	}
+	listAfterReturns struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 *string
+		result4 error
+	}
 	FetchStub        func(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*workitem.WorkItem, error)
 	fetchMutex       sync.RWMutex
 	fetchArgsForCall []struct {
@@ -137,6 +166,77 @@ type WorkItemRepository struct {
 		result1 map[string]workitem.WICountsPerIteration
 		result2 error
 	}
+	LoadRevisionStub        func(ctx context.Context, spaceID uuid.UUID, revision workitem.Revision) (*workitem.WorkItem, error)
+	loadRevisionMutex       sync.RWMutex
+	loadRevisionArgsForCall []struct {
+		ctx      context.Context
+		spaceID  uuid.UUID
+		revision workitem.Revision
+	}
+	loadRevisionReturns struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}
+	RevertStub        func(ctx context.Context, spaceID uuid.UUID, workitemID string, revisionID uuid.UUID, version int, modifierID uuid.UUID) (*workitem.WorkItem, error)
+	revertMutex       sync.RWMutex
+	revertArgsForCall []struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		workitemID string
+		revisionID uuid.UUID
+		version    int
+		modifierID uuid.UUID
+	}
+	revertReturns struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}
+	ListDeletedStub        func(ctx context.Context, spaceID uuid.UUID, start *int, limit *int) ([]workitem.DeletedWorkItem, uint64, error)
+	listDeletedMutex       sync.RWMutex
+	listDeletedArgsForCall []struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		start   *int
+		limit   *int
+	}
+	listDeletedReturns struct {
+		result1 []workitem.DeletedWorkItem
+		result2 uint64
+		result3 error
+	}
+	LoadDeletedStub        func(ctx context.Context, spaceID uuid.UUID, workitemID string) (*workitem.DeletedWorkItem, error)
+	loadDeletedMutex       sync.RWMutex
+	loadDeletedArgsForCall []struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		workitemID string
+	}
+	loadDeletedReturns struct {
+		result1 *workitem.DeletedWorkItem
+		result2 error
+	}
+	RestoreStub        func(ctx context.Context, spaceID uuid.UUID, workitemID string, restorerID uuid.UUID) (*workitem.WorkItem, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		workitemID string
+		restorerID uuid.UUID
+	}
+	restoreReturns struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}
+	PurgeDeletedStub        func(ctx context.Context, before time.Time) ([]string, error)
+	purgeDeletedMutex       sync.RWMutex
+	purgeDeletedArgsForCall []struct {
+		ctx    context.Context
+		before time.Time
+	}
+	purgeDeletedReturns struct {
+		result1 []string
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -170,6 +270,29 @@ func (fake *WorkItemRepository) LoadByID(ctx context.Context, ID string) (*worki
 	return fake.loadByIDReturns.result1, fake.loadByIDReturns.result2
 }
 
+func (fake *WorkItemRepository) LoadByKey(ctx context.Context, spaceID uuid.UUID, key string) (*workitem.WorkItem, error) {
+	fake.loadByKeyMutex.Lock()
+	fake.loadByKeyArgsForCall = append(fake.loadByKeyArgsForCall, struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		key     string
+	}{ctx, spaceID, key})
+	fake.recordInvocation("LoadByKey", []interface{}{ctx, spaceID, key})
+	fake.loadByKeyMutex.Unlock()
+	if fake.LoadByKeyStub != nil {
+		return fake.LoadByKeyStub(ctx, spaceID, key)
+	}
+	return fake.loadByKeyReturns.result1, fake.loadByKeyReturns.result2
+}
+
+func (fake *WorkItemRepository) LoadByKeyReturns(result1 *workitem.WorkItem, result2 error) {
+	fake.LoadByKeyStub = nil
+	fake.loadByKeyReturns = struct {
+		result1 *workitem.WorkItem
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *WorkItemRepository) LoadCallCount() int {
 	fake.loadMutex.RLock()
 	defer fake.loadMutex.RUnlock()
@@ -366,19 +489,20 @@ func (fake *WorkItemRepository) Count(ctx context.Context, spaceID uuid.UUID, c
 	return fake.countReturns.result1, fake.countReturns.result2
 }
 
-func (fake *WorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, c criteria.Expression, start *int, length *int) ([]workitem.WorkItem, uint64, error) {
+func (fake *WorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, c criteria.Expression, sort []workitem.SortField, start *int, length *int) ([]workitem.WorkItem, uint64, error) {
 	fake.listMutex.Lock()
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
 		ctx      context.Context
 		spaceID  uuid.UUID
 		criteria criteria.Expression
+		sort     []workitem.SortField
 		start    *int
 		length   *int
-	}{ctx, spaceID, c, start, length})
-	fake.recordInvocation("List", []interface{}{ctx, spaceID, c, start, length})
+	}{ctx, spaceID, c, sort, start, length})
+	fake.recordInvocation("List", []interface{}{ctx, spaceID, c, sort, start, length})
 	fake.listMutex.Unlock()
 	if fake.ListStub != nil {
-		return fake.ListStub(ctx, spaceID, c, start, length)
+		return fake.ListStub(ctx, spaceID, c, sort, start, length)
 	}
 	return fake.listReturns.result1, fake.listReturns.result2, fake.listReturns.result3
 }
@@ -389,10 +513,10 @@ func (fake *WorkItemRepository) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *WorkItemRepository) ListArgsForCall(i int) (context.Context, uuid.UUID, criteria.Expression, *int, *int) {
+func (fake *WorkItemRepository) ListArgsForCall(i int) (context.Context, uuid.UUID, criteria.Expression, []workitem.SortField, *int, *int) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
-	return fake.listArgsForCall[i].ctx, fake.listArgsForCall[i].spaceID, fake.listArgsForCall[i].criteria, fake.listArgsForCall[i].start, fake.listArgsForCall[i].length
+	return fake.listArgsForCall[i].ctx, fake.listArgsForCall[i].spaceID, fake.listArgsForCall[i].criteria, fake.listArgsForCall[i].sort, fake.listArgsForCall[i].start, fake.listArgsForCall[i].length
 }
 
 func (fake *WorkItemRepository) ListReturns(result1 []workitem.WorkItem, result2 uint64, result3 error) {
@@ -404,6 +528,46 @@ func (fake *WorkItemRepository) ListReturns(result1 []workitem.WorkItem, result2
 	}{result1, result2, result3}
 }
 
+func (fake *WorkItemRepository) ListAfter(ctx context.Context, spaceID uuid.UUID, c criteria.Expression, sort []workitem.SortField, cursor *string, limit int) ([]workitem.WorkItem, uint64, *string, error) {
+	fake.listAfterMutex.Lock()
+	fake.listAfterArgsForCall = append(fake.listAfterArgsForCall, struct {
+		ctx      context.Context
+		spaceID  uuid.UUID
+		criteria criteria.Expression
+		sort     []workitem.SortField
+		cursor   *string
+		limit    int
+	}{ctx, spaceID, c, sort, cursor, limit})
+	fake.recordInvocation("ListAfter", []interface{}{ctx, spaceID, c, sort, cursor, limit})
+	fake.listAfterMutex.Unlock()
+	if fake.ListAfterStub != nil {
+		return fake.ListAfterStub(ctx, spaceID, c, sort, cursor, limit)
+	}
+	return fake.listAfterReturns.result1, fake.listAfterReturns.result2, fake.listAfterReturns.result3, fake.listAfterReturns.result4
+}
+
+func (fake *WorkItemRepository) ListAfterCallCount() int {
+	fake.listAfterMutex.RLock()
+	defer fake.listAfterMutex.RUnlock()
+	return len(fake.listAfterArgsForCall)
+}
+
+func (fake *WorkItemRepository) ListAfterArgsForCall(i int) (context.Context, uuid.UUID, criteria.Expression, []workitem.SortField, *string, int) {
+	fake.listAfterMutex.RLock()
+	defer fake.listAfterMutex.RUnlock()
+	return fake.listAfterArgsForCall[i].ctx, fake.listAfterArgsForCall[i].spaceID, fake.listAfterArgsForCall[i].criteria, fake.listAfterArgsForCall[i].sort, fake.listAfterArgsForCall[i].cursor, fake.listAfterArgsForCall[i].limit
+}
+
+func (fake *WorkItemRepository) ListAfterReturns(result1 []workitem.WorkItem, result2 uint64, result3 *string, result4 error) {
+	fake.ListAfterStub = nil
+	fake.listAfterReturns = struct {
+		result1 []workitem.WorkItem
+		result2 uint64
+		result3 *string
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
 func (fake *WorkItemRepository) Fetch(ctx context.Context, spaceID uuid.UUID, c criteria.Expression) (*workitem.WorkItem, error) {
 	fake.fetchMutex.Lock()
 	fake.fetchArgsForCall = append(fake.fetchArgsForCall, struct {
@@ -507,6 +671,100 @@ func (fake *WorkItemRepository) GetCountsForIterationReturns(result1 map[string]
 	}{result1, result2}
 }
 
+func (fake *WorkItemRepository) LoadRevision(ctx context.Context, spaceID uuid.UUID, revision workitem.Revision) (*workitem.WorkItem, error) {
+	fake.loadRevisionMutex.Lock()
+	fake.loadRevisionArgsForCall = append(fake.loadRevisionArgsForCall, struct {
+		ctx      context.Context
+		spaceID  uuid.UUID
+		revision workitem.Revision
+	}{ctx, spaceID, revision})
+	fake.recordInvocation("LoadRevision", []interface{}{ctx, spaceID, revision})
+	fake.loadRevisionMutex.Unlock()
+	if fake.LoadRevisionStub != nil {
+		return fake.LoadRevisionStub(ctx, spaceID, revision)
+	}
+	return fake.loadRevisionReturns.result1, fake.loadRevisionReturns.result2
+}
+
+func (fake *WorkItemRepository) Revert(ctx context.Context, spaceID uuid.UUID, workitemID string, revisionID uuid.UUID, version int, modifierID uuid.UUID) (*workitem.WorkItem, error) {
+	fake.revertMutex.Lock()
+	fake.revertArgsForCall = append(fake.revertArgsForCall, struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		workitemID string
+		revisionID uuid.UUID
+		version    int
+		modifierID uuid.UUID
+	}{ctx, spaceID, workitemID, revisionID, version, modifierID})
+	fake.recordInvocation("Revert", []interface{}{ctx, spaceID, workitemID, revisionID, version, modifierID})
+	fake.revertMutex.Unlock()
+	if fake.RevertStub != nil {
+		return fake.RevertStub(ctx, spaceID, workitemID, revisionID, version, modifierID)
+	}
+	return fake.revertReturns.result1, fake.revertReturns.result2
+}
+
+func (fake *WorkItemRepository) ListDeleted(ctx context.Context, spaceID uuid.UUID, start *int, limit *int) ([]workitem.DeletedWorkItem, uint64, error) {
+	fake.listDeletedMutex.Lock()
+	fake.listDeletedArgsForCall = append(fake.listDeletedArgsForCall, struct {
+		ctx     context.Context
+		spaceID uuid.UUID
+		start   *int
+		limit   *int
+	}{ctx, spaceID, start, limit})
+	fake.recordInvocation("ListDeleted", []interface{}{ctx, spaceID, start, limit})
+	fake.listDeletedMutex.Unlock()
+	if fake.ListDeletedStub != nil {
+		return fake.ListDeletedStub(ctx, spaceID, start, limit)
+	}
+	return fake.listDeletedReturns.result1, fake.listDeletedReturns.result2, fake.listDeletedReturns.result3
+}
+
+func (fake *WorkItemRepository) LoadDeleted(ctx context.Context, spaceID uuid.UUID, workitemID string) (*workitem.DeletedWorkItem, error) {
+	fake.loadDeletedMutex.Lock()
+	fake.loadDeletedArgsForCall = append(fake.loadDeletedArgsForCall, struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		workitemID string
+	}{ctx, spaceID, workitemID})
+	fake.recordInvocation("LoadDeleted", []interface{}{ctx, spaceID, workitemID})
+	fake.loadDeletedMutex.Unlock()
+	if fake.LoadDeletedStub != nil {
+		return fake.LoadDeletedStub(ctx, spaceID, workitemID)
+	}
+	return fake.loadDeletedReturns.result1, fake.loadDeletedReturns.result2
+}
+
+func (fake *WorkItemRepository) Restore(ctx context.Context, spaceID uuid.UUID, workitemID string, restorerID uuid.UUID) (*workitem.WorkItem, error) {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		ctx        context.Context
+		spaceID    uuid.UUID
+		workitemID string
+		restorerID uuid.UUID
+	}{ctx, spaceID, workitemID, restorerID})
+	fake.recordInvocation("Restore", []interface{}{ctx, spaceID, workitemID, restorerID})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(ctx, spaceID, workitemID, restorerID)
+	}
+	return fake.restoreReturns.result1, fake.restoreReturns.result2
+}
+
+func (fake *WorkItemRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	fake.purgeDeletedMutex.Lock()
+	fake.purgeDeletedArgsForCall = append(fake.purgeDeletedArgsForCall, struct {
+		ctx    context.Context
+		before time.Time
+	}{ctx, before})
+	fake.recordInvocation("PurgeDeleted", []interface{}{ctx, before})
+	fake.purgeDeletedMutex.Unlock()
+	if fake.PurgeDeletedStub != nil {
+		return fake.PurgeDeletedStub(ctx, before)
+	}
+	return fake.purgeDeletedReturns.result1, fake.purgeDeletedReturns.result2
+}
+
 func (fake *WorkItemRepository) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -522,6 +780,8 @@ func (fake *WorkItemRepository) Invocations() map[string][][]interface{} {
 	defer fake.createMutex.RUnlock()
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
+	fake.listAfterMutex.RLock()
+	defer fake.listAfterMutex.RUnlock()
 	fake.fetchMutex.RLock()
 	defer fake.fetchMutex.RUnlock()
 	fake.getCountsPerIterationMutex.RLock()
@@ -0,0 +1,3 @@
+// Package trash contains the job that permanently deletes the work items which
+// have been deleted for longer than the retention period.
+package trash
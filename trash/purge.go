@@ -0,0 +1,111 @@
+package trash
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/almighty/almighty-core/application"
+	"github.com/almighty/almighty-core/log"
+	"github.com/almighty/almighty-core/workitem/attachment"
+
+	errs "github.com/pkg/errors"
+	"github.com/robfig/cron"
+	"golang.org/x/net/context"
+)
+
+// PurgeConfiguration the configuration of the Purger
+type PurgeConfiguration interface {
+	GetTrashRetention() time.Duration
+	GetTrashPurgeSchedule() string
+}
+
+// Purger permanently deletes the work items that have been in the trash for longer than the retention period
+type Purger struct {
+	db     application.DB
+	config PurgeConfiguration
+	store  attachment.BlobStore
+	cron   *cron.Cron
+}
+
+// NewPurger creates a new Purger removing the content of the attachments of the purged work items
+// from the given blob store
+func NewPurger(db application.DB, config PurgeConfiguration, store attachment.BlobStore) *Purger {
+	return &Purger{db: db, config: config, store: store, cron: cron.New()}
+}
+
+// Start schedules the purge job according to the configured schedule. Nothing is scheduled
+// if the schedule is empty.
+func (p *Purger) Start() error {
+	schedule := p.config.GetTrashPurgeSchedule()
+	if schedule == "" {
+		log.Info(nil, map[string]interface{}{}, "Trash purge job is disabled")
+		return nil
+	}
+	err := p.cron.AddFunc(schedule, func() {
+		p.Purge(context.Background())
+	})
+	if err != nil {
+		return errs.Wrapf(err, "invalid trash purge schedule '%s'", schedule)
+	}
+	p.cron.Start()
+	return nil
+}
+
+// Stop stops the purge job
+// This should be called only from main
+func (p *Purger) Stop() {
+	p.cron.Stop()
+}
+
+// Purge permanently deletes the work items that were deleted before the retention period, along with
+// their links, revisions, comments and attachments. It returns the number of purged work items.
+func (p *Purger) Purge(ctx context.Context) (int, error) {
+	before := time.Now().Add(-p.config.GetTrashRetention())
+	var purged []string
+	var attachments []string
+	err := application.Transactional(p.db, func(appl application.Application) error {
+		ids, err := appl.WorkItems().PurgeDeleted(ctx, before)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		for _, id := range ids {
+			if err := appl.Comments().Purge(ctx, id); err != nil {
+				return errs.Wrapf(err, "failed to purge the comments of work item %s", id)
+			}
+			wiID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return errs.WithStack(err)
+			}
+			attachmentIDs, err := appl.Attachments().Purge(ctx, wiID)
+			if err != nil {
+				return errs.Wrapf(err, "failed to purge the attachments of work item %s", id)
+			}
+			for _, attachmentID := range attachmentIDs {
+				attachments = append(attachments, attachmentID.String())
+			}
+		}
+		purged = ids
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"before": before,
+			"err":    err,
+		}, "unable to purge the trash")
+		return 0, errs.WithStack(err)
+	}
+	// the content is only removed once the metadata is gone for good, a failure leaves unreachable content
+	for _, key := range attachments {
+		if err := p.store.Delete(ctx, key); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"attachment_id": key,
+				"err":           err,
+			}, "unable to delete the content of a purged attachment")
+		}
+	}
+	log.Info(ctx, map[string]interface{}{
+		"before": before,
+		"purged": len(purged),
+	}, "Trash purged")
+	return len(purged), nil
+}
@@ -0,0 +1,148 @@
+package trash_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/comment"
+	"github.com/almighty/almighty-core/gormapplication"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	"github.com/almighty/almighty-core/trash"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type purgeConfiguration struct {
+	retention time.Duration
+	schedule  string
+}
+
+func (c purgeConfiguration) GetTrashRetention() time.Duration {
+	return c.retention
+}
+
+func (c purgeConfiguration) GetTrashPurgeSchedule() string {
+	return c.schedule
+}
+
+func TestStartPurger(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// the purger does not touch the database until the job runs
+	purger := trash.NewPurger(nil, purgeConfiguration{schedule: ""}, nil)
+	assert.Nil(t, purger.Start())
+	purger.Stop()
+	purger = trash.NewPurger(nil, purgeConfiguration{schedule: "@every 1h"}, nil)
+	assert.Nil(t, purger.Start())
+	purger.Stop()
+	purger = trash.NewPurger(nil, purgeConfiguration{schedule: "whenever"}, nil)
+	assert.NotNil(t, purger.Start())
+}
+
+type purgeBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	clean    func()
+	ctx      context.Context
+	identity account.Identity
+}
+
+func TestRunPurgeBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &purgeBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+// SetupSuite overrides the DBTestSuite's function but calls it before doing anything else
+// The SetupSuite method will run before the tests in the suite are run.
+// It sets up a database connection for all the tests in this suite without polluting global space.
+func (s *purgeBlackBoxTest) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	s.ctx = migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(s.ctx)
+}
+
+func (s *purgeBlackBoxTest) SetupTest() {
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	identity, err := testsupport.CreateTestIdentity(s.DB, "jdoe", "test")
+	require.Nil(s.T(), err)
+	s.identity = identity
+}
+
+func (s *purgeBlackBoxTest) TearDownTest() {
+	s.clean()
+}
+
+func (s *purgeBlackBoxTest) TestPurgeExpiredWorkItems() {
+	// given
+	workitemRepository := workitem.NewWorkItemRepository(s.DB)
+	dir, err := ioutil.TempDir("", "attachments")
+	require.Nil(s.T(), err)
+	defer os.RemoveAll(dir)
+	store, err := attachment.NewFileSystemBlobStore(dir)
+	require.Nil(s.T(), err)
+	var items []*workitem.WorkItem
+	var attachments []*attachment.Attachment
+	for _, title := range []string{"Expired", "Recently deleted"} {
+		wi, err := workitemRepository.Create(
+			s.ctx, space.SystemSpace, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.identity.ID)
+		require.Nil(s.T(), err)
+		err = comment.NewRepository(s.DB).Create(s.ctx, &comment.Comment{ParentID: wi.ID, Body: "Comment of " + title, CreatedBy: s.identity.ID}, s.identity.ID)
+		require.Nil(s.T(), err)
+		wiID, err := strconv.ParseUint(wi.ID, 10, 64)
+		require.Nil(s.T(), err)
+		a := &attachment.Attachment{ID: uuid.NewV4(), WorkItemID: wiID, Name: "log.txt", ContentType: "text/plain", CreatedBy: s.identity.ID}
+		require.Nil(s.T(), attachment.Upload(s.ctx, store, a, strings.NewReader("Log of "+title), 1024))
+		require.Nil(s.T(), attachment.NewRepository(s.DB).Create(s.ctx, a))
+		attachments = append(attachments, a)
+		err = workitemRepository.Delete(s.ctx, space.SystemSpace, wi.ID, s.identity.ID)
+		require.Nil(s.T(), err)
+		items = append(items, wi)
+	}
+	err = s.DB.Unscoped().Model(&workitem.WorkItemStorage{}).Where("id = ?", items[0].ID).UpdateColumn("deleted_at", time.Now().Add(-48*time.Hour)).Error
+	require.Nil(s.T(), err)
+	purger := trash.NewPurger(gormapplication.NewGormDB(s.DB), purgeConfiguration{retention: 24 * time.Hour}, store)
+	// when
+	purged, err := purger.Purge(s.ctx)
+	// then
+	require.Nil(s.T(), err)
+	assert.True(s.T(), purged >= 1)
+	_, err = workitemRepository.LoadDeleted(s.ctx, space.SystemSpace, items[0].ID)
+	assert.NotNil(s.T(), err)
+	var count int
+	err = s.DB.Unscoped().Model(&comment.Comment{}).Where("parent_id = ?", items[0].ID).Count(&count).Error
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, count)
+	_, err = attachment.NewRepository(s.DB).Load(s.ctx, attachments[0].WorkItemID, attachments[0].ID)
+	assert.NotNil(s.T(), err)
+	_, err = store.Get(s.ctx, attachments[0].ID.String())
+	assert.NotNil(s.T(), err)
+	_, err = workitemRepository.LoadDeleted(s.ctx, space.SystemSpace, items[1].ID)
+	assert.Nil(s.T(), err)
+	count, err = comment.NewRepository(s.DB).Count(s.ctx, items[1].ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, count)
+	_, err = attachment.NewRepository(s.DB).Load(s.ctx, attachments[1].WorkItemID, attachments[1].ID)
+	assert.Nil(s.T(), err)
+	content, err := store.Get(s.ctx, attachments[1].ID.String())
+	require.Nil(s.T(), err)
+	content.Close()
+}
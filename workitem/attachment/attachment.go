@@ -0,0 +1,30 @@
+package attachment
+
+import (
+	"github.com/almighty/almighty-core/gormsupport"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeAttachment is the JSON API type of work item attachments
+const APIStringTypeAttachment = "workitemattachments"
+
+// Attachment holds the metadata of a file attached to a work item. Its content is kept
+// in a BlobStore under the ID of the attachment.
+type Attachment struct {
+	gormsupport.Lifecycle
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	WorkItemID  uint64
+	Name        string
+	Size        int64
+	ContentType string
+	// Checksum is the hex encoded SHA-256 digest of the content
+	Checksum  string
+	CreatedBy uuid.UUID `sql:"type:uuid"` // Belongs To Identity
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (a Attachment) TableName() string {
+	return "work_item_attachments"
+}
@@ -0,0 +1,119 @@
+package attachment
+
+import (
+	"time"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// Repository encapsulates storage & retrieval of the metadata of work item attachments
+type Repository interface {
+	Create(ctx context.Context, a *Attachment) error
+	Load(ctx context.Context, workItemID uint64, id uuid.UUID) (*Attachment, error)
+	List(ctx context.Context, workItemID uint64) ([]Attachment, error)
+	Delete(ctx context.Context, workItemID uint64, id uuid.UUID) error
+	Purge(ctx context.Context, workItemID uint64) ([]uuid.UUID, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormAttachmentRepository{db: db}
+}
+
+// GormAttachmentRepository is the implementation of the storage interface for work item attachments.
+type GormAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new attachment. The ID is kept if it is set, so that the content can be
+// stored under it beforehand.
+// returns BadParameterError or InternalError
+func (r *GormAttachmentRepository) Create(ctx context.Context, a *Attachment) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "create"}, time.Now())
+	if a.Name == "" {
+		return errors.NewBadParameterError("name", a.Name).Expected("not empty")
+	}
+	if a.ID == uuid.Nil {
+		a.ID = uuid.NewV4()
+	}
+	if err := r.db.Create(a).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"attachment_id": a.ID,
+			"err":           err,
+		}, "unable to create the attachment")
+		return errors.NewInternalError(err.Error())
+	}
+	return nil
+}
+
+// Load returns the attachment of the given work item with the given ID
+// returns NotFoundError or InternalError
+func (r *GormAttachmentRepository) Load(ctx context.Context, workItemID uint64, id uuid.UUID) (*Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "get"}, time.Now())
+	var result Attachment
+	tx := r.db.Where("work_item_id = ? AND id = ?", workItemID, id).First(&result)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("attachment", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	return &result, nil
+}
+
+// List returns the attachments of the given work item, oldest first
+// returns InternalError
+func (r *GormAttachmentRepository) List(ctx context.Context, workItemID uint64) ([]Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "list"}, time.Now())
+	result := []Attachment{}
+	if err := r.db.Where("work_item_id = ?", workItemID).Order("created_at, id").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return result, nil
+}
+
+// Delete permanently deletes the attachment of the given work item with the given ID. The caller
+// is responsible for removing its content from the blob store.
+// returns NotFoundError or InternalError
+func (r *GormAttachmentRepository) Delete(ctx context.Context, workItemID uint64, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("attachment", id.String())
+	}
+	tx := r.db.Unscoped().Where("work_item_id = ?", workItemID).Delete(&Attachment{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("attachment", id.String())
+	}
+	return nil
+}
+
+// Purge permanently deletes all attachments of the given work item and returns their IDs, so that
+// the caller can remove their content from the blob store.
+// returns InternalError
+func (r *GormAttachmentRepository) Purge(ctx context.Context, workItemID uint64) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "purge"}, time.Now())
+	var ids []uuid.UUID
+	if err := r.db.Unscoped().Model(&Attachment{}).Where("work_item_id = ?", workItemID).Pluck("id", &ids).Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	if err := r.db.Unscoped().Where("id IN (?)", ids).Delete(&Attachment{}).Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"wi_id":       workItemID,
+		"attachments": len(ids),
+	}, "Attachments purged")
+	return ids, nil
+}
@@ -0,0 +1,123 @@
+package attachment_test
+
+import (
+	"strconv"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/attachment"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestAttachmentRepository struct {
+	gormtestsupport.DBTestSuite
+
+	clean      func()
+	ctx        context.Context
+	repo       attachment.Repository
+	identity   account.Identity
+	workItemID uint64
+}
+
+func TestRunAttachmentRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestAttachmentRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../../config.yaml")})
+}
+
+// SetupSuite overrides the DBTestSuite's function but calls it before doing anything else
+// so that the system work item types exist.
+func (test *TestAttachmentRepository) SetupSuite() {
+	test.DBTestSuite.SetupSuite()
+	test.ctx = migration.NewMigrationContext(context.Background())
+	test.DBTestSuite.PopulateDBTestSuite(test.ctx)
+}
+
+func (test *TestAttachmentRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+	test.repo = attachment.NewRepository(test.DB)
+	var err error
+	test.identity, err = testsupport.CreateTestIdentity(test.DB, "TestAttachmentRepository user", "test provider")
+	require.Nil(test.T(), err)
+	wi, err := workitem.NewWorkItemRepository(test.DB).Create(test.ctx, space.SystemSpace, workitem.SystemBug, map[string]interface{}{
+		workitem.SystemTitle: "Crash on startup",
+		workitem.SystemState: workitem.SystemStateNew,
+	}, test.identity.ID)
+	require.Nil(test.T(), err)
+	test.workItemID, err = strconv.ParseUint(wi.ID, 10, 64)
+	require.Nil(test.T(), err)
+}
+
+func (test *TestAttachmentRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestAttachmentRepository) create(name string) attachment.Attachment {
+	a := attachment.Attachment{
+		ID:          uuid.NewV4(),
+		WorkItemID:  test.workItemID,
+		Name:        name,
+		Size:        4,
+		ContentType: "text/plain",
+		Checksum:    "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		CreatedBy:   test.identity.ID,
+	}
+	require.Nil(test.T(), test.repo.Create(test.ctx, &a))
+	return a
+}
+
+func (test *TestAttachmentRepository) TestCreateLoadAndList() {
+	// when
+	first := test.create("first.txt")
+	second := test.create("second.txt")
+	// then the ID given before the content was stored is kept
+	loaded, err := test.repo.Load(test.ctx, test.workItemID, first.ID)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), first.ID, loaded.ID)
+	assert.Equal(test.T(), "first.txt", loaded.Name)
+	assert.Equal(test.T(), int64(4), loaded.Size)
+	_, err = test.repo.Load(test.ctx, test.workItemID+1, first.ID)
+	assert.IsType(test.T(), errors.NotFoundError{}, errs.Cause(err))
+	list, err := test.repo.List(test.ctx, test.workItemID)
+	require.Nil(test.T(), err)
+	require.Len(test.T(), list, 2)
+	assert.Equal(test.T(), first.ID, list[0].ID)
+	assert.Equal(test.T(), second.ID, list[1].ID)
+	// an attachment needs a name
+	err = test.repo.Create(test.ctx, &attachment.Attachment{WorkItemID: test.workItemID})
+	assert.IsType(test.T(), errors.BadParameterError{}, errs.Cause(err))
+}
+
+func (test *TestAttachmentRepository) TestDeleteAndPurge() {
+	// given
+	first := test.create("first.txt")
+	second := test.create("second.txt")
+	// when
+	require.Nil(test.T(), test.repo.Delete(test.ctx, test.workItemID, first.ID))
+	// then
+	_, err := test.repo.Load(test.ctx, test.workItemID, first.ID)
+	assert.IsType(test.T(), errors.NotFoundError{}, errs.Cause(err))
+	assert.IsType(test.T(), errors.NotFoundError{}, errs.Cause(test.repo.Delete(test.ctx, test.workItemID, first.ID)))
+	// when
+	purged, err := test.repo.Purge(test.ctx, test.workItemID)
+	// then
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), []uuid.UUID{second.ID}, purged)
+	list, err := test.repo.List(test.ctx, test.workItemID)
+	require.Nil(test.T(), err)
+	assert.Empty(test.T(), list)
+}
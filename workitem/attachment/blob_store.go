@@ -0,0 +1,101 @@
+package attachment
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/almighty/almighty-core/errors"
+
+	errs "github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// BlobStore stores the content of the attachments under opaque keys
+type BlobStore interface {
+	// Put stores the content read from the given reader under the given key. Nothing is stored
+	// if reading the content fails.
+	Put(ctx context.Context, key string, content io.Reader) error
+	// Get returns the content stored under the given key, the caller must close it.
+	// returns NotFoundError if nothing is stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under the given key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// FileSystemBlobStore implements BlobStore with one file per key in a directory of the local file system
+type FileSystemBlobStore struct {
+	root string
+}
+
+// NewFileSystemBlobStore creates a blob store keeping its content in the given directory, which is
+// created if it does not exist
+func NewFileSystemBlobStore(root string) (*FileSystemBlobStore, error) {
+	if err := os.MkdirAll(root, 0750); err != nil {
+		return nil, errs.Wrapf(err, "failed to create the attachments directory %s", root)
+	}
+	return &FileSystemBlobStore{root: root}, nil
+}
+
+// path returns the path of the file holding the content of the given key. Keys are used as file
+// names and must not refer to other directories.
+func (s *FileSystemBlobStore) path(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || filepath.Base(key) != key {
+		return "", errors.NewBadParameterError("key", key)
+	}
+	return filepath.Join(s.root, key), nil
+}
+
+// Put implements BlobStore. The content is written to a temporary file which is renamed once complete,
+// so that readers never see partial content.
+func (s *FileSystemBlobStore) Put(ctx context.Context, key string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	tmp, err := ioutil.TempFile(s.root, ".upload-")
+	if err != nil {
+		return errors.NewInternalError(err.Error())
+	}
+	_, err = io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errs.Wrapf(err, "failed to store the content of %s", key)
+	}
+	return nil
+}
+
+// Get implements BlobStore
+func (s *FileSystemBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, errors.NewNotFoundError("attachment content", key)
+	}
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return f, nil
+}
+
+// Delete implements BlobStore
+func (s *FileSystemBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.NewInternalError(err.Error())
+	}
+	return nil
+}
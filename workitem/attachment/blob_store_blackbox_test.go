@@ -0,0 +1,83 @@
+package attachment_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem/attachment"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBlobStore(t *testing.T) (*attachment.FileSystemBlobStore, string) {
+	dir, err := ioutil.TempDir("", "attachments")
+	require.Nil(t, err)
+	store, err := attachment.NewFileSystemBlobStore(filepath.Join(dir, "store"))
+	require.Nil(t, err)
+	return store, dir
+}
+
+func TestFileSystemBlobStore(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	store, dir := newTestBlobStore(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	require.Nil(t, store.Put(ctx, "foo", strings.NewReader("bar")))
+	content, err := store.Get(ctx, "foo")
+	require.Nil(t, err)
+	data, err := ioutil.ReadAll(content)
+	content.Close()
+	require.Nil(t, err)
+	assert.Equal(t, "bar", string(data))
+
+	require.Nil(t, store.Delete(ctx, "foo"))
+	_, err = store.Get(ctx, "foo")
+	assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	// deleting missing content is fine
+	assert.Nil(t, store.Delete(ctx, "foo"))
+
+	// keys cannot escape the directory of the store
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(store.Put(ctx, "../foo", strings.NewReader("bar"))))
+	_, err = store.Get(ctx, "..")
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+}
+
+func TestUpload(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	store, dir := newTestBlobStore(t)
+	defer os.RemoveAll(dir)
+	ctx := context.Background()
+
+	a := attachment.Attachment{ID: uuid.NewV4(), Name: "test.txt"}
+	require.Nil(t, attachment.Upload(ctx, store, &a, strings.NewReader("test"), 4))
+	assert.Equal(t, int64(4), a.Size)
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", a.Checksum)
+	content, err := store.Get(ctx, a.ID.String())
+	require.Nil(t, err)
+	content.Close()
+
+	// content exceeding the maximum size is not stored
+	tooLarge := attachment.Attachment{ID: uuid.NewV4(), Name: "test.txt"}
+	err = attachment.Upload(ctx, store, &tooLarge, strings.NewReader("tests"), 4)
+	require.NotNil(t, err)
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	assert.Contains(t, err.Error(), "at most 4 bytes")
+	_, err = store.Get(ctx, tooLarge.ID.String())
+	assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	files, err := ioutil.ReadDir(filepath.Join(dir, "store"))
+	require.Nil(t, err)
+	assert.Len(t, files, 1)
+}
@@ -0,0 +1,3 @@
+// Package attachment provides the files attached to work items. The metadata of the
+// attachments is stored in the database and their content in a pluggable blob store.
+package attachment
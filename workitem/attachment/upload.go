@@ -0,0 +1,49 @@
+package attachment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/almighty/almighty-core/errors"
+
+	errs "github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// errTooLarge aborts the storage of content exceeding the maximum size
+var errTooLarge = errs.New("content too large")
+
+// sizeLimitReader counts the bytes read from the wrapped reader and fails once they exceed the maximum
+type sizeLimitReader struct {
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n, errTooLarge
+	}
+	return n, err
+}
+
+// Upload stores the given content in the blob store under the ID of the attachment and sets the size
+// and the checksum of the attachment. Content of more than maxSize bytes is not stored.
+// returns BadParameterError or InternalError
+func Upload(ctx context.Context, store BlobStore, a *Attachment, content io.Reader, maxSize int64) error {
+	hash := sha256.New()
+	limited := &sizeLimitReader{r: io.TeeReader(content, hash), max: maxSize}
+	if err := store.Put(ctx, a.ID.String(), limited); err != nil {
+		if limited.read > maxSize {
+			return errors.NewBadParameterError("size", limited.read).Expected(fmt.Sprintf("at most %d bytes", maxSize))
+		}
+		return errs.Wrapf(err, "failed to upload attachment %s", a.Name)
+	}
+	a.Size = limited.read
+	a.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return nil
+}
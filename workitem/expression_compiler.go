@@ -1,9 +1,12 @@
 package workitem
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/almighty/almighty-core/criteria"
 	uuid "github.com/satori/go.uuid"
@@ -16,12 +19,20 @@ const (
 // Compile takes an expression and compiles it to a where clause for use with gorm.DB.Where()
 // Returns the number of expected parameters for the query and a slice of errors if something goes wrong
 func Compile(where criteria.Expression) (whereClause string, parameters []interface{}, err []error) {
+	return CompileWithFieldKinds(where, nil)
+}
+
+// CompileWithFieldKinds works like Compile but uses the given kinds of the work item fields
+// to convert values and cast JSON fields in comparisons (e.g. "<" or "in").
+// The kind of fields that are not in the map is derived from the value they are compared with.
+func CompileWithFieldKinds(where criteria.Expression, fieldKinds map[string]Kind) (whereClause string, parameters []interface{}, err []error) {
 	criteria.IteratePostOrder(where, bubbleUpJSONContext)
 
-	compiler := newExpressionCompiler()
+	compiler := newExpressionCompiler(fieldKinds)
 	compiled := where.Accept(&compiler)
+	whereClause, _ = compiled.(string)
 
-	return compiled.(string), compiler.parameters, compiler.err
+	return whereClause, compiler.parameters, compiler.err
 }
 
 // mark expression tree nodes that reference json fields
@@ -43,24 +54,41 @@ func bubbleUpJSONContext(exp criteria.Expression) bool {
 	return true
 }
 
+// columnFields maps the names of the fields that are stored in columns of the
+// work item table to the names of those columns
+var columnFields = map[string]string{
+	"ID":            "ID",
+	"Type":          "Type",
+	"Version":       "Version",
+	SystemCreatedAt: "created_at",
+	SystemUpdatedAt: "updated_at",
+	SystemOrder:     "execution_order",
+}
+
+// columnKinds holds the kinds of the column fields that need conversion in comparisons
+var columnKinds = map[string]Kind{
+	"Version":       KindInteger,
+	SystemCreatedAt: KindInstant,
+	SystemUpdatedAt: KindInstant,
+	SystemOrder:     KindFloat,
+}
+
 // does the field name reference a json field or a column?
 func isJSONField(fieldName string) bool {
-	switch fieldName {
-	case "ID", "Type", "Version":
-		return false
-	}
-	return true
+	_, isColumn := columnFields[fieldName]
+	return !isColumn
 }
 
-func newExpressionCompiler() expressionCompiler {
-	return expressionCompiler{parameters: []interface{}{}}
+func newExpressionCompiler(fieldKinds map[string]Kind) expressionCompiler {
+	return expressionCompiler{parameters: []interface{}{}, fieldKinds: fieldKinds}
 }
 
 // expressionCompiler takes an expression and compiles it to a where clause for our gorm models
 // implements criteria.ExpressionVisitor
 type expressionCompiler struct {
-	parameters []interface{} // records the number of parameter expressions encountered
-	err        []error       // record any errors found in the expression
+	parameters []interface{}   // records the number of parameter expressions encountered
+	err        []error         // record any errors found in the expression
+	fieldKinds map[string]Kind // the kinds of the JSON fields, may be nil
 }
 
 // visitor implementation
@@ -68,14 +96,14 @@ type expressionCompiler struct {
 
 func (c *expressionCompiler) Field(f *criteria.FieldExpression) interface{} {
 	if !isJSONField(f.FieldName) {
-		return f.FieldName
+		return columnFields[f.FieldName]
 	}
 	if strings.Contains(f.FieldName, "'") {
 		// beware of injection, it's a reasonable restriction for field names, make sure it's not allowed when creating wi types
 		c.err = append(c.err, fmt.Errorf("single quote not allowed in field name"))
 		return nil
 	}
-	return "Fields@>'{\"" + f.FieldName + "\""
+	return "Fields@>'{" + quoteJSONString(f.FieldName)
 }
 
 func (c *expressionCompiler) And(a *criteria.AndExpression) interface{} {
@@ -97,6 +125,9 @@ func (c *expressionCompiler) Or(a *criteria.OrExpression) interface{} {
 }
 
 func (c *expressionCompiler) Equals(e *criteria.EqualsExpression) interface{} {
+	if condition, ok := c.listElement(e); ok {
+		return condition
+	}
 	if isInJSONContext(e.Left()) {
 		return c.binary(e, ":")
 	}
@@ -104,6 +135,9 @@ func (c *expressionCompiler) Equals(e *criteria.EqualsExpression) interface{} {
 }
 
 func (c *expressionCompiler) Not(e *criteria.NotExpression) interface{} {
+	if condition, ok := c.listElement(e); ok {
+		return "NOT " + condition
+	}
 	if isInJSONContext(e.Left()) {
 		condition := c.binary(e, ":")
 		if condition != nil {
@@ -114,6 +148,29 @@ func (c *expressionCompiler) Not(e *criteria.NotExpression) interface{} {
 	return c.binary(e, "!=")
 }
 
+// listElement compiles the comparison of a list field with a single value, which holds if
+// the value is an element of the list, e.g. "system.labels = x" is compiled to a containment
+// test of ["x"]. Returns false if the comparison is not of this kind.
+func (c *expressionCompiler) listElement(e criteria.BinaryExpression) (string, bool) {
+	f, isField := e.Left().(*criteria.FieldExpression)
+	l, isLiteral := e.Right().(*criteria.LiteralExpression)
+	if !isField || !isLiteral || c.fieldKinds[f.FieldName] != KindList {
+		return "", false
+	}
+	if kind := reflect.ValueOf(l.Value).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		return "", false
+	}
+	value, err := c.convertToString(l.Value)
+	if err != nil {
+		return "", false
+	}
+	left := f.Accept(c)
+	if left == nil {
+		return "", false
+	}
+	return "(" + left.(string) + " : [" + value + "]}')", true
+}
+
 func (c *expressionCompiler) Parameter(v *criteria.ParameterExpression) interface{} {
 	c.err = append(c.err, fmt.Errorf("Parameter expression not supported"))
 	return nil
@@ -155,7 +212,7 @@ func (c *expressionCompiler) Literal(v *criteria.LiteralExpression) interface{}
 func (c *expressionCompiler) wrapStrings(value []string) string {
 	wrapped := []string{}
 	for i := 0; i < len(value); i++ {
-		wrapped = append(wrapped, "\""+value[i]+"\"")
+		wrapped = append(wrapped, quoteJSONString(value[i]))
 	}
 	return strings.Join(wrapped, ",")
 }
@@ -174,13 +231,364 @@ func (c *expressionCompiler) convertToString(value interface{}) (string, error)
 	case uint64:
 		result = strconv.FormatUint(t, 10)
 	case string:
-		result = "\"" + t + "\""
+		result = quoteJSONString(t)
 	case bool:
 		result = strconv.FormatBool(t)
 	case uuid.UUID:
-		result = t.String()
+		result = quoteJSONString(t.String())
 	default:
 		return "", fmt.Errorf("unknown value type of %v: %T", value, value)
 	}
 	return result, nil
 }
+
+// quoteJSONString returns the given string as a JSON string literal that can
+// be embedded in a single quoted SQL string, i.e. with single quotes doubled
+func quoteJSONString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return strings.Replace(string(quoted), "'", "''", -1)
+}
+
+// operandType describes how a value has to be converted in order to be compared with a field
+type operandType struct {
+	kind   Kind
+	column bool // true if the field is stored in a column instead of the JSON fields
+}
+
+func (c *expressionCompiler) LessThan(e *criteria.LessThanExpression) interface{} {
+	return c.comparison(e, "<")
+}
+
+func (c *expressionCompiler) LessThanOrEqual(e *criteria.LessThanOrEqualExpression) interface{} {
+	return c.comparison(e, "<=")
+}
+
+func (c *expressionCompiler) GreaterThan(e *criteria.GreaterThanExpression) interface{} {
+	return c.comparison(e, ">")
+}
+
+func (c *expressionCompiler) GreaterThanOrEqual(e *criteria.GreaterThanOrEqualExpression) interface{} {
+	return c.comparison(e, ">=")
+}
+
+func (c *expressionCompiler) Substring(e *criteria.SubstringExpression) interface{} {
+	right, ok := e.Right().(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("right side of substring expression must be a literal"))
+		return nil
+	}
+	value, ok := right.Value.(string)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("substring value must be a string but is %T", right.Value))
+		return nil
+	}
+	return c.match(e.Left(), "%"+escapeLikePattern(value)+"%")
+}
+
+func (c *expressionCompiler) ILike(e *criteria.ILikeExpression) interface{} {
+	right, ok := e.Right().(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("right side of ilike expression must be a literal"))
+		return nil
+	}
+	pattern, ok := right.Value.(string)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("ilike pattern must be a string but is %T", right.Value))
+		return nil
+	}
+	return c.match(e.Left(), pattern)
+}
+
+// match compiles a case-insensitive pattern match of the given term
+func (c *expressionCompiler) match(exp criteria.Expression, pattern string) interface{} {
+	var left interface{}
+	if f, ok := exp.(*criteria.FieldExpression); ok {
+		t := c.operandType(f, nil)
+		if t.column {
+			left = columnFields[f.FieldName] + "::text"
+		} else if t.kind == KindMarkup {
+			// only search the content of markup fields, not the markup type
+			left = c.jsonAccess(f.FieldName, "->") + "->>'content'"
+		} else {
+			left = c.jsonAccess(f.FieldName, "->>")
+		}
+	} else {
+		left = exp.Accept(c)
+	}
+	if left == nil {
+		return nil
+	}
+	c.parameters = append(c.parameters, pattern)
+	return "(" + left.(string) + " ILIKE ?)"
+}
+
+func (c *expressionCompiler) In(e *criteria.InExpression) interface{} {
+	right, ok := e.Right().(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, fmt.Errorf("right side of in expression must be a literal"))
+		return nil
+	}
+	values := reflect.ValueOf(right.Value)
+	if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+		c.err = append(c.err, fmt.Errorf("right side of in expression must be a list but is %T", right.Value))
+		return nil
+	}
+	if values.Len() == 0 {
+		// nothing is contained in the empty list
+		return "false"
+	}
+	var t operandType
+	var left interface{}
+	if f, ok := e.Left().(*criteria.FieldExpression); ok {
+		t = c.operandType(f, values.Index(0).Interface())
+		if !t.column && t.kind == KindList {
+			// list fields match if any of their elements is in the given values
+			placeholders := make([]string, values.Len())
+			for i := 0; i < values.Len(); i++ {
+				placeholders[i] = "?"
+				c.parameters = append(c.parameters, fmt.Sprint(values.Index(i).Interface()))
+			}
+			return "jsonb_exists_any(" + c.jsonAccess(f.FieldName, "->") + ", ARRAY[" + strings.Join(placeholders, ", ") + "]::text[])"
+		}
+		left = c.fieldValue(f, t)
+	} else {
+		left = e.Left().Accept(c)
+	}
+	if left == nil {
+		return nil
+	}
+	placeholders := make([]string, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		if !c.addParameter(values.Index(i).Interface(), t) {
+			return nil
+		}
+		placeholders[i] = "?"
+	}
+	return "(" + left.(string) + " IN (" + strings.Join(placeholders, ", ") + "))"
+}
+
+func (c *expressionCompiler) IsNull(e *criteria.IsNullExpression) interface{} {
+	if f, ok := e.Operand().(*criteria.FieldExpression); ok && isJSONField(f.FieldName) {
+		if c.checkFieldName(f.FieldName) {
+			// missing fields, JSON null values and empty lists are treated as null
+			return "(" + c.jsonAccess(f.FieldName, "->>") + " IS NULL OR " + c.jsonAccess(f.FieldName, "->") + " = '[]'::jsonb)"
+		}
+		return nil
+	}
+	operand := e.Operand().Accept(c)
+	if operand == nil {
+		return nil
+	}
+	return "(" + operand.(string) + " IS NULL)"
+}
+
+func (c *expressionCompiler) IsNotNull(e *criteria.IsNotNullExpression) interface{} {
+	if f, ok := e.Operand().(*criteria.FieldExpression); ok && isJSONField(f.FieldName) {
+		if c.checkFieldName(f.FieldName) {
+			return "(" + c.jsonAccess(f.FieldName, "->>") + " IS NOT NULL AND " + c.jsonAccess(f.FieldName, "->") + " != '[]'::jsonb)"
+		}
+		return nil
+	}
+	operand := e.Operand().Accept(c)
+	if operand == nil {
+		return nil
+	}
+	return "(" + operand.(string) + " IS NOT NULL)"
+}
+
+// comparison compiles ordering operators. Fields are cast according to their kind and
+// literals are converted to match the field they are compared with.
+func (c *expressionCompiler) comparison(e criteria.BinaryExpression, op string) interface{} {
+	var field *criteria.FieldExpression
+	var t operandType
+	if f, ok := e.Left().(*criteria.FieldExpression); ok {
+		field, t = f, c.operandType(f, literalValue(e.Right()))
+	} else if f, ok := e.Right().(*criteria.FieldExpression); ok {
+		field, t = f, c.operandType(f, literalValue(e.Left()))
+	}
+	left := c.operand(e.Left(), field, t)
+	right := c.operand(e.Right(), field, t)
+	if left == nil || right == nil {
+		return nil
+	}
+	return "(" + left.(string) + " " + op + " " + right.(string) + ")"
+}
+
+// operand compiles one side of a comparison with the given field of the given type
+func (c *expressionCompiler) operand(exp criteria.Expression, field *criteria.FieldExpression, t operandType) interface{} {
+	switch e := exp.(type) {
+	case *criteria.FieldExpression:
+		if e != field {
+			return c.fieldValue(e, c.operandType(e, nil))
+		}
+		return c.fieldValue(e, t)
+	case *criteria.LiteralExpression:
+		if !c.addParameter(e.Value, t) {
+			return nil
+		}
+		return "?"
+	}
+	return exp.Accept(c)
+}
+
+// operandType determines the type of the given field. If the kind of a JSON field is
+// unknown, it is derived from the value it is compared with.
+func (c *expressionCompiler) operandType(f *criteria.FieldExpression, value interface{}) operandType {
+	if !isJSONField(f.FieldName) {
+		kind, ok := columnKinds[f.FieldName]
+		if !ok {
+			kind = KindString
+		}
+		return operandType{kind: kind, column: true}
+	}
+	if kind, ok := c.fieldKinds[f.FieldName]; ok {
+		return operandType{kind: kind}
+	}
+	switch value.(type) {
+	case time.Time:
+		return operandType{kind: KindInstant}
+	case int, int32, int64, uint, uint32, uint64:
+		return operandType{kind: KindInteger}
+	case float32, float64:
+		return operandType{kind: KindFloat}
+	}
+	return operandType{kind: KindString}
+}
+
+// fieldValue returns the SQL term to access the value of the given field, cast to its type
+func (c *expressionCompiler) fieldValue(f *criteria.FieldExpression, t operandType) interface{} {
+	if t.column {
+		return columnFields[f.FieldName]
+	}
+	if !c.checkFieldName(f.FieldName) {
+		return nil
+	}
+	value := c.jsonAccess(f.FieldName, "->>")
+	switch t.kind {
+	case KindInteger, KindDuration, KindInstant, KindWorkitemReference:
+		// instants are stored as nanoseconds since the epoch
+		return "(" + value + ")::bigint"
+	case KindFloat:
+		return "(" + value + ")::float8"
+	}
+	return value
+}
+
+// jsonAccess returns the term to access the given JSON field with the given operator ("->" or "->>")
+func (c *expressionCompiler) jsonAccess(fieldName string, op string) string {
+	return "Fields" + op + "'" + strings.Replace(fieldName, "'", "''", -1) + "'"
+}
+
+// checkFieldName records an error if the field name cannot be used in a query
+func (c *expressionCompiler) checkFieldName(fieldName string) bool {
+	if strings.Contains(fieldName, "?") {
+		// gorm would treat the question mark as a placeholder
+		c.err = append(c.err, fmt.Errorf("question mark not allowed in field name"))
+		return false
+	}
+	return true
+}
+
+// addParameter converts the given value to the given type and adds it to the parameters
+func (c *expressionCompiler) addParameter(value interface{}, t operandType) bool {
+	converted, err := convertComparisonValue(value, t)
+	if err != nil {
+		c.err = append(c.err, err)
+		return false
+	}
+	c.parameters = append(c.parameters, converted)
+	return true
+}
+
+// literalValue returns the value of the given expression if it is a literal, nil otherwise
+func literalValue(exp criteria.Expression) interface{} {
+	if l, ok := exp.(*criteria.LiteralExpression); ok {
+		return l.Value
+	}
+	return nil
+}
+
+// instantLayouts are the accepted string representations of instants
+var instantLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// convertComparisonValue converts a literal value so it can be compared with a field of the given type
+func convertComparisonValue(value interface{}, t operandType) (interface{}, error) {
+	switch t.kind {
+	case "":
+		// not compared with a field
+		return value, nil
+	case KindInstant:
+		var instant time.Time
+		switch v := value.(type) {
+		case time.Time:
+			instant = v
+		case string:
+			var err error
+			for _, layout := range instantLayouts {
+				instant, err = time.Parse(layout, v)
+				if err == nil {
+					break
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("value %q is not a valid instant", v)
+			}
+		default:
+			return nil, fmt.Errorf("value %v should be an instant but is %T", value, value)
+		}
+		if t.column {
+			return instant, nil
+		}
+		return instant.UnixNano(), nil
+	case KindInteger, KindDuration, KindWorkitemReference:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case float64:
+			return v, nil
+		case string:
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("value %q is not a valid integer", v)
+			}
+			return i, nil
+		}
+		return nil, fmt.Errorf("value %v should be an integer but is %T", value, value)
+	case KindFloat:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("value %q is not a valid float", v)
+			}
+			return f, nil
+		}
+		return nil, fmt.Errorf("value %v should be a float but is %T", value, value)
+	}
+	if t.column {
+		return value, nil
+	}
+	// values of JSON fields are compared as text
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case uuid.UUID:
+		return v.String(), nil
+	case nil:
+		return nil, fmt.Errorf("null is not a valid comparison value, use an is null expression instead")
+	}
+	return fmt.Sprint(value), nil
+}
+
+// escapeLikePattern escapes the wildcards of LIKE patterns in the given value
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
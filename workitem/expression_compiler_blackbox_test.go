@@ -4,11 +4,13 @@ import (
 	"reflect"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	. "github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/resource"
 	. "github.com/almighty/almighty-core/workitem"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestField(t *testing.T) {
@@ -54,3 +56,88 @@ func TestArray(t *testing.T) {
 
 	assert.Equal(t, "(Fields@>'{\"system.assignees\" : [\"1\",\"2\",\"3\"]}')", where)
 }
+
+func TestQuotedStrings(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, Equals(Field("foo"), Literal(`O'Brien "jr"`)), `(Fields@>'{"foo" : "O''Brien \"jr\""}')`, []interface{}{})
+	where, _, _ := Compile(Equals(Field("system.assignees"), Literal([]string{"it's"})))
+	assert.Equal(t, `(Fields@>'{"system.assignees" : ["it''s"]}')`, where)
+}
+
+func TestComparison(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	createdAt := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+	// column fields
+	expect(t, GreaterThan(Field(SystemCreatedAt), Literal("2017-03-01")), "(created_at > ?)", []interface{}{createdAt})
+	expect(t, LessThanOrEqual(Field(SystemUpdatedAt), Literal(createdAt)), "(updated_at <= ?)", []interface{}{createdAt})
+	expect(t, GreaterThanOrEqual(Field(SystemOrder), Literal(5)), "(execution_order >= ?)", []interface{}{float64(5)})
+	expect(t, LessThan(Literal(2), Field("Version")), "(? < Version)", []interface{}{int64(2)})
+	// JSON fields with kinds derived from the literal
+	expect(t, LessThan(Field("foo"), Literal(5)), "((Fields->>'foo')::bigint < ?)", []interface{}{int64(5)})
+	expect(t, GreaterThan(Field("foo"), Literal(2.5)), "((Fields->>'foo')::float8 > ?)", []interface{}{2.5})
+	expect(t, GreaterThan(Field("foo"), Literal(createdAt)), "((Fields->>'foo')::bigint > ?)", []interface{}{createdAt.UnixNano()})
+	expect(t, LessThan(Field("foo"), Literal("abc")), "(Fields->>'foo' < ?)", []interface{}{"abc"})
+	expect(t, And(LessThan(Field("foo"), Literal(1)), Equals(Field("bar"), Literal("x"))), "(((Fields->>'foo')::bigint < ?) and (Fields@>'{\"bar\" : \"x\"}'))", []interface{}{int64(1)})
+}
+
+func TestComparisonWithFieldKinds(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	kinds := map[string]Kind{"due": KindInstant, "points": KindInteger, "effort": KindFloat, "o'dd": KindString}
+	due := time.Date(2017, 3, 1, 12, 30, 0, 0, time.UTC)
+	expectWithKinds(t, kinds, GreaterThan(Field("due"), Literal("2017-03-01T12:30:00Z")), "((Fields->>'due')::bigint > ?)", []interface{}{due.UnixNano()})
+	expectWithKinds(t, kinds, LessThan(Field("points"), Literal("8")), "((Fields->>'points')::bigint < ?)", []interface{}{int64(8)})
+	expectWithKinds(t, kinds, LessThan(Field("effort"), Literal(3)), "((Fields->>'effort')::float8 < ?)", []interface{}{float64(3)})
+	expectWithKinds(t, kinds, LessThan(Field("o'dd"), Literal(3)), "(Fields->>'o''dd' < ?)", []interface{}{"3"})
+	_, _, errs := CompileWithFieldKinds(GreaterThan(Field("due"), Literal("yesterday")), kinds)
+	assert.NotEmpty(t, errs)
+	_, _, errs = CompileWithFieldKinds(GreaterThan(Field("points"), Literal(true)), kinds)
+	assert.NotEmpty(t, errs)
+}
+
+func TestIn(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	kinds := map[string]Kind{"system.assignees": KindList, "points": KindInteger}
+	expect(t, In(Field("system.state"), Literal([]string{"new", "open"})), "(Fields->>'system.state' IN (?, ?))", []interface{}{"new", "open"})
+	expect(t, In(Field("Type"), Literal([]interface{}{"a"})), "(Type IN (?))", []interface{}{"a"})
+	expect(t, In(Field("foo"), Literal([]int{})), "false", []interface{}{})
+	expectWithKinds(t, kinds, In(Field("points"), Literal([]interface{}{1, "2"})), "((Fields->>'points')::bigint IN (?, ?))", []interface{}{int64(1), int64(2)})
+	expectWithKinds(t, kinds, In(Field("system.assignees"), Literal([]string{"a", "b"})), "jsonb_exists_any(Fields->'system.assignees', ARRAY[?, ?]::text[])", []interface{}{"a", "b"})
+	// the negation of "in" compares a list field with each value
+	expectWithKinds(t, kinds, Equals(Field("system.assignees"), Literal("a")), `(Fields@>'{"system.assignees" : ["a"]}')`, []interface{}{})
+	expectWithKinds(t, kinds, And(Not(Field("system.assignees"), Literal("a")), Not(Field("system.assignees"), Literal("b"))), `(NOT (Fields@>'{"system.assignees" : ["a"]}') and NOT (Fields@>'{"system.assignees" : ["b"]}'))`, []interface{}{})
+	_, _, errs := Compile(In(Field("foo"), Literal("a")))
+	assert.NotEmpty(t, errs)
+}
+
+func TestNull(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, IsNull(Field("system.assignees")), "(Fields->>'system.assignees' IS NULL OR Fields->'system.assignees' = '[]'::jsonb)", []interface{}{})
+	expect(t, IsNotNull(Field("system.assignees")), "(Fields->>'system.assignees' IS NOT NULL AND Fields->'system.assignees' != '[]'::jsonb)", []interface{}{})
+	expect(t, IsNull(Field(SystemCreatedAt)), "(created_at IS NULL)", []interface{}{})
+	expect(t, IsNotNull(Field("Version")), "(Version IS NOT NULL)", []interface{}{})
+	_, _, errs := Compile(IsNull(Field("what?")))
+	assert.NotEmpty(t, errs)
+}
+
+func TestSubstringAndILike(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	expect(t, Substring(Field("system.title"), Literal("50%_off")), "(Fields->>'system.title' ILIKE ?)", []interface{}{`%50\%\_off%`})
+	expectWithKinds(t, map[string]Kind{"system.description": KindMarkup}, Substring(Field("system.description"), Literal("foo")), "(Fields->'system.description'->>'content' ILIKE ?)", []interface{}{"%foo%"})
+	expect(t, ILike(Field("system.title"), Literal("foo%bar")), "(Fields->>'system.title' ILIKE ?)", []interface{}{"foo%bar"})
+	expect(t, ILike(Field("Type"), Literal("abc%")), "(Type::text ILIKE ?)", []interface{}{"abc%"})
+	_, _, errs := Compile(Substring(Field("system.title"), Literal(5)))
+	assert.NotEmpty(t, errs)
+}
+
+func expectWithKinds(t *testing.T, kinds map[string]Kind, expr Expression, expectedClause string, expectedParameters []interface{}) {
+	clause, parameters, err := CompileWithFieldKinds(expr, kinds)
+	require.Empty(t, err)
+	assert.Equal(t, expectedClause, clause)
+	assert.Equal(t, expectedParameters, parameters)
+}
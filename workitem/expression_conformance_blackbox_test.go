@@ -0,0 +1,104 @@
+package workitem_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/almighty/almighty-core/criteria"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/query"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	"github.com/almighty/almighty-core/workitem"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+// expressionConformanceTest checks that the in-memory evaluator selects the same
+// work items as the SQL compiler for the conformance cases
+type expressionConformanceTest struct {
+	gormtestsupport.DBTestSuite
+	repo      workitem.WorkItemRepository
+	clean     func()
+	ctx       context.Context
+	spaceID   uuid.UUID
+	workItems []workitem.WorkItem
+}
+
+func TestRunExpressionConformanceTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &expressionConformanceTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *expressionConformanceTest) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	s.ctx = migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(s.ctx)
+}
+
+func (s *expressionConformanceTest) SetupTest() {
+	s.repo = workitem.NewWorkItemRepository(s.DB)
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	testIdentity, err := testsupport.CreateTestIdentity(s.DB, "jdoe", "test")
+	require.Nil(s.T(), err)
+	spaceInstance := space.Space{
+		Name: "Conformance space " + uuid.NewV4().String(),
+	}
+	_, err = space.NewRepository(s.DB).Create(s.ctx, &spaceInstance)
+	require.Nil(s.T(), err)
+	s.spaceID = spaceInstance.ID
+	wit, err := workitem.NewWorkItemTypeRepository(s.DB).Create(s.ctx, space.SystemSpace, nil, nil, "conformance"+uuid.NewV4().String(), nil, "fa-bomb", conformanceFields)
+	require.Nil(s.T(), err)
+	for _, fields := range conformanceWorkItems {
+		// Create adds the creator to the fields, which are shared with the unit tests
+		copied := map[string]interface{}{}
+		for name, value := range fields {
+			copied[name] = value
+		}
+		_, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, copied, testIdentity.ID)
+		require.Nil(s.T(), err)
+	}
+	// evaluate the work items as they are loaded from the database
+	loaded, _, err := s.repo.List(s.ctx, s.spaceID, criteria.Literal(true), nil, nil, nil)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), loaded, len(conformanceWorkItems))
+	s.workItems = loaded
+}
+
+func (s *expressionConformanceTest) TearDownTest() {
+	s.clean()
+}
+
+func (s *expressionConformanceTest) TestEvaluatorAgreesWithDatabase() {
+	for _, c := range conformanceCases {
+		exp, err := query.Parse(&c.query)
+		require.Nil(s.T(), err, "failed to parse %q", c.query)
+		// when
+		listed, _, dbErr := s.repo.List(s.ctx, s.spaceID, exp, nil, nil, nil)
+		evaluated, err := evaluateConformanceCase(s.T(), c.query, s.workItems)
+		// then
+		if c.fails {
+			assert.NotNil(s.T(), dbErr, "expected database error for %q", c.query)
+			assert.NotNil(s.T(), err, "expected evaluator error for %q", c.query)
+			continue
+		}
+		require.Nil(s.T(), dbErr, "failed to list %q", c.query)
+		require.Nil(s.T(), err, "failed to evaluate %q", c.query)
+		titles := []string{}
+		for _, wi := range listed {
+			titles = append(titles, wi.Fields["title"].(string))
+		}
+		sort.Strings(titles)
+		assert.Equal(s.T(), titles, evaluated, "evaluator and database disagree on %q", c.query)
+		if c.expected != nil {
+			assert.Equal(s.T(), c.expected, titles, "wrong work items for %q", c.query)
+		}
+	}
+}
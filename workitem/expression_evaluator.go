@@ -0,0 +1,797 @@
+package workitem
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/almighty/almighty-core/criteria"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Evaluate returns true if the given work item matches the expression. It
+// follows the semantics of the where clause created by Compile, so a work item
+// is found by a query with the compiled expression exactly when Evaluate
+// returns true for it. Expressions that cannot be compiled or that would make
+// the query fail (e.g. comparing a text field with a number) return an error.
+// Unlike the database, which compares text according to its collation, strings
+// are compared byte-wise.
+func Evaluate(exp criteria.Expression, wi WorkItem) (bool, error) {
+	return EvaluateWithFieldKinds(exp, wi, nil)
+}
+
+// EvaluateWithFieldKinds works like Evaluate but uses the given kinds of the work
+// item fields like CompileWithFieldKinds does.
+func EvaluateWithFieldKinds(exp criteria.Expression, wi WorkItem, fieldKinds map[string]Kind) (bool, error) {
+	evaluator := expressionEvaluator{compiler: newExpressionCompiler(fieldKinds), workItem: wi}
+	result := exp.Accept(&evaluator)
+	if evaluator.err != nil {
+		return false, evaluator.err
+	}
+	return result == true, nil
+}
+
+// expressionEvaluator evaluates an expression against a single work item. Conditions
+// evaluate to a bool, SQL null values are represented as nil.
+// implements criteria.ExpressionVisitor
+type expressionEvaluator struct {
+	// compiler determines the types of the operands the same way as the SQL compiler
+	compiler expressionCompiler
+	workItem WorkItem
+	err      error // the first error found in the expression
+}
+
+// fail records the given error and returns nil
+func (e *expressionEvaluator) fail(format string, args ...interface{}) interface{} {
+	if e.err == nil {
+		e.err = fmt.Errorf(format, args...)
+	}
+	return nil
+}
+
+func (e *expressionEvaluator) Field(f *criteria.FieldExpression) interface{} {
+	return e.fail("field %s cannot be used as a condition", f.FieldName)
+}
+
+func (e *expressionEvaluator) And(a *criteria.AndExpression) interface{} {
+	left, right := e.condition(a.Left()), e.condition(a.Right())
+	return left && right
+}
+
+func (e *expressionEvaluator) Or(a *criteria.OrExpression) interface{} {
+	left, right := e.condition(a.Left()), e.condition(a.Right())
+	return left || right
+}
+
+// condition evaluates the given expression as a condition, null is treated as false
+func (e *expressionEvaluator) condition(exp criteria.Expression) bool {
+	switch result := exp.Accept(e).(type) {
+	case bool:
+		return result
+	case nil:
+		return false
+	default:
+		e.fail("%v is not a condition", result)
+		return false
+	}
+}
+
+func (e *expressionEvaluator) Equals(eq *criteria.EqualsExpression) interface{} {
+	if isJSONFieldExpression(eq.Left()) || isJSONFieldExpression(eq.Right()) {
+		return e.jsonContains(eq)
+	}
+	return e.equals(eq.Left(), eq.Right())
+}
+
+func (e *expressionEvaluator) Not(n *criteria.NotExpression) interface{} {
+	if isJSONFieldExpression(n.Left()) || isJSONFieldExpression(n.Right()) {
+		if result, ok := e.jsonContains(n).(bool); ok {
+			return !result
+		}
+		return nil
+	}
+	if result, ok := e.equals(n.Left(), n.Right()).(bool); ok {
+		return !result
+	}
+	return nil
+}
+
+func (e *expressionEvaluator) Parameter(v *criteria.ParameterExpression) interface{} {
+	return e.fail("Parameter expression not supported")
+}
+
+func (e *expressionEvaluator) Literal(v *criteria.LiteralExpression) interface{} {
+	if _, ok := v.Value.(bool); !ok {
+		return e.fail("literal %v is not a condition", v.Value)
+	}
+	return v.Value
+}
+
+// isJSONFieldExpression returns true if the expression accesses a field stored in the JSON fields
+func isJSONFieldExpression(exp criteria.Expression) bool {
+	f, ok := exp.(*criteria.FieldExpression)
+	return ok && isJSONField(f.FieldName)
+}
+
+// jsonContains evaluates equality with a JSON field, which is compiled to a containment
+// test: the field contains the literal if they are equal or if both are lists and every
+// element of the literal is in the field.
+func (e *expressionEvaluator) jsonContains(exp criteria.BinaryExpression) interface{} {
+	field, isField := exp.Left().(*criteria.FieldExpression)
+	literal, isLiteral := exp.Right().(*criteria.LiteralExpression)
+	if !isField || !isLiteral {
+		return e.fail("JSON fields can only be compared with literals on the right side")
+	}
+	if strings.Contains(field.FieldName, "'") {
+		return e.fail("single quote not allowed in field name")
+	}
+	value, err := jsonLiteralValue(literal.Value)
+	if err != nil {
+		return e.fail(err.Error())
+	}
+	if _, isList := value.([]interface{}); !isList && e.compiler.fieldKinds[field.FieldName] == KindList {
+		// a single value is compared with the elements of a list field
+		value = []interface{}{value}
+	}
+	stored, ok := e.jsonField(field.FieldName)
+	if !ok {
+		return false
+	}
+	return jsonContains(stored, value)
+}
+
+// jsonLiteralValue converts a literal to its JSON value like the compiler does for containment tests
+func jsonLiteralValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case int:
+		return json.Number(strconv.FormatInt(int64(v), 10)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(v, 10)), nil
+	case uint:
+		return json.Number(strconv.FormatUint(uint64(v), 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(v, 10)), nil
+	case string, bool:
+		return v, nil
+	case uuid.UUID:
+		return v.String(), nil
+	case []string:
+		result := make([]interface{}, len(v))
+		for i, s := range v {
+			result[i] = s
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unknown value type of %v: %T", value, value)
+}
+
+// jsonContains implements the containment operator "@>" of JSONB values
+func jsonContains(stored interface{}, value interface{}) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		elements, ok := stored.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, element := range v {
+			found := false
+			for _, candidate := range elements {
+				if jsonContains(candidate, element) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		members, ok := stored.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, member := range v {
+			candidate, ok := members[key]
+			if !ok || !jsonContains(candidate, member) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		n, ok := stored.(json.Number)
+		return ok && numbersEqual(n, v)
+	case string, bool, nil:
+		return stored == value
+	}
+	return false
+}
+
+// numbersEqual compares JSON numbers by value, e.g. 2 equals 2.0
+func numbersEqual(a, b json.Number) bool {
+	x, okX := new(big.Rat).SetString(a.String())
+	y, okY := new(big.Rat).SetString(b.String())
+	return okX && okY && x.Cmp(y) == 0
+}
+
+// equals compares two terms that are not JSON fields with "="
+func (e *expressionEvaluator) equals(leftExp, rightExp criteria.Expression) interface{} {
+	left, right := e.term(leftExp), e.term(rightExp)
+	if e.err != nil || left == nil || right == nil {
+		return nil
+	}
+	// gorm expands slices to lists, "a = (x)" is the same as "a = x"
+	if values := reflect.ValueOf(right); values.Kind() == reflect.Slice {
+		if values.Len() != 1 {
+			return e.fail("cannot compare %v with a list of %d values", left, values.Len())
+		}
+		right = values.Index(0).Interface()
+	}
+	if _, isColumn := leftExp.(*criteria.FieldExpression); isColumn {
+		converted, err := convertColumnValue(left, right)
+		if err != nil {
+			return e.fail(err.Error())
+		}
+		right = converted
+	}
+	result, err := compareValues(left, right)
+	if err != nil {
+		return e.fail(err.Error())
+	}
+	return result == 0
+}
+
+// term evaluates an operand that is not compared with a field of a known type
+func (e *expressionEvaluator) term(exp criteria.Expression) interface{} {
+	switch t := exp.(type) {
+	case *criteria.FieldExpression:
+		return e.fieldValue(t, e.compiler.operandType(t, nil))
+	case *criteria.LiteralExpression:
+		return t.Value
+	}
+	return exp.Accept(e)
+}
+
+func (e *expressionEvaluator) LessThan(lt *criteria.LessThanExpression) interface{} {
+	return e.comparison(lt, func(c int) bool { return c < 0 })
+}
+
+func (e *expressionEvaluator) LessThanOrEqual(le *criteria.LessThanOrEqualExpression) interface{} {
+	return e.comparison(le, func(c int) bool { return c <= 0 })
+}
+
+func (e *expressionEvaluator) GreaterThan(gt *criteria.GreaterThanExpression) interface{} {
+	return e.comparison(gt, func(c int) bool { return c > 0 })
+}
+
+func (e *expressionEvaluator) GreaterThanOrEqual(ge *criteria.GreaterThanOrEqualExpression) interface{} {
+	return e.comparison(ge, func(c int) bool { return c >= 0 })
+}
+
+// comparison evaluates ordering operators, the operands are converted like the compiler does
+func (e *expressionEvaluator) comparison(exp criteria.BinaryExpression, test func(int) bool) interface{} {
+	var field *criteria.FieldExpression
+	var t operandType
+	if f, ok := exp.Left().(*criteria.FieldExpression); ok {
+		field, t = f, e.compiler.operandType(f, literalValue(exp.Right()))
+	} else if f, ok := exp.Right().(*criteria.FieldExpression); ok {
+		field, t = f, e.compiler.operandType(f, literalValue(exp.Left()))
+	}
+	left := e.operand(exp.Left(), field, t)
+	right := e.operand(exp.Right(), field, t)
+	if e.err != nil || left == nil || right == nil {
+		return nil
+	}
+	result, err := compareValues(left, right)
+	if err != nil {
+		return e.fail(err.Error())
+	}
+	return test(result)
+}
+
+// operand evaluates one side of a comparison with the given field of the given type
+func (e *expressionEvaluator) operand(exp criteria.Expression, field *criteria.FieldExpression, t operandType) interface{} {
+	switch o := exp.(type) {
+	case *criteria.FieldExpression:
+		if o != field {
+			return e.fieldValue(o, e.compiler.operandType(o, nil))
+		}
+		return e.fieldValue(o, t)
+	case *criteria.LiteralExpression:
+		value, err := convertComparisonValue(o.Value, t)
+		if err != nil {
+			return e.fail(err.Error())
+		}
+		if t.column {
+			value, err = convertColumnValue(e.columnValue(field.FieldName), value)
+			if err != nil {
+				return e.fail(err.Error())
+			}
+		}
+		return value
+	}
+	return exp.Accept(e)
+}
+
+func (e *expressionEvaluator) Substring(s *criteria.SubstringExpression) interface{} {
+	right, ok := s.Right().(*criteria.LiteralExpression)
+	if !ok {
+		return e.fail("right side of substring expression must be a literal")
+	}
+	value, ok := right.Value.(string)
+	if !ok {
+		return e.fail("substring value must be a string but is %T", right.Value)
+	}
+	return e.match(s.Left(), "%"+escapeLikePattern(value)+"%")
+}
+
+func (e *expressionEvaluator) ILike(i *criteria.ILikeExpression) interface{} {
+	right, ok := i.Right().(*criteria.LiteralExpression)
+	if !ok {
+		return e.fail("right side of ilike expression must be a literal")
+	}
+	pattern, ok := right.Value.(string)
+	if !ok {
+		return e.fail("ilike pattern must be a string but is %T", right.Value)
+	}
+	return e.match(i.Left(), pattern)
+}
+
+// match evaluates a case-insensitive pattern match of the text of the given term
+func (e *expressionEvaluator) match(exp criteria.Expression, pattern string) interface{} {
+	var text interface{}
+	if f, ok := exp.(*criteria.FieldExpression); ok {
+		t := e.compiler.operandType(f, nil)
+		if t.column {
+			text = columnText(e.columnValue(f.FieldName))
+		} else if !e.checkFieldName(f.FieldName) {
+			return nil
+		} else if stored, ok := e.jsonField(f.FieldName); ok {
+			if t.kind == KindMarkup {
+				// only search the content of markup fields, not the markup type
+				members, _ := stored.(map[string]interface{})
+				stored = members["content"]
+			}
+			text = jsonText(stored)
+		}
+	} else {
+		text = e.term(exp)
+	}
+	if e.err != nil || text == nil {
+		return nil
+	}
+	s, ok := text.(string)
+	if !ok {
+		return e.fail("cannot match %v against a pattern", text)
+	}
+	matcher, err := likePattern(pattern)
+	if err != nil {
+		return e.fail(err.Error())
+	}
+	return matcher.MatchString(s)
+}
+
+// likePattern converts an ILIKE pattern into a regular expression
+func likePattern(pattern string) (*regexp.Regexp, error) {
+	var result bytes.Buffer
+	result.WriteString("(?is)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '%':
+			result.WriteString(".*")
+		case '_':
+			result.WriteString(".")
+		case '\\':
+			i++
+			if i == len(runes) {
+				return nil, fmt.Errorf("LIKE pattern must not end with escape character")
+			}
+			result.WriteString(regexp.QuoteMeta(string(runes[i])))
+		default:
+			result.WriteString(regexp.QuoteMeta(string(runes[i])))
+		}
+	}
+	result.WriteString("$")
+	return regexp.Compile(result.String())
+}
+
+func (e *expressionEvaluator) In(in *criteria.InExpression) interface{} {
+	right, ok := in.Right().(*criteria.LiteralExpression)
+	if !ok {
+		return e.fail("right side of in expression must be a literal")
+	}
+	values := reflect.ValueOf(right.Value)
+	if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+		return e.fail("right side of in expression must be a list but is %T", right.Value)
+	}
+	if values.Len() == 0 {
+		// nothing is contained in the empty list
+		return false
+	}
+	var left interface{}
+	var t operandType
+	f, isField := in.Left().(*criteria.FieldExpression)
+	if isField {
+		t = e.compiler.operandType(f, values.Index(0).Interface())
+		if !t.column && t.kind == KindList {
+			// list fields match if any of their elements is in the given values
+			if !e.checkFieldName(f.FieldName) {
+				return nil
+			}
+			stored, ok := e.jsonField(f.FieldName)
+			if !ok {
+				return nil
+			}
+			for i := 0; i < values.Len(); i++ {
+				if jsonExists(stored, fmt.Sprint(values.Index(i).Interface())) {
+					return true
+				}
+			}
+			return false
+		}
+		left = e.fieldValue(f, t)
+	} else {
+		left = e.term(in.Left())
+	}
+	if e.err != nil || left == nil {
+		return nil
+	}
+	for i := 0; i < values.Len(); i++ {
+		value, err := convertComparisonValue(values.Index(i).Interface(), t)
+		if err == nil && t.column {
+			value, err = convertColumnValue(left, value)
+		}
+		if err != nil {
+			return e.fail(err.Error())
+		}
+		result, err := compareValues(left, value)
+		if err != nil {
+			return e.fail(err.Error())
+		}
+		if result == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonExists implements the "?" operator of JSONB values, which tests whether a string
+// is an element of an array, the key of an object or the value of a string
+func jsonExists(stored interface{}, value string) bool {
+	switch s := stored.(type) {
+	case []interface{}:
+		for _, element := range s {
+			if element == value {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		_, ok := s[value]
+		return ok
+	case string:
+		return s == value
+	}
+	return false
+}
+
+func (e *expressionEvaluator) IsNull(n *criteria.IsNullExpression) interface{} {
+	if isNull, ok := e.isNull(n.Operand()); ok {
+		return isNull
+	}
+	return nil
+}
+
+func (e *expressionEvaluator) IsNotNull(n *criteria.IsNotNullExpression) interface{} {
+	if isNull, ok := e.isNull(n.Operand()); ok {
+		return !isNull
+	}
+	return nil
+}
+
+// isNull returns true if the given operand has no value. Missing fields, JSON null
+// values and empty lists are treated as null.
+func (e *expressionEvaluator) isNull(exp criteria.Expression) (bool, bool) {
+	if f, ok := exp.(*criteria.FieldExpression); ok && isJSONField(f.FieldName) {
+		if !e.checkFieldName(f.FieldName) {
+			return false, false
+		}
+		stored, _ := e.jsonField(f.FieldName)
+		list, isList := stored.([]interface{})
+		return stored == nil || (isList && len(list) == 0), true
+	}
+	value := e.term(exp)
+	return value == nil, e.err == nil
+}
+
+// fieldValue returns the value of the given field, converted to its type. JSON fields
+// are accessed as text and cast like in the compiled query.
+func (e *expressionEvaluator) fieldValue(f *criteria.FieldExpression, t operandType) interface{} {
+	if t.column {
+		return e.columnValue(f.FieldName)
+	}
+	if !e.checkFieldName(f.FieldName) {
+		return nil
+	}
+	stored, ok := e.jsonField(f.FieldName)
+	if !ok {
+		return nil
+	}
+	text := jsonText(stored)
+	if text == nil {
+		return nil
+	}
+	switch t.kind {
+	case KindInteger, KindDuration, KindInstant, KindWorkitemReference:
+		i, err := strconv.ParseInt(strings.TrimSpace(text.(string)), 10, 64)
+		if err != nil {
+			return e.fail("invalid input syntax for integer: %q", text)
+		}
+		return i
+	case KindFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(text.(string)), 64)
+		if err != nil {
+			return e.fail("invalid input syntax for type double precision: %q", text)
+		}
+		return f
+	}
+	return text
+}
+
+// checkFieldName records an error if the field name cannot be used in a query
+func (e *expressionEvaluator) checkFieldName(fieldName string) bool {
+	if strings.Contains(fieldName, "?") {
+		e.fail("question mark not allowed in field name")
+		return false
+	}
+	return true
+}
+
+// jsonField returns the value of the given field as it is stored in the JSON fields of the
+// work item table, i.e. as decoded JSON with json.Number for numbers. Returns false if the
+// work item has no such field.
+func (e *expressionEvaluator) jsonField(name string) (interface{}, bool) {
+	value, ok := e.workItem.Fields[name]
+	if !ok {
+		return nil, false
+	}
+	switch v := value.(type) {
+	case time.Time:
+		// instants are stored as nanoseconds since the epoch
+		value = v.UnixNano()
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		e.fail("cannot convert the value of field %s: %s", name, err.Error())
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil {
+		e.fail("cannot convert the value of field %s: %s", name, err.Error())
+		return nil, false
+	}
+	return result, true
+}
+
+// columnValue returns the value of a field that is stored in a column of the work item table
+func (e *expressionEvaluator) columnValue(name string) interface{} {
+	switch name {
+	case "ID":
+		if id, err := strconv.ParseInt(e.workItem.ID, 10, 64); err == nil {
+			return id
+		}
+		return e.workItem.ID
+	case "Type":
+		return e.workItem.Type
+	case "Version":
+		return int64(e.workItem.Version)
+	}
+	switch value := e.workItem.Fields[name].(type) {
+	case time.Time:
+		return value
+	case float64:
+		return value
+	}
+	return nil
+}
+
+// convertColumnValue converts a value to the type of the given column value, like the database
+// does with the parameters of a query
+func convertColumnValue(column interface{}, value interface{}) (interface{}, error) {
+	s, isString := value.(string)
+	switch column.(type) {
+	case int64:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int64, float64:
+			return v, nil
+		}
+		if isString {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid input syntax for integer: %v", value)
+	case float64:
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+		if isString {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid input syntax for type double precision: %v", value)
+	case uuid.UUID:
+		if id, ok := value.(uuid.UUID); ok {
+			return id, nil
+		}
+		if isString {
+			if id, err := uuid.FromString(s); err == nil {
+				return id, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid input syntax for uuid: %v", value)
+	case time.Time:
+		if instant, ok := value.(time.Time); ok {
+			return instant, nil
+		}
+		if isString {
+			for _, layout := range instantLayouts {
+				if instant, err := time.Parse(layout, s); err == nil {
+					return instant, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("invalid input syntax for type timestamp: %v", value)
+	}
+	return value, nil
+}
+
+// columnText returns the text representation of a column value
+func columnText(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04:05.999999-07")
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case uuid.UUID:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// jsonText returns the text of a JSON value like the "->>" operator: strings are
+// returned without quotes and null is returned as nil
+func jsonText(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	}
+	var result bytes.Buffer
+	writeJSONB(&result, value)
+	return result.String()
+}
+
+// writeJSONB writes a JSON value in the output format of JSONB, which orders the keys
+// of objects by length and separates elements with ", "
+func writeJSONB(w *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case []interface{}:
+		w.WriteString("[")
+		for i, element := range v {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			writeJSONB(w, element)
+		}
+		w.WriteString("]")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Sort(jsonbKeys(keys))
+		w.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			writeJSONB(w, key)
+			w.WriteString(": ")
+			writeJSONB(w, v[key])
+		}
+		w.WriteString("}")
+	default:
+		encoded, _ := json.Marshal(v)
+		w.Write(encoded)
+	}
+}
+
+// jsonbKeys sorts the keys of an object by length first, like JSONB does
+type jsonbKeys []string
+
+func (k jsonbKeys) Len() int      { return len(k) }
+func (k jsonbKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k jsonbKeys) Less(i, j int) bool {
+	if len(k[i]) != len(k[j]) {
+		return len(k[i]) < len(k[j])
+	}
+	return k[i] < k[j]
+}
+
+// compareValues returns -1, 0 or 1 if a is less than, equal to or greater than b
+func compareValues(a, b interface{}) (int, error) {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return x.Cmp(y), nil
+		}
+	}
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), nil
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			if x == y {
+				return 0, nil
+			}
+			if y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			if x.Before(y) {
+				return -1, nil
+			}
+			if x.After(y) {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case uuid.UUID:
+		if y, ok := b.(uuid.UUID); ok {
+			return strings.Compare(x.String(), y.String()), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %v (%T) with %v (%T)", a, a, b, b)
+}
+
+// toFloat converts numbers to big floats, so that large integers keep their precision
+func toFloat(value interface{}) (*big.Float, bool) {
+	switch v := value.(type) {
+	case int:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int64:
+		return new(big.Float).SetInt64(v), true
+	case uint64:
+		return new(big.Float).SetUint64(v), true
+	case float64:
+		return big.NewFloat(v), true
+	}
+	return nil, false
+}
@@ -0,0 +1,211 @@
+package workitem_test
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	. "github.com/almighty/almighty-core/criteria"
+	"github.com/almighty/almighty-core/query"
+	"github.com/almighty/almighty-core/rendering"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceFields are the fields of the work item type used by the conformance tests,
+// which check that the in-memory evaluator and the SQL compiler agree
+var conformanceFields = map[string]workitem.FieldDefinition{
+	"title":       {Type: workitem.SimpleType{Kind: workitem.KindString}},
+	"description": {Type: workitem.SimpleType{Kind: workitem.KindMarkup}},
+	"points":      {Type: workitem.SimpleType{Kind: workitem.KindInteger}},
+	"estimate":    {Type: workitem.SimpleType{Kind: workitem.KindFloat}},
+	"tags": {Type: workitem.ListType{
+		SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+		ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+	}},
+}
+
+// conformanceWorkItems holds the fields of the work items of the conformance tests by title
+var conformanceWorkItems = map[string]map[string]interface{}{
+	"Fix the crash": {
+		"title":       "Fix the crash",
+		"description": rendering.MarkupContent{Content: "crash when *saving*", Markup: rendering.SystemMarkupMarkdown},
+		"points":      3,
+		"estimate":    1.5,
+		"tags":        []interface{}{"ui", "bug"},
+	},
+	"add 100% coverage": {
+		"title":    "add 100% coverage",
+		"points":   13,
+		"estimate": 8.0,
+		"tags":     []interface{}{"backend"},
+	},
+	"Crash_report": {
+		"title": "Crash_report",
+		"tags":  []interface{}{},
+	},
+	"Zebra": {
+		"title":       "Zebra",
+		"description": rendering.MarkupContent{Content: "plain", Markup: rendering.SystemMarkupPlainText},
+		"points":      0,
+		"estimate":    -2.25,
+		"tags":        []interface{}{"ui"},
+	},
+}
+
+// conformanceCases are queries with the titles of the conformance work items they select.
+// Queries that fail in the database must fail in the evaluator as well.
+var conformanceCases = []struct {
+	query    string
+	expected []string
+	fails    bool
+}{
+	{query: `true`, expected: []string{"Crash_report", "Fix the crash", "Zebra", "add 100% coverage"}},
+	{query: `false`},
+	{query: `title = "Zebra"`, expected: []string{"Zebra"}},
+	{query: `title != "Zebra"`, expected: []string{"Crash_report", "Fix the crash", "add 100% coverage"}},
+	{query: `title IN ("Zebra", "Crash_report", "crash_report")`, expected: []string{"Crash_report", "Zebra"}},
+	{query: `points = 3`, expected: []string{"Fix the crash"}},
+	{query: `points = 3.0`, expected: []string{"Fix the crash"}},
+	{query: `estimate = 8`, expected: []string{"add 100% coverage"}},
+	{query: `points = "3"`},
+	{query: `points > 2`, expected: []string{"Fix the crash", "add 100% coverage"}},
+	{query: `points <= 3`, expected: []string{"Fix the crash", "Zebra"}},
+	{query: `NOT points > 2`, expected: []string{"Zebra"}},
+	{query: `points IN (3, 13)`, expected: []string{"Fix the crash", "add 100% coverage"}},
+	{query: `estimate < 0`, expected: []string{"Zebra"}},
+	{query: `estimate >= 1.5 AND points < 10`, expected: []string{"Fix the crash"}},
+	{query: `estimate > "x"`, fails: true},
+	{query: `tags = ["ui"]`, expected: []string{"Fix the crash", "Zebra"}},
+	{query: `tags = ["bug", "ui"]`, expected: []string{"Fix the crash"}},
+	{query: `tags = "ui"`, expected: []string{"Fix the crash", "Zebra"}},
+	{query: `tags != "ui"`, expected: []string{"Crash_report", "add 100% coverage"}},
+	{query: `tags NOT IN ("ui", "bug")`, expected: []string{"Crash_report", "add 100% coverage"}},
+	{query: `NOT tags IN ("backend", "bug")`, expected: []string{"Crash_report", "Zebra"}},
+	{query: `tags != ["ui"]`, expected: []string{"Crash_report", "add 100% coverage"}},
+	{query: `tags IN ("backend", "bug")`, expected: []string{"Fix the crash", "add 100% coverage"}},
+	{query: `tags IS NULL`, expected: []string{"Crash_report"}},
+	{query: `points IS NULL`, expected: []string{"Crash_report"}},
+	{query: `points IS NOT NULL`, expected: []string{"Fix the crash", "Zebra", "add 100% coverage"}},
+	{query: `description IS NULL`, expected: []string{"Crash_report", "add 100% coverage"}},
+	{query: `unknown IS NULL OR unknown = "x"`, expected: []string{"Crash_report", "Fix the crash", "Zebra", "add 100% coverage"}},
+	{query: `title CONTAINS "crash"`, expected: []string{"Crash_report", "Fix the crash"}},
+	{query: `title CONTAINS "_"`, expected: []string{"Crash_report"}},
+	{query: `title ILIKE "%100\\%%"`, expected: []string{"add 100% coverage"}},
+	{query: `title ILIKE "z_bra"`, expected: []string{"Zebra"}},
+	{query: `description CONTAINS "SAVING"`, expected: []string{"Fix the crash"}},
+	{query: `description CONTAINS "markdown"`},
+	{query: `Version = 0 AND title ILIKE "z%"`, expected: []string{"Zebra"}},
+	{query: `Version > 0`},
+}
+
+// conformanceKinds returns the kinds of the conformance fields like the work item repository does
+func conformanceKinds() map[string]workitem.Kind {
+	result := map[string]workitem.Kind{}
+	for name, field := range conformanceFields {
+		result[name] = field.Type.GetKind()
+	}
+	return result
+}
+
+// evaluateConformanceCase returns the sorted titles of the given work items that match the query
+func evaluateConformanceCase(t *testing.T, q string, workItems []workitem.WorkItem) ([]string, error) {
+	exp, err := query.Parse(&q)
+	require.Nil(t, err, "failed to parse %q", q)
+	result := []string{}
+	for _, wi := range workItems {
+		matches, err := workitem.EvaluateWithFieldKinds(exp, wi, conformanceKinds())
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			result = append(result, wi.Fields["title"].(string))
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func TestEvaluateConformance(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	var workItems []workitem.WorkItem
+	for _, fields := range conformanceWorkItems {
+		workItems = append(workItems, workitem.WorkItem{ID: "1", Fields: fields})
+	}
+	for _, c := range conformanceCases {
+		titles, err := evaluateConformanceCase(t, c.query, workItems)
+		if c.fails {
+			assert.NotNil(t, err, "expected error for %q", c.query)
+			continue
+		}
+		require.Nil(t, err, "failed to evaluate %q", c.query)
+		if c.expected == nil {
+			c.expected = []string{}
+		}
+		assert.Equal(t, c.expected, titles, "wrong work items for %q", c.query)
+	}
+}
+
+func TestEvaluateColumns(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	typeID := uuid.NewV4()
+	created := time.Date(2017, 3, 1, 12, 0, 0, 0, time.UTC)
+	wi := workitem.WorkItem{
+		ID:      "42",
+		Type:    typeID,
+		Version: 2,
+		Fields: map[string]interface{}{
+			workitem.SystemCreatedAt: created,
+			workitem.SystemOrder:     1000.0,
+		},
+	}
+	testData := []struct {
+		exp      Expression
+		expected bool
+	}{
+		{Equals(Field("ID"), Literal("42")), true},
+		{GreaterThan(Field("ID"), Literal(41)), true},
+		{Equals(Field("Type"), Literal([]uuid.UUID{typeID})), true},
+		{Equals(Field("Type"), Literal(uuid.NewV4().String())), false},
+		{Not(Field("Version"), Literal(2)), false},
+		{GreaterThan(Field(workitem.SystemCreatedAt), Literal("2017-02-28")), true},
+		{LessThan(Field(workitem.SystemCreatedAt), Literal(created)), false},
+		{LessThanOrEqual(Field(workitem.SystemOrder), Literal(1000)), true},
+		{In(Field("Version"), Literal([]interface{}{1, 2})), true},
+		{Substring(Field("Type"), Literal(typeID.String()[:8])), true},
+		{IsNull(Field(workitem.SystemUpdatedAt)), true},
+	}
+	for _, d := range testData {
+		matches, err := workitem.Evaluate(d.exp, wi)
+		require.Nil(t, err, "failed to evaluate %s", String(d.exp))
+		assert.Equal(t, d.expected, matches, "wrong result for %s", String(d.exp))
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	wi := workitem.WorkItem{ID: "1", Fields: map[string]interface{}{"a": "x"}}
+	for _, exp := range []Expression{
+		Parameter(),
+		Field("a"),
+		Literal("a"),
+		Equals(Field("a"), Parameter()),
+		Equals(Field("it's"), Literal("x")),
+		IsNull(Field("what?")),
+		Equals(Field("a"), Literal(map[string]interface{}{})),
+		In(Field("a"), Literal("x")),
+		ILike(Field("a"), Literal(`x\`)),
+		Substring(Field("a"), Literal(5)),
+		Equals(Field("Version"), Literal("two")),
+		And(Literal(true), Parameter()),
+	} {
+		_, err := workitem.Evaluate(exp, wi)
+		assert.NotNil(t, err, "expected error for %s", String(exp))
+	}
+}
@@ -3,11 +3,14 @@ package workitem
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"time"
 
 	"strings"
 
 	"github.com/almighty/almighty-core/convert"
+	"github.com/almighty/almighty-core/rendering"
 
 	errs "github.com/pkg/errors"
 )
@@ -27,15 +30,17 @@ const (
 	KindList              Kind = "list"
 	KindMarkup            Kind = "markup"
 	KindArea              Kind = "area"
+	KindLabel             Kind = "label"
 	KindCodebase          Kind = "codebase"
+	KindRollUp            Kind = "rollup"
 )
 
 // Kind is the kind of field type
 type Kind string
 
-// IsSimpleType returns 'true' if the kind is simple, i.e., not a list nor an enum
+// IsSimpleType returns 'true' if the kind is simple, i.e., not a list, an enum nor a roll-up
 func (k Kind) IsSimpleType() bool {
-	return k != KindEnum && k != KindList
+	return k != KindEnum && k != KindList && k != KindRollUp
 }
 
 // FieldType describes the possible values of a FieldDefinition
@@ -55,6 +60,11 @@ type FieldDefinition struct {
 	Label       string
 	Description string
 	Type        FieldType
+	// Rules are the optional constraints on the values of the field
+	Rules *FieldRules `json:",omitempty"`
+	// DefaultValue is the value of the field when a work item is created without it, in the
+	// representation of the REST API layer
+	DefaultValue interface{} `json:",omitempty"`
 }
 
 // Ensure FieldDefinition implements the Equaler interface
@@ -76,15 +86,85 @@ func (f FieldDefinition) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if (f.Rules == nil) != (other.Rules == nil) {
+		return false
+	}
+	if f.Rules != nil && !f.Rules.Equal(*other.Rules) {
+		return false
+	}
+	if !reflect.DeepEqual(f.DefaultValue, other.DefaultValue) {
+		return false
+	}
 	return f.Type.Equal(other.Type)
 }
 
-// ConvertToModel converts a field value for use in the persistence layer
+// ConvertToModel converts a field value for use in the persistence layer. A value violating the
+// rules of the field is reported with a BadParameterError.
 func (f FieldDefinition) ConvertToModel(name string, value interface{}) (interface{}, error) {
 	if f.Required && (value == nil || (f.Type.GetKind() == KindString && strings.TrimSpace(value.(string)) == "")) {
 		return nil, fmt.Errorf("Value %s is required", name)
 	}
-	return f.Type.ConvertToModel(value)
+	converted, err := f.Type.ConvertToModel(value)
+	if err != nil || f.Rules == nil {
+		return converted, err
+	}
+	if err := f.Rules.Check(name, converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
+
+// NormalizeValue converts a field value as decoded from JSON, e.g. a default value or the value of
+// a template, into the form expected by ConvertToModel: whole numbers become integers for integer
+// and duration fields, RFC3339 strings become instants and strings or maps become markup content.
+// Values that cannot be converted are returned as is and rejected by ConvertToModel.
+func (f FieldDefinition) NormalizeValue(value interface{}) interface{} {
+	return normalizeValue(f.Type, value)
+}
+
+func normalizeValue(fieldType FieldType, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch t := fieldType.(type) {
+	case ListType:
+		return normalizeList(t.ComponentType, value)
+	case *ListType:
+		return normalizeList(t.ComponentType, value)
+	case EnumType:
+		return normalizeValue(t.BaseType, value)
+	case *EnumType:
+		return normalizeValue(t.BaseType, value)
+	}
+	switch fieldType.GetKind() {
+	case KindInteger, KindDuration:
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			return int(number)
+		}
+	case KindInstant:
+		if s, ok := value.(string); ok {
+			if instant, err := time.Parse(time.RFC3339, s); err == nil {
+				return instant
+			}
+		}
+	case KindMarkup:
+		if markup := rendering.NewMarkupContentFromValue(value); markup != nil {
+			return *markup
+		}
+	}
+	return value
+}
+
+func normalizeList(componentType FieldType, value interface{}) interface{} {
+	elements, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	result := make([]interface{}, len(elements))
+	for i, element := range elements {
+		result[i] = normalizeValue(componentType, element)
+	}
+	return result
 }
 
 // ConvertFromModel converts a field value for use in the REST API layer
@@ -96,10 +176,12 @@ func (f FieldDefinition) ConvertFromModel(name string, value interface{}) (inter
 }
 
 type rawFieldDef struct {
-	Required    bool
-	Label       string
-	Description string
-	Type        *json.RawMessage
+	Required     bool
+	Label        string
+	Description  string
+	Type         *json.RawMessage
+	Rules        *FieldRules
+	DefaultValue interface{}
 }
 
 // Ensure rawFieldDef implements the Equaler interface
@@ -121,6 +203,12 @@ func (f rawFieldDef) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if !reflect.DeepEqual(f.Rules, other.Rules) {
+		return false
+	}
+	if !reflect.DeepEqual(f.DefaultValue, other.DefaultValue) {
+		return false
+	}
 	if f.Type == nil && other.Type == nil {
 		return true
 	}
@@ -152,21 +240,28 @@ func (f *FieldDefinition) UnmarshalJSON(bytes []byte) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Rules: temp.Rules, DefaultValue: temp.DefaultValue}
 	case KindEnum:
 		theType := EnumType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Rules: temp.Rules, DefaultValue: temp.DefaultValue}
+	case KindRollUp:
+		theType := RollUpType{}
+		err = json.Unmarshal(*temp.Type, &theType)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Rules: temp.Rules, DefaultValue: temp.DefaultValue}
 	default:
 		theType := SimpleType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Rules: temp.Rules, DefaultValue: temp.DefaultValue}
 	}
 	return nil
 }
@@ -182,7 +277,7 @@ func ConvertAnyToKind(any interface{}) (*Kind, error) {
 func ConvertStringToKind(k string) (*Kind, error) {
 	kind := Kind(k)
 	switch kind {
-	case KindString, KindInteger, KindFloat, KindInstant, KindDuration, KindURL, KindWorkitemReference, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindCodebase:
+	case KindString, KindInteger, KindFloat, KindInstant, KindDuration, KindURL, KindWorkitemReference, KindUser, KindEnum, KindList, KindIteration, KindMarkup, KindArea, KindLabel, KindCodebase, KindRollUp:
 		return &kind, nil
 	}
 	return nil, fmt.Errorf("kind '%s' is not a simple type", k)
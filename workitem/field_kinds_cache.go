@@ -0,0 +1,68 @@
+package workitem
+
+import (
+	"sync"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// spaceFieldKinds holds the kinds of the fields of the work item types used in a space
+type spaceFieldKinds struct {
+	typeIDs map[uuid.UUID]bool
+	kinds   map[string]Kind
+}
+
+// FieldKindsCache holds the kinds of the fields of the work item types used per space.
+// The fields of a work item type never change, so the kinds of a space only need to be
+// recomputed when a work item type is used in the space for the first time.
+type FieldKindsCache struct {
+	spaces  map[uuid.UUID]spaceFieldKinds
+	mapLock sync.RWMutex
+}
+
+// NewFieldKindsCache constructs FieldKindsCache
+func NewFieldKindsCache() *FieldKindsCache {
+	return &FieldKindsCache{spaces: map[uuid.UUID]spaceFieldKinds{}}
+}
+
+// Get returns the kinds of the fields used in the space with the given ID.
+// The second value (ok) is a bool that is true if the kinds of the space exist in the cache, and false if not.
+func (c *FieldKindsCache) Get(spaceID uuid.UUID) (map[string]Kind, bool) {
+	c.mapLock.RLock()
+	defer c.mapLock.RUnlock()
+	s, ok := c.spaces[spaceID]
+	return s.kinds, ok
+}
+
+// Put puts the kinds of the fields of the given work item types used in the space with the given ID to the cache
+func (c *FieldKindsCache) Put(spaceID uuid.UUID, typeIDs []uuid.UUID, kinds map[string]Kind) {
+	c.mapLock.Lock()
+	defer c.mapLock.Unlock()
+	s := spaceFieldKinds{typeIDs: make(map[uuid.UUID]bool, len(typeIDs)), kinds: kinds}
+	for _, typeID := range typeIDs {
+		s.typeIDs[typeID] = true
+	}
+	c.spaces[spaceID] = s
+}
+
+// Use removes the kinds of the space with the given ID from the cache if the work item type
+// with the given ID was not used in the space when they were computed
+func (c *FieldKindsCache) Use(spaceID uuid.UUID, typeID uuid.UUID) {
+	c.mapLock.RLock()
+	s, ok := c.spaces[spaceID]
+	known := ok && s.typeIDs[typeID]
+	c.mapLock.RUnlock()
+	if !ok || known {
+		return
+	}
+	c.mapLock.Lock()
+	defer c.mapLock.Unlock()
+	delete(c.spaces, spaceID)
+}
+
+// Clear clears the cache
+func (c *FieldKindsCache) Clear() {
+	c.mapLock.Lock()
+	defer c.mapLock.Unlock()
+	c.spaces = map[uuid.UUID]spaceFieldKinds{}
+}
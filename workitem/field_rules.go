@@ -0,0 +1,180 @@
+package workitem
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/almighty/almighty-core/convert"
+	"github.com/almighty/almighty-core/errors"
+)
+
+// FieldRules are the declarative constraints on the values of a field, in addition to its type. Each
+// rule only applies to some kinds of fields and missing rules do not constrain the value.
+type FieldRules struct {
+	// Min and Max are the inclusive bounds of integer and float values
+	Min *float64 `json:",omitempty"`
+	Max *float64 `json:",omitempty"`
+	// MaxLength is the maximum number of characters of string values
+	MaxLength *int `json:",omitempty"`
+	// Pattern is a regular expression that string values must match
+	Pattern *string `json:",omitempty"`
+	// URLSchemes are the allowed schemes of URL values, e.g. "https"
+	URLSchemes []string `json:",omitempty"`
+	// NotBefore and NotAfter are the inclusive bounds of instant values
+	NotBefore *time.Time `json:",omitempty"`
+	NotAfter  *time.Time `json:",omitempty"`
+	// MinItems and MaxItems are the inclusive bounds of the number of elements of list values
+	MinItems *int `json:",omitempty"`
+	MaxItems *int `json:",omitempty"`
+}
+
+// Ensure FieldRules implements the Equaler interface
+var _ convert.Equaler = FieldRules{}
+var _ convert.Equaler = (*FieldRules)(nil)
+
+// Equal returns true if two FieldRules objects are equal; otherwise false is returned.
+func (r FieldRules) Equal(u convert.Equaler) bool {
+	other, ok := u.(FieldRules)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(r, other)
+}
+
+// Validate checks that the rules apply to the kind of the given field type and that they are consistent
+// returns BadParameterError
+func (r FieldRules) Validate(fieldType FieldType) error {
+	kind := fieldType.GetKind()
+	applies := func(set bool, rule string, kinds ...Kind) error {
+		if !set {
+			return nil
+		}
+		for _, k := range kinds {
+			if k == kind {
+				return nil
+			}
+		}
+		names := make([]string, len(kinds))
+		for i, k := range kinds {
+			names[i] = string(k)
+		}
+		return errors.NewBadParameterError("rules."+rule, kind).Expected("a field of kind " + strings.Join(names, " or "))
+	}
+	for _, err := range []error{
+		applies(r.Min != nil, "min", KindInteger, KindFloat),
+		applies(r.Max != nil, "max", KindInteger, KindFloat),
+		applies(r.MaxLength != nil, "maxLength", KindString),
+		applies(r.Pattern != nil, "pattern", KindString),
+		applies(len(r.URLSchemes) > 0, "urlSchemes", KindURL),
+		applies(r.NotBefore != nil, "notBefore", KindInstant),
+		applies(r.NotAfter != nil, "notAfter", KindInstant),
+		applies(r.MinItems != nil, "minItems", KindList),
+		applies(r.MaxItems != nil, "maxItems", KindList),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return errors.NewBadParameterError("rules.max", *r.Max).Expected(fmt.Sprintf("at least the minimum %v", *r.Min))
+	}
+	if r.MaxLength != nil && *r.MaxLength < 0 {
+		return errors.NewBadParameterError("rules.maxLength", *r.MaxLength).Expected("a positive length")
+	}
+	if r.Pattern != nil {
+		if _, err := regexp.Compile(*r.Pattern); err != nil {
+			return errors.NewBadParameterError("rules.pattern", *r.Pattern).Expected("a valid regular expression")
+		}
+	}
+	if r.NotBefore != nil && r.NotAfter != nil && r.NotBefore.After(*r.NotAfter) {
+		return errors.NewBadParameterError("rules.notAfter", *r.NotAfter).Expected(fmt.Sprintf("at least %v", *r.NotBefore))
+	}
+	if r.MinItems != nil && *r.MinItems < 0 {
+		return errors.NewBadParameterError("rules.minItems", *r.MinItems).Expected("a positive count")
+	}
+	if r.MinItems != nil && r.MaxItems != nil && *r.MinItems > *r.MaxItems {
+		return errors.NewBadParameterError("rules.maxItems", *r.MaxItems).Expected(fmt.Sprintf("at least the minimum %d", *r.MinItems))
+	}
+	return nil
+}
+
+// Check verifies that the given value of the named field, as converted for the persistence layer, satisfies
+// the rules. Missing values are not checked, they are handled by the Required flag of the field.
+// returns BadParameterError
+func (r FieldRules) Check(name string, value interface{}) error {
+	switch v := value.(type) {
+	case int:
+		return r.checkRange(name, v, float64(v))
+	case float64:
+		return r.checkRange(name, v, v)
+	case int64:
+		return r.checkInstant(name, time.Unix(0, v).UTC())
+	case string:
+		return r.checkString(name, v)
+	case []interface{}:
+		if v == nil {
+			// list types convert a missing value to a nil slice
+			return nil
+		}
+		if r.MinItems != nil && len(v) < *r.MinItems {
+			return errors.NewBadParameterError(name, v).Expected(fmt.Sprintf("at least %d items", *r.MinItems))
+		}
+		if r.MaxItems != nil && len(v) > *r.MaxItems {
+			return errors.NewBadParameterError(name, v).Expected(fmt.Sprintf("at most %d items", *r.MaxItems))
+		}
+	}
+	return nil
+}
+
+func (r FieldRules) checkRange(name string, value interface{}, number float64) error {
+	if r.Min != nil && number < *r.Min {
+		return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at least %v", *r.Min))
+	}
+	if r.Max != nil && number > *r.Max {
+		return errors.NewBadParameterError(name, value).Expected(fmt.Sprintf("at most %v", *r.Max))
+	}
+	return nil
+}
+
+func (r FieldRules) checkInstant(name string, t time.Time) error {
+	if r.NotBefore != nil && t.Before(*r.NotBefore) {
+		return errors.NewBadParameterError(name, t).Expected(fmt.Sprintf("not before %v", r.NotBefore.UTC()))
+	}
+	if r.NotAfter != nil && t.After(*r.NotAfter) {
+		return errors.NewBadParameterError(name, t).Expected(fmt.Sprintf("not after %v", r.NotAfter.UTC()))
+	}
+	return nil
+}
+
+func (r FieldRules) checkString(name string, s string) error {
+	if r.MaxLength != nil && utf8.RuneCountInString(s) > *r.MaxLength {
+		return errors.NewBadParameterError(name, s).Expected(fmt.Sprintf("at most %d characters", *r.MaxLength))
+	}
+	if r.Pattern != nil {
+		pattern, err := regexp.Compile(*r.Pattern)
+		if err != nil {
+			return errors.NewBadParameterError(name, s).Expected("a valid pattern in the field definition")
+		}
+		if !pattern.MatchString(s) {
+			return errors.NewBadParameterError(name, s).Expected(fmt.Sprintf("a value matching %s", *r.Pattern))
+		}
+	}
+	if len(r.URLSchemes) > 0 {
+		u, err := url.Parse(s)
+		if err != nil {
+			return errors.NewBadParameterError(name, s).Expected("a URL")
+		}
+		for _, scheme := range r.URLSchemes {
+			if strings.EqualFold(scheme, u.Scheme) {
+				return nil
+			}
+		}
+		return errors.NewBadParameterError(name, s).Expected("a URL with the scheme " + strings.Join(r.URLSchemes, " or "))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package workitem_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/rendering"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem"
+
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldRulesValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	min, max := 1.0, 10.0
+	length, count := 5, 2
+	pattern, invalidPattern := "^[a-z]+$", "(["
+	stInteger := workitem.SimpleType{Kind: workitem.KindInteger}
+	stString := workitem.SimpleType{Kind: workitem.KindString}
+	list := workitem.ListType{SimpleType: workitem.SimpleType{Kind: workitem.KindList}, ComponentType: stString}
+
+	assert.Nil(t, workitem.FieldRules{Min: &min, Max: &max}.Validate(stInteger))
+	assert.Nil(t, workitem.FieldRules{MaxLength: &length, Pattern: &pattern}.Validate(stString))
+	assert.Nil(t, workitem.FieldRules{MinItems: &count}.Validate(list))
+	assert.Nil(t, workitem.FieldRules{URLSchemes: []string{"https"}}.Validate(workitem.SimpleType{Kind: workitem.KindURL}))
+
+	// rules that do not apply to the kind of the field
+	assert.IsType(t, errors.BadParameterError{}, workitem.FieldRules{MaxLength: &length}.Validate(stInteger))
+	assert.IsType(t, errors.BadParameterError{}, workitem.FieldRules{Min: &min}.Validate(stString))
+	assert.IsType(t, errors.BadParameterError{}, workitem.FieldRules{MinItems: &count}.Validate(stString))
+	// inconsistent rules
+	assert.IsType(t, errors.BadParameterError{}, workitem.FieldRules{Min: &max, Max: &min}.Validate(stInteger))
+	assert.IsType(t, errors.BadParameterError{}, workitem.FieldRules{Pattern: &invalidPattern}.Validate(stString))
+}
+
+func TestFieldRulesCheck(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	min, max := 1.0, 10.0
+	length, minItems, maxItems := 5, 1, 2
+	pattern := "^[a-z]+$"
+	notBefore := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2017, 12, 31, 0, 0, 0, 0, time.UTC)
+	rules := workitem.FieldRules{
+		Min:       &min,
+		Max:       &max,
+		MaxLength: &length,
+		Pattern:   &pattern,
+		NotBefore: &notBefore,
+		NotAfter:  &notAfter,
+		MinItems:  &minItems,
+		MaxItems:  &maxItems,
+	}
+	// valid values and missing values
+	assert.Nil(t, rules.Check("foo", 5))
+	assert.Nil(t, rules.Check("foo", 10.0))
+	assert.Nil(t, rules.Check("foo", "abc"))
+	assert.Nil(t, rules.Check("foo", time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC).UnixNano()))
+	assert.Nil(t, rules.Check("foo", []interface{}{"a"}))
+	assert.Nil(t, rules.Check("foo", nil))
+	assert.Nil(t, rules.Check("foo", []interface{}(nil)))
+
+	// invalid values name the field and the violated rule
+	err := rules.Check("foo", 0)
+	require.NotNil(t, err)
+	assert.IsType(t, errors.BadParameterError{}, err)
+	assert.Contains(t, err.Error(), "'foo'")
+	assert.Contains(t, err.Error(), "at least 1")
+	assert.Contains(t, rules.Check("foo", 10.5).Error(), "at most 10")
+	assert.Contains(t, rules.Check("foo", "abcdef").Error(), "at most 5 characters")
+	assert.Contains(t, rules.Check("foo", "ABC").Error(), "matching ^[a-z]+$")
+	assert.Contains(t, rules.Check("foo", time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC).UnixNano()).Error(), "not before")
+	assert.Contains(t, rules.Check("foo", time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC).UnixNano()).Error(), "not after")
+	assert.Contains(t, rules.Check("foo", []interface{}{}).Error(), "at least 1 items")
+	assert.Contains(t, rules.Check("foo", []interface{}{"a", "b", "c"}).Error(), "at most 2 items")
+
+	urlRules := workitem.FieldRules{URLSchemes: []string{"https"}}
+	assert.Nil(t, urlRules.Check("foo", "HTTPS://example.com"))
+	assert.Contains(t, urlRules.Check("foo", "ftp://example.com").Error(), "scheme https")
+}
+
+func TestFieldDefinitionConvertToModelWithRules(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	length := 3
+	def := workitem.FieldDefinition{
+		Label: "Code",
+		Type:  workitem.SimpleType{Kind: workitem.KindString},
+		Rules: &workitem.FieldRules{MaxLength: &length},
+	}
+	converted, err := def.ConvertToModel("code", "abc")
+	require.Nil(t, err)
+	assert.Equal(t, "abc", converted)
+	_, err = def.ConvertToModel("code", "abcd")
+	require.NotNil(t, err)
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+
+	// a missing value of an optional list field is not checked against the minimum number of items
+	minItems := 1
+	listDef := workitem.FieldDefinition{
+		Label: "Labels",
+		Type: workitem.ListType{
+			SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+			ComponentType: workitem.SimpleType{Kind: workitem.KindString},
+		},
+		Rules: &workitem.FieldRules{MinItems: &minItems},
+	}
+	_, err = listDef.ConvertToModel("labels", nil)
+	assert.Nil(t, err)
+	_, err = listDef.ConvertToModel("labels", []interface{}{})
+	require.NotNil(t, err)
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+
+	// the rules are part of the stored field definition
+	bytes, err := json.Marshal(def)
+	require.Nil(t, err)
+	unmarshalled := workitem.FieldDefinition{}
+	require.Nil(t, json.Unmarshal(bytes, &unmarshalled))
+	assert.Equal(t, def, unmarshalled)
+	assert.True(t, def.Equal(unmarshalled))
+	assert.False(t, def.Equal(workitem.FieldDefinition{Label: "Code", Type: def.Type}))
+}
+
+func TestFieldDefinitionNormalizeValue(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	integer := workitem.FieldDefinition{Type: workitem.SimpleType{Kind: workitem.KindInteger}}
+	assert.Equal(t, 3, integer.NormalizeValue(3.0))
+	assert.Equal(t, 3.5, integer.NormalizeValue(3.5))
+	assert.Nil(t, integer.NormalizeValue(nil))
+
+	instant := workitem.FieldDefinition{Type: workitem.SimpleType{Kind: workitem.KindInstant}}
+	assert.Equal(t, time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC), instant.NormalizeValue("2017-06-01T00:00:00Z"))
+
+	markup := workitem.FieldDefinition{Type: workitem.SimpleType{Kind: workitem.KindMarkup}}
+	assert.Equal(t, rendering.NewMarkupContent("# Steps", rendering.SystemMarkupMarkdown), markup.NormalizeValue(map[string]interface{}{
+		"content": "# Steps",
+		"markup":  rendering.SystemMarkupMarkdown,
+	}))
+
+	list := workitem.FieldDefinition{Type: workitem.ListType{
+		SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+		ComponentType: workitem.SimpleType{Kind: workitem.KindInteger},
+	}}
+	assert.Equal(t, []interface{}{1, 2}, list.NormalizeValue([]interface{}{1.0, 2.0}))
+
+	// the default value is part of the stored field definition
+	def := workitem.FieldDefinition{Label: "Effort", Type: integer.Type, DefaultValue: 3.0}
+	bytes, err := json.Marshal(def)
+	require.Nil(t, err)
+	unmarshalled := workitem.FieldDefinition{}
+	require.Nil(t, json.Unmarshal(bytes, &unmarshalled))
+	assert.True(t, def.Equal(unmarshalled))
+	assert.False(t, def.Equal(workitem.FieldDefinition{Label: "Effort", Type: integer.Type}))
+}
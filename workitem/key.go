@@ -0,0 +1,19 @@
+package workitem
+
+import (
+	"regexp"
+	"strings"
+)
+
+// keyPattern matches the human-readable key of a work item: the key prefix of its space, followed by
+// the number of the work item in the space, e.g. "PLAT-42"
+var keyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]{0,9}-[1-9][0-9]*$`)
+
+// ParseKey returns the normalized, upper-case form of the given work item key and true if the string
+// is a work item key; otherwise false is returned.
+func ParseKey(s string) (string, bool) {
+	if !keyPattern.MatchString(s) {
+		return "", false
+	}
+	return strings.ToUpper(s), true
+}
@@ -0,0 +1,27 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	key, ok := workitem.ParseKey("PLAT-42")
+	assert.True(t, ok)
+	assert.Equal(t, "PLAT-42", key)
+	key, ok = workitem.ParseKey("plat2-7")
+	assert.True(t, ok)
+	assert.Equal(t, "PLAT2-7", key)
+
+	for _, s := range []string{"42", "PLAT", "PLAT-", "PLAT-0", "PLAT-042", "2PLAT-4", "PLAT-4a", "TOOLONGPREFIX-1", "id:42"} {
+		_, ok := workitem.ParseKey(s)
+		assert.False(t, ok, s)
+	}
+}
@@ -0,0 +1,56 @@
+package link
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// In a link type with the dependency topology the source of a link blocks the
+// target of the link: the target cannot be done before the source is resolved
+// or closed. Work items in any other state are open.
+
+// BlockedWorkItem is an open work item whose dependencies are not all closed.
+type BlockedWorkItem struct {
+	WorkItemID string
+	// BlockerIDs holds the IDs of the open work items blocking the work item
+	BlockerIDs []string
+}
+
+// MisplannedDependency is a direct or transitive dependency between two open
+// work items where the blocking work item is planned in an iteration that
+// starts after the iteration of the work item it blocks.
+type MisplannedDependency struct {
+	// LinkID is the link from the blocking work item on the shortest chain of
+	// dependencies to the blocked work item
+	LinkID             uuid.UUID
+	BlockerID          string
+	BlockerIterationID uuid.UUID
+	BlockedID          string
+	BlockedIterationID uuid.UUID
+}
+
+// openDependency is a link of a dependency link type between two open work items
+type openDependency struct {
+	linkID    uuid.UUID
+	blockerID uint64
+	blockedID uint64
+}
+
+// plannedIteration is the iteration an open work item is planned in
+type plannedIteration struct {
+	id      uuid.UUID
+	startAt *time.Time
+}
+
+// startsAfter returns true if the iteration starts after the given other iteration
+func (i plannedIteration) startsAfter(other plannedIteration) bool {
+	return !uuid.Equal(i.id, other.id) && i.startAt != nil && other.startAt != nil && i.startAt.After(*other.startAt)
+}
+
+// workItemIDs sorts work item IDs in ascending order
+type workItemIDs []uint64
+
+func (ids workItemIDs) Len() int           { return len(ids) }
+func (ids workItemIDs) Less(i, j int) bool { return ids[i] < ids[j] }
+func (ids workItemIDs) Swap(i, j int)      { ids[i], ids[j] = ids[j], ids[i] }
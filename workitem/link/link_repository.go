@@ -2,14 +2,18 @@ package link
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/errors"
 	"github.com/almighty/almighty-core/gormsupport"
+	"github.com/almighty/almighty-core/iteration"
 	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/workitem"
 	"github.com/goadesign/goa"
@@ -34,9 +38,14 @@ type WorkItemLinkRepository interface {
 	List(ctx context.Context) ([]WorkItemLink, error)
 	ListByWorkItemID(ctx context.Context, wiIDStr string) ([]WorkItemLink, error)
 	DeleteRelatedLinks(ctx context.Context, wiIDStr string, suppressorID uuid.UUID) error
+	RestoreRelatedLinks(ctx context.Context, wiIDStr string, since time.Time, restorerID uuid.UUID) error
 	Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID) error
 	Save(ctx context.Context, linkCat WorkItemLink, modifierID uuid.UUID) (*WorkItemLink, error)
 	ListWorkItemChildren(ctx context.Context, parent string) ([]workitem.WorkItem, error)
+	Traverse(ctx context.Context, spaceID uuid.UUID, wiIDStr string, linkTypeID uuid.UUID, direction TraversalDirection, maxDepth *int, filter criteria.Expression) ([]TraversedWorkItem, error)
+	ListBlocked(ctx context.Context, spaceID uuid.UUID) ([]BlockedWorkItem, error)
+	ListMisplannedDependencies(ctx context.Context, spaceID uuid.UUID) ([]MisplannedDependency, error)
+	CriticalPath(ctx context.Context, spaceID uuid.UUID, rootIDStr string) ([]string, error)
 }
 
 // NewWorkItemLinkRepository creates a work item link repository based on gorm
@@ -97,7 +106,7 @@ func (r *GormWorkItemLinkRepository) ValidateCorrectSourceAndTargetType(ctx cont
 }
 
 // Create creates a new work item link in the repository.
-// Returns BadParameterError, ConversionError or InternalError
+// Returns BadParameterError, DataConflictError, ConversionError or InternalError
 func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targetID uint64, linkTypeID uuid.UUID, creatorID uuid.UUID) (*WorkItemLink, error) {
 	link := &WorkItemLink{
 		SourceID:   sourceID,
@@ -110,6 +119,9 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err := r.ValidateCorrectSourceAndTargetType(ctx, sourceID, targetID, linkTypeID); err != nil {
 		return nil, errs.WithStack(err)
 	}
+	if err := r.CheckTopology(ctx, *link); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	db := r.db.Create(link)
 	if db.Error != nil {
 		if gormsupport.IsUniqueViolation(db.Error, "work_item_links_unique_idx") {
@@ -122,9 +134,65 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err := r.revisionRepo.Create(ctx, creatorID, RevisionTypeCreate, *link); err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	// the source may aggregate its new child
+	if err := r.workItemRepo.RecomputeRollUps(ctx, link.SourceID); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	return link, nil
 }
 
+// CheckTopology returns an error if the given link violates the topology of its link type, ignoring
+// the stored version of the link itself: in a tree a work item has a single parent and neither a tree
+// nor a dependency may contain a cycle. The links of the type are locked until the end of the
+// transaction, so that concurrent transactions cannot create a violation together.
+// returns NotFoundError, DataConflictError or InternalError
+func (r *GormWorkItemLinkRepository) CheckTopology(ctx context.Context, lnk WorkItemLink) error {
+	linkType, err := r.workItemLinkTypeRepo.LoadTypeFromDBByID(ctx, lnk.LinkTypeID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	if linkType.Topology != TopologyTree && linkType.Topology != TopologyDependency {
+		return nil
+	}
+	db := r.db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", lnk.LinkTypeID.String())
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	if linkType.Topology == TopologyTree {
+		// in a tree a work item can only have a single parent
+		var parent struct {
+			SourceID uint64
+		}
+		db = r.db.Model(&WorkItemLink{}).Select("source_id").Where("target_id = ? AND link_type_id = ? AND id <> ?", lnk.TargetID, lnk.LinkTypeID, lnk.ID).Limit(1).Scan(&parent)
+		if db.Error != nil && !db.RecordNotFound() {
+			return errors.NewInternalError(db.Error.Error())
+		}
+		if !db.RecordNotFound() {
+			return errors.NewDataConflictError(fmt.Sprintf("work item %d already has a link of type '%s' from work item %d", lnk.TargetID, linkType.Name, parent.SourceID))
+		}
+	}
+	// the link closes a cycle if its source can be reached from its target
+	var cycle struct {
+		Path string
+	}
+	db = r.db.Raw(fmt.Sprintf(`WITH RECURSIVE reachable(id, path) AS (
+			SELECT ?::bigint, ARRAY[?::bigint]
+			UNION ALL
+			SELECT l.target_id, r.path || l.target_id
+			FROM reachable r JOIN %[1]s l ON l.source_id = r.id
+			WHERE l.link_type_id = ? AND l.id <> ? AND l.deleted_at IS NULL AND NOT l.target_id = ANY(r.path)
+		)
+		SELECT array_to_string(?::bigint || path, ' -> ') AS path FROM reachable WHERE id = ? LIMIT 1`, WorkItemLink{}.TableName()),
+		lnk.TargetID, lnk.TargetID, lnk.LinkTypeID, lnk.ID, lnk.SourceID, lnk.SourceID).Scan(&cycle)
+	if db.Error != nil && !db.RecordNotFound() {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	if !db.RecordNotFound() {
+		return errors.NewDataConflictError(fmt.Sprintf("the link of type '%s' would create the cycle %s", linkType.Name, cycle.Path))
+	}
+	return nil
+}
+
 // Load returns the work item link for the given ID.
 // Returns NotFoundError, ConversionError or InternalError
 func (r *GormWorkItemLinkRepository) Load(ctx context.Context, ID uuid.UUID) (*WorkItemLink, error) {
@@ -206,6 +274,82 @@ func (r *GormWorkItemLinkRepository) DeleteRelatedLinks(ctx context.Context, wiI
 	return nil
 }
 
+// RestoreRelatedLinks restores the deleted links in which the source or target equals the given
+// work item ID and that were deleted at or after the given time, i.e. together with the work item.
+// Links to work items that are still deleted and links that have been created again in the
+// meantime are not restored.
+// returns NotFoundError, DataConflictError or InternalError
+func (r *GormWorkItemLinkRepository) RestoreRelatedLinks(ctx context.Context, wiIDStr string, since time.Time, restorerID uuid.UUID) error {
+	log.Info(ctx, map[string]interface{}{
+		"workitem_id": wiIDStr,
+	}, "Restoring the links related to work item")
+
+	wiID, err := strconv.ParseUint(wiIDStr, 10, 64)
+	if err != nil {
+		// treat as not found: clients don't know it must be a uint64
+		return errors.NewNotFoundError("work item link", wiIDStr)
+	}
+	var workitemLinks = []WorkItemLink{}
+	db := r.db.Unscoped().Where("? in (source_id, target_id) AND deleted_at >= ?", wiID, since).Find(&workitemLinks)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	// restore one by one to trigger the creation of a new work item link revision
+	for _, workitemLink := range workitemLinks {
+		if err := r.restoreLink(ctx, workitemLink, restorerID); err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// restoreLink restores the given deleted work item link unless one of its work items is
+// still deleted or an equal link exists
+func (r *GormWorkItemLinkRepository) restoreLink(ctx context.Context, lnk WorkItemLink, restorerID uuid.UUID) error {
+	var count int
+	db := r.db.Model(&workitem.WorkItemStorage{}).Where("id IN (?)", []uint64{lnk.SourceID, lnk.TargetID}).Count(&count)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	if count < 2 && lnk.SourceID != lnk.TargetID {
+		log.Info(ctx, map[string]interface{}{
+			"wil_id": lnk.ID,
+		}, "Not restoring the work item link to a deleted work item")
+		return nil
+	}
+	db = r.db.Model(&WorkItemLink{}).Where("source_id = ? AND target_id = ? AND link_type_id = ?", lnk.SourceID, lnk.TargetID, lnk.LinkTypeID).Count(&count)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	if count > 0 {
+		log.Info(ctx, map[string]interface{}{
+			"wil_id": lnk.ID,
+		}, "Not restoring the work item link that has been created again")
+		return nil
+	}
+	if err := r.CheckTopology(ctx, lnk); err != nil {
+		return errs.WithStack(err)
+	}
+	lnk.Version = lnk.Version + 1
+	lnk.DeletedAt = nil
+	db = r.db.Unscoped().Model(&WorkItemLink{}).Where("id = ?", lnk.ID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    lnk.Version,
+	})
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wil_id": lnk.ID,
+			"err":    db.Error,
+		}, "unable to restore work item link")
+		return errors.NewInternalError(db.Error.Error())
+	}
+	// save a revision of the restored work item link
+	if err := r.revisionRepo.Create(ctx, restorerID, RevisionTypeRestore, lnk); err != nil {
+		return errs.Wrapf(err, "error while restoring work item link")
+	}
+	return r.workItemRepo.RecomputeRollUps(ctx, lnk.SourceID)
+}
+
 // Delete deletes the work item link with the given id
 // returns NotFoundError or InternalError
 func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkItemLink, suppressorID uuid.UUID) error {
@@ -228,11 +372,11 @@ func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkIte
 	if err := r.revisionRepo.Create(ctx, suppressorID, RevisionTypeDelete, lnk); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
-	return nil
+	return r.workItemRepo.RecomputeRollUps(ctx, lnk.SourceID)
 }
 
 // Save updates the given work item link in storage. Version must be the same as the one int the stored version.
-// returns NotFoundError, VersionConflictError, ConversionError or InternalError
+// returns NotFoundError, VersionConflictError, DataConflictError, ConversionError or InternalError
 func (r *GormWorkItemLinkRepository) Save(ctx context.Context, linkToSave WorkItemLink, modifierID uuid.UUID) (*WorkItemLink, error) {
 	logrus.Info(fmt.Sprintf("saving workitem link with type =  %s", linkToSave.LinkTypeID))
 	existingLink := WorkItemLink{}
@@ -257,6 +401,9 @@ func (r *GormWorkItemLinkRepository) Save(ctx context.Context, linkToSave WorkIt
 	if err := r.ValidateCorrectSourceAndTargetType(ctx, existingLink.SourceID, existingLink.TargetID, existingLink.LinkTypeID); err != nil {
 		return nil, errs.WithStack(err)
 	}
+	if err := r.CheckTopology(ctx, linkToSave); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	// save
 	db = r.db.Save(&linkToSave)
 	if db.Error != nil {
@@ -270,6 +417,11 @@ func (r *GormWorkItemLinkRepository) Save(ctx context.Context, linkToSave WorkIt
 	if err := r.revisionRepo.Create(ctx, modifierID, RevisionTypeUpdate, linkToSave); err != nil {
 		return nil, errs.Wrapf(err, "error while saving work item")
 	}
+	for _, sourceID := range []uint64{existingLink.SourceID, linkToSave.SourceID} {
+		if err := r.workItemRepo.RecomputeRollUps(ctx, sourceID); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wil_id": linkToSave.ID,
 	}, "Work item link updated")
@@ -316,3 +468,360 @@ func (r *GormWorkItemLinkRepository) ListWorkItemChildren(ctx context.Context, p
 
 	return res, nil
 }
+
+// Traverse returns the work items in the given space that are reached from the given work item by
+// following links of the given type transitively, either from their source to their target or the
+// other way round, and that match the given filter. Each work item is returned once along with its
+// shortest path from the start work item, ordered by depth. A nil maxDepth follows the links to the
+// end; cycles are never followed twice.
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormWorkItemLinkRepository) Traverse(ctx context.Context, spaceID uuid.UUID, wiIDStr string, linkTypeID uuid.UUID, direction TraversalDirection, maxDepth *int, filter criteria.Expression) ([]TraversedWorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "traverse", "query"}, time.Now())
+	if err := CheckValidTraversalDirection(direction); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if maxDepth != nil && *maxDepth < 1 {
+		return nil, errors.NewBadParameterError("depth", *maxDepth).Expected("a positive number")
+	}
+	start, err := r.workItemRepo.LoadFromDB(ctx, wiIDStr)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if !uuid.Equal(start.SpaceID, spaceID) {
+		return nil, errors.NewNotFoundError("work item", wiIDStr)
+	}
+	if _, err := r.workItemLinkTypeRepo.LoadTypeFromDBByID(ctx, linkTypeID); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	fieldKinds, err := r.workItemRepo.FieldKinds(ctx, spaceID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	where, parameters, compileErrors := workitem.CompileWithFieldKinds(filter, fieldKinds)
+	if compileErrors != nil {
+		return nil, errors.NewBadParameterError("filter", filter)
+	}
+	from, to := "source_id", "target_id"
+	if direction == TraversalReverse {
+		from, to = to, from
+	}
+	depthCondition := ""
+	arguments := []interface{}{start.ID, start.ID, linkTypeID}
+	if maxDepth != nil {
+		depthCondition = "AND lvl.level_depth < ?"
+		arguments = append(arguments, *maxDepth)
+	}
+	arguments = append(arguments, linkTypeID, start.ID)
+	arguments = append(arguments, parameters...)
+	arguments = append(arguments, spaceID)
+	// the links are followed level by level: every level holds the work items that are reached first at
+	// its depth, so that each work item is expanded only once. The path of a work item leads through its
+	// predecessor with the lowest ID on the previous level.
+	query := fmt.Sprintf(`WITH RECURSIVE levels(level_depth, level_nodes, level_visited) AS (
+			SELECT 0, ARRAY[?::bigint], ARRAY[?::bigint]
+			UNION ALL
+			SELECT lvl.level_depth + 1, successors.level_next, lvl.level_visited || successors.level_next
+			FROM levels lvl, LATERAL (
+				SELECT ARRAY(
+					SELECT DISTINCT l.%[3]s FROM %[1]s l
+					WHERE l.link_type_id = ? AND l.deleted_at IS NULL AND l.%[2]s = ANY(lvl.level_nodes) AND NOT l.%[3]s = ANY(lvl.level_visited)
+				) AS level_next
+			) successors
+			WHERE cardinality(lvl.level_nodes) > 0 %[4]s
+		), nodes AS (
+			SELECT unnest(level_nodes) AS node, level_depth AS depth FROM levels
+		), predecessors AS (
+			SELECT n.node, n.depth, min(p.node) AS predecessor
+			FROM nodes n
+			JOIN %[1]s l ON l.%[3]s = n.node AND l.link_type_id = ? AND l.deleted_at IS NULL
+			JOIN nodes p ON p.node = l.%[2]s AND p.depth = n.depth - 1
+			GROUP BY n.node, n.depth
+		), traversal(traversal_node, traversal_depth, traversal_path) AS (
+			SELECT ?::bigint, 0, ARRAY[]::bigint[]
+			UNION ALL
+			SELECT pr.node, pr.depth, t.traversal_path || t.traversal_node
+			FROM traversal t JOIN predecessors pr ON pr.predecessor = t.traversal_node AND pr.depth = t.traversal_depth + 1
+		)
+		SELECT %[5]s.*, traversal.traversal_depth, array_to_string(traversal.traversal_path || traversal.traversal_node, ',') AS traversal_path
+		FROM %[5]s JOIN traversal ON traversal.traversal_node = %[5]s.id
+		WHERE traversal.traversal_depth > 0 AND (%[6]s) AND %[5]s.space_id = ? AND %[5]s.deleted_at IS NULL
+		ORDER BY traversal.traversal_depth, %[5]s.id`,
+		WorkItemLink{}.TableName(), from, to, depthCondition, workitem.WorkItemStorage{}.TableName(), where)
+	db := r.db.Raw(query, arguments...)
+	rows, err := db.Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	defer rows.Close()
+	type traversalRow struct {
+		workitem.WorkItemStorage
+		TraversalDepth int
+		TraversalPath  string
+	}
+	res := []TraversedWorkItem{}
+	for rows.Next() {
+		value := traversalRow{}
+		if err := db.ScanRows(rows, &value); err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		wiType, err := r.workItemTypeRepo.LoadTypeFromDB(ctx, value.Type)
+		if err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		modelWI, err := workitem.ConvertWorkItemStorageToModel(wiType, &value.WorkItemStorage)
+		if err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		res = append(res, TraversedWorkItem{
+			WorkItem: *modelWI,
+			Depth:    value.TraversalDepth,
+			Path:     strings.Split(value.TraversalPath, ","),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return res, nil
+}
+
+// openDependencies returns the SQL joining the links of the dependency link types with their open
+// and not deleted source and target work items, available as "blocker" and "blocked"
+func openDependencies() string {
+	doneStates := make([]string, len(workitem.SystemDoneStates))
+	for i, state := range workitem.SystemDoneStates {
+		doneStates[i] = "'" + state + "'"
+	}
+	openState := fmt.Sprintf("coalesce(Fields->>'%s', '') NOT IN (%s)", workitem.SystemState, strings.Join(doneStates, ", "))
+	return fmt.Sprintf(`%[1]s l
+		JOIN %[2]s lt ON lt.id = l.link_type_id AND lt.topology = '%[3]s' AND lt.deleted_at IS NULL
+		JOIN %[4]s blocker ON blocker.id = l.source_id AND blocker.deleted_at IS NULL AND blocker.%[5]s
+		JOIN %[4]s blocked ON blocked.id = l.target_id AND blocked.deleted_at IS NULL AND blocked.%[5]s`,
+		WorkItemLink{}.TableName(), WorkItemLinkType{}.TableName(), TopologyDependency, workitem.WorkItemStorage{}.TableName(), openState)
+}
+
+// ListBlocked returns the open work items of the given space that are blocked by open work items
+// through links of the dependency link types, ordered by ID.
+// returns InternalError
+func (r *GormWorkItemLinkRepository) ListBlocked(ctx context.Context, spaceID uuid.UUID) ([]BlockedWorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "blocked", "query"}, time.Now())
+	query := fmt.Sprintf(`SELECT blocked.id::text, string_agg(blocker.id::text, ',' ORDER BY blocker.id)
+		FROM %s
+		WHERE l.deleted_at IS NULL AND blocked.space_id = ?
+		GROUP BY blocked.id
+		ORDER BY blocked.id`, openDependencies())
+	rows, err := r.db.Raw(query, spaceID).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	defer rows.Close()
+	res := []BlockedWorkItem{}
+	for rows.Next() {
+		var blockedID, blockerIDs string
+		if err := rows.Scan(&blockedID, &blockerIDs); err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		res = append(res, BlockedWorkItem{WorkItemID: blockedID, BlockerIDs: strings.Split(blockerIDs, ",")})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return res, nil
+}
+
+// loadOpenDependencies returns the links of the dependency link types between the open work items
+// blocking the open work items of the given space, grouped by the blocked work item and ordered by
+// the blocking work item, along with the iterations the work items are planned in
+// returns InternalError
+func (r *GormWorkItemLinkRepository) loadOpenDependencies(ctx context.Context, spaceID uuid.UUID) (map[uint64][]openDependency, map[uint64]plannedIteration, error) {
+	iterationTable := (&iteration.Iteration{}).TableName()
+	query := fmt.Sprintf(`SELECT l.id, blocker.id, blocked.id, blocker_iteration.id::text, blocker_iteration.start_at,
+		blocked_iteration.id::text, blocked_iteration.start_at
+		FROM %[1]s
+		LEFT JOIN %[2]s blocker_iteration ON blocker_iteration.id::text = blocker.Fields->>'%[3]s'
+		LEFT JOIN %[2]s blocked_iteration ON blocked_iteration.id::text = blocked.Fields->>'%[3]s'
+		WHERE l.deleted_at IS NULL AND blocked.space_id = ?
+		ORDER BY blocked.id, blocker.id`, openDependencies(), iterationTable, workitem.SystemIteration)
+	rows, err := r.db.Raw(query, spaceID).Rows()
+	if err != nil {
+		return nil, nil, errors.NewInternalError(err.Error())
+	}
+	defer rows.Close()
+	blockers := map[uint64][]openDependency{}
+	iterations := map[uint64]plannedIteration{}
+	for rows.Next() {
+		var d openDependency
+		var blockerIterationID, blockedIterationID *string
+		var blockerStartAt, blockedStartAt *time.Time
+		if err := rows.Scan(&d.linkID, &d.blockerID, &d.blockedID, &blockerIterationID, &blockerStartAt, &blockedIterationID, &blockedStartAt); err != nil {
+			return nil, nil, errors.NewInternalError(err.Error())
+		}
+		blockers[d.blockedID] = append(blockers[d.blockedID], d)
+		if blockerIterationID != nil {
+			iterations[d.blockerID] = plannedIteration{id: uuid.FromStringOrNil(*blockerIterationID), startAt: blockerStartAt}
+		}
+		if blockedIterationID != nil {
+			iterations[d.blockedID] = plannedIteration{id: uuid.FromStringOrNil(*blockedIterationID), startAt: blockedStartAt}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.NewInternalError(err.Error())
+	}
+	return blockers, iterations, nil
+}
+
+// ListMisplannedDependencies returns the direct and transitive dependencies between open work items
+// of the given space where the blocking work item is planned in an iteration that starts after the
+// iteration of the blocked work item, ordered by the blocked and the blocking work item. Chains of
+// dependencies are followed through unplanned work items as well.
+// returns InternalError
+func (r *GormWorkItemLinkRepository) ListMisplannedDependencies(ctx context.Context, spaceID uuid.UUID) ([]MisplannedDependency, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "misplanned", "query"}, time.Now())
+	blockers, iterations, err := r.loadOpenDependencies(ctx, spaceID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	blockedIDs := make([]uint64, 0, len(blockers))
+	for blockedID := range blockers {
+		blockedIDs = append(blockedIDs, blockedID)
+	}
+	sort.Sort(workItemIDs(blockedIDs))
+	res := []MisplannedDependency{}
+	for _, blockedID := range blockedIDs {
+		blockedIteration, planned := iterations[blockedID]
+		if !planned {
+			continue
+		}
+		// the blockers are reached by following the links backwards, the link through which a
+		// blocker is reached first starts its shortest chain to the blocked work item
+		links := map[uint64]uuid.UUID{}
+		misplanned := []uint64{}
+		queue := []uint64{blockedID}
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			for _, d := range blockers[node] {
+				if _, reached := links[d.blockerID]; reached || d.blockerID == blockedID {
+					continue
+				}
+				links[d.blockerID] = d.linkID
+				queue = append(queue, d.blockerID)
+				if blockerIteration, ok := iterations[d.blockerID]; ok && blockerIteration.startsAfter(blockedIteration) {
+					misplanned = append(misplanned, d.blockerID)
+				}
+			}
+		}
+		sort.Sort(workItemIDs(misplanned))
+		for _, blockerID := range misplanned {
+			res = append(res, MisplannedDependency{
+				LinkID:             links[blockerID],
+				BlockerID:          strconv.FormatUint(blockerID, 10),
+				BlockerIterationID: iterations[blockerID].id,
+				BlockedID:          strconv.FormatUint(blockedID, 10),
+				BlockedIterationID: blockedIteration.id,
+			})
+		}
+	}
+	return res, nil
+}
+
+// CriticalPath returns the IDs of the work items on the longest chain of open work items that block
+// the given work item through links of the dependency link types, from the work item that can be
+// done first up to the given work item. A DataConflictError naming the cycle is returned if the
+// work items blocking the given work item block each other.
+// returns NotFoundError, DataConflictError or InternalError
+func (r *GormWorkItemLinkRepository) CriticalPath(ctx context.Context, spaceID uuid.UUID, rootIDStr string) ([]string, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "criticalpath", "query"}, time.Now())
+	root, err := r.workItemRepo.LoadFromDB(ctx, rootIDStr)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if !uuid.Equal(root.SpaceID, spaceID) {
+		return nil, errors.NewNotFoundError("work item", rootIDStr)
+	}
+	blockers, _, err := r.loadOpenDependencies(ctx, spaceID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// collect the work items blocking the root directly or transitively along with the number of
+	// their blockers and the work items they block
+	pending := map[uint64]int{root.ID: len(blockers[root.ID])}
+	blocked := map[uint64][]uint64{}
+	stack := []uint64{root.ID}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range blockers[node] {
+			blocked[d.blockerID] = append(blocked[d.blockerID], node)
+			if _, reached := pending[d.blockerID]; !reached {
+				pending[d.blockerID] = len(blockers[d.blockerID])
+				stack = append(stack, d.blockerID)
+			}
+		}
+	}
+	// the dependencies are acyclic, so the longest chain ending at each work item is known once the
+	// chains of all its blockers are known, i.e. in topological order
+	lengths := map[uint64]int{}
+	predecessors := map[uint64]uint64{}
+	queue := []uint64{}
+	for node, count := range pending {
+		if count == 0 {
+			queue = append(queue, node)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		lengths[node] = 1
+		for _, d := range blockers[node] {
+			if lengths[d.blockerID]+1 > lengths[node] {
+				lengths[node] = lengths[d.blockerID] + 1
+				predecessors[node] = d.blockerID
+			}
+		}
+		for _, next := range blocked[node] {
+			pending[next]--
+			if pending[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if pending[root.ID] > 0 {
+		// the dependency link types are checked for cycles one by one, so the dependencies may still
+		// be cyclic across link types
+		return nil, errors.NewDataConflictError(fmt.Sprintf("the work items blocking work item %d form the cycle %s", root.ID, dependencyCycle(root.ID, blockers, pending)))
+	}
+	path := make([]string, lengths[root.ID])
+	for node, i := root.ID, len(path)-1; i >= 0; node, i = predecessors[node], i-1 {
+		path[i] = strconv.FormatUint(node, 10)
+	}
+	return path, nil
+}
+
+// dependencyCycle returns a cycle of the work items that were left pending by the topological sort
+// of the work items blocking the given work item, e.g. "3 -> 5 -> 3". Every pending work item has
+// a pending blocker, so following them from the given work item leads into a cycle.
+func dependencyCycle(start uint64, blockers map[uint64][]openDependency, pending map[uint64]int) string {
+	positions := map[uint64]int{}
+	var chain []uint64
+	node := start
+	for {
+		if position, visited := positions[node]; visited {
+			chain = append(chain[position:], node)
+			break
+		}
+		positions[node] = len(chain)
+		chain = append(chain, node)
+		for _, d := range blockers[node] {
+			if pending[d.blockerID] > 0 {
+				node = d.blockerID
+				break
+			}
+		}
+	}
+	ids := make([]string, len(chain))
+	// the blockers come first, as in the critical path
+	for i, id := range chain {
+		ids[len(chain)-1-i] = strconv.FormatUint(id, 10)
+	}
+	return strings.Join(ids, " -> ")
+}
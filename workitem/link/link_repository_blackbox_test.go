@@ -0,0 +1,473 @@
+package link_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/criteria"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/iteration"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	testsupport "github.com/almighty/almighty-core/test"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/link"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRunWorkItemLinkRepositoryBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &linkRepositoryBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../../config.yaml")})
+}
+
+type linkRepositoryBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repository      link.WorkItemLinkRepository
+	workItemRepo    workitem.WorkItemRepository
+	clean           func()
+	ctx             context.Context
+	testIdentity    account.Identity
+	spaceID         uuid.UUID
+	parentChildType uuid.UUID
+	parentWITID     uuid.UUID
+	childWITID      uuid.UUID
+}
+
+// SetupSuite overrides the DBTestSuite's function but calls it before doing anything else
+// The SetupSuite method will run before the tests in the suite are run.
+// It sets up a database connection for all the tests in this suite without polluting global space.
+func (s *linkRepositoryBlackBoxTest) SetupSuite() {
+	s.DBTestSuite.SetupSuite()
+	s.ctx = migration.NewMigrationContext(context.Background())
+	s.DBTestSuite.PopulateDBTestSuite(s.ctx)
+}
+
+func (s *linkRepositoryBlackBoxTest) SetupTest() {
+	s.repository = link.NewWorkItemLinkRepository(s.DB)
+	s.workItemRepo = workitem.NewWorkItemRepository(s.DB)
+	s.clean = cleaner.DeleteCreatedEntities(s.DB)
+	var err error
+	s.testIdentity, err = testsupport.CreateTestIdentity(s.DB, "jdoe", "test")
+	require.Nil(s.T(), err)
+	testSpace, err := space.NewRepository(s.DB).Create(s.ctx, &space.Space{
+		Name: "test-space" + uuid.NewV4().String(),
+	})
+	require.Nil(s.T(), err)
+	s.spaceID = testSpace.ID
+	// a parent type rolling up the points and the states of its children
+	witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+	childWIT, err := witRepo.Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "story "+uuid.NewV4().String(), nil, "fa-book", map[string]workitem.FieldDefinition{
+		"points": {Label: "Points", Type: workitem.SimpleType{Kind: workitem.KindFloat}},
+	})
+	require.Nil(s.T(), err)
+	s.childWITID = childWIT.ID
+	rollUp := func(function workitem.RollUpFunction, field string) workitem.FieldDefinition {
+		return workitem.FieldDefinition{
+			Label: string(function),
+			Type: workitem.RollUpType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindRollUp},
+				Function:   function,
+				Field:      field,
+			},
+		}
+	}
+	parentWIT, err := witRepo.Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "feature "+uuid.NewV4().String(), nil, "fa-cubes", map[string]workitem.FieldDefinition{
+		"total_points": rollUp(workitem.RollUpSum, "points"),
+		"stories":      rollUp(workitem.RollUpCount, ""),
+		"done":         rollUp(workitem.RollUpPercentComplete, ""),
+	})
+	require.Nil(s.T(), err)
+	s.parentWITID = parentWIT.ID
+	categoryName := "test-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	linkType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test parent child "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "parent of", "child of", link.TopologyTree, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	s.parentChildType = linkType.ID
+}
+
+func (s *linkRepositoryBlackBoxTest) TearDownTest() {
+	s.clean()
+}
+
+func (s *linkRepositoryBlackBoxTest) createWorkItem(witID uuid.UUID, fields map[string]interface{}) (*workitem.WorkItem, uint64) {
+	fields[workitem.SystemTitle] = "Title"
+	if _, ok := fields[workitem.SystemState]; !ok {
+		fields[workitem.SystemState] = workitem.SystemStateNew
+	}
+	wi, err := s.workItemRepo.Create(s.ctx, s.spaceID, witID, fields, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	id, err := strconv.ParseUint(wi.ID, 10, 64)
+	require.Nil(s.T(), err)
+	return wi, id
+}
+
+// requireRollUps loads the work item with the given ID and verifies its roll-up fields
+func (s *linkRepositoryBlackBoxTest) requireRollUps(id string, totalPoints, stories, done interface{}) *workitem.WorkItem {
+	wi, err := s.workItemRepo.Load(s.ctx, s.spaceID, id)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), totalPoints, wi.Fields["total_points"], "total_points")
+	assert.Equal(s.T(), stories, wi.Fields["stories"], "stories")
+	assert.Equal(s.T(), done, wi.Fields["done"], "done")
+	return wi
+}
+
+func (s *linkRepositoryBlackBoxTest) TestRollUpFields() {
+	// given
+	parent, parentID := s.createWorkItem(s.parentWITID, map[string]interface{}{})
+	s.requireRollUps(parent.ID, 0.0, 0.0, nil)
+	child1, child1ID := s.createWorkItem(s.childWITID, map[string]interface{}{"points": 3.0})
+	_, child2ID := s.createWorkItem(s.childWITID, map[string]interface{}{"points": 5.0, workitem.SystemState: workitem.SystemStateClosed})
+	// when linking the children
+	_, err := s.repository.Create(s.ctx, parentID, child1ID, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	s.requireRollUps(parent.ID, 3.0, 1.0, 0.0)
+	link2, err := s.repository.Create(s.ctx, parentID, child2ID, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// then
+	loaded := s.requireRollUps(parent.ID, 8.0, 2.0, 50.0)
+	// when changing a child
+	child1.Fields["points"] = 4.0
+	_, err = s.workItemRepo.Save(s.ctx, s.spaceID, *child1, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// then the parent is updated without changing its version or its other fields
+	updated := s.requireRollUps(parent.ID, 9.0, 2.0, 50.0)
+	assert.Equal(s.T(), parent.Version, loaded.Version)
+	assert.Equal(s.T(), parent.Version, updated.Version)
+	assert.Equal(s.T(), "Title", updated.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), workitem.SystemStateNew, updated.Fields[workitem.SystemState])
+	// and the roll-up fields can be queried
+	found, _, err := s.workItemRepo.List(s.ctx, s.spaceID, criteria.GreaterThan(criteria.Field("total_points"), criteria.Literal(8)), nil, nil, nil)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), found, 1)
+	assert.Equal(s.T(), parent.ID, found[0].ID)
+	// when setting a computed value
+	loaded.Fields["total_points"] = 42.0
+	_, err = s.workItemRepo.Save(s.ctx, s.spaceID, *loaded, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// then it is ignored
+	s.requireRollUps(parent.ID, 9.0, 2.0, 50.0)
+	// when deleting a link
+	require.Nil(s.T(), s.repository.Delete(s.ctx, link2.ID, s.testIdentity.ID))
+	s.requireRollUps(parent.ID, 4.0, 1.0, 0.0)
+	// when deleting a child
+	require.Nil(s.T(), s.workItemRepo.Delete(s.ctx, s.spaceID, child1.ID, s.testIdentity.ID))
+	s.requireRollUps(parent.ID, 0.0, 0.0, nil)
+}
+
+func (s *linkRepositoryBlackBoxTest) TestRollUpFieldsOfAncestors() {
+	// given a grand parent rolling up the number of children of its children
+	witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+	grandParentWIT, err := witRepo.Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "experience "+uuid.NewV4().String(), nil, "fa-map", map[string]workitem.FieldDefinition{
+		"stories": {
+			Label: "Stories",
+			Type: workitem.RollUpType{
+				SimpleType: workitem.SimpleType{Kind: workitem.KindRollUp},
+				Function:   workitem.RollUpSum,
+				Field:      "stories",
+			},
+		},
+	})
+	require.Nil(s.T(), err)
+	grandParent, grandParentID := s.createWorkItem(grandParentWIT.ID, map[string]interface{}{})
+	_, parentID := s.createWorkItem(s.parentWITID, map[string]interface{}{})
+	_, childID := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, err = s.repository.Create(s.ctx, grandParentID, parentID, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when
+	_, err = s.repository.Create(s.ctx, parentID, childID, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// then
+	wi, err := s.workItemRepo.Load(s.ctx, s.spaceID, grandParent.ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), 1.0, wi.Fields["stories"])
+}
+
+func (s *linkRepositoryBlackBoxTest) TestCreateEnforcesTreeTopology() {
+	// given a chain of three work items
+	_, id1 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, id2 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, id3 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, err := s.repository.Create(s.ctx, id1, id2, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	link23, err := s.repository.Create(s.ctx, id2, id3, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when giving a work item a second parent
+	_, err = s.repository.Create(s.ctx, id1, id3, s.parentChildType, s.testIdentity.ID)
+	// then
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+	// when closing the cycle
+	_, err = s.repository.Create(s.ctx, id3, id1, s.parentChildType, s.testIdentity.ID)
+	// then the error names the cycle
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+	assert.Contains(s.T(), err.Error(), fmt.Sprintf("%d -> %d -> %d -> %d", id3, id1, id2, id3))
+	// when linking a work item to itself
+	_, err = s.repository.Create(s.ctx, id1, id1, s.parentChildType, s.testIdentity.ID)
+	// then
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+	// when reversing a link
+	link23.SourceID, link23.TargetID = id3, id2
+	_, err = s.repository.Save(s.ctx, *link23, s.testIdentity.ID)
+	// then it conflicts with the existing parent of the new target
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+}
+
+func (s *linkRepositoryBlackBoxTest) TestCreateEnforcesDependencyTopology() {
+	// given
+	categoryName := "dependency-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	dependencyType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test depends on "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "depends on", "is dependency of", link.TopologyDependency, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	networkType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test relates to "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "relates to", "is related to", link.TopologyNetwork, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	_, id1 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, id2 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, id3 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	// when a work item depends on several others
+	_, err = s.repository.Create(s.ctx, id1, id2, dependencyType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id1, id3, dependencyType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	link23, err := s.repository.Create(s.ctx, id2, id3, dependencyType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// then a dependency loop is refused
+	_, err = s.repository.Create(s.ctx, id3, id1, dependencyType.ID, s.testIdentity.ID)
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+	assert.Contains(s.T(), err.Error(), fmt.Sprintf("%d -> %d -> ", id3, id1))
+	// but links of other types may form a cycle
+	_, err = s.repository.Create(s.ctx, id3, id1, networkType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// and a deleted link does not count
+	require.Nil(s.T(), s.repository.Delete(s.ctx, link23.ID, s.testIdentity.ID))
+	_, err = s.repository.Create(s.ctx, id3, id2, dependencyType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+}
+
+func (s *linkRepositoryBlackBoxTest) TestTraverse() {
+	// given a tree 1 -> 2 -> 3 and 1 -> 4 where 4 is closed
+	wi1, id1 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi2, id2 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi3, id3 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi4, id4 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed})
+	_, err := s.repository.Create(s.ctx, id1, id2, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id2, id3, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id1, id4, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	all := criteria.Literal(true)
+	// when listing the descendants
+	nodes, err := s.repository.Traverse(s.ctx, s.spaceID, wi1.ID, s.parentChildType, link.TraversalForward, nil, all)
+	// then they are ordered by depth with their paths
+	require.Nil(s.T(), err)
+	require.Len(s.T(), nodes, 3)
+	assert.Equal(s.T(), wi2.ID, nodes[0].ID)
+	assert.Equal(s.T(), 1, nodes[0].Depth)
+	assert.Equal(s.T(), []string{wi1.ID, wi2.ID}, nodes[0].Path)
+	assert.Equal(s.T(), wi4.ID, nodes[1].ID)
+	assert.Equal(s.T(), 1, nodes[1].Depth)
+	assert.Equal(s.T(), wi3.ID, nodes[2].ID)
+	assert.Equal(s.T(), 2, nodes[2].Depth)
+	assert.Equal(s.T(), []string{wi1.ID, wi2.ID, wi3.ID}, nodes[2].Path)
+	// when limiting the depth
+	depth := 1
+	nodes, err = s.repository.Traverse(s.ctx, s.spaceID, wi1.ID, s.parentChildType, link.TraversalForward, &depth, all)
+	// then only the children are returned
+	require.Nil(s.T(), err)
+	require.Len(s.T(), nodes, 2)
+	assert.Equal(s.T(), wi2.ID, nodes[0].ID)
+	assert.Equal(s.T(), wi4.ID, nodes[1].ID)
+	// when filtering the descendants
+	closed := criteria.Equals(criteria.Field(workitem.SystemState), criteria.Literal(workitem.SystemStateClosed))
+	nodes, err = s.repository.Traverse(s.ctx, s.spaceID, wi1.ID, s.parentChildType, link.TraversalForward, nil, closed)
+	// then
+	require.Nil(s.T(), err)
+	require.Len(s.T(), nodes, 1)
+	assert.Equal(s.T(), wi4.ID, nodes[0].ID)
+	// when listing the ancestors
+	nodes, err = s.repository.Traverse(s.ctx, s.spaceID, wi3.ID, s.parentChildType, link.TraversalReverse, nil, all)
+	// then
+	require.Nil(s.T(), err)
+	require.Len(s.T(), nodes, 2)
+	assert.Equal(s.T(), wi2.ID, nodes[0].ID)
+	assert.Equal(s.T(), wi1.ID, nodes[1].ID)
+	assert.Equal(s.T(), []string{wi3.ID, wi2.ID, wi1.ID}, nodes[1].Path)
+	// when the direction is unknown
+	_, err = s.repository.Traverse(s.ctx, s.spaceID, wi1.ID, s.parentChildType, link.TraversalDirection("sideways"), nil, all)
+	// then
+	require.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+}
+
+func (s *linkRepositoryBlackBoxTest) TestTraverseDiamonds() {
+	// given a chain of diamonds 1 -> 2 -> 4 -> 5 -> 7 and 1 -> 3 -> 4 -> 6 -> 7
+	categoryName := "network-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	networkType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test relates to "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "relates to", "is related to", link.TopologyNetwork, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	wis := make([]*workitem.WorkItem, 7)
+	ids := make([]uint64, 7)
+	for i := range wis {
+		wis[i], ids[i] = s.createWorkItem(s.childWITID, map[string]interface{}{})
+	}
+	for _, l := range [][2]int{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {3, 4}, {3, 5}, {4, 6}, {5, 6}} {
+		_, err := s.repository.Create(s.ctx, ids[l[0]], ids[l[1]], networkType.ID, s.testIdentity.ID)
+		require.Nil(s.T(), err)
+	}
+	// when
+	nodes, err := s.repository.Traverse(s.ctx, s.spaceID, wis[0].ID, networkType.ID, link.TraversalForward, nil, criteria.Literal(true))
+	// then every work item is reached once on its shortest path
+	require.Nil(s.T(), err)
+	require.Len(s.T(), nodes, 6)
+	assert.Equal(s.T(), wis[3].ID, nodes[2].ID)
+	assert.Equal(s.T(), 2, nodes[2].Depth)
+	assert.Equal(s.T(), wis[6].ID, nodes[5].ID)
+	assert.Equal(s.T(), 4, nodes[5].Depth)
+	assert.Equal(s.T(), []string{wis[0].ID, wis[1].ID, wis[3].ID, wis[4].ID, wis[6].ID}, nodes[5].Path)
+}
+
+func (s *linkRepositoryBlackBoxTest) TestDependencyAnalysis() {
+	// given a dependency type where the source blocks the target
+	categoryName := "dependency-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	blocksType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test blocks "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "blocks", "is blocked by", link.TopologyDependency, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	// and two iterations, the first one starting before the second one
+	iterationRepo := iteration.NewIterationRepository(s.DB)
+	start := time.Now()
+	later := start.Add(14 * 24 * time.Hour)
+	first := iteration.Iteration{SpaceID: s.spaceID, Name: "first " + uuid.NewV4().String(), StartAt: &start}
+	require.Nil(s.T(), iterationRepo.Create(s.ctx, &first))
+	second := iteration.Iteration{SpaceID: s.spaceID, Name: "second " + uuid.NewV4().String(), StartAt: &later}
+	require.Nil(s.T(), iterationRepo.Create(s.ctx, &second))
+	// and the dependencies 1 -> 2 -> 3, 1 -> 3 and 4 -> 3 where 4 is closed
+	// and 1 is planned after 2
+	wi1, id1 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemIteration: second.ID.String()})
+	wi2, id2 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemIteration: first.ID.String()})
+	wi3, id3 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemIteration: second.ID.String()})
+	_, id4 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemState: workitem.SystemStateClosed})
+	link12, err := s.repository.Create(s.ctx, id1, id2, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id2, id3, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id1, id3, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id4, id3, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// and a tree link that is no dependency
+	_, err = s.repository.Create(s.ctx, id3, id1, s.parentChildType, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when listing the blocked work items
+	blocked, err := s.repository.ListBlocked(s.ctx, s.spaceID)
+	// then the closed blocker is ignored
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []link.BlockedWorkItem{
+		{WorkItemID: wi2.ID, BlockerIDs: []string{wi1.ID}},
+		{WorkItemID: wi3.ID, BlockerIDs: []string{wi1.ID, wi2.ID}},
+	}, blocked)
+	// when listing the dependencies planned in the wrong order
+	misplanned, err := s.repository.ListMisplannedDependencies(s.ctx, s.spaceID)
+	// then
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []link.MisplannedDependency{
+		{LinkID: link12.ID, BlockerID: wi1.ID, BlockerIterationID: second.ID, BlockedID: wi2.ID, BlockedIterationID: first.ID},
+	}, misplanned)
+	// when computing the critical path of 3
+	path, err := s.repository.CriticalPath(s.ctx, s.spaceID, wi3.ID)
+	// then the longest chain is returned
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{wi1.ID, wi2.ID, wi3.ID}, path)
+	// when computing the critical path of a work item without open blockers
+	path, err = s.repository.CriticalPath(s.ctx, s.spaceID, wi1.ID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{wi1.ID}, path)
+	// when the work item is in another space
+	_, err = s.repository.CriticalPath(s.ctx, uuid.NewV4(), wi1.ID)
+	// then
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+}
+
+func (s *linkRepositoryBlackBoxTest) TestTransitiveDependencyAnalysis() {
+	// given a dependency type where the source blocks the target
+	categoryName := "dependency-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	blocksType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test blocks "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "blocks", "is blocked by", link.TopologyDependency, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	// and two iterations, the first one starting before the second one
+	iterationRepo := iteration.NewIterationRepository(s.DB)
+	start := time.Now()
+	later := start.Add(14 * 24 * time.Hour)
+	first := iteration.Iteration{SpaceID: s.spaceID, Name: "first " + uuid.NewV4().String(), StartAt: &start}
+	require.Nil(s.T(), iterationRepo.Create(s.ctx, &first))
+	second := iteration.Iteration{SpaceID: s.spaceID, Name: "second " + uuid.NewV4().String(), StartAt: &later}
+	require.Nil(s.T(), iterationRepo.Create(s.ctx, &second))
+	// and the dependencies 1 -> 2 -> 3 -> 4 and 5 -> 2 where 1 is planned after 4,
+	// 2 and 3 are not planned and 5 is resolved
+	wi1, id1 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemIteration: second.ID.String()})
+	wi2, id2 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi3, id3 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi4, id4 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemIteration: first.ID.String()})
+	_, id5 := s.createWorkItem(s.childWITID, map[string]interface{}{workitem.SystemState: workitem.SystemStateResolved, workitem.SystemIteration: second.ID.String()})
+	link12, err := s.repository.Create(s.ctx, id1, id2, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id2, id3, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id3, id4, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id5, id2, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when listing the dependencies planned in the wrong order
+	misplanned, err := s.repository.ListMisplannedDependencies(s.ctx, s.spaceID)
+	// then the chain through the unplanned work items is found
+	require.Nil(s.T(), err)
+	require.Equal(s.T(), []link.MisplannedDependency{
+		{LinkID: link12.ID, BlockerID: wi1.ID, BlockerIterationID: second.ID, BlockedID: wi4.ID, BlockedIterationID: first.ID},
+	}, misplanned)
+	// when computing the critical path of 4
+	path, err := s.repository.CriticalPath(s.ctx, s.spaceID, wi4.ID)
+	// then the resolved blocker is ignored
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{wi1.ID, wi2.ID, wi3.ID, wi4.ID}, path)
+}
+
+func (s *linkRepositoryBlackBoxTest) TestCriticalPathWithCycleAcrossLinkTypes() {
+	// given two dependency types where the source blocks the target
+	categoryName := "dependency-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	blocksType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test blocks "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "blocks", "is blocked by", link.TopologyDependency, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	precedesType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test precedes "+uuid.NewV4().String(), nil, workitem.SystemPlannerItem, workitem.SystemPlannerItem, "precedes", "succeeds", link.TopologyDependency, linkCategory.ID, s.spaceID)
+	require.Nil(s.T(), err)
+	// and the dependencies 1 -> 2 -> 3 of the first type and 2 -> 1 of the second type
+	wi1, id1 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi2, id2 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	wi3, id3 := s.createWorkItem(s.childWITID, map[string]interface{}{})
+	_, err = s.repository.Create(s.ctx, id1, id2, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id2, id3, blocksType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	_, err = s.repository.Create(s.ctx, id2, id1, precedesType.ID, s.testIdentity.ID)
+	require.Nil(s.T(), err)
+	// when computing the critical path of 3
+	_, err = s.repository.CriticalPath(s.ctx, s.spaceID, wi3.ID)
+	// then the error names the cycle
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+	assert.Contains(s.T(), err.Error(), wi2.ID+" -> "+wi1.ID+" -> "+wi2.ID)
+}
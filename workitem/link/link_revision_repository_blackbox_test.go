@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/errors"
 	"github.com/almighty/almighty-core/gormsupport/cleaner"
 	"github.com/almighty/almighty-core/gormtestsupport"
 	"github.com/almighty/almighty-core/migration"
@@ -16,6 +17,7 @@ import (
 	"github.com/almighty/almighty-core/workitem"
 	"github.com/almighty/almighty-core/workitem/link"
 
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -191,3 +193,71 @@ func (s *revisionRepositoryBlackBoxTest) TestStoreWorkItemLinkRevisionsWhenDelet
 	assert.Equal(s.T(), s.targetWorkItemID, revision2.WorkItemLinkTargetID)
 	assert.Equal(s.T(), s.testLinkType1ID, revision2.WorkItemLinkTypeID)
 }
+
+func (s *revisionRepositoryBlackBoxTest) deleteSourceWorkItem() (workitem.WorkItemRepository, *workitem.DeletedWorkItem) {
+	workitemRepository := workitem.NewWorkItemRepository(s.DB)
+	sourceWorkItem, err := workitemRepository.LoadByID(s.ctx, strconv.FormatUint(s.sourceWorkItemID, 10))
+	require.Nil(s.T(), err)
+	err = workitemRepository.Delete(s.ctx, sourceWorkItem.SpaceID, sourceWorkItem.ID, s.testIdentity2.ID)
+	require.Nil(s.T(), err)
+	err = link.NewWorkItemLinkRepository(s.DB).DeleteRelatedLinks(s.ctx, sourceWorkItem.ID, s.testIdentity2.ID)
+	require.Nil(s.T(), err)
+	deleted, err := workitemRepository.LoadDeleted(s.ctx, sourceWorkItem.SpaceID, sourceWorkItem.ID)
+	require.Nil(s.T(), err)
+	_, err = workitemRepository.Restore(s.ctx, sourceWorkItem.SpaceID, sourceWorkItem.ID, s.testIdentity3.ID)
+	require.Nil(s.T(), err)
+	return workitemRepository, deleted
+}
+
+func (s *revisionRepositoryBlackBoxTest) TestStoreWorkItemLinkRevisionsWhenRestoringWorkItem() {
+	// given
+	linkRepository := link.NewWorkItemLinkRepository(s.DB)
+	workitemLink, err := linkRepository.Create(s.ctx, s.sourceWorkItemID, s.targetWorkItemID, s.testLinkType1ID, s.testIdentity1.ID)
+	require.Nil(s.T(), err)
+	_, deleted := s.deleteSourceWorkItem()
+	// when
+	err = linkRepository.RestoreRelatedLinks(s.ctx, deleted.ID, deleted.DeletedAt, s.testIdentity3.ID)
+	// then
+	require.Nil(s.T(), err)
+	restoredLink, err := linkRepository.Load(s.ctx, workitemLink.ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitemLink.Version+1, restoredLink.Version)
+	workitemLinkRevisions, err := s.revisionRepository.List(s.ctx, workitemLink.ID)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), workitemLinkRevisions, 3)
+	revision3 := workitemLinkRevisions[2]
+	assert.Equal(s.T(), link.RevisionTypeRestore, revision3.Type)
+	assert.Equal(s.T(), s.testIdentity3.ID, revision3.ModifierIdentity)
+	assert.Equal(s.T(), restoredLink.Version, revision3.WorkItemLinkVersion)
+}
+
+func (s *revisionRepositoryBlackBoxTest) TestRestoreWorkItemLinksConflictingWithNewParent() {
+	// given
+	linkRepository := link.NewWorkItemLinkRepository(s.DB)
+	sourceWorkItem, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.ctx, strconv.FormatUint(s.sourceWorkItemID, 10))
+	require.Nil(s.T(), err)
+	categoryName := "tree-category" + uuid.NewV4().String()
+	linkCategory, err := link.NewWorkItemLinkCategoryRepository(s.DB).Create(s.ctx, &categoryName, nil)
+	require.Nil(s.T(), err)
+	treeLinkType, err := link.NewWorkItemLinkTypeRepository(s.DB).Create(s.ctx, "test tree link type", nil, workitem.SystemBug, workitem.SystemBug, "parent of", "child of", link.TopologyTree, linkCategory.ID, sourceWorkItem.SpaceID)
+	require.Nil(s.T(), err)
+	_, err = linkRepository.Create(s.ctx, s.sourceWorkItemID, s.targetWorkItemID, treeLinkType.ID, s.testIdentity1.ID)
+	require.Nil(s.T(), err)
+	workitemRepository, deleted := s.deleteSourceWorkItem()
+	// the target gets a new parent while the source is deleted
+	otherParent, err := workitemRepository.Create(
+		s.ctx, sourceWorkItem.SpaceID, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "Other parent",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.testIdentity1.ID)
+	require.Nil(s.T(), err)
+	otherParentID, err := strconv.ParseUint(otherParent.ID, 10, 64)
+	require.Nil(s.T(), err)
+	_, err = linkRepository.Create(s.ctx, otherParentID, s.targetWorkItemID, treeLinkType.ID, s.testIdentity1.ID)
+	require.Nil(s.T(), err)
+	// when
+	err = linkRepository.RestoreRelatedLinks(s.ctx, deleted.ID, deleted.DeletedAt, s.testIdentity3.ID)
+	// then
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+}
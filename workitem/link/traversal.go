@@ -0,0 +1,39 @@
+package link
+
+import (
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/workitem"
+)
+
+// TraversalDirection tells in which direction the links are followed when
+// traversing the work items linked to a work item.
+type TraversalDirection string
+
+// Traversal directions
+const (
+	// TraversalForward follows the links from their source to their target,
+	// e.g. from a parent to its children or from a blocker to the blocked work items
+	TraversalForward TraversalDirection = "forward"
+	// TraversalReverse follows the links from their target to their source,
+	// e.g. from a child to its parents
+	TraversalReverse TraversalDirection = "reverse"
+)
+
+// CheckValidTraversalDirection returns nil if the given direction is valid;
+// otherwise a BadParameterError is returned.
+func CheckValidTraversalDirection(d TraversalDirection) error {
+	if d != TraversalForward && d != TraversalReverse {
+		return errors.NewBadParameterError("direction", d).Expected(string(TraversalForward) + "|" + string(TraversalReverse))
+	}
+	return nil
+}
+
+// TraversedWorkItem is a work item that was reached by a link traversal.
+type TraversedWorkItem struct {
+	workitem.WorkItem
+	// Depth is the number of links between the start work item and this work item
+	Depth int
+	// Path holds the IDs of the work items on the shortest path from the start
+	// work item to this work item, both included
+	Path []string
+}
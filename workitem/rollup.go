@@ -0,0 +1,110 @@
+package workitem
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/log"
+
+	"golang.org/x/net/context"
+)
+
+// parentChildLinkTypes selects the link types whose links connect a parent work item (the source)
+// to a child work item (the target)
+const parentChildLinkTypes = "SELECT id FROM work_item_link_types WHERE forward_name = 'parent of' AND deleted_at IS NULL"
+
+// RecomputeRollUps computes the roll-up fields of the work item with the given ID from its children and,
+// if they changed, the roll-up fields of its ancestors. Deleted work items are ignored.
+// returns InternalError
+func (r *GormWorkItemRepository) RecomputeRollUps(ctx context.Context, id uint64) error {
+	return r.recomputeRollUps(ctx, id, map[uint64]bool{})
+}
+
+// recomputeParentRollUps recomputes the roll-up fields of the parents of the work item with the given
+// ID, e.g. after the work item has been changed
+func (r *GormWorkItemRepository) recomputeParentRollUps(ctx context.Context, id uint64, visited map[uint64]bool) error {
+	var parentIDs []uint64
+	db := r.db.Table("work_item_links").Where("target_id = ? AND deleted_at IS NULL AND link_type_id IN ("+parentChildLinkTypes+")", id).Pluck("source_id", &parentIDs)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	for _, parentID := range parentIDs {
+		if err := r.recomputeRollUps(ctx, parentID, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormWorkItemRepository) recomputeRollUps(ctx context.Context, id uint64, visited map[uint64]bool) error {
+	// the visited work items guard against cycles of parent-child links
+	if visited[id] {
+		return nil
+	}
+	visited[id] = true
+	wi := WorkItemStorage{}
+	tx := r.db.First(&wi, id)
+	if tx.RecordNotFound() {
+		return nil
+	}
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error.Error())
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, wi.Type)
+	if err != nil {
+		return errors.NewInternalError(err.Error())
+	}
+	rollUps := map[string]RollUpType{}
+	for fieldName, fieldDef := range wiType.Fields {
+		if rollUp, ok := fieldDef.Type.(RollUpType); ok {
+			rollUps[fieldName] = rollUp
+		}
+	}
+	if len(rollUps) == 0 {
+		return nil
+	}
+	var children []WorkItemStorage
+	db := r.db.Where("id IN (SELECT target_id FROM work_item_links WHERE source_id = ? AND deleted_at IS NULL AND link_type_id IN ("+parentChildLinkTypes+"))", id).Find(&children)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	changed := map[string]interface{}{}
+	for fieldName, rollUp := range rollUps {
+		values := make([]interface{}, len(children))
+		for i, child := range children {
+			values[i] = child.Fields[rollUp.AggregatedField()]
+		}
+		value := rollUp.Compute(values)
+		if !reflect.DeepEqual(wi.Fields[fieldName], value) {
+			changed[fieldName] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	// only the computed values are written, so that concurrent changes of the other fields are not
+	// overwritten, and they neither change the version nor the update time of the work item
+	fieldNames := make([]string, 0, len(changed))
+	for fieldName := range changed {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+	fields := "coalesce(fields, '{}'::jsonb)"
+	var parameters []interface{}
+	for _, fieldName := range fieldNames {
+		value, err := json.Marshal(changed[fieldName])
+		if err != nil {
+			return errors.NewInternalError(err.Error())
+		}
+		fields = "jsonb_set(" + fields + ", ARRAY[?], ?::jsonb)"
+		parameters = append(parameters, fieldName, string(value))
+	}
+	db = r.db.Exec("UPDATE "+WorkItemStorage{}.TableName()+" SET fields = "+fields+" WHERE id = ?", append(parameters, id)...)
+	if db.Error != nil {
+		return errors.NewInternalError(db.Error.Error())
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": id}, "Roll-up fields of work item updated")
+	return r.recomputeParentRollUps(ctx, id, visited)
+}
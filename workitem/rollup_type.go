@@ -0,0 +1,157 @@
+package workitem
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/almighty/almighty-core/convert"
+	"github.com/almighty/almighty-core/errors"
+
+	errs "github.com/pkg/errors"
+)
+
+// RollUpFunction is the aggregation of the values of the children of a work item
+type RollUpFunction string
+
+// constants for the possible aggregations of roll-up fields
+const (
+	RollUpSum             RollUpFunction = "sum"
+	RollUpMin             RollUpFunction = "min"
+	RollUpMax             RollUpFunction = "max"
+	RollUpCount           RollUpFunction = "count"
+	RollUpPercentComplete RollUpFunction = "percent_complete"
+)
+
+// RollUpType describes a computed field whose value aggregates a field of the work items linked to
+// a work item as its children. The value is a float and is kept up to date by the work item and link
+// repositories, values given by users are ignored.
+type RollUpType struct {
+	SimpleType
+	Function RollUpFunction
+	// Field is the aggregated field of the children. For percent_complete it is the state field
+	// and defaults to system.state, it is not used by count.
+	Field string
+	// DoneValues are the values of the state field of the children that are complete for
+	// percent_complete, "closed" if empty
+	DoneValues []string
+}
+
+// Ensure RollUpType implements the Equaler interface
+var _ convert.Equaler = RollUpType{}
+var _ convert.Equaler = (*RollUpType)(nil)
+
+// Equal returns true if two RollUpType objects are equal; otherwise false is returned.
+func (self RollUpType) Equal(u convert.Equaler) bool {
+	other, ok := u.(RollUpType)
+	if !ok {
+		return false
+	}
+	if !self.SimpleType.Equal(other.SimpleType) {
+		return false
+	}
+	if self.Function != other.Function || self.Field != other.Field {
+		return false
+	}
+	return reflect.DeepEqual(self.DoneValues, other.DoneValues)
+}
+
+// Validate checks that the function is known and that the aggregated field is given if needed
+// returns BadParameterError
+func (fieldType RollUpType) Validate() error {
+	switch fieldType.Function {
+	case RollUpSum, RollUpMin, RollUpMax:
+		if fieldType.Field == "" {
+			return errors.NewBadParameterError("rollUp.field", fieldType.Field).Expected(fmt.Sprintf("the numeric field aggregated by %s", fieldType.Function))
+		}
+	case RollUpCount, RollUpPercentComplete:
+	default:
+		return errors.NewBadParameterError("rollUp.function", fieldType.Function).Expected("sum, min, max, count or percent_complete")
+	}
+	return nil
+}
+
+// AggregatedField returns the field of the children that is aggregated
+func (fieldType RollUpType) AggregatedField() string {
+	if fieldType.Function == RollUpPercentComplete && fieldType.Field == "" {
+		return SystemState
+	}
+	return fieldType.Field
+}
+
+// Compute aggregates the given values of the aggregated field of the children, one value per child.
+// The minimum, maximum and percent complete of work items without children are nil.
+func (fieldType RollUpType) Compute(values []interface{}) interface{} {
+	switch fieldType.Function {
+	case RollUpCount:
+		return float64(len(values))
+	case RollUpPercentComplete:
+		if len(values) == 0 {
+			return nil
+		}
+		doneValues := fieldType.DoneValues
+		if len(doneValues) == 0 {
+			doneValues = []string{SystemStateClosed}
+		}
+		done := 0
+		for _, value := range values {
+			for _, doneValue := range doneValues {
+				if value != nil && fmt.Sprint(value) == doneValue {
+					done++
+					break
+				}
+			}
+		}
+		return float64(100*done) / float64(len(values))
+	}
+	var result interface{}
+	if fieldType.Function == RollUpSum {
+		result = 0.0
+	}
+	for _, value := range values {
+		number, ok := toFloat(value)
+		if !ok {
+			continue
+		}
+		switch {
+		case result == nil:
+			result = number
+		case fieldType.Function == RollUpSum:
+			result = result.(float64) + number
+		case fieldType.Function == RollUpMin && number < result.(float64):
+			result = number
+		case fieldType.Function == RollUpMax && number > result.(float64):
+			result = number
+		}
+	}
+	return result
+}
+
+// toFloat returns the given integer or float value as a float
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// ConvertToModel implements the FieldType interface
+func (fieldType RollUpType) ConvertToModel(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	number, ok := toFloat(value)
+	if !ok {
+		return nil, errs.Errorf("value %v should be %s, but is %s", value, "float64", reflect.TypeOf(value).Name())
+	}
+	return number, nil
+}
+
+// ConvertFromModel implements the FieldType interface
+func (fieldType RollUpType) ConvertFromModel(value interface{}) (interface{}, error) {
+	return fieldType.ConvertToModel(value)
+}
@@ -0,0 +1,79 @@
+package workitem_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rollUp(function workitem.RollUpFunction, field string, doneValues ...string) workitem.RollUpType {
+	return workitem.RollUpType{
+		SimpleType: workitem.SimpleType{Kind: workitem.KindRollUp},
+		Function:   function,
+		Field:      field,
+		DoneValues: doneValues,
+	}
+}
+
+func TestRollUpTypeValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	assert.Nil(t, rollUp(workitem.RollUpSum, "points").Validate())
+	assert.Nil(t, rollUp(workitem.RollUpCount, "").Validate())
+	assert.Nil(t, rollUp(workitem.RollUpPercentComplete, "").Validate())
+	assert.IsType(t, errors.BadParameterError{}, rollUp(workitem.RollUpMax, "").Validate())
+	assert.IsType(t, errors.BadParameterError{}, rollUp("avg", "points").Validate())
+
+	assert.Equal(t, workitem.SystemState, rollUp(workitem.RollUpPercentComplete, "").AggregatedField())
+	assert.Equal(t, "points", rollUp(workitem.RollUpSum, "points").AggregatedField())
+}
+
+func TestRollUpTypeCompute(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	// values of the children as they are stored, missing and invalid values are ignored
+	values := []interface{}{3.0, 5, nil, "foo", 1.5}
+	assert.Equal(t, 9.5, rollUp(workitem.RollUpSum, "points").Compute(values))
+	assert.Equal(t, 1.5, rollUp(workitem.RollUpMin, "points").Compute(values))
+	assert.Equal(t, 5.0, rollUp(workitem.RollUpMax, "points").Compute(values))
+	assert.Equal(t, 5.0, rollUp(workitem.RollUpCount, "").Compute(values))
+
+	// work items without children
+	assert.Equal(t, 0.0, rollUp(workitem.RollUpSum, "points").Compute(nil))
+	assert.Nil(t, rollUp(workitem.RollUpMax, "points").Compute(nil))
+	assert.Equal(t, 0.0, rollUp(workitem.RollUpCount, "").Compute(nil))
+	assert.Nil(t, rollUp(workitem.RollUpPercentComplete, "").Compute(nil))
+
+	states := []interface{}{workitem.SystemStateClosed, workitem.SystemStateOpen, workitem.SystemStateResolved, nil}
+	assert.Equal(t, 25.0, rollUp(workitem.RollUpPercentComplete, "").Compute(states))
+	assert.Equal(t, 50.0, rollUp(workitem.RollUpPercentComplete, "", workitem.SystemStateClosed, workitem.SystemStateResolved).Compute(states))
+}
+
+func TestRollUpFieldDefinitionMarshalling(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	def := workitem.FieldDefinition{
+		Label: "Done",
+		Type:  rollUp(workitem.RollUpPercentComplete, workitem.SystemState, workitem.SystemStateResolved),
+	}
+	bytes, err := json.Marshal(def)
+	require.Nil(t, err)
+	unmarshalled := workitem.FieldDefinition{}
+	require.Nil(t, json.Unmarshal(bytes, &unmarshalled))
+	assert.Equal(t, def, unmarshalled)
+
+	converted, err := def.ConvertToModel("done", 5)
+	require.Nil(t, err)
+	assert.Equal(t, 5.0, converted)
+	_, err = def.ConvertToModel("done", "foo")
+	assert.NotNil(t, err)
+}
@@ -44,7 +44,7 @@ func (fieldType SimpleType) ConvertToModel(value interface{}) (interface{}, erro
 	}
 	valueType := reflect.TypeOf(value)
 	switch fieldType.GetKind() {
-	case KindString, KindUser, KindIteration, KindArea:
+	case KindString, KindUser, KindIteration, KindArea, KindLabel:
 		if valueType.Kind() != reflect.String {
 			return nil, errs.Errorf("value %v should be %s, but is %s", value, "string", valueType.Name())
 		}
@@ -114,7 +114,7 @@ func (fieldType SimpleType) ConvertFromModel(value interface{}) (interface{}, er
 	}
 	valueType := reflect.TypeOf(value)
 	switch fieldType.GetKind() {
-	case KindString, KindURL, KindUser, KindInteger, KindFloat, KindDuration, KindIteration, KindArea:
+	case KindString, KindURL, KindUser, KindInteger, KindFloat, KindDuration, KindIteration, KindArea, KindLabel:
 		return value, nil
 	case KindInstant:
 		return time.Unix(0, value.(int64)), nil
@@ -0,0 +1,124 @@
+package workitem
+
+import (
+	"strings"
+
+	"github.com/almighty/almighty-core/criteria"
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
+
+	errs "github.com/pkg/errors"
+)
+
+// defaultOrder is the order of work item lists if no sort fields are given.
+// It is also used to break ties between work items with equal sort keys.
+var defaultOrder = []gormsupport.OrderTerm{
+	{Expression: "execution_order", Descending: true, Key: gormsupport.KeyFloat},
+	{Expression: "id", Descending: true, Key: gormsupport.KeyInteger},
+}
+
+// SortField is one key of the order in which work items are listed
+type SortField struct {
+	Name       string
+	Descending bool
+}
+
+// ParseSort parses the value of a "sort" query parameter: a comma separated
+// list of field names, each optionally prefixed with "-" for descending order
+// (e.g. "-system.updated_at,system.title"). No sort fields are returned for a
+// nil or empty value.
+func ParseSort(sort *string) ([]SortField, error) {
+	if sort == nil || strings.TrimSpace(*sort) == "" {
+		return nil, nil
+	}
+	var result []SortField
+	for _, key := range strings.Split(*sort, ",") {
+		key = strings.TrimSpace(key)
+		field := SortField{Name: key}
+		if strings.HasPrefix(key, "-") {
+			field = SortField{Name: strings.TrimSpace(key[1:]), Descending: true}
+		} else if strings.HasPrefix(key, "+") {
+			field.Name = strings.TrimSpace(key[1:])
+		}
+		if field.Name == "" {
+			return nil, errors.NewBadParameterError("sort", *sort).Expected("comma separated list of field names, optionally prefixed with '-'")
+		}
+		result = append(result, field)
+	}
+	return result, nil
+}
+
+// String returns the sort field in the syntax accepted by ParseSort
+func (f SortField) String() string {
+	if f.Descending {
+		return "-" + f.Name
+	}
+	return f.Name
+}
+
+// FormatSort returns the given sort fields in the syntax accepted by ParseSort
+func FormatSort(sort []SortField) string {
+	keys := make([]string, len(sort))
+	for i, f := range sort {
+		keys[i] = f.String()
+	}
+	return strings.Join(keys, ",")
+}
+
+// CompileOrder returns an order clause for use with gorm.DB.Order(). JSON fields
+// are cast according to their kind in fieldKinds, so numbers and instants are not
+// compared as text. Work items without a value for a sort field are listed last.
+func CompileOrder(sort []SortField, fieldKinds map[string]Kind) (string, error) {
+	terms, err := orderTerms(sort, fieldKinds)
+	if err != nil {
+		return "", errs.WithStack(err)
+	}
+	return gormsupport.OrderClause(terms), nil
+}
+
+// orderTerms returns the terms of the order of a work item list. The default order
+// and the ID of the work items are appended to make the order unique, which is
+// required for keyset pagination.
+func orderTerms(sort []SortField, fieldKinds map[string]Kind) ([]gormsupport.OrderTerm, error) {
+	compiler := newExpressionCompiler(fieldKinds)
+	terms := make([]gormsupport.OrderTerm, 0, len(sort)+len(defaultOrder))
+	for _, s := range sort {
+		f := &criteria.FieldExpression{FieldName: s.Name}
+		t := compiler.operandType(f, nil)
+		var term interface{}
+		if !t.column && t.kind == KindMarkup {
+			// markup fields are sorted by their content
+			if compiler.checkFieldName(s.Name) {
+				term = compiler.jsonAccess(s.Name, "->") + "->>'content'"
+			}
+		} else {
+			term = compiler.fieldValue(f, t)
+		}
+		if term == nil {
+			return nil, errors.NewBadParameterError("sort", s.Name).Expected("field name without question marks")
+		}
+		terms = append(terms, gormsupport.OrderTerm{Expression: term.(string), Descending: s.Descending, Nullable: true, Key: orderKeyType(s.Name, t)})
+	}
+	return append(terms, defaultOrder...), nil
+}
+
+// orderKeyType returns the type of the values of a sort field as read by orderKeys, which
+// is needed to check the keys of a decoded cursor
+func orderKeyType(fieldName string, t operandType) gormsupport.KeyType {
+	if t.column && fieldName == "ID" {
+		return gormsupport.KeyInteger
+	}
+	switch t.kind {
+	case KindInteger, KindDuration, KindWorkitemReference:
+		return gormsupport.KeyInteger
+	case KindInstant:
+		if t.column {
+			return gormsupport.KeyTimestamp
+		}
+		// instants in the JSON fields are compared as nanoseconds since the epoch
+		return gormsupport.KeyInteger
+	case KindFloat:
+		return gormsupport.KeyFloat
+	}
+	return gormsupport.KeyText
+}
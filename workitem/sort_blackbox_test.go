@@ -0,0 +1,66 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/almighty/almighty-core/resource"
+	. "github.com/almighty/almighty-core/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	sort, err := ParseSort(nil)
+	require.Nil(t, err)
+	assert.Empty(t, sort)
+
+	value := " -system.updated_at, system.title,+system.order "
+	sort, err = ParseSort(&value)
+	require.Nil(t, err)
+	assert.Equal(t, []SortField{
+		{Name: SystemUpdatedAt, Descending: true},
+		{Name: SystemTitle},
+		{Name: SystemOrder},
+	}, sort)
+	assert.Equal(t, "-system.updated_at,system.title,system.order", FormatSort(sort))
+
+	for _, invalid := range []string{"system.title,", "-", ",system.title", "a,,b"} {
+		value := invalid
+		_, err := ParseSort(&value)
+		assert.NotNil(t, err, "expected error for %q", invalid)
+	}
+}
+
+func TestCompileOrder(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	kinds := map[string]Kind{
+		"points":          KindInteger,
+		"estimate":        KindFloat,
+		"due":             KindInstant,
+		SystemTitle:       KindString,
+		SystemDescription: KindMarkup,
+	}
+	testData := []struct {
+		sort     []SortField
+		expected string
+	}{
+		{nil, "execution_order desc, id desc"},
+		{[]SortField{{Name: SystemUpdatedAt, Descending: true}}, "updated_at desc nulls last, execution_order desc, id desc"},
+		{[]SortField{{Name: SystemTitle}}, "Fields->>'system.title' asc nulls last, execution_order desc, id desc"},
+		{[]SortField{{Name: "points", Descending: true}, {Name: "estimate"}},
+			"(Fields->>'points')::bigint desc nulls last, (Fields->>'estimate')::float8 asc nulls last, execution_order desc, id desc"},
+		{[]SortField{{Name: "due"}}, "(Fields->>'due')::bigint asc nulls last, execution_order desc, id desc"},
+		{[]SortField{{Name: SystemDescription}}, "Fields->'system.description'->>'content' asc nulls last, execution_order desc, id desc"},
+		{[]SortField{{Name: "unknown'field"}}, "Fields->>'unknown''field' asc nulls last, execution_order desc, id desc"},
+	}
+	for _, d := range testData {
+		order, err := CompileOrder(d.sort, kinds)
+		require.Nil(t, err)
+		assert.Equal(t, d.expected, order)
+	}
+	_, err := CompileOrder([]SortField{{Name: "what?"}}, kinds)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,3 @@
+// Package template provides the work item templates, which are named sets of field
+// values that a space defines to pre-fill the work items of a type when they are created.
+package template
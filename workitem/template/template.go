@@ -0,0 +1,79 @@
+package template
+
+import (
+	"strings"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
+	"github.com/almighty/almighty-core/workitem"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeTemplate is the JSON API type of work item templates
+const APIStringTypeTemplate = "workitemtemplates"
+
+// Template is a named set of field values that pre-fills the work items of a type created in a space
+type Template struct {
+	gormsupport.Lifecycle
+	ID             uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID        uuid.UUID `sql:"type:uuid"`
+	WorkItemTypeID uuid.UUID `sql:"type:uuid"`
+	Name           string
+	Description    string
+	// Fields are the pre-filled field values in the representation of the REST API layer,
+	// e.g. a markup description as a string and the assignees as a list of identity IDs
+	Fields  workitem.Fields `sql:"type:jsonb"`
+	Version int
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (t Template) TableName() string {
+	return "work_item_templates"
+}
+
+// Apply sets the fields of the template that have no value in the given fields of a new work item
+// of the given type. The values are converted from their JSON form like the default values of fields.
+func (t Template) Apply(wit workitem.WorkItemType, fields map[string]interface{}) {
+	for name, value := range t.Fields {
+		if fields[name] != nil {
+			continue
+		}
+		if fieldDef, ok := wit.Fields[name]; ok {
+			value = fieldDef.NormalizeValue(value)
+		}
+		fields[name] = value
+	}
+}
+
+// readOnlyFields are the fields of a work item that are set by the system and cannot be pre-filled
+var readOnlyFields = []string{
+	workitem.SystemCreator,
+	workitem.SystemCreatedAt,
+	workitem.SystemUpdatedAt,
+	workitem.SystemOrder,
+}
+
+// validate checks that the template has a name and that its fields are fields of the given work
+// item type with valid values
+func (t Template) validate(wit workitem.WorkItemType) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.NewBadParameterError("name", t.Name).Expected("not empty")
+	}
+	for name, value := range t.Fields {
+		fieldDef, ok := wit.Fields[name]
+		if !ok {
+			return errors.NewBadParameterError("fields", name).Expected("a field of the work item type " + wit.Name)
+		}
+		for _, readOnly := range readOnlyFields {
+			if name == readOnly {
+				return errors.NewBadParameterError("fields", name).Expected("a field that is not set by the system")
+			}
+		}
+		if _, err := fieldDef.ConvertToModel(name, fieldDef.NormalizeValue(value)); err != nil {
+			return errors.NewBadParameterError("fields."+name, value).Expected(err.Error())
+		}
+	}
+	return nil
+}
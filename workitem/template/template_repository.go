@@ -0,0 +1,144 @@
+package template
+
+import (
+	"time"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
+	"github.com/almighty/almighty-core/log"
+	"github.com/almighty/almighty-core/workitem"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/net/context"
+)
+
+// TemplateRepository encapsulates storage & retrieval of work item templates
+type TemplateRepository interface {
+	Create(ctx context.Context, t *Template) error
+	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Template, error)
+	Save(ctx context.Context, t Template) (*Template, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error
+	List(ctx context.Context, spaceID uuid.UUID, witID *uuid.UUID) ([]Template, error)
+}
+
+// NewTemplateRepository creates a new storage type.
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &GormTemplateRepository{db: db}
+}
+
+// GormTemplateRepository is the implementation of the storage interface for work item templates.
+type GormTemplateRepository struct {
+	db *gorm.DB
+}
+
+// validate checks the given template against its work item type
+func (r *GormTemplateRepository) validate(ctx context.Context, t Template) error {
+	wit, err := workitem.NewWorkItemTypeRepository(r.db).LoadTypeFromDB(ctx, t.WorkItemTypeID)
+	if err != nil {
+		return errors.NewBadParameterError("workItemTypeID", t.WorkItemTypeID).Expected("an existing work item type")
+	}
+	return t.validate(*wit)
+}
+
+// Create creates a new work item template
+// returns BadParameterError or InternalError
+func (r *GormTemplateRepository) Create(ctx context.Context, t *Template) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "create"}, time.Now())
+	if err := r.validate(ctx, *t); err != nil {
+		return errs.WithStack(err)
+	}
+	t.ID = uuid.NewV4()
+	if err := r.db.Create(t).Error; err != nil {
+		// (space_id, work_item_type_id, name) needs to be unique
+		if gormsupport.IsUniqueViolation(err, "work_item_templates_name_idx") {
+			return errors.NewBadParameterError("name", t.Name).Expected("unique")
+		}
+		log.Error(ctx, map[string]interface{}{
+			"template_id": t.ID,
+			"err":         err,
+		}, "unable to create the work item template")
+		return errors.NewInternalError(err.Error())
+	}
+	return nil
+}
+
+// Load returns the work item template of the given space with the given ID
+// returns NotFoundError or InternalError
+func (r *GormTemplateRepository) Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Template, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "get"}, time.Now())
+	var result Template
+	tx := r.db.Where("space_id = ? AND id = ?", spaceID, id).First(&result)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item template", id.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	return &result, nil
+}
+
+// Save updates the given work item template. Version must be the same as the one in the stored version
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormTemplateRepository) Save(ctx context.Context, t Template) (*Template, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "save"}, time.Now())
+	existing, err := r.Load(ctx, t.SpaceID, t.ID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// the type of the pre-filled work items cannot change
+	t.WorkItemTypeID = existing.WorkItemTypeID
+	if err := r.validate(ctx, t); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	oldVersion := t.Version
+	t.Version++
+	tx := r.db.Where("version = ?", oldVersion).Save(&t)
+	if tx.Error != nil {
+		if gormsupport.IsUniqueViolation(tx.Error, "work_item_templates_name_idx") {
+			return nil, errors.NewBadParameterError("name", t.Name).Expected("unique")
+		}
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"template_id": t.ID,
+	}, "work item template updated successfully")
+	return &t, nil
+}
+
+// Delete deletes the work item template of the given space with the given ID
+// returns NotFoundError or InternalError
+func (r *GormTemplateRepository) Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "delete"}, time.Now())
+	if id == uuid.Nil {
+		return errors.NewNotFoundError("work item template", id.String())
+	}
+	tx := r.db.Where("space_id = ?", spaceID).Delete(&Template{ID: id})
+	if tx.Error != nil {
+		return errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("work item template", id.String())
+	}
+	return nil
+}
+
+// List returns the work item templates of the given space ordered by name, only those of the given
+// work item type if it is not nil
+func (r *GormTemplateRepository) List(ctx context.Context, spaceID uuid.UUID, witID *uuid.UUID) ([]Template, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtemplate", "list"}, time.Now())
+	result := []Template{}
+	db := r.db.Where("space_id = ?", spaceID).Order("name, id")
+	if witID != nil {
+		db = db.Where("work_item_type_id = ?", *witID)
+	}
+	if err := db.Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return result, nil
+}
@@ -0,0 +1,186 @@
+package template_test
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	localerror "github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport/cleaner"
+	"github.com/almighty/almighty-core/gormtestsupport"
+	"github.com/almighty/almighty-core/migration"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/space"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/almighty/almighty-core/workitem/template"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestTemplateRepository struct {
+	gormtestsupport.DBTestSuite
+
+	clean   func()
+	ctx     context.Context
+	repo    template.TemplateRepository
+	spaceID uuid.UUID
+}
+
+func TestRunTemplateRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestTemplateRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../../config.yaml")})
+}
+
+// SetupSuite overrides the DBTestSuite's function but calls it before doing anything else
+// so that the system work item types exist.
+func (test *TestTemplateRepository) SetupSuite() {
+	test.DBTestSuite.SetupSuite()
+	test.ctx = migration.NewMigrationContext(context.Background())
+	test.DBTestSuite.PopulateDBTestSuite(test.ctx)
+}
+
+func (test *TestTemplateRepository) SetupTest() {
+	test.clean = cleaner.DeleteCreatedEntities(test.DB)
+	test.repo = template.NewTemplateRepository(test.DB)
+	s, err := space.NewRepository(test.DB).Create(test.ctx, &space.Space{
+		Name: "TestTemplateRepository " + uuid.NewV4().String(),
+	})
+	require.Nil(test.T(), err)
+	test.spaceID = s.ID
+}
+
+func (test *TestTemplateRepository) TearDownTest() {
+	test.clean()
+}
+
+func (test *TestTemplateRepository) newTemplate(name string) template.Template {
+	return template.Template{
+		SpaceID:        test.spaceID,
+		WorkItemTypeID: workitem.SystemBug,
+		Name:           name,
+		Description:    "A bug report",
+		Fields: workitem.Fields{
+			workitem.SystemState:       workitem.SystemStateNew,
+			workitem.SystemDescription: "## Steps to reproduce",
+		},
+	}
+}
+
+func (test *TestTemplateRepository) TestCreateAndLoad() {
+	// given
+	t := test.newTemplate("bug report")
+	// when
+	err := test.repo.Create(test.ctx, &t)
+	// then
+	require.Nil(test.T(), err)
+	require.NotEqual(test.T(), uuid.Nil, t.ID)
+	loaded, err := test.repo.Load(test.ctx, test.spaceID, t.ID)
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), t.Name, loaded.Name)
+	assert.Equal(test.T(), t.Description, loaded.Description)
+	assert.Equal(test.T(), workitem.SystemBug, loaded.WorkItemTypeID)
+	assert.Equal(test.T(), t.Fields, loaded.Fields)
+	// a template is only found in its space
+	_, err = test.repo.Load(test.ctx, space.SystemSpace, t.ID)
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+}
+
+func (test *TestTemplateRepository) TestCreateInvalid() {
+	// unnamed templates
+	t := test.newTemplate(" ")
+	err := test.repo.Create(test.ctx, &t)
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(err))
+	// unknown work item types
+	t = test.newTemplate("unknown type")
+	t.WorkItemTypeID = uuid.NewV4()
+	err = test.repo.Create(test.ctx, &t)
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(err))
+	// fields of another work item type
+	t = test.newTemplate("unknown field")
+	t.Fields["foo"] = "bar"
+	err = test.repo.Create(test.ctx, &t)
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(err))
+	// fields set by the system
+	t = test.newTemplate("creator")
+	t.Fields[workitem.SystemCreator] = uuid.NewV4().String()
+	err = test.repo.Create(test.ctx, &t)
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(err))
+	// invalid field values
+	t = test.newTemplate("invalid state")
+	t.Fields[workitem.SystemState] = "foo"
+	err = test.repo.Create(test.ctx, &t)
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(err))
+	// duplicate names for the same type in the same space
+	t = test.newTemplate("bug report")
+	require.Nil(test.T(), test.repo.Create(test.ctx, &t))
+	t = test.newTemplate("bug report")
+	err = test.repo.Create(test.ctx, &t)
+	assert.IsType(test.T(), localerror.BadParameterError{}, errors.Cause(err))
+}
+
+func (test *TestTemplateRepository) TestSave() {
+	// given
+	t := test.newTemplate("bug report")
+	require.Nil(test.T(), test.repo.Create(test.ctx, &t))
+	// when
+	t.Name = "crash report"
+	t.WorkItemTypeID = workitem.SystemFeature
+	saved, err := test.repo.Save(test.ctx, t)
+	// then the work item type is kept
+	require.Nil(test.T(), err)
+	assert.Equal(test.T(), "crash report", saved.Name)
+	assert.Equal(test.T(), workitem.SystemBug, saved.WorkItemTypeID)
+	assert.Equal(test.T(), t.Version+1, saved.Version)
+	// when saving an outdated version
+	_, err = test.repo.Save(test.ctx, t)
+	// then
+	assert.IsType(test.T(), localerror.VersionConflictError{}, errors.Cause(err))
+}
+
+func (test *TestTemplateRepository) TestListAndDelete() {
+	// given
+	bug := test.newTemplate("b")
+	require.Nil(test.T(), test.repo.Create(test.ctx, &bug))
+	feature := test.newTemplate("a")
+	feature.WorkItemTypeID = workitem.SystemFeature
+	require.Nil(test.T(), test.repo.Create(test.ctx, &feature))
+	// when
+	all, err := test.repo.List(test.ctx, test.spaceID, nil)
+	// then the templates are ordered by name
+	require.Nil(test.T(), err)
+	require.Len(test.T(), all, 2)
+	assert.Equal(test.T(), feature.ID, all[0].ID)
+	assert.Equal(test.T(), bug.ID, all[1].ID)
+	bugs, err := test.repo.List(test.ctx, test.spaceID, &workitem.SystemBug)
+	require.Nil(test.T(), err)
+	require.Len(test.T(), bugs, 1)
+	assert.Equal(test.T(), bug.ID, bugs[0].ID)
+	// when
+	err = test.repo.Delete(test.ctx, test.spaceID, bug.ID)
+	// then
+	require.Nil(test.T(), err)
+	_, err = test.repo.Load(test.ctx, test.spaceID, bug.ID)
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+	err = test.repo.Delete(test.ctx, test.spaceID, bug.ID)
+	assert.IsType(test.T(), localerror.NotFoundError{}, errors.Cause(err))
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given a template with values as they are decoded from JSON
+	wit := workitem.WorkItemType{Fields: map[string]workitem.FieldDefinition{
+		"effort": {Type: workitem.SimpleType{Kind: workitem.KindInteger}},
+		"size":   {Type: workitem.SimpleType{Kind: workitem.KindInteger}},
+	}}
+	tmpl := template.Template{Fields: workitem.Fields{"effort": 3.0, "size": 2.0}}
+	fields := map[string]interface{}{"size": 5}
+	// when
+	tmpl.Apply(wit, fields)
+	// then the missing fields are filled with converted values, given values are kept
+	assert.Equal(t, map[string]interface{}{"effort": 3, "size": 5}, fields)
+}
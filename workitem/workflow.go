@@ -0,0 +1,155 @@
+package workitem
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/almighty/almighty-core/convert"
+	"github.com/almighty/almighty-core/errors"
+)
+
+// Workflow restricts the changes of the value of an enum field of a work item type (usually the
+// state) to a set of transitions. A work item type without transitions has no workflow.
+type Workflow struct {
+	// Field is the name of the enum field whose changes are restricted
+	Field string `json:"field"`
+	// Transitions are the allowed changes of the field value
+	Transitions []Transition `json:"transitions"`
+}
+
+// Transition allows to change the value of the workflow field from one enum value to another
+type Transition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// RequiredFields must have a value for the transition to be taken, e.g. the assignees of a work
+	// item that is set in progress
+	RequiredFields []string `json:"required_fields,omitempty"`
+}
+
+// Ensure Workflow implements the Equaler interface
+var _ convert.Equaler = Workflow{}
+var _ convert.Equaler = (*Workflow)(nil)
+
+// Equal returns true if two Workflow objects are equal; otherwise false is returned.
+func (w Workflow) Equal(u convert.Equaler) bool {
+	other, ok := u.(Workflow)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(w, other)
+}
+
+// IsEmpty returns true if the workflow has no transitions, in which case any change is allowed
+func (w Workflow) IsEmpty() bool {
+	return len(w.Transitions) == 0
+}
+
+// Value implements the driver.Valuer interface, an empty workflow is stored as NULL
+func (w Workflow) Value() (driver.Value, error) {
+	if w.IsEmpty() {
+		return nil, nil
+	}
+	return toBytes(w)
+}
+
+// Scan implements the sql.Scanner interface
+func (w *Workflow) Scan(src interface{}) error {
+	if src == nil {
+		*w = Workflow{}
+		return nil
+	}
+	return fromBytes(src, w)
+}
+
+// Validate checks that the workflow field is an enum field of the given field definitions and that the
+// transitions only refer to its values and to existing fields
+// returns BadParameterError
+func (w Workflow) Validate(fields FieldDefinitions) error {
+	if w.IsEmpty() {
+		return nil
+	}
+	fieldDef, ok := fields[w.Field]
+	if !ok {
+		return errors.NewBadParameterError("workflow.field", w.Field).Expected("a field of the work item type")
+	}
+	var enum *EnumType
+	switch t := fieldDef.Type.(type) {
+	case EnumType:
+		enum = &t
+	case *EnumType:
+		enum = t
+	default:
+		return errors.NewBadParameterError("workflow.field", w.Field).Expected("an enum field")
+	}
+	for _, transition := range w.Transitions {
+		for _, value := range []string{transition.From, transition.To} {
+			if !contains(enum.Values, value) {
+				return errors.NewBadParameterError("workflow.transitions", value).Expected(fmt.Sprintf("a value of the field %s", w.Field))
+			}
+		}
+		for _, required := range transition.RequiredFields {
+			if _, ok := fields[required]; !ok {
+				return errors.NewBadParameterError("workflow.transitions.required_fields", required).Expected("a field of the work item type")
+			}
+		}
+	}
+	return nil
+}
+
+// NextStates returns the values the workflow field can be changed to from the given value, in the order
+// of the transitions
+func (w Workflow) NextStates(from string) []string {
+	var next []string
+	for _, transition := range w.Transitions {
+		if transition.From == from {
+			next = append(next, transition.To)
+		}
+	}
+	return next
+}
+
+// CheckTransition verifies that the workflow allows to change the stored fields of a work item from the
+// given old value to the new fields. Fields that had no value before can be set to any value.
+// returns BadParameterError
+func (w Workflow) CheckTransition(oldValue interface{}, newFields Fields) error {
+	if w.IsEmpty() || oldValue == nil {
+		return nil
+	}
+	from := fmt.Sprint(oldValue)
+	to := fmt.Sprint(newFields[w.Field])
+	if from == to {
+		return nil
+	}
+	for _, transition := range w.Transitions {
+		if transition.From != from || transition.To != to {
+			continue
+		}
+		for _, required := range transition.RequiredFields {
+			if isEmptyFieldValue(newFields[required]) {
+				return errors.NewBadParameterError(required, newFields[required]).Expected(fmt.Sprintf("a value to change %s from '%s' to '%s'", w.Field, from, to))
+			}
+		}
+		return nil
+	}
+	next := w.NextStates(from)
+	if len(next) == 0 {
+		return errors.NewBadParameterError(w.Field, to).Expected(fmt.Sprintf("no change, '%s' is a final state", from))
+	}
+	return errors.NewBadParameterError(w.Field, to).Expected(fmt.Sprintf("one of the next states of '%s': %s", from, strings.Join(next, ", ")))
+}
+
+// isEmptyFieldValue returns true if the given stored field value is missing, an empty string or an empty list
+func isEmptyFieldValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem"
+
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWorkflow = workitem.Workflow{
+	Field: workitem.SystemState,
+	Transitions: []workitem.Transition{
+		{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+		{From: workitem.SystemStateOpen, To: workitem.SystemStateInProgress, RequiredFields: []string{workitem.SystemAssignees}},
+		{From: workitem.SystemStateOpen, To: workitem.SystemStateClosed},
+		{From: workitem.SystemStateInProgress, To: workitem.SystemStateClosed},
+	},
+}
+
+var testWorkflowFields = workitem.FieldDefinitions{
+	workitem.SystemState: {
+		Type: workitem.EnumType{
+			SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
+			BaseType:   workitem.SimpleType{Kind: workitem.KindString},
+			Values: []interface{}{
+				workitem.SystemStateNew,
+				workitem.SystemStateOpen,
+				workitem.SystemStateInProgress,
+				workitem.SystemStateClosed,
+			},
+		},
+	},
+	workitem.SystemTitle: {Type: workitem.SimpleType{Kind: workitem.KindString}},
+	workitem.SystemAssignees: {
+		Type: workitem.ListType{
+			SimpleType:    workitem.SimpleType{Kind: workitem.KindList},
+			ComponentType: workitem.SimpleType{Kind: workitem.KindUser},
+		},
+	},
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	assert.Nil(t, testWorkflow.Validate(testWorkflowFields))
+	assert.Nil(t, workitem.Workflow{}.Validate(testWorkflowFields))
+
+	unknownField := workitem.Workflow{Field: "foo", Transitions: testWorkflow.Transitions}
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(unknownField.Validate(testWorkflowFields)))
+
+	notEnum := workitem.Workflow{Field: workitem.SystemTitle, Transitions: testWorkflow.Transitions}
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(notEnum.Validate(testWorkflowFields)))
+
+	unknownValue := workitem.Workflow{
+		Field:       workitem.SystemState,
+		Transitions: []workitem.Transition{{From: workitem.SystemStateNew, To: "foo"}},
+	}
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(unknownValue.Validate(testWorkflowFields)))
+
+	unknownRequiredField := workitem.Workflow{
+		Field:       workitem.SystemState,
+		Transitions: []workitem.Transition{{From: workitem.SystemStateNew, To: workitem.SystemStateOpen, RequiredFields: []string{"foo"}}},
+	}
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(unknownRequiredField.Validate(testWorkflowFields)))
+}
+
+func TestWorkflowNextStates(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	assert.Equal(t, []string{workitem.SystemStateInProgress, workitem.SystemStateClosed}, testWorkflow.NextStates(workitem.SystemStateOpen))
+	assert.Empty(t, testWorkflow.NextStates(workitem.SystemStateClosed))
+}
+
+func TestWorkflowCheckTransition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	fields := func(state string, assignees ...interface{}) workitem.Fields {
+		return workitem.Fields{
+			workitem.SystemState:     state,
+			workitem.SystemAssignees: assignees,
+		}
+	}
+	// allowed transitions and unchanged or initial values
+	assert.Nil(t, testWorkflow.CheckTransition(workitem.SystemStateNew, fields(workitem.SystemStateOpen)))
+	assert.Nil(t, testWorkflow.CheckTransition(workitem.SystemStateClosed, fields(workitem.SystemStateClosed)))
+	assert.Nil(t, testWorkflow.CheckTransition(nil, fields(workitem.SystemStateClosed)))
+	assert.Nil(t, workitem.Workflow{}.CheckTransition(workitem.SystemStateClosed, fields(workitem.SystemStateNew)))
+	assert.Nil(t, testWorkflow.CheckTransition(workitem.SystemStateOpen, fields(workitem.SystemStateInProgress, "jdoe")))
+
+	// the error names the allowed next states
+	err := testWorkflow.CheckTransition(workitem.SystemStateNew, fields(workitem.SystemStateClosed))
+	require.NotNil(t, err)
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	assert.Contains(t, err.Error(), "one of the next states of 'new': open")
+
+	err = testWorkflow.CheckTransition(workitem.SystemStateClosed, fields(workitem.SystemStateNew))
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "'closed' is a final state")
+
+	// required fields must be set
+	err = testWorkflow.CheckTransition(workitem.SystemStateOpen, fields(workitem.SystemStateInProgress))
+	require.NotNil(t, err)
+	assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	assert.Contains(t, err.Error(), workitem.SystemAssignees)
+}
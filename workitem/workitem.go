@@ -17,10 +17,19 @@ type WorkItem struct {
 	Version int
 	// ID of the space to which this work item belongs
 	SpaceID uuid.UUID
+	// The human-readable key of the work item in its space, e.g. "PLAT-42"
+	Key string
 	// The field values, according to the field type
 	Fields map[string]interface{}
 }
 
+// DeletedWorkItem is a work item in the trash of its space
+type DeletedWorkItem struct {
+	WorkItem
+	// the time when the work item was deleted
+	DeletedAt time.Time
+}
+
 // WICountsPerIteration counting work item states by iteration
 type WICountsPerIteration struct {
 	IterationId string `gorm:"column:iterationid"`
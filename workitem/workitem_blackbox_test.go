@@ -67,6 +67,11 @@ func TestWorkItem_Equal(t *testing.T) {
 	i.SpaceID = uuid.NewV4()
 	assert.False(t, a.Equal(i))
 
+	// Test key difference
+	k := a
+	k.Key = "PLAT-42"
+	assert.False(t, a.Equal(k))
+
 	j := workitem.WorkItemStorage{
 		ID:      0,
 		Type:    a.Type,
@@ -2,6 +2,8 @@ package workitem
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -9,6 +11,7 @@ import (
 
 	"github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
 	"github.com/almighty/almighty-core/log"
 	"github.com/almighty/almighty-core/rendering"
 
@@ -32,15 +35,23 @@ const (
 type WorkItemRepository interface {
 	LoadByID(ctx context.Context, ID string) (*WorkItem, error)
 	Load(ctx context.Context, spaceID uuid.UUID, ID string) (*WorkItem, error)
+	LoadByKey(ctx context.Context, spaceID uuid.UUID, key string) (*WorkItem, error)
 	Save(ctx context.Context, spaceID uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Reorder(ctx context.Context, direction DirectionType, targetID *string, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Delete(ctx context.Context, spaceID uuid.UUID, ID string, suppressorID uuid.UUID) error
 	Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, error)
-	List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, start *int, length *int) ([]WorkItem, uint64, error)
+	List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []SortField, start *int, length *int) ([]WorkItem, uint64, error)
+	ListAfter(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []SortField, cursor *string, limit int) ([]WorkItem, uint64, *string, error)
 	Fetch(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*WorkItem, error)
 	GetCountsPerIteration(ctx context.Context, spaceID uuid.UUID) (map[string]WICountsPerIteration, error)
 	GetCountsForIteration(ctx context.Context, iterationID uuid.UUID) (map[string]WICountsPerIteration, error)
 	Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error)
+	LoadRevision(ctx context.Context, spaceID uuid.UUID, revision Revision) (*WorkItem, error)
+	Revert(ctx context.Context, spaceID uuid.UUID, workitemID string, revisionID uuid.UUID, version int, modifierID uuid.UUID) (*WorkItem, error)
+	ListDeleted(ctx context.Context, spaceID uuid.UUID, start *int, limit *int) ([]DeletedWorkItem, uint64, error)
+	LoadDeleted(ctx context.Context, spaceID uuid.UUID, workitemID string) (*DeletedWorkItem, error)
+	Restore(ctx context.Context, spaceID uuid.UUID, workitemID string, restorerID uuid.UUID) (*WorkItem, error)
+	PurgeDeleted(ctx context.Context, before time.Time) ([]string, error)
 }
 
 // NewWorkItemRepository creates a GormWorkItemRepository
@@ -138,6 +149,62 @@ func (r *GormWorkItemRepository) Load(ctx context.Context, spaceID uuid.UUID, wo
 	return ConvertWorkItemStorageToModel(wiType, &res)
 }
 
+// LoadByKey returns the work item with the given human-readable key, e.g. "PLAT-42", in the given space
+// returns NotFoundError, ConversionError or InternalError
+func (r *GormWorkItemRepository) LoadByKey(ctx context.Context, spaceID uuid.UUID, key string) (*WorkItem, error) {
+	normalized, ok := ParseKey(key)
+	if !ok {
+		return nil, errors.NewNotFoundError("work item", key)
+	}
+	res := WorkItemStorage{}
+	tx := r.db.Model(&res).Where("key=? AND space_id=?", normalized, spaceID).First(&res)
+	if tx.RecordNotFound() {
+		log.Error(ctx, map[string]interface{}{
+			"wi_key":   key,
+			"space_id": spaceID,
+		}, "work item not found")
+		return nil, errors.NewNotFoundError("work item", key)
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, res.Type)
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	return ConvertWorkItemStorageToModel(wiType, &res)
+}
+
+// nextKey returns the key of the next work item created in the given space. The number of the key is
+// taken from a counter per space, so that concurrent transactions never hand out the same key.
+// returns BadParameterError or InternalError
+func (r *GormWorkItemRepository) nextKey(ctx context.Context, spaceID uuid.UUID) (string, error) {
+	var prefix struct {
+		KeyPrefix string
+	}
+	db := r.db.Table("spaces").Select("key_prefix").Where("id = ? AND deleted_at IS NULL", spaceID).Scan(&prefix)
+	if db.RecordNotFound() {
+		return "", errors.NewBadParameterError("spaceID", spaceID)
+	}
+	if db.Error != nil {
+		return "", errors.NewInternalError(db.Error.Error())
+	}
+	var number struct {
+		LastNumber int64
+	}
+	db = r.db.Raw(`INSERT INTO work_item_numbers (space_id, last_number) VALUES (?, 1)
+		ON CONFLICT (space_id) DO UPDATE SET last_number = work_item_numbers.last_number + 1
+		RETURNING last_number`, spaceID).Scan(&number)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"space_id": spaceID,
+			"err":      db.Error,
+		}, "unable to number the work item")
+		return "", errors.NewInternalError(db.Error.Error())
+	}
+	return fmt.Sprintf("%s-%d", prefix.KeyPrefix, number.LastNumber), nil
+}
+
 // LoadTopWorkitem returns top most work item of the list. Top most workitem has the Highest order.
 // returns NotFoundError, ConversionError or InternalError
 func (r *GormWorkItemRepository) LoadTopWorkitem(ctx context.Context) (*WorkItem, error) {
@@ -211,10 +278,180 @@ func (r *GormWorkItemRepository) Delete(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	if err := r.recomputeParentRollUps(ctx, id, map[uint64]bool{}); err != nil {
+		return errs.WithStack(err)
+	}
 	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID, "space_id": spaceID}, "Work item deleted successfully!")
 	return nil
 }
 
+// ListDeleted returns the deleted work items of the given space, most recently deleted first,
+// starting with start (zero-based) and returning at most limit items
+// returns BadParameterError, ConversionError or InternalError
+func (r *GormWorkItemRepository) ListDeleted(ctx context.Context, spaceID uuid.UUID, start *int, limit *int) ([]DeletedWorkItem, uint64, error) {
+	db := r.db.Unscoped().Model(&WorkItemStorage{}).Where("space_id = ? AND deleted_at IS NOT NULL", spaceID)
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(err.Error())
+	}
+	if start != nil {
+		if *start < 0 {
+			return nil, 0, errors.NewBadParameterError("start", *start)
+		}
+		db = db.Offset(*start)
+	}
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, 0, errors.NewBadParameterError("limit", *limit)
+		}
+		db = db.Limit(*limit)
+	}
+	items := []WorkItemStorage{}
+	if err := db.Order("deleted_at desc, id desc").Find(&items).Error; err != nil {
+		return nil, 0, errors.NewInternalError(err.Error())
+	}
+	res := make([]DeletedWorkItem, len(items))
+	for index := range items {
+		deleted, err := r.convertDeletedStorageToModel(ctx, &items[index])
+		if err != nil {
+			return nil, 0, errs.WithStack(err)
+		}
+		res[index] = *deleted
+	}
+	return res, count, nil
+}
+
+// LoadDeleted returns the deleted work item with the given ID or human-readable key of the given space
+// returns NotFoundError, DataConflictError, ConversionError or InternalError
+func (r *GormWorkItemRepository) LoadDeleted(ctx context.Context, spaceID uuid.UUID, workitemID string) (*DeletedWorkItem, error) {
+	res, err := r.loadDeletedFromDB(ctx, spaceID, workitemID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return r.convertDeletedStorageToModel(ctx, res)
+}
+
+// loadDeletedFromDB returns the deleted work item with the given ID or human-readable key of the given space.
+// A DataConflictError is returned if the work item exists but has not been deleted.
+func (r *GormWorkItemRepository) loadDeletedFromDB(ctx context.Context, spaceID uuid.UUID, workitemID string) (*WorkItemStorage, error) {
+	res := WorkItemStorage{}
+	tx := r.db.Unscoped()
+	if key, ok := ParseKey(workitemID); ok {
+		tx = tx.Where("key = ? AND space_id = ?", key, spaceID)
+	} else {
+		id, err := strconv.ParseUint(workitemID, 10, 64)
+		if err != nil || id == 0 {
+			// treating this as a not found error: the fact that we're using number internal is implementation detail
+			return nil, errors.NewNotFoundError("work item", workitemID)
+		}
+		tx = tx.Where("id = ? AND space_id = ?", id, spaceID)
+	}
+	tx = tx.First(&res)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item", workitemID)
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	if res.DeletedAt == nil {
+		return nil, errors.NewDataConflictError(fmt.Sprintf("work item %s is not deleted", workitemID))
+	}
+	return &res, nil
+}
+
+func (r *GormWorkItemRepository) convertDeletedStorageToModel(ctx context.Context, item *WorkItemStorage) (*DeletedWorkItem, error) {
+	wiType, err := r.witr.LoadTypeFromDB(ctx, item.Type)
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	wi, err := ConvertWorkItemStorageToModel(wiType, item)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return &DeletedWorkItem{WorkItem: *wi, DeletedAt: *item.DeletedAt}, nil
+}
+
+// Restore moves the deleted work item with the given ID out of the trash and stores a new revision of it.
+// The links of the work item are not restored, see the work item link repository for this.
+// returns NotFoundError, DataConflictError, ConversionError or InternalError
+func (r *GormWorkItemRepository) Restore(ctx context.Context, spaceID uuid.UUID, workitemID string, restorerID uuid.UUID) (*WorkItem, error) {
+	res, err := r.loadDeletedFromDB(ctx, spaceID, workitemID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// the work item may be identified by its key
+	id := strconv.FormatUint(res.ID, 10)
+	wiType, err := r.witr.LoadTypeFromDB(ctx, res.Type)
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	tx := r.db.Unscoped().Model(res).Where("version = ?", res.Version).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    res.Version + 1,
+	})
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":    id,
+			"space_id": spaceID,
+			"err":      tx.Error,
+		}, "unable to restore the work item")
+		return nil, errors.NewInternalError(tx.Error.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	// the children may have changed while the work item was deleted and its parents aggregate it again
+	visited := map[uint64]bool{}
+	if err := r.recomputeRollUps(ctx, res.ID, visited); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := r.recomputeParentRollUps(ctx, res.ID, visited); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	restored, err := r.LoadFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	// store a revision of the restored work item
+	err = r.wirr.Create(context.Background(), restorerID, RevisionTypeRestore, *restored)
+	if err != nil {
+		return nil, errs.Wrapf(err, "error while restoring work item")
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":    id,
+		"space_id": spaceID,
+	}, "Work item restored")
+	return ConvertWorkItemStorageToModel(wiType, restored)
+}
+
+// PurgeDeleted permanently deletes the work items that were deleted before the given time, along with
+// their links and revisions. It returns the IDs of the purged work items.
+// returns InternalError
+func (r *GormWorkItemRepository) PurgeDeleted(ctx context.Context, before time.Time) ([]string, error) {
+	var ids []uint64
+	db := r.db.Unscoped().Model(&WorkItemStorage{}).Where("deleted_at < ?", before).Pluck("id", &ids)
+	if db.Error != nil {
+		return nil, errors.NewInternalError(db.Error.Error())
+	}
+	if len(ids) == 0 {
+		return []string{}, nil
+	}
+	// links and revisions of the work items are deleted by the database
+	db = r.db.Unscoped().Where("id IN (?)", ids).Delete(&WorkItemStorage{})
+	if db.Error != nil {
+		return nil, errors.NewInternalError(db.Error.Error())
+	}
+	res := make([]string, len(ids))
+	for index, id := range ids {
+		res[index] = strconv.FormatUint(id, 10)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"before": before,
+		"wi_ids": res,
+	}, "Purged deleted work items")
+	return res, nil
+}
+
 // Calculates the order of the reorder workitem
 func (r *GormWorkItemRepository) CalculateOrder(above, below *float64) float64 {
 	return (*above + *below) / 2
@@ -277,6 +514,7 @@ func (r *GormWorkItemRepository) FindFirstItem(id string) (*float64, error) {
 // The order of workitems are spaced by a factor of 1000.
 // The new order of workitem := (order of previousitem + order of nextitem)/2
 // Version must be the same as the one int the stored version
+// Only the order of the workitem changes, the other fields of the given workitem are ignored
 func (r *GormWorkItemRepository) Reorder(ctx context.Context, direction DirectionType, targetID *string, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error) {
 	var order float64
 	res := WorkItemStorage{}
@@ -297,9 +535,9 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, direction Directio
 		return nil, errors.NewVersionConflictError("version conflict")
 	}
 
-	wiType, err := r.witr.LoadTypeFromDB(ctx, wi.Type)
+	wiType, err := r.witr.LoadTypeFromDB(ctx, res.Type)
 	if err != nil {
-		return nil, errors.NewBadParameterError("Type", wi.Type)
+		return nil, errors.NewInternalError(err.Error())
 	}
 
 	switch direction {
@@ -371,23 +609,10 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, direction Directio
 	default:
 		return &wi, nil
 	}
+	// only the order changes, the stored fields are kept so that reordering can neither bypass
+	// the workflow nor the field rules of the work item type
 	res.Version = res.Version + 1
-	res.Type = wi.Type
-	res.Fields = Fields{}
-
 	res.ExecutionOrder = order
-
-	for fieldName, fieldDef := range wiType.Fields {
-		if fieldName == SystemCreatedAt || fieldName == SystemUpdatedAt || fieldName == SystemOrder {
-			continue
-		}
-		fieldValue := wi.Fields[fieldName]
-		var err error
-		res.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
-		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
-		}
-	}
 	tx = tx.Where("Version = ?", wi.Version).Save(&res)
 	if err := tx.Error; err != nil {
 		return nil, errors.NewInternalError(err.Error())
@@ -403,6 +628,75 @@ func (r *GormWorkItemRepository) Reorder(ctx context.Context, direction Directio
 	return ConvertWorkItemStorageToModel(wiType, &res)
 }
 
+// fieldConversionError reports the failed conversion of a field value, keeping the detailed error of a
+// violated field rule
+func fieldConversionError(fieldName string, fieldValue interface{}, err error) error {
+	if _, ok := errs.Cause(err).(errors.BadParameterError); ok {
+		return err
+	}
+	return errors.NewBadParameterError(fieldName, fieldValue)
+}
+
+// LoadRevision returns the work item as it was stored by the given revision. The creation time and the order are
+// taken from the current work item since revisions do not store them, the update time is the time of the revision.
+// returns NotFoundError, ConversionError or InternalError
+func (r *GormWorkItemRepository) LoadRevision(ctx context.Context, spaceID uuid.UUID, revision Revision) (*WorkItem, error) {
+	workitemID := strconv.FormatUint(revision.WorkItemID, 10)
+	res, err := r.LoadFromDB(ctx, workitemID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if !uuid.Equal(res.SpaceID, spaceID) || revision.Type == RevisionTypeDelete {
+		return nil, errors.NewNotFoundError("work item", workitemID)
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, revision.WorkItemTypeID)
+	if err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	res.Type = revision.WorkItemTypeID
+	res.Version = revision.WorkItemVersion
+	res.Fields = revision.WorkItemFields
+	res.UpdatedAt = revision.Time
+	return ConvertWorkItemStorageToModel(wiType, res)
+}
+
+// Revert restores the fields of the work item from the given revision and stores them as a new revision.
+// Version must be the same as the one in the stored version. The type, the creation time and the order
+// of the work item are not changed.
+// returns NotFoundError, VersionConflictError, BadParameterError, ConversionError or InternalError
+func (r *GormWorkItemRepository) Revert(ctx context.Context, spaceID uuid.UUID, workitemID string, revisionID uuid.UUID, version int, modifierID uuid.UUID) (*WorkItem, error) {
+	current, err := r.Load(ctx, spaceID, workitemID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	revision, err := r.wirr.Load(ctx, workitemID, revisionID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if revision.Type == RevisionTypeDelete {
+		return nil, errors.NewBadParameterError("revision", revisionID.String()).Expected("a revision that created or updated the work item")
+	}
+	restored, err := r.LoadRevision(ctx, spaceID, *revision)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	reverted := *current
+	reverted.Version = version
+	reverted.Fields = map[string]interface{}{}
+	for name, value := range restored.Fields {
+		reverted.Fields[name] = value
+	}
+	for _, name := range []string{SystemCreatedAt, SystemUpdatedAt, SystemOrder} {
+		reverted.Fields[name] = current.Fields[name]
+	}
+	log.Info(ctx, map[string]interface{}{
+		"wi_id":       workitemID,
+		"space_id":    spaceID,
+		"revision_id": revisionID,
+	}, "Reverting work item")
+	return r.Save(ctx, spaceID, reverted, modifierID)
+}
+
 // Save updates the given work item in storage. Version must be the same as the one int the stored version
 // returns NotFoundError, VersionConflictError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error) {
@@ -435,22 +729,32 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, wi
 	if err != nil {
 		return nil, errors.NewBadParameterError("Type", wi.Type)
 	}
+	fieldKindsCache.Use(spaceID, wiType.ID)
 
 	res.Version = res.Version + 1
 	res.Type = wi.Type
+	oldFields := res.Fields
 	res.Fields = Fields{}
 	res.ExecutionOrder = wi.Fields[SystemOrder].(float64)
 	for fieldName, fieldDef := range wiType.Fields {
 		if fieldName == SystemCreatedAt || fieldName == SystemUpdatedAt || fieldName == SystemOrder {
 			continue
 		}
+		if fieldDef.Type.GetKind() == KindRollUp {
+			// computed fields keep their value
+			res.Fields[fieldName] = oldFields[fieldName]
+			continue
+		}
 		fieldValue := wi.Fields[fieldName]
 		var err error
 		res.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
+			return nil, fieldConversionError(fieldName, fieldValue, err)
 		}
 	}
+	if err := wiType.Workflow.CheckTransition(oldFields[wiType.Workflow.Field], res.Fields); err != nil {
+		return nil, err
+	}
 
 	tx = tx.Where("Version = ?", wi.Version).Save(&res)
 	if err := tx.Error; err != nil {
@@ -469,6 +773,10 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, wi
 	if err != nil {
 		return nil, errs.Wrapf(err, "error while saving work item")
 	}
+	// the parents may aggregate the changed fields
+	if err := r.recomputeParentRollUps(ctx, res.ID, map[uint64]bool{}); err != nil {
+		return nil, errs.WithStack(err)
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wi_id":    wi.ID,
 		"space_id": spaceID,
@@ -476,13 +784,15 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, wi
 	return ConvertWorkItemStorageToModel(wiType, &res)
 }
 
-// Create creates a new work item in the repository
+// Create creates a new work item in the repository. Fields without a value are set to the default
+// value of their definition.
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, error) {
 	wiType, err := r.witr.LoadTypeFromDB(ctx, typeID)
 	if err != nil {
 		return nil, errors.NewBadParameterError("typeID", typeID)
 	}
+	fieldKindsCache.Use(spaceID, typeID)
 
 	// The order of workitems are spaced by a factor of 1000.
 	pos, err := r.LoadHighestOrder()
@@ -490,22 +800,36 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 		return nil, errors.NewInternalError(err.Error())
 	}
 	pos = pos + orderValue
+	key, err := r.nextKey(ctx, spaceID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
 	wi := WorkItemStorage{
 		Type:           typeID,
 		Fields:         Fields{},
 		ExecutionOrder: pos,
 		SpaceID:        spaceID,
+		Key:            key,
 	}
 	fields[SystemCreator] = creatorID.String()
 	for fieldName, fieldDef := range wiType.Fields {
 		if fieldName == SystemCreatedAt || fieldName == SystemUpdatedAt || fieldName == SystemOrder {
 			continue
 		}
+		if rollUp, ok := fieldDef.Type.(RollUpType); ok {
+			// a new work item has no children
+			wi.Fields[fieldName] = rollUp.Compute(nil)
+			continue
+		}
 		fieldValue := fields[fieldName]
+		if fieldValue == nil && fieldDef.DefaultValue != nil {
+			// defaults are stored as read from JSON, given values are converted as they are
+			fieldValue = fieldDef.NormalizeValue(fieldDef.DefaultValue)
+		}
 		var err error
 		wi.Fields[fieldName], err = fieldDef.ConvertToModel(fieldName, fieldValue)
 		if err != nil {
-			return nil, errors.NewBadParameterError(fieldName, fieldValue)
+			return nil, fieldConversionError(fieldName, fieldValue, err)
 		}
 		if fieldName == SystemDescription && wi.Fields[fieldName] != nil {
 			description := rendering.NewMarkupContentFromMap(wi.Fields[fieldName].(map[string]interface{}))
@@ -551,16 +875,91 @@ func ConvertWorkItemStorageToModel(wiType *WorkItemType, wi *WorkItemStorage) (*
 
 }
 
-// extracted this function from List() in order to close the rows object with "defer" for more readability
-// workaround for https://github.com/lib/pq/issues/81
-func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, start *int, limit *int) ([]WorkItemStorage, uint64, error) {
-	where, parameters, compileError := Compile(criteria)
+// fieldKindsCache holds the kinds of the fields used per space, see FieldKinds
+var fieldKindsCache = NewFieldKindsCache()
+
+// FieldKinds returns the kinds of the fields of all work item types that are used in the given space.
+// For enum fields the kind of the enum values is returned and for roll-up fields the float kind.
+// Fields that are defined with different kinds by different types are left out, so that their
+// values are compared as strings, and the conflict is logged. The returned map is shared and must
+// not be modified.
+func (r *GormWorkItemRepository) FieldKinds(ctx context.Context, spaceID uuid.UUID) (map[string]Kind, error) {
+	if result, ok := fieldKindsCache.Get(spaceID); ok {
+		return result, nil
+	}
+	var typeIDs []uuid.UUID
+	db := r.db.Model(&WorkItemStorage{}).Where("space_id = ?", spaceID).Pluck("DISTINCT type", &typeIDs)
+	if db.Error != nil {
+		return nil, errors.NewInternalError(db.Error.Error())
+	}
+	result := map[string]Kind{}
+	definedBy := map[string]uuid.UUID{}
+	conflicts := map[string]bool{}
+	for _, typeID := range typeIDs {
+		wiType, err := r.witr.LoadTypeFromDB(ctx, typeID)
+		if err != nil {
+			return nil, errors.NewInternalError(err.Error())
+		}
+		for fieldName, fieldDef := range wiType.Fields {
+			var kind Kind
+			switch t := fieldDef.Type.(type) {
+			case EnumType:
+				kind = t.BaseType.GetKind()
+			case RollUpType:
+				// roll-up values are compared like floats
+				kind = KindFloat
+			default:
+				kind = fieldDef.Type.GetKind()
+			}
+			if existing, ok := result[fieldName]; ok && existing != kind {
+				log.Warn(ctx, map[string]interface{}{
+					"space_id": spaceID,
+					"field":    fieldName,
+					"wit_ids":  []uuid.UUID{definedBy[fieldName], typeID},
+					"kinds":    []Kind{existing, kind},
+				}, "field is defined with different kinds by the work item types of the space")
+				conflicts[fieldName] = true
+			}
+			if !conflicts[fieldName] {
+				result[fieldName] = kind
+				definedBy[fieldName] = typeID
+			}
+		}
+	}
+	for fieldName := range conflicts {
+		delete(result, fieldName)
+	}
+	fieldKindsCache.Put(spaceID, typeIDs, result)
+	return result, nil
+}
+
+// listQuery returns the query for the work items in the given space that match the given
+// criteria.Expression, along with the terms of the order given by the sort fields
+func (r *GormWorkItemRepository) listQuery(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []SortField) (*gorm.DB, []gormsupport.OrderTerm, error) {
+	fieldKinds, err := r.FieldKinds(ctx, spaceID)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
+	}
+	where, parameters, compileError := CompileWithFieldKinds(criteria, fieldKinds)
 	if compileError != nil {
-		return nil, 0, errors.NewBadParameterError("expression", criteria)
+		return nil, nil, errors.NewBadParameterError("expression", criteria)
+	}
+	terms, err := orderTerms(sort, fieldKinds)
+	if err != nil {
+		return nil, nil, errs.WithStack(err)
 	}
 	where = where + " AND space_id = ?"
 	parameters = append(parameters, spaceID)
-	db := r.db.Model(&WorkItemStorage{}).Where(where, parameters...)
+	return r.db.Model(&WorkItemStorage{}).Where(where, parameters...), terms, nil
+}
+
+// extracted this function from List() in order to close the rows object with "defer" for more readability
+// workaround for https://github.com/lib/pq/issues/81
+func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []SortField, start *int, limit *int) ([]WorkItemStorage, uint64, error) {
+	db, terms, err := r.listQuery(ctx, spaceID, criteria, sort)
+	if err != nil {
+		return nil, 0, errs.WithStack(err)
+	}
 	orgDB := db
 	if start != nil {
 		if *start < 0 {
@@ -575,7 +974,7 @@ func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uu
 		db = db.Limit(*limit)
 	}
 
-	db = db.Select("count(*) over () as cnt2 , *").Order("execution_order desc")
+	db = db.Select("count(*) over () as cnt2 , *").Order(gormsupport.OrderClause(terms))
 
 	rows, err := db.Rows()
 	if err != nil {
@@ -627,30 +1026,124 @@ func (r *GormWorkItemRepository) listItemsFromDB(ctx context.Context, spaceID uu
 	return result, count, nil
 }
 
-// List returns work item selected by the given criteria.Expression, starting with start (zero-based) and returning at most limit items
-func (r *GormWorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, start *int, limit *int) ([]WorkItem, uint64, error) {
-	result, count, err := r.listItemsFromDB(ctx, spaceID, criteria, start, limit)
+// List returns work item selected by the given criteria.Expression, ordered by the given sort fields,
+// starting with start (zero-based) and returning at most limit items
+func (r *GormWorkItemRepository) List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []SortField, start *int, limit *int) ([]WorkItem, uint64, error) {
+	result, count, err := r.listItemsFromDB(ctx, spaceID, criteria, sort, start, limit)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
-	res := make([]WorkItem, len(result))
-	for index, value := range result {
+	res, err := r.convertStorageToModel(ctx, result)
+	if err != nil {
+		return nil, 0, errs.WithStack(err)
+	}
+	return res, count, nil
+}
+
+// ListAfter returns at most limit work items selected by the given criteria.Expression, ordered by the
+// given sort fields and following the work item the given cursor points to. The list starts with the
+// first work item if there is no cursor. It also returns the total number of matching work items and
+// a cursor pointing to the last returned work item, if more work items follow.
+func (r *GormWorkItemRepository) ListAfter(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, sort []SortField, cursor *string, limit int) ([]WorkItem, uint64, *string, error) {
+	if limit <= 0 {
+		return nil, 0, nil, errors.NewBadParameterError("limit", limit)
+	}
+	db, terms, err := r.listQuery(ctx, spaceID, criteria, sort)
+	if err != nil {
+		return nil, 0, nil, errs.WithStack(err)
+	}
+	var count uint64
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, nil, errors.NewInternalError(err.Error())
+	}
+	order := FormatSort(sort)
+	if cursor != nil && *cursor != "" {
+		c, err := gormsupport.DecodeCursor(*cursor)
+		if err != nil {
+			return nil, 0, nil, errs.WithStack(err)
+		}
+		if c.Order != order {
+			return nil, 0, nil, errors.NewBadParameterError("page[cursor]", *cursor).Expected("cursor for sort order '" + order + "'")
+		}
+		// a tampered cursor must not cause a database error
+		c, err = c.ConvertKeys(terms)
+		if err != nil {
+			return nil, 0, nil, errs.WithStack(err)
+		}
+		where, parameters, err := gormsupport.KeysetCondition(terms, *c)
+		if err != nil {
+			return nil, 0, nil, errs.WithStack(err)
+		}
+		db = db.Where(where, parameters...)
+	}
+	// fetch one more item to find out whether another page follows
+	var result []WorkItemStorage
+	if err := db.Order(gormsupport.OrderClause(terms)).Limit(limit + 1).Find(&result).Error; err != nil {
+		return nil, 0, nil, errors.NewInternalError(err.Error())
+	}
+	var next *string
+	if len(result) > limit {
+		result = result[:limit]
+		keys, err := r.orderKeys(terms, result[limit-1].ID)
+		if err != nil {
+			return nil, 0, nil, errs.WithStack(err)
+		}
+		token := gormsupport.Cursor{Order: order, Keys: keys}.Encode()
+		next = &token
+	}
+	res, err := r.convertStorageToModel(ctx, result)
+	if err != nil {
+		return nil, 0, nil, errs.WithStack(err)
+	}
+	return res, count, next, nil
+}
+
+// orderKeys returns the values of the given order terms for the work item with the given ID
+func (r *GormWorkItemRepository) orderKeys(terms []gormsupport.OrderTerm, id uint64) ([]interface{}, error) {
+	expressions := make([]string, len(terms))
+	keys := make([]interface{}, len(terms))
+	destinations := make([]interface{}, len(terms))
+	for i, t := range terms {
+		expressions[i] = t.Expression
+		destinations[i] = &keys[i]
+	}
+	row := r.db.Model(&WorkItemStorage{}).Select(strings.Join(expressions, ", ")).Where("id = ?", id).Row()
+	if err := row.Scan(destinations...); err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	for i, key := range keys {
+		// text and uuid values may be returned as bytes
+		if b, ok := key.([]byte); ok {
+			keys[i] = string(b)
+		}
+	}
+	return keys, nil
+}
+
+// convertStorageToModel converts the given work items to their model representation
+func (r *GormWorkItemRepository) convertStorageToModel(ctx context.Context, items []WorkItemStorage) ([]WorkItem, error) {
+	res := make([]WorkItem, len(items))
+	for index, value := range items {
 		wiType, err := r.witr.LoadTypeFromDB(ctx, value.Type)
 		if err != nil {
-			return nil, 0, errors.NewInternalError(err.Error())
+			return nil, errors.NewInternalError(err.Error())
 		}
 		modelWI, err := ConvertWorkItemStorageToModel(wiType, &value)
 		if err != nil {
-			return nil, 0, errors.NewInternalError(err.Error())
+			return nil, errors.NewInternalError(err.Error())
 		}
 		res[index] = *modelWI
 	}
-	return res, count, nil
+	return res, nil
 }
 
 // Counts returns the amount of work item that satisfy the given criteria.Expression
 func (r *GormWorkItemRepository) Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error) {
-	where, parameters, compileError := Compile(criteria)
+	fieldKinds, err := r.FieldKinds(ctx, spaceID)
+	if err != nil {
+		return 0, errs.WithStack(err)
+	}
+	where, parameters, compileError := CompileWithFieldKinds(criteria, fieldKinds)
 	if compileError != nil {
 		return 0, errors.NewBadParameterError("expression", criteria)
 	}
@@ -665,7 +1158,7 @@ func (r *GormWorkItemRepository) Count(ctx context.Context, spaceID uuid.UUID, c
 // Fetch fetches the (first) work item matching by the given criteria.Expression.
 func (r *GormWorkItemRepository) Fetch(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*WorkItem, error) {
 	limit := 1
-	results, count, err := r.List(ctx, spaceID, criteria, nil, &limit)
+	results, count, err := r.List(ctx, spaceID, criteria, nil, nil, &limit)
 	if err != nil {
 		return nil, err
 	}
@@ -2,10 +2,14 @@ package workitem_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/almighty/almighty-core/codebase"
+	"github.com/almighty/almighty-core/criteria"
 	"github.com/almighty/almighty-core/errors"
+	"github.com/almighty/almighty-core/gormsupport"
 	"github.com/almighty/almighty-core/gormsupport/cleaner"
 	"github.com/almighty/almighty-core/gormtestsupport"
 	"github.com/almighty/almighty-core/iteration"
@@ -301,3 +305,508 @@ func (s *workItemRepoBlackBoxTest) TestCodebaseAttributes() {
 	assert.Equal(s.T(), file, cb.FileName)
 	assert.Equal(s.T(), line, cb.LineNumber)
 }
+
+func (s *workItemRepoBlackBoxTest) TestListWithRelationalOperators() {
+	// given
+	title := "relational operators " + uuid.NewV4().String()
+	assigned, err := s.repo.Create(
+		s.ctx, s.spaceID, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle:     title,
+			workitem.SystemState:     workitem.SystemStateNew,
+			workitem.SystemAssignees: []string{"A"},
+		}, s.creatorID)
+	require.Nil(s.T(), err)
+	unassigned, err := s.repo.Create(
+		s.ctx, s.spaceID, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: title,
+			workitem.SystemState: workitem.SystemStateOpen,
+		}, s.creatorID)
+	require.Nil(s.T(), err)
+	sameTitle := criteria.Equals(criteria.Field(workitem.SystemTitle), criteria.Literal(title))
+	list := func(exp criteria.Expression) []string {
+		items, _, err := s.repo.List(s.ctx, s.spaceID, criteria.And(sameTitle, exp), nil, nil, nil)
+		require.Nil(s.T(), err)
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.ID)
+		}
+		return result
+	}
+	// the database stores timestamps with less precision than time.Time
+	before := assigned.Fields[workitem.SystemCreatedAt].(time.Time).Add(-time.Second)
+	// when/then
+	assert.Len(s.T(), list(criteria.GreaterThanOrEqual(criteria.Field(workitem.SystemCreatedAt), criteria.Literal(before))), 2)
+	assert.Len(s.T(), list(criteria.LessThan(criteria.Field(workitem.SystemCreatedAt), criteria.Literal(before))), 0)
+	assert.Equal(s.T(), []string{unassigned.ID}, list(criteria.IsNull(criteria.Field(workitem.SystemAssignees))))
+	assert.Equal(s.T(), []string{assigned.ID}, list(criteria.IsNotNull(criteria.Field(workitem.SystemAssignees))))
+	assert.Equal(s.T(), []string{assigned.ID}, list(criteria.In(criteria.Field(workitem.SystemAssignees), criteria.Literal([]string{"A", "C"}))))
+	assert.Equal(s.T(), []string{unassigned.ID}, list(criteria.In(criteria.Field(workitem.SystemState), criteria.Literal([]string{workitem.SystemStateOpen, workitem.SystemStateClosed}))))
+	assert.Len(s.T(), list(criteria.Substring(criteria.Field(workitem.SystemTitle), criteria.Literal("RELATIONAL"))), 2)
+	assert.Len(s.T(), list(criteria.ILike(criteria.Field(workitem.SystemTitle), criteria.Literal("operators%"))), 0)
+}
+
+func (s *workItemRepoBlackBoxTest) TestListSorted() {
+	// given
+	marker := "sorted " + uuid.NewV4().String()
+	create := func(title string, state string) string {
+		wi, err := s.repo.Create(
+			s.ctx, s.spaceID, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: marker + " " + title,
+				workitem.SystemState: state,
+			}, s.creatorID)
+		require.Nil(s.T(), err)
+		return wi.ID
+	}
+	b := create("b", workitem.SystemStateOpen)
+	a := create("a", workitem.SystemStateNew)
+	c := create("c", workitem.SystemStateNew)
+	list := func(sort string) []string {
+		sortFields, err := workitem.ParseSort(&sort)
+		require.Nil(s.T(), err)
+		items, _, err := s.repo.List(s.ctx, s.spaceID, criteria.Substring(criteria.Field(workitem.SystemTitle), criteria.Literal(marker)), sortFields, nil, nil)
+		require.Nil(s.T(), err)
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.ID)
+		}
+		return result
+	}
+	// when/then
+	assert.Equal(s.T(), []string{c, a, b}, list(""))
+	assert.Equal(s.T(), []string{a, b, c}, list("system.title"))
+	assert.Equal(s.T(), []string{c, b, a}, list("-system.title"))
+	assert.Equal(s.T(), []string{a, c, b}, list("system.state,system.title"))
+	assert.Equal(s.T(), []string{c, a, b}, list("system.state,-system.created_at"))
+	assert.Equal(s.T(), []string{b, a, c}, list("system.order"))
+}
+
+func (s *workItemRepoBlackBoxTest) TestListAfterCursor() {
+	// given
+	marker := "paged " + uuid.NewV4().String()
+	for _, title := range []string{"c", "a", "d", "b"} {
+		_, err := s.repo.Create(
+			s.ctx, s.spaceID, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: marker + " " + title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.creatorID)
+		require.Nil(s.T(), err)
+	}
+	exp := criteria.Substring(criteria.Field(workitem.SystemTitle), criteria.Literal(marker))
+	sort := []workitem.SortField{{Name: workitem.SystemTitle}}
+	// when paging through all items
+	var titles []string
+	var cursor *string
+	for i := 0; i < 3; i++ {
+		items, count, next, err := s.repo.ListAfter(s.ctx, s.spaceID, exp, sort, cursor, 3)
+		require.Nil(s.T(), err)
+		assert.Equal(s.T(), uint64(4), count)
+		for _, item := range items {
+			titles = append(titles, item.Fields[workitem.SystemTitle].(string))
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	// then
+	assert.Equal(s.T(), []string{marker + " a", marker + " b", marker + " c", marker + " d"}, titles)
+	// when the cursor is used with a different order
+	_, _, _, err := s.repo.ListAfter(s.ctx, s.spaceID, exp, []workitem.SortField{{Name: workitem.SystemTitle, Descending: true}}, cursor, 3)
+	// then
+	assert.NotNil(s.T(), err)
+	// when the keys of the cursor have been tampered with
+	for _, keys := range [][]interface{}{
+		{marker, "not a number", 1},
+		{marker, 1.5},
+		{marker, 1.5, 1, 2},
+		{marker, nil, 1},
+	} {
+		tampered := gormsupport.Cursor{Order: workitem.SystemTitle, Keys: keys}.Encode()
+		_, _, _, err = s.repo.ListAfter(s.ctx, s.spaceID, exp, sort, &tampered, 3)
+		// then
+		assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err), "expected error for keys %v", keys)
+	}
+}
+
+func (s *workItemRepoBlackBoxTest) TestLoadRevisionAndRevert() {
+	// given
+	wi, err := s.repo.Create(
+		s.ctx, s.spaceID, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.creatorID)
+	require.Nil(s.T(), err)
+	wi.Fields[workitem.SystemTitle] = "Accidentally changed"
+	wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+	wi, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	require.Nil(s.T(), err)
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.ctx, wi.ID)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), revisions, 2)
+	// when
+	old, err := s.repo.LoadRevision(s.ctx, s.spaceID, revisions[0])
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), wi.ID, old.ID)
+	assert.Equal(s.T(), 0, old.Version)
+	assert.Equal(s.T(), "Title", old.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), workitem.SystemStateNew, old.Fields[workitem.SystemState])
+	assert.Equal(s.T(), wi.Fields[workitem.SystemOrder], old.Fields[workitem.SystemOrder])
+	_, err = s.repo.LoadRevision(s.ctx, uuid.NewV4(), revisions[0])
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	// when reverting an outdated version
+	_, err = s.repo.Revert(s.ctx, s.spaceID, wi.ID, revisions[0].ID, 0, s.creatorID)
+	// then
+	require.IsType(s.T(), errors.VersionConflictError{}, errs.Cause(err))
+	// when
+	reverted, err := s.repo.Revert(s.ctx, s.spaceID, wi.ID, revisions[0].ID, wi.Version, s.creatorID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), wi.Version+1, reverted.Version)
+	assert.Equal(s.T(), "Title", reverted.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), workitem.SystemStateNew, reverted.Fields[workitem.SystemState])
+	revisions, err = workitem.NewRevisionRepository(s.DB).List(s.ctx, wi.ID)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), revisions, 3)
+	assert.Equal(s.T(), workitem.RevisionTypeUpdate, revisions[2].Type)
+	assert.Equal(s.T(), "Title", revisions[2].WorkItemFields[workitem.SystemTitle])
+	// when reverting a deleted work item
+	err = s.repo.Delete(s.ctx, s.spaceID, wi.ID, s.creatorID)
+	require.Nil(s.T(), err)
+	_, err = s.repo.Revert(s.ctx, s.spaceID, wi.ID, revisions[0].ID, reverted.Version, s.creatorID)
+	// then
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+}
+
+func (s *workItemRepoBlackBoxTest) TestListAndRestoreDeleted() {
+	// given
+	spaceInstance := space.Space{
+		Name: "Trash space" + uuid.NewV4().String(),
+	}
+	_, err := space.NewRepository(s.DB).Create(s.ctx, &spaceInstance)
+	require.Nil(s.T(), err)
+	var items []*workitem.WorkItem
+	for _, title := range []string{"Kept", "Deleted first", "Deleted last"} {
+		wi, err := s.repo.Create(
+			s.ctx, spaceInstance.ID, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.creatorID)
+		require.Nil(s.T(), err)
+		items = append(items, wi)
+	}
+	require.Nil(s.T(), s.repo.Delete(s.ctx, spaceInstance.ID, items[1].ID, s.creatorID))
+	require.Nil(s.T(), s.repo.Delete(s.ctx, spaceInstance.ID, items[2].ID, s.creatorID))
+	// when
+	deleted, count, err := s.repo.ListDeleted(s.ctx, spaceInstance.ID, nil, nil)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(2), count)
+	require.Len(s.T(), deleted, 2)
+	assert.Equal(s.T(), items[2].ID, deleted[0].ID)
+	assert.Equal(s.T(), items[1].ID, deleted[1].ID)
+	assert.Equal(s.T(), "Deleted first", deleted[1].Fields[workitem.SystemTitle])
+	assert.False(s.T(), deleted[1].DeletedAt.IsZero())
+	limit := 1
+	deleted, count, err = s.repo.ListDeleted(s.ctx, spaceInstance.ID, nil, &limit)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(2), count)
+	require.Len(s.T(), deleted, 1)
+	// when
+	restored, err := s.repo.Restore(s.ctx, spaceInstance.ID, items[1].ID, s.creatorID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), items[1].Version+1, restored.Version)
+	assert.Equal(s.T(), "Deleted first", restored.Fields[workitem.SystemTitle])
+	loaded, err := s.repo.Load(s.ctx, spaceInstance.ID, items[1].ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), restored.Version, loaded.Version)
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.ctx, items[1].ID)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), revisions, 3)
+	assert.Equal(s.T(), workitem.RevisionTypeRestore, revisions[2].Type)
+	assert.Equal(s.T(), "Deleted first", revisions[2].WorkItemFields[workitem.SystemTitle])
+	_, count, err = s.repo.ListDeleted(s.ctx, spaceInstance.ID, nil, nil)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), uint64(1), count)
+	// when restoring a work item that is not deleted
+	_, err = s.repo.Restore(s.ctx, spaceInstance.ID, items[0].ID, s.creatorID)
+	// then
+	require.IsType(s.T(), errors.DataConflictError{}, errs.Cause(err))
+	// when restoring a work item of another space
+	_, err = s.repo.Restore(s.ctx, s.spaceID, items[2].ID, s.creatorID)
+	// then
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	// when restoring a work item by its key
+	require.NotEmpty(s.T(), items[2].Key)
+	restored, err = s.repo.Restore(s.ctx, spaceInstance.ID, items[2].Key, s.creatorID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), items[2].ID, restored.ID)
+	assert.Equal(s.T(), items[2].Version+1, restored.Version)
+}
+
+func (s *workItemRepoBlackBoxTest) TestPurgeDeleted() {
+	// given
+	var items []*workitem.WorkItem
+	for _, title := range []string{"Expired", "Recently deleted"} {
+		wi, err := s.repo.Create(
+			s.ctx, s.spaceID, workitem.SystemBug,
+			map[string]interface{}{
+				workitem.SystemTitle: title,
+				workitem.SystemState: workitem.SystemStateNew,
+			}, s.creatorID)
+		require.Nil(s.T(), err)
+		require.Nil(s.T(), s.repo.Delete(s.ctx, s.spaceID, wi.ID, s.creatorID))
+		items = append(items, wi)
+	}
+	err := s.DB.Unscoped().Model(&workitem.WorkItemStorage{}).Where("id = ?", items[0].ID).UpdateColumn("deleted_at", time.Now().Add(-48*time.Hour)).Error
+	require.Nil(s.T(), err)
+	// when
+	purged, err := s.repo.PurgeDeleted(s.ctx, time.Now().Add(-24*time.Hour))
+	// then
+	require.Nil(s.T(), err)
+	assert.Contains(s.T(), purged, items[0].ID)
+	assert.NotContains(s.T(), purged, items[1].ID)
+	_, err = s.repo.LoadDeleted(s.ctx, s.spaceID, items[0].ID)
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.ctx, items[0].ID)
+	require.Nil(s.T(), err)
+	assert.Empty(s.T(), revisions)
+	deleted, err := s.repo.LoadDeleted(s.ctx, s.spaceID, items[1].ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), "Recently deleted", deleted.Fields[workitem.SystemTitle])
+}
+
+func (s *workItemRepoBlackBoxTest) TestSaveEnforcesWorkflow() {
+	// given a work item type with a workflow
+	witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+	wit, err := witRepo.Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "workflow "+uuid.NewV4().String(), nil, "fa-bomb", map[string]workitem.FieldDefinition{})
+	require.Nil(s.T(), err)
+	_, err = witRepo.SetWorkflow(s.ctx, s.spaceID, wit.ID, wit.Version, workitem.Workflow{
+		Field: workitem.SystemState,
+		Transitions: []workitem.Transition{
+			{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+			{From: workitem.SystemStateNew, To: workitem.SystemStateResolved},
+			{From: workitem.SystemStateOpen, To: workitem.SystemStateInProgress, RequiredFields: []string{workitem.SystemAssignees}},
+		},
+	})
+	require.Nil(s.T(), err)
+	wi, err := s.repo.Create(
+		s.ctx, s.spaceID, wit.ID,
+		map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.creatorID)
+	require.Nil(s.T(), err)
+	// when skipping a state
+	wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+	_, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	// then
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+	assert.Contains(s.T(), err.Error(), "open, resolved")
+	// when following the workflow
+	wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+	wi, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	require.Nil(s.T(), err)
+	// when missing a required field
+	wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+	_, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+	// when setting the required field
+	wi.Fields[workitem.SystemAssignees] = []string{s.creatorID.String()}
+	wi, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.SystemStateInProgress, wi.Fields[workitem.SystemState])
+	// when reordering with changed fields
+	wi.Fields[workitem.SystemState] = workitem.SystemStateNew
+	wi.Fields[workitem.SystemTitle] = "Reordered"
+	reordered, err := s.repo.Reorder(s.ctx, workitem.DirectionBottom, nil, *wi, s.creatorID)
+	// then only the order changes
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.SystemStateInProgress, reordered.Fields[workitem.SystemState])
+	assert.Equal(s.T(), "Title", reordered.Fields[workitem.SystemTitle])
+	assert.Equal(s.T(), wi.Version+1, reordered.Version)
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateAndSaveEnforceFieldRules() {
+	// given a work item type with a constrained field
+	maxEffort := 40.0
+	wit, err := workitem.NewWorkItemTypeRepository(s.DB).Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "rules "+uuid.NewV4().String(), nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		"effort": {
+			Label: "Effort",
+			Type:  workitem.SimpleType{Kind: workitem.KindInteger},
+			Rules: &workitem.FieldRules{Max: &maxEffort},
+		},
+	})
+	require.Nil(s.T(), err)
+	fields := map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+		"effort":             41,
+	}
+	// when
+	_, err = s.repo.Create(s.ctx, s.spaceID, wit.ID, fields, s.creatorID)
+	// then
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+	assert.Contains(s.T(), err.Error(), "'effort'")
+	assert.Contains(s.T(), err.Error(), "at most 40")
+	// when
+	fields["effort"] = 40
+	wi, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, fields, s.creatorID)
+	require.Nil(s.T(), err)
+	wi.Fields["effort"] = 50
+	_, err = s.repo.Save(s.ctx, s.spaceID, *wi, s.creatorID)
+	// then
+	require.NotNil(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "at most 40")
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateAppliesDefaultValues() {
+	// given a work item type with default values as they are decoded from JSON
+	wit, err := workitem.NewWorkItemTypeRepository(s.DB).Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "defaults "+uuid.NewV4().String(), nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		"effort": {
+			Label:        "Effort",
+			Type:         workitem.SimpleType{Kind: workitem.KindInteger},
+			DefaultValue: 3.0,
+		},
+		"checklist": {
+			Label:        "Checklist",
+			Type:         workitem.SimpleType{Kind: workitem.KindMarkup},
+			DefaultValue: map[string]interface{}{"content": "- [ ] tested", "markup": rendering.SystemMarkupMarkdown},
+		},
+	})
+	require.Nil(s.T(), err)
+	// when
+	wi, err := s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+		"effort":             5,
+	}, s.creatorID)
+	// then the given values take precedence over the default values
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), 5, wi.Fields["effort"])
+	assert.Equal(s.T(), rendering.NewMarkupContent("- [ ] tested", rendering.SystemMarkupMarkdown), wi.Fields["checklist"])
+	// when
+	wi, err = s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}, s.creatorID)
+	// then
+	require.Nil(s.T(), err)
+	loaded, err := s.repo.LoadByID(s.ctx, wi.ID)
+	require.Nil(s.T(), err)
+	assert.EqualValues(s.T(), 3, loaded.Fields["effort"])
+	// when a given value is in the JSON form of a default value
+	_, err = s.repo.Create(s.ctx, s.spaceID, wit.ID, map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+		"effort":             5.0,
+	}, s.creatorID)
+	// then only default values are converted
+	require.NotNil(s.T(), err)
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateWITWithInvalidDefaultValue() {
+	// when
+	_, err := workitem.NewWorkItemTypeRepository(s.DB).Create(s.ctx, s.spaceID, nil, &workitem.SystemPlannerItem, "defaults "+uuid.NewV4().String(), nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		"effort": {
+			Label:        "Effort",
+			Type:         workitem.SimpleType{Kind: workitem.KindInteger},
+			DefaultValue: "three",
+		},
+	})
+	// then
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+	assert.Contains(s.T(), err.Error(), "fields.effort.defaultValue")
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateNumbersWorkItemsPerSpace() {
+	// given a new space
+	sp, err := space.NewRepository(s.DB).Create(s.ctx, &space.Space{Name: "Platform " + uuid.NewV4().String()})
+	require.Nil(s.T(), err)
+	fields := map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}
+	// when
+	first, err := s.repo.Create(s.ctx, sp.ID, workitem.SystemBug, fields, s.creatorID)
+	require.Nil(s.T(), err)
+	second, err := s.repo.Create(s.ctx, sp.ID, workitem.SystemBug, fields, s.creatorID)
+	require.Nil(s.T(), err)
+	// then the work items are numbered in the order of their creation
+	assert.Equal(s.T(), sp.KeyPrefix+"-1", first.Key)
+	assert.Equal(s.T(), sp.KeyPrefix+"-2", second.Key)
+	// and the keys are kept when the work items are updated
+	second.Fields[workitem.SystemTitle] = "Updated"
+	saved, err := s.repo.Save(s.ctx, sp.ID, *second, s.creatorID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), second.Key, saved.Key)
+	// and the work items can be loaded by their key in any case
+	loaded, err := s.repo.LoadByKey(s.ctx, sp.ID, strings.ToLower(second.Key))
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), second.ID, loaded.ID)
+	// but not in other spaces
+	_, err = s.repo.LoadByKey(s.ctx, s.spaceID, second.Key)
+	assert.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	_, err = s.repo.LoadByKey(s.ctx, sp.ID, sp.KeyPrefix+"-3")
+	assert.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+}
+
+func (s *workItemRepoBlackBoxTest) TestCreateInUnknownSpace() {
+	// when
+	_, err := s.repo.Create(s.ctx, uuid.NewV4(), workitem.SystemBug, map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}, s.creatorID)
+	// then
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+}
+
+func (s *workItemRepoBlackBoxTest) TestFieldKinds() {
+	// given a space and two types that define the "size" field with different kinds
+	sp, err := space.NewRepository(s.DB).Create(s.ctx, &space.Space{Name: "Field kinds " + uuid.NewV4().String()})
+	require.Nil(s.T(), err)
+	witRepo := workitem.NewWorkItemTypeRepository(s.DB)
+	numbered, err := witRepo.Create(s.ctx, sp.ID, nil, &workitem.SystemPlannerItem, "numbered "+uuid.NewV4().String(), nil, "fa-bug", map[string]workitem.FieldDefinition{
+		"size":   {Label: "Size", Type: workitem.SimpleType{Kind: workitem.KindInteger}},
+		"effort": {Label: "Effort", Type: workitem.SimpleType{Kind: workitem.KindFloat}},
+	})
+	require.Nil(s.T(), err)
+	named, err := witRepo.Create(s.ctx, sp.ID, nil, &workitem.SystemPlannerItem, "named "+uuid.NewV4().String(), nil, "fa-bug", map[string]workitem.FieldDefinition{
+		"size": {Label: "Size", Type: workitem.SimpleType{Kind: workitem.KindString}},
+	})
+	require.Nil(s.T(), err)
+	repo := workitem.NewWorkItemRepository(s.DB)
+	fields := map[string]interface{}{
+		workitem.SystemTitle: "Title",
+		workitem.SystemState: workitem.SystemStateNew,
+	}
+	_, err = repo.Create(s.ctx, sp.ID, numbered.ID, fields, s.creatorID)
+	require.Nil(s.T(), err)
+	// when
+	kinds, err := repo.FieldKinds(s.ctx, sp.ID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.KindInteger, kinds["size"])
+	// when a work item of the other type is created
+	_, err = repo.Create(s.ctx, sp.ID, named.ID, fields, s.creatorID)
+	require.Nil(s.T(), err)
+	kinds, err = repo.FieldKinds(s.ctx, sp.ID)
+	// then the conflicting field is left out
+	require.Nil(s.T(), err)
+	_, ok := kinds["size"]
+	assert.False(s.T(), ok)
+	assert.Equal(s.T(), workitem.KindFloat, kinds["effort"])
+}
@@ -0,0 +1,69 @@
+package workitem
+
+import (
+	"reflect"
+	"sort"
+)
+
+// FieldChange is the change of a single field from one revision of a work item to the next
+type FieldChange struct {
+	// Name is the name of the changed field
+	Name string
+	// OldValue is the stored value before the change, nil if the field was not set
+	OldValue interface{}
+	// NewValue is the stored value after the change, nil if the field was unset
+	NewValue interface{}
+}
+
+// RevisionChanges holds a revision together with the fields it changed
+type RevisionChanges struct {
+	Revision
+	Changes []FieldChange
+}
+
+// CompareFields returns the changes from the previous field values to the current ones, ordered by field name.
+// Fields that are missing or nil on one side are reported with a nil value on that side.
+func CompareFields(previous Fields, current Fields) []FieldChange {
+	names := map[string]struct{}{}
+	for name := range previous {
+		names[name] = struct{}{}
+	}
+	for name := range current {
+		names[name] = struct{}{}
+	}
+	result := []FieldChange{}
+	for name := range names {
+		oldValue := previous[name]
+		newValue := current[name]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		result = append(result, FieldChange{Name: name, OldValue: oldValue, NewValue: newValue})
+	}
+	sort.Sort(fieldChangesByName(result))
+	return result
+}
+
+// ComputeRevisionChanges compares the field snapshots of consecutive revisions of a work item. The revisions must be
+// ordered by time, like RevisionRepository.List returns them. The first revision is compared against an empty work
+// item. A deletion does not store any fields and therefore has no changes.
+func ComputeRevisionChanges(revisions []Revision) []RevisionChanges {
+	result := make([]RevisionChanges, len(revisions))
+	previous := Fields{}
+	for i, revision := range revisions {
+		result[i] = RevisionChanges{Revision: revision, Changes: []FieldChange{}}
+		if revision.Type == RevisionTypeDelete {
+			continue
+		}
+		result[i].Changes = CompareFields(previous, revision.WorkItemFields)
+		previous = revision.WorkItemFields
+	}
+	return result
+}
+
+// fieldChangesByName implements sort.Interface
+type fieldChangesByName []FieldChange
+
+func (c fieldChangesByName) Len() int           { return len(c) }
+func (c fieldChangesByName) Less(i, j int) bool { return c[i].Name < c[j].Name }
+func (c fieldChangesByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
@@ -0,0 +1,67 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/almighty/almighty-core/resource"
+	"github.com/almighty/almighty-core/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given
+	previous := workitem.Fields{
+		workitem.SystemTitle:     "A",
+		workitem.SystemState:     "new",
+		workitem.SystemAssignees: []interface{}{"a"},
+		"points":                 float64(3),
+		"removed":                "x",
+	}
+	current := workitem.Fields{
+		workitem.SystemTitle:     "A",
+		workitem.SystemState:     "closed",
+		workitem.SystemAssignees: []interface{}{"a", "b"},
+		"points":                 float64(3),
+		"added":                  true,
+		"removed":                nil,
+	}
+	// when
+	changes := workitem.CompareFields(previous, current)
+	// then
+	assert.Equal(t, []workitem.FieldChange{
+		{Name: "added", OldValue: nil, NewValue: true},
+		{Name: "removed", OldValue: "x", NewValue: nil},
+		{Name: workitem.SystemAssignees, OldValue: []interface{}{"a"}, NewValue: []interface{}{"a", "b"}},
+		{Name: workitem.SystemState, OldValue: "new", NewValue: "closed"},
+	}, changes)
+	assert.Empty(t, workitem.CompareFields(current, current))
+	assert.Empty(t, workitem.CompareFields(nil, workitem.Fields{}))
+}
+
+func TestComputeRevisionChanges(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given
+	revisions := []workitem.Revision{
+		{Type: workitem.RevisionTypeCreate, WorkItemFields: workitem.Fields{workitem.SystemTitle: "A", workitem.SystemState: "new"}},
+		{Type: workitem.RevisionTypeUpdate, WorkItemFields: workitem.Fields{workitem.SystemTitle: "A", workitem.SystemState: "open"}},
+		{Type: workitem.RevisionTypeUpdate, WorkItemFields: workitem.Fields{workitem.SystemTitle: "B", workitem.SystemState: "open"}},
+		{Type: workitem.RevisionTypeDelete, WorkItemFields: workitem.Fields{}},
+	}
+	// when
+	result := workitem.ComputeRevisionChanges(revisions)
+	// then
+	require.Len(t, result, 4)
+	assert.Equal(t, []workitem.FieldChange{
+		{Name: workitem.SystemState, NewValue: "new"},
+		{Name: workitem.SystemTitle, NewValue: "A"},
+	}, result[0].Changes)
+	assert.Equal(t, []workitem.FieldChange{{Name: workitem.SystemState, OldValue: "new", NewValue: "open"}}, result[1].Changes)
+	assert.Equal(t, []workitem.FieldChange{{Name: workitem.SystemTitle, OldValue: "A", NewValue: "B"}}, result[2].Changes)
+	assert.Equal(t, workitem.RevisionTypeDelete, result[3].Type)
+	assert.Empty(t, result[3].Changes)
+	assert.Empty(t, workitem.ComputeRevisionChanges(nil))
+}
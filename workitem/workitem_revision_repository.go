@@ -19,6 +19,12 @@ type RevisionRepository interface {
 	Create(ctx context.Context, modifierID uuid.UUID, revisionType RevisionType, workitem WorkItemStorage) error
 	// List retrieves all revisions for a given work item
 	List(ctx context.Context, workitemID string) ([]Revision, error)
+	// Load retrieves the revision with the given ID of a given work item
+	Load(ctx context.Context, workitemID string, revisionID uuid.UUID) (*Revision, error)
+	// LoadAt retrieves the latest revision of a given work item that was stored at or before the given time
+	LoadAt(ctx context.Context, workitemID string, at time.Time) (*Revision, error)
+	// LoadVersion retrieves the revision of a given work item that stored the given version
+	LoadVersion(ctx context.Context, workitemID string, version int) (*Revision, error)
 }
 
 // NewRevisionRepository creates a GormRevisionRepository
@@ -68,3 +74,36 @@ func (r *GormRevisionRepository) List(ctx context.Context, workitemID string) ([
 	}
 	return revisions, nil
 }
+
+// Load retrieves the revision with the given ID of a given work item
+// returns NotFoundError or InternalError
+func (r *GormRevisionRepository) Load(ctx context.Context, workitemID string, revisionID uuid.UUID) (*Revision, error) {
+	return r.first(ctx, workitemID, fmt.Sprintf("revision %s", revisionID), r.db.Where("id = ?", revisionID))
+}
+
+// LoadAt retrieves the latest revision of a given work item that was stored at or before the given time
+// returns NotFoundError or InternalError
+func (r *GormRevisionRepository) LoadAt(ctx context.Context, workitemID string, at time.Time) (*Revision, error) {
+	return r.first(ctx, workitemID, fmt.Sprintf("revision at %s", at.Format(time.RFC3339)), r.db.Where("revision_time <= ?", at).Order("revision_time desc"))
+}
+
+// LoadVersion retrieves the revision of a given work item that stored the given version. Deleting a work item
+// does not change its version, the revision that created or updated the version is returned.
+// returns NotFoundError or InternalError
+func (r *GormRevisionRepository) LoadVersion(ctx context.Context, workitemID string, version int) (*Revision, error) {
+	return r.first(ctx, workitemID, fmt.Sprintf("revision with version %d", version), r.db.Where("work_item_version = ? AND revision_type <> ?", version, RevisionTypeDelete).Order("revision_time desc"))
+}
+
+// first returns the first revision of the given work item selected by the given query
+func (r *GormRevisionRepository) first(ctx context.Context, workitemID string, description string, query *gorm.DB) (*Revision, error) {
+	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID}, "Loading %s of work item", description)
+	revision := Revision{}
+	db := query.Where("work_item_id = ?", workitemID).First(&revision)
+	if db.RecordNotFound() {
+		return nil, errors.NewNotFoundError(description+" of work item", workitemID)
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(fmt.Sprintf("failed to retrieve work item revision: %s", err.Error()))
+	}
+	return &revision, nil
+}
@@ -7,8 +7,10 @@ import (
 	"net/url"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/almighty/almighty-core/account"
+	"github.com/almighty/almighty-core/errors"
 	"github.com/almighty/almighty-core/gormsupport/cleaner"
 	"github.com/almighty/almighty-core/gormtestsupport"
 	"github.com/almighty/almighty-core/migration"
@@ -18,6 +20,8 @@ import (
 	"github.com/almighty/almighty-core/workitem"
 
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -140,3 +144,52 @@ func (s *workItemRevisionRepositoryBlackBoxTest) TestStoreRevisions() {
 	assert.Equal(s.T(), s.testIdentity3.ID, revision4.ModifierIdentity)
 	require.Empty(s.T(), revision4.WorkItemFields)
 }
+
+func (s *workItemRevisionRepositoryBlackBoxTest) TestLoadRevisions() {
+	ctx := goa.NewContext(context.Background(), nil, &http.Request{Host: "localhost"}, url.Values{})
+	// given
+	workItem, err := s.repository.Create(
+		ctx, space.SystemSpace, workitem.SystemBug,
+		map[string]interface{}{
+			workitem.SystemTitle: "Title",
+			workitem.SystemState: workitem.SystemStateNew,
+		}, s.testIdentity1.ID)
+	require.Nil(s.T(), err)
+	workItem.Fields[workitem.SystemTitle] = "Updated Title"
+	workItem, err = s.repository.Save(ctx, space.SystemSpace, *workItem, s.testIdentity2.ID)
+	require.Nil(s.T(), err)
+	err = s.repository.Delete(ctx, space.SystemSpace, workItem.ID, s.testIdentity3.ID)
+	require.Nil(s.T(), err)
+	revisions, err := s.revisionRepository.List(ctx, workItem.ID)
+	require.Nil(s.T(), err)
+	require.Len(s.T(), revisions, 3)
+
+	// when
+	revision, err := s.revisionRepository.Load(ctx, workItem.ID, revisions[1].ID)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), "Updated Title", revision.WorkItemFields[workitem.SystemTitle])
+	_, err = s.revisionRepository.Load(ctx, workItem.ID, uuid.NewV4())
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	// when
+	revision, err = s.revisionRepository.LoadVersion(ctx, workItem.ID, 0)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.RevisionTypeCreate, revision.Type)
+	// the deletion keeps the version of the last update
+	revision, err = s.revisionRepository.LoadVersion(ctx, workItem.ID, 1)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.RevisionTypeUpdate, revision.Type)
+	_, err = s.revisionRepository.LoadVersion(ctx, workItem.ID, 2)
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+	// when
+	revision, err = s.revisionRepository.LoadAt(ctx, workItem.ID, revisions[1].Time)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), revisions[1].ID, revision.ID)
+	revision, err = s.revisionRepository.LoadAt(ctx, workItem.ID, time.Now())
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workitem.RevisionTypeDelete, revision.Type)
+	_, err = s.revisionRepository.LoadAt(ctx, workItem.ID, revisions[0].Time.Add(-time.Second))
+	require.IsType(s.T(), errors.NotFoundError{}, errs.Cause(err))
+}
@@ -24,6 +24,8 @@ type WorkItemStorage struct {
 	ExecutionOrder float64
 	// Reference to one Space
 	SpaceID uuid.UUID `sql:"type:uuid"`
+	// the human-readable key of the work item in its space, e.g. "PLAT-42"
+	Key string
 }
 
 const (
@@ -64,6 +66,9 @@ func (wi WorkItemStorage) Equal(u convert.Equaler) bool {
 	if wi.SpaceID != other.SpaceID {
 		return false
 	}
+	if wi.Key != other.Key {
+		return false
+	}
 	return wi.Fields.Equal(other.Fields)
 }
 
@@ -31,6 +31,9 @@ const (
 	SystemIteration           = "system.iteration"
 	SystemArea                = "system.area"
 	SystemCodebase            = "system.codebase"
+	SystemLabels              = "system.labels"
+	// SystemKey is the read-only attribute with the human-readable key of a work item, e.g. "PLAT-42"
+	SystemKey = "system.key"
 
 	SystemStateOpen       = "open"
 	SystemStateNew        = "new"
@@ -39,6 +42,10 @@ const (
 	SystemStateClosed     = "closed"
 )
 
+// SystemDoneStates are the states of the workflow in which a work item is done, e.g. it no longer
+// blocks the work items depending on it
+var SystemDoneStates = []string{SystemStateResolved, SystemStateClosed}
+
 // Never ever change these UUIDs!!!
 var (
 	// base item type with common fields for planner item types like userstory, experience, bug, feature, etc.
@@ -69,6 +76,8 @@ type WorkItemType struct {
 	Path string
 	// definitions of the fields this work item type supports
 	Fields FieldDefinitions `sql:"type:jsonb"`
+	// the optional workflow restricting the transitions of an enum field
+	Workflow Workflow `sql:"type:jsonb"`
 	// Reference to one Space
 	SpaceID uuid.UUID `sql:"type:uuid"`
 }
@@ -154,6 +163,9 @@ func (wit WorkItemType) Equal(u convert.Equaler) bool {
 			return false
 		}
 	}
+	if !wit.Workflow.Equal(other.Workflow) {
+		return false
+	}
 	if wit.SpaceID != other.SpaceID {
 		return false
 	}
@@ -168,6 +180,7 @@ func (wit WorkItemType) ConvertWorkItemStorageToModel(workItem WorkItemStorage)
 		Version: workItem.Version,
 		Fields:  map[string]interface{}{},
 		SpaceID: workItem.SpaceID,
+		Key:     workItem.Key,
 	}
 
 	for name, field := range wit.Fields {
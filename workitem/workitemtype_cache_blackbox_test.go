@@ -55,6 +55,23 @@ func TestGetReturnNotOkAfterClear(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestGetReturnNotOkAfterRemove(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	c := workitem.NewWorkItemTypeCache()
+	removed := workitem.WorkItemType{ID: uuid.NewV4(), Name: "testRemove"}
+	kept := workitem.WorkItemType{ID: uuid.NewV4(), Name: "testKeep"}
+	c.Put(removed)
+	c.Put(kept)
+
+	c.Remove(removed.ID)
+	_, ok := c.Get(removed.ID)
+	assert.False(t, ok)
+	_, ok = c.Get(kept.ID)
+	assert.True(t, ok)
+}
+
 func TestNoFailuresWithConcurrentMapReadAndMapWrite(t *testing.T) {
 	t.Parallel()
 	resource.Require(t, resource.UnitTest)
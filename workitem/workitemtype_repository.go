@@ -22,6 +22,7 @@ type WorkItemTypeRepository interface {
 	Create(ctx context.Context, spaceID uuid.UUID, id *uuid.UUID, extendedTypeID *uuid.UUID, name string, description *string, icon string, fields map[string]FieldDefinition) (*WorkItemType, error)
 	List(ctx context.Context, spaceID uuid.UUID, start *int, length *int) ([]WorkItemType, error)
 	ListPlannerItems(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error)
+	SetWorkflow(ctx context.Context, spaceID uuid.UUID, id uuid.UUID, version int, workflow Workflow) (*WorkItemType, error)
 }
 
 // NewWorkItemTypeRepository creates a wi type repository based on gorm
@@ -102,6 +103,13 @@ func (r *GormWorkItemTypeRepository) LoadTypeFromDB(ctx context.Context, id uuid
 // ClearGlobalWorkItemTypeCache removes all work items from the global cache
 func ClearGlobalWorkItemTypeCache() {
 	cache.Clear()
+	fieldKindsCache.Clear()
+}
+
+// RemoveWorkItemTypeFromCache removes the work item type with the given ID from the global cache,
+// so that it is reloaded on next use
+func RemoveWorkItemTypeFromCache(id uuid.UUID) {
+	cache.Remove(id)
 }
 
 // Create creates a new work item in the repository
@@ -121,6 +129,7 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 
 	allFields := map[string]FieldDefinition{}
 	path := LtreeSafeID(*id)
+	var workflow Workflow
 	if extendedTypeID != nil {
 		extendedType := WorkItemType{}
 		db := r.db.Model(&extendedType).Where("id=?", extendedTypeID).First(&extendedType)
@@ -135,6 +144,8 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 			allFields[key] = value
 		}
 		path = extendedType.Path + pathSep + path
+		// the workflow of the extended type applies to the new type as well
+		workflow = extendedType.Workflow
 	}
 	// now process new fields, checking whether they are already there.
 	for field, definition := range fields {
@@ -142,6 +153,21 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 		if exists && !compatibleFields(existing, definition) {
 			return nil, fmt.Errorf("incompatible change for field %s", field)
 		}
+		if definition.Rules != nil {
+			if err := definition.Rules.Validate(definition.Type); err != nil {
+				return nil, errs.Wrapf(err, "invalid rules for field %s", field)
+			}
+		}
+		if rollUp, ok := definition.Type.(RollUpType); ok {
+			if err := rollUp.Validate(); err != nil {
+				return nil, errs.Wrapf(err, "invalid roll-up field %s", field)
+			}
+		}
+		if definition.DefaultValue != nil {
+			if _, err := definition.ConvertToModel(field, definition.NormalizeValue(definition.DefaultValue)); err != nil {
+				return nil, errors.NewBadParameterError("fields."+field+".defaultValue", definition.DefaultValue).Expected(err.Error())
+			}
+		}
 		allFields[field] = definition
 	}
 
@@ -153,6 +179,7 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 		Icon:        icon,
 		Path:        path,
 		Fields:      allFields,
+		Workflow:    workflow,
 		SpaceID:     spaceID,
 	}
 
@@ -164,6 +191,42 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 	return &created, nil
 }
 
+// SetWorkflow replaces the workflow of the work item type with the given spaceID and id, an empty
+// workflow removes it. Version must be the same as the one in the stored version. The cached type
+// is left as is, callers remove it with RemoveWorkItemTypeFromCache once the transaction has ended.
+// returns NotFoundError, BadParameterError, VersionConflictError, InternalError
+func (r *GormWorkItemTypeRepository) SetWorkflow(ctx context.Context, spaceID uuid.UUID, id uuid.UUID, version int, workflow Workflow) (*WorkItemType, error) {
+	res := WorkItemType{}
+	db := r.db.Model(&res).Where("id=? AND space_id=?", id, spaceID).First(&res)
+	if db.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item type", id.String())
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(err.Error())
+	}
+	if res.Version != version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	if err := workflow.Validate(res.Fields); err != nil {
+		return nil, err
+	}
+	res.Workflow = workflow
+	res.Version = version + 1
+	tx := r.db.Where("version = ?", version).Save(&res)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wit_id": id,
+			"err":    err,
+		}, "unable to set the workflow of the work item type")
+		return nil, errors.NewInternalError(err.Error())
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	log.Debug(ctx, map[string]interface{}{"wit_id": id}, "Work item type workflow set successfully!")
+	return &res, nil
+}
+
 // List returns work item types that derives from PlannerItem type
 func (r *GormWorkItemTypeRepository) ListPlannerItems(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error) {
 	var rows []WorkItemType
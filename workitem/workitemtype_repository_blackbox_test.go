@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/almighty/almighty-core/errors"
 	"github.com/almighty/almighty-core/gormsupport/cleaner"
 	"github.com/almighty/almighty-core/gormtestsupport"
 	"github.com/almighty/almighty-core/migration"
@@ -12,6 +13,7 @@ import (
 	"github.com/almighty/almighty-core/workitem"
 
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -138,3 +140,86 @@ func (s *workItemTypeRepoBlackBoxTest) TestDoNotCreateWITWithMissingBaseType() {
 	require.NotNil(s.T(), err)
 	require.Nil(s.T(), extendedWit)
 }
+
+func (s *workItemTypeRepoBlackBoxTest) TestSetWorkflow() {
+	// given
+	baseWit, err := s.repo.Create(s.ctx, space.SystemSpace, nil, &workitem.SystemPlannerItem, "foo.bar", nil, "fa-bomb", map[string]workitem.FieldDefinition{})
+	require.Nil(s.T(), err)
+	workflow := workitem.Workflow{
+		Field: workitem.SystemState,
+		Transitions: []workitem.Transition{
+			{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+		},
+	}
+	// when
+	updated, err := s.repo.SetWorkflow(s.ctx, space.SystemSpace, baseWit.ID, baseWit.Version, workflow)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), baseWit.Version+1, updated.Version)
+	workitem.RemoveWorkItemTypeFromCache(baseWit.ID)
+	loaded, err := s.repo.Load(s.ctx, space.SystemSpace, baseWit.ID)
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workflow, loaded.Workflow)
+	// the workflow is inherited by extending types
+	extendedWit, err := s.repo.Create(s.ctx, space.SystemSpace, nil, &baseWit.ID, "foo.baz", nil, "fa-bomb", map[string]workitem.FieldDefinition{})
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workflow, extendedWit.Workflow)
+	// an outdated version is rejected
+	_, err = s.repo.SetWorkflow(s.ctx, space.SystemSpace, baseWit.ID, baseWit.Version, workitem.Workflow{})
+	assert.IsType(s.T(), errors.VersionConflictError{}, errs.Cause(err))
+	// an empty workflow removes it
+	updated, err = s.repo.SetWorkflow(s.ctx, space.SystemSpace, baseWit.ID, updated.Version, workitem.Workflow{})
+	require.Nil(s.T(), err)
+	assert.True(s.T(), updated.Workflow.IsEmpty())
+}
+
+func (s *workItemTypeRepoBlackBoxTest) TestSetWorkflowOfSystemType() {
+	// given
+	bug, err := s.repo.LoadTypeFromDB(s.ctx, workitem.SystemBug)
+	require.Nil(s.T(), err)
+	workflow := workitem.Workflow{
+		Field: workitem.SystemState,
+		Transitions: []workitem.Transition{
+			{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+		},
+	}
+	// when
+	updated, err := s.repo.SetWorkflow(s.ctx, space.SystemSpace, workitem.SystemBug, bug.Version, workflow)
+	// then
+	require.Nil(s.T(), err)
+	assert.Equal(s.T(), workflow, updated.Workflow)
+	// restore the system type for the other tests
+	_, err = s.repo.SetWorkflow(s.ctx, space.SystemSpace, workitem.SystemBug, updated.Version, workitem.Workflow{})
+	require.Nil(s.T(), err)
+	workitem.RemoveWorkItemTypeFromCache(workitem.SystemBug)
+}
+
+func (s *workItemTypeRepoBlackBoxTest) TestSetInvalidWorkflow() {
+	// given
+	wit, err := s.repo.Create(s.ctx, space.SystemSpace, nil, &workitem.SystemPlannerItem, "foo.bar", nil, "fa-bomb", map[string]workitem.FieldDefinition{})
+	require.Nil(s.T(), err)
+	// when
+	_, err = s.repo.SetWorkflow(s.ctx, space.SystemSpace, wit.ID, wit.Version, workitem.Workflow{
+		Field: workitem.SystemTitle,
+		Transitions: []workitem.Transition{
+			{From: "foo", To: "bar"},
+		},
+	})
+	// then
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, err)
+	_, err = s.repo.SetWorkflow(s.ctx, space.SystemSpace, uuid.NewV4(), 0, workitem.Workflow{})
+	assert.IsType(s.T(), errors.NotFoundError{}, err)
+}
+
+func (s *workItemTypeRepoBlackBoxTest) TestCreateWITWithInvalidFieldRules() {
+	maxLength := 10
+	_, err := s.repo.Create(s.ctx, space.SystemSpace, nil, nil, "foo_bar", nil, "fa-bomb", map[string]workitem.FieldDefinition{
+		"foo": {
+			Type:  workitem.SimpleType{Kind: workitem.KindInteger},
+			Rules: &workitem.FieldRules{MaxLength: &maxLength},
+		},
+	})
+	require.NotNil(s.T(), err)
+	assert.IsType(s.T(), errors.BadParameterError{}, errs.Cause(err))
+}